@@ -30,10 +30,16 @@ import (
 	httpHandler "GURLS-Backend/internal/handler/http"
 	"GURLS-Backend/internal/repository/postgres"
 	"GURLS-Backend/internal/service"
+	"GURLS-Backend/pkg/geoip"
+	"GURLS-Backend/pkg/linkcheck"
 	"GURLS-Backend/pkg/logger"
+	"GURLS-Backend/pkg/pagemeta"
+	"GURLS-Backend/pkg/reputation"
+	"GURLS-Backend/pkg/urlpolicy"
 	"GURLS-Backend/pkg/useragent"
 	"context"
 	lg "log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -43,6 +49,7 @@ import (
 	"go.uber.org/zap"
 
 	_ "GURLS-Backend/docs" // Import swagger docs
+	_ "time/tzdata"        // Embedded time zone database for link timezones
 )
 
 func main() {
@@ -93,9 +100,23 @@ func main() {
 		log.Warn("failed to initialize User-Agent parser, using fallback", zap.Error(err))
 	}
 
+	// Initialize GeoIP database; the file is reloaded when it changes on disk
+	geoDB := geoip.Open(cfg.GeoIP.DatabasePath, log)
+	geoReloadInterval := parseDuration(log, "geoip reload_interval", cfg.GeoIP.ReloadInterval, time.Minute)
+	if err := geoDB.StartWatching(geoReloadInterval); err != nil {
+		log.Fatal("failed to start GeoIP database watcher", zap.Error(err))
+	}
+
 	// Initialize storage and service
 	storage := postgres.New(db, log)
-	urlShortenerService := service.NewURLShortener(storage, &cfg.URLShortener)
+	aliasPolicy, err := service.NewAliasPolicy(storage, &cfg.AliasPolicy, log)
+	if err != nil {
+		log.Fatal("invalid alias_policy configuration", zap.Error(err))
+	}
+	urlShortenerService, err := service.NewURLShortener(storage, &cfg.URLShortener, aliasPolicy)
+	if err != nil {
+		log.Fatal("invalid url_shortener configuration", zap.Error(err))
+	}
 	
 	// Initialize Payment service
 	paymentService := service.NewPaymentService(storage, &cfg.Payment, log)
@@ -103,20 +124,157 @@ func main() {
 	// Initialize JWT service for authentication
 	jwtConfig := &auth.JWTConfig{
 		SecretKey:            []byte("your-secret-key-here"), // TODO: Move to config
-		AccessTokenDuration:  15 * time.Minute,
-		RefreshTokenDuration: 24 * time.Hour * 7, // 7 days
+		AccessTokenDuration:  15*time.Minute,
+		RefreshTokenDuration: 24*time.Hour * 7, // 7 days
 		Issuer:               "GURLS-Backend",
 	}
 	jwtService := auth.NewJWTService(jwtConfig)
 	passwordService := auth.NewPasswordService()
 
+	// Initialize unlock service for password-protected links
+	unlockTTL := parseDuration(log, "link unlock_ttl", cfg.LinkProtection.UnlockTTL, 30*time.Minute)
+	attemptsWindow := parseDuration(log, "link attempts_window", cfg.LinkProtection.AttemptsWindow, 15*time.Minute)
+	linkUnlockService := auth.NewLinkUnlockService(&auth.LinkUnlockConfig{
+		SecretKey:      []byte(cfg.LinkProtection.UnlockSecret),
+		TokenDuration:  unlockTTL,
+		MaxAttempts:    cfg.LinkProtection.MaxAttempts,
+		AttemptsWindow: attemptsWindow,
+	}, passwordService)
+
+	// Initialize trash purger for soft-deleted links
+	purgeInterval := parseDuration(log, "trash purge_interval", cfg.Trash.PurgeInterval, time.Hour)
+	trashPurger := service.NewTrashPurger(storage, service.TrashPurgerConfig{
+		Retention: time.Duration(cfg.Trash.RetentionDays) * 24*time.Hour,
+		Interval:  purgeInterval,
+	}, log)
+	if err := trashPurger.Start(); err != nil {
+		log.Fatal("failed to start trash purger", zap.Error(err))
+	}
+
+	// Initialize custom domain service; ownership is verified with DNS TXT records
+	domainService := service.NewDomainService(storage, net.DefaultResolver, log)
+
+	// Initialize destination URL policy: normalization, redirect loops and private networks
+	urlPolicy := service.NewURLPolicy(storage, &cfg.URLPolicy, cfg.URLShortener.BaseURL, net.DefaultResolver, log)
+
+	// Initialize destination reputation: local threat lists, checked on create and rescanned periodically
+	threatLists := reputation.NewBlocklist(reputation.BlocklistFiles{
+		Domains:      cfg.Reputation.DomainsFile,
+		URLs:         cfg.Reputation.URLsFile,
+		HashPrefixes: cfg.Reputation.HashPrefixesFile,
+	})
+	if err := threatLists.Reload(); err != nil {
+		log.Warn("failed to load threat lists", zap.Error(err))
+	}
+	log.Info("threat lists loaded", zap.Int("entries", threatLists.Len()))
+	scanInterval := parseDuration(log, "reputation scan_interval", cfg.Reputation.ScanInterval, 6*time.Hour)
+	scanBatchSize := cfg.Reputation.ScanBatchSize
+	if scanBatchSize <= 0 {
+		scanBatchSize = 500
+	}
+	reputationService := service.NewReputationService(storage, threatLists, service.ReputationConfig{
+		Interval:  scanInterval,
+		BatchSize: scanBatchSize,
+	}, log)
+	if err := reputationService.Start(); err != nil {
+		log.Fatal("failed to start reputation scanner", zap.Error(err))
+	}
+
+	// Initialize link health checker: destinations are probed periodically to find broken links
+	healthCheckInterval := parseDuration(log, "health_check interval", cfg.HealthCheck.Interval, time.Hour)
+	healthCheckTimeout := parseDuration(log, "health_check timeout", cfg.HealthCheck.Timeout, 10*time.Second)
+	backoffBase := parseDuration(log, "health_check backoff_base", cfg.HealthCheck.BackoffBase, time.Minute)
+	backoffMax := parseDuration(log, "health_check backoff_max", cfg.HealthCheck.BackoffMax, 30*time.Minute)
+	// A host must come out of backoff before the next pass, otherwise its links are skipped forever
+	if backoffMax >= healthCheckInterval {
+		log.Warn("health_check backoff_max must be shorter than interval, using half of interval",
+			zap.Duration("backoff_max", backoffMax),
+			zap.Duration("interval", healthCheckInterval))
+		backoffMax = healthCheckInterval / 2
+	}
+	backoffBase = min(backoffBase, backoffMax)
+	healthCheckBatchSize := cfg.HealthCheck.BatchSize
+	if healthCheckBatchSize <= 0 {
+		healthCheckBatchSize = 200
+	}
+	failureThreshold := cfg.HealthCheck.FailureThreshold
+	if failureThreshold <= 0 {
+		failureThreshold = 3
+	}
+	// Destinations are user input: internal addresses are refused on connect, redirects included
+	var dialControl func(network, address string, c syscall.RawConn) error
+	if cfg.URLPolicy.BlockPrivateNetworks {
+		dialControl = urlpolicy.DialControl
+	}
+	prober := linkcheck.New(linkcheck.NewClient(healthCheckTimeout, dialControl), linkcheck.Config{
+		Timeout:            healthCheckTimeout,
+		PerHostConcurrency: cfg.HealthCheck.PerHostConcurrency,
+		BackoffBase:        backoffBase,
+		BackoffMax:         backoffMax,
+		UserAgent:          cfg.HealthCheck.UserAgent,
+	})
+	var healthNotifier service.LinkHealthNotifier = service.NewLogHealthNotifier(log)
+	if cfg.HealthCheck.WebhookURL != "" {
+		healthNotifier = service.NewWebhookHealthNotifier(cfg.HealthCheck.WebhookURL, &http.Client{Timeout: 10*time.Second})
+	}
+	linkHealthService := service.NewLinkHealthService(storage, prober, healthNotifier, service.LinkHealthConfig{
+		Interval:         healthCheckInterval,
+		BatchSize:        healthCheckBatchSize,
+		Workers:          cfg.HealthCheck.Workers,
+		FailureThreshold: failureThreshold,
+	}, log)
+	if cfg.HealthCheck.Enabled {
+		if err := linkHealthService.Start(); err != nil {
+			log.Fatal("failed to start link health checker", zap.Error(err))
+		}
+	} else {
+		log.Info("skipping link health checks (health_check.enabled: false)")
+	}
+
+	// Initialize metadata fetcher: empty titles and descriptions are filled from destination pages
+	metadataTimeout := parseDuration(log, "metadata timeout", cfg.Metadata.Timeout, 10*time.Second)
+	sweepInterval := parseDuration(log, "metadata sweep_interval", cfg.Metadata.SweepInterval, 10*time.Minute)
+	sweepBatchSize := cfg.Metadata.SweepBatchSize
+	if sweepBatchSize <= 0 {
+		sweepBatchSize = 100
+	}
+	pageFetcher := pagemeta.NewFetcher(linkcheck.NewClient(metadataTimeout, dialControl), cfg.Metadata.MaxBytes, cfg.Metadata.UserAgent)
+	metadataService := service.NewMetadataService(storage, pageFetcher, service.MetadataConfig{
+		Workers:        cfg.Metadata.Workers,
+		QueueSize:      cfg.Metadata.QueueSize,
+		Timeout:        metadataTimeout,
+		SweepInterval:  sweepInterval,
+		SweepBatchSize: sweepBatchSize,
+	}, log)
+	if cfg.Metadata.Enabled {
+		if err := metadataService.Start(); err != nil {
+			log.Fatal("failed to start metadata fetcher", zap.Error(err))
+		}
+	} else {
+		log.Info("skipping metadata fetching (metadata.enabled: false)")
+	}
+
+	// Forwarding headers are trusted only from configured proxies
+	trustedProxies, err := httpHandler.ParseTrustedProxies(cfg.HTTPServer.TrustedProxies)
+	if err != nil {
+		log.Fatal("invalid http_server trusted_proxies", zap.Error(err))
+	}
+
 	// Create unified HTTP server
 	httpAPIServer := httpHandler.NewServer(
 		storage,
 		urlShortenerService,
 		paymentService,
+		domainService,
+		aliasPolicy,
+		urlPolicy,
+		reputationService,
+		metadataService,
 		jwtService,
 		passwordService,
+		linkUnlockService,
+		geoDB,
+		trustedProxies,
 		log,
 		cfg.URLShortener.BaseURL,
 	)
@@ -128,9 +286,9 @@ func main() {
 	unifiedHTTPServer := &http.Server{
 		Addr:         ":8080",
 		Handler:      httpMux,
-		ReadTimeout:  30 * time.Second,
-		WriteTimeout: 30 * time.Second,
-		IdleTimeout:  60 * time.Second,
+		ReadTimeout:  30*time.Second,
+		WriteTimeout: 30*time.Second,
+		IdleTimeout:  60*time.Second,
 	}
 
 	log.Info("starting unified HTTP server (web-only architecture)", zap.String("address", ":8080"))
@@ -158,4 +316,37 @@ func main() {
 	} else {
 		log.Info("unified HTTP server stopped")
 	}
+
+	// Stop background workers
+	if err := trashPurger.Stop(); err != nil {
+		log.Error("failed to stop trash purger", zap.Error(err))
+	}
+	if err := reputationService.Stop(); err != nil {
+		log.Error("failed to stop reputation scanner", zap.Error(err))
+	}
+	if cfg.HealthCheck.Enabled {
+		if err := linkHealthService.Stop(); err != nil {
+			log.Error("failed to stop link health checker", zap.Error(err))
+		}
+	}
+	if cfg.Metadata.Enabled {
+		if err := metadataService.Stop(); err != nil {
+			log.Error("failed to stop metadata fetcher", zap.Error(err))
+		}
+	}
+	geoDB.StopWatching()
+}
+
+// parseDuration parses a duration setting. Empty, malformed and non-positive values are replaced
+// with the fallback: background worker tickers panic on intervals <= 0.
+func parseDuration(log *zap.Logger, name, value string, fallback time.Duration) time.Duration {
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		log.Warn("invalid "+name+", using default",
+			zap.String("value", value),
+			zap.Duration("default", fallback),
+			zap.Error(err))
+		return fallback
+	}
+	return duration
 }
\ No newline at end of file
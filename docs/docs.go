@@ -23,6 +23,95 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/api/account/utm-defaults": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the account-level UTM tags applied to new links that do not set their own",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Get UTM defaults",
+                "responses": {
+                    "200": {
+                        "description": "UTM defaults",
+                        "schema": {
+                            "$ref": "#/definitions/domain.UTMParams"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the account-level UTM tags. Missing or empty fields are cleared. Existing links keep their tags.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Update UTM defaults",
+                "parameters": [
+                    {
+                        "description": "UTM defaults",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.UTMParams"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated UTM defaults",
+                        "schema": {
+                            "$ref": "#/definitions/domain.UTMParams"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
         "/api/auth/login": {
             "post": {
                 "description": "Authenticate user and receive JWT tokens",
@@ -127,30 +216,82 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/links/{alias}": {
-            "delete": {
+        "/api/domains": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a specific link by alias",
+                "description": "Get the user's custom domains with their verification status",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Links"
+                    "Domains"
                 ],
-                "summary": "Delete a link",
+                "summary": "List custom domains",
+                "responses": {
+                    "200": {
+                        "description": "User domains",
+                        "schema": {
+                            "$ref": "#/definitions/http.ListDomainsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Register a domain for short links. Ownership is proven by publishing the returned TXT record and calling the verify endpoint; links can be created on the domain only after verification.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Domains"
+                ],
+                "summary": "Add a custom domain",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Link alias",
-                        "name": "alias",
-                        "in": "path",
-                        "required": true
+                        "description": "Domain name",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.DomainRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Link deleted successfully"
+                    "201": {
+                        "description": "Domain added",
+                        "schema": {
+                            "$ref": "#/definitions/http.DomainInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid domain name",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     },
                     "401": {
                         "description": "Authentication required",
@@ -162,7 +303,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Access denied",
+                        "description": "Feature not available in the plan",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -170,8 +311,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Link not found",
+                    "409": {
+                        "description": "Domain already exists",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -182,50 +323,89 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/shorten": {
-            "post": {
+        "/api/domains/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new shortened URL",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get a custom domain with its verification record",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Links"
+                    "Domains"
                 ],
-                "summary": "Create a short link",
+                "summary": "Get a custom domain",
                 "parameters": [
                     {
-                        "description": "Link creation request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/http.CreateLinkRequest"
-                        }
+                        "type": "integer",
+                        "description": "Domain ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Link created successfully",
+                    "200": {
+                        "description": "Domain",
                         "schema": {
-                            "$ref": "#/definitions/http.CreateLinkResponse"
+                            "$ref": "#/definitions/http.DomainInfo"
                         }
                     },
-                    "400": {
-                        "description": "Invalid request data",
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Domain not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a custom domain. Domains with links, including links in the trash, cannot be deleted.",
+                "tags": [
+                    "Domains"
+                ],
+                "summary": "Delete a custom domain",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Domain ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Domain deleted"
                     },
                     "401": {
                         "description": "Authentication required",
@@ -237,7 +417,16 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Subscription limit reached",
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Domain not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -246,7 +435,7 @@ const docTemplate = `{
                         }
                     },
                     "409": {
-                        "description": "Alias already exists",
+                        "description": "Domain has links",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -256,84 +445,2907 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "auth.AuthResponse": {
-            "type": "object",
-            "properties": {
-                "access_token": {
-                    "type": "string"
-                },
-                "refresh_token": {
-                    "type": "string"
-                },
-                "user": {
-                    "$ref": "#/definitions/auth.UserInfo"
-                }
-            }
-        },
-        "auth.LoginRequest": {
-            "type": "object",
-            "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "password": {
-                    "type": "string"
-                }
-            }
-        },
-        "auth.RegisterRequest": {
-            "type": "object",
-            "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "password": {
-                    "type": "string"
-                }
-            }
         },
-        "auth.UserInfo": {
-            "type": "object",
-            "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "email_verified": {
-                    "type": "boolean"
-                },
-                "id": {
-                    "type": "integer"
+        "/api/domains/{id}/verify": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Look up the verification TXT record of the domain and mark the domain as verified when the token matches",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Domains"
+                ],
+                "summary": "Verify a custom domain",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Domain ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Domain verified",
+                        "schema": {
+                            "$ref": "#/definitions/http.DomainInfo"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Domain not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Domain already verified by another account",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Verification record not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "502": {
+                        "description": "DNS lookup failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/links": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a page of the user's links with optional filters and sorting",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Links"
+                ],
+                "summary": "List links",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Created at or after (RFC3339)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created before (RFC3339)",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "active, scheduled, paused, expired or exhausted",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only links with (true) or without (false) a password",
+                        "name": "has_password",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search in alias, destination, title and description",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only links with the tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at (default), click_count or title",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc (default desc)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Links page",
+                        "schema": {
+                            "$ref": "#/definitions/http.ListLinksResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/links/broken": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get active links whose destination failed several background checks in a row: unreachable, 404, 410 or 5xx. A link leaves the list after the next successful check.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Links"
+                ],
+                "summary": "List broken links",
+                "responses": {
+                    "200": {
+                        "description": "Broken links",
+                        "schema": {
+                            "$ref": "#/definitions/http.ListBrokenLinksResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/links/bulk": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create links from a JSON array or a CSV file (columns: url, title, custom_alias, expires_at). The whole batch is checked against the plan before any link is created. Large batches or async=true are processed in the background.",
+                "consumes": [
+                    "application/json",
+                    "text/csv",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Links"
+                ],
+                "summary": "Bulk create links",
+                "parameters": [
+                    {
+                        "description": "Links to create (JSON)",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/service.BulkLinkInput"
+                            }
+                        }
+                    },
+                    {
+                        "type": "file",
+                        "description": "CSV file with links",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Process the batch in the background",
+                        "name": "async",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Per-row results",
+                        "schema": {
+                            "$ref": "#/definitions/http.BulkCreateLinksResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Job accepted",
+                        "schema": {
+                            "$ref": "#/definitions/http.BulkJobResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Batch exceeds the current plan",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/links/bulk/{job_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get progress and per-row results of an asynchronous bulk creation job",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Links"
+                ],
+                "summary": "Get bulk job status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Job status",
+                        "schema": {
+                            "$ref": "#/definitions/service.BulkJob"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/links/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stream the user's links with click counts and per-device totals as CSV or NDJSON. Supports the same filters and sorting as the link list.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "Links"
+                ],
+                "summary": "Export links",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Export format: csv (default) or ndjson",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created at or after (RFC3339)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created before (RFC3339)",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Link status: active, scheduled, paused, expired, exhausted",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by password protection",
+                        "name": "has_password",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search in alias, URL, title and description",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only links with the tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field: created_at, click_count, title",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order: asc or desc (default desc)",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Exported links",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/links/trash": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get links in the trash that can still be restored",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Links"
+                ],
+                "summary": "List deleted links",
+                "responses": {
+                    "200": {
+                        "description": "Deleted links",
+                        "schema": {
+                            "$ref": "#/definitions/http.ListLinksResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/links/{alias}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move a link to the trash. The alias stays reserved until the trash retention window passes.",
+                "tags": [
+                    "Links"
+                ],
+                "summary": "Delete a link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link alias",
+                        "name": "alias",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Custom domain of the link, omitted for the service domain",
+                        "name": "domain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Link deleted successfully"
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change destination, title, description, expiration or pause a link. Destination changes are recorded in the revision history.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Links"
+                ],
+                "summary": "Update a link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link alias",
+                        "name": "alias",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Custom domain of the link, omitted for the service domain",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.UpdateLinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated link",
+                        "schema": {
+                            "$ref": "#/definitions/http.LinkInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data or destination rejected by the URL policy or listed as unsafe",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied or enabling a link disabled after a threat-list match (code: link_flagged)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/links/{alias}/appeal": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ask to re-enable a link that was disabled because its destination was found in threat lists. Destinations are re-checked against the current lists: if none is listed anymore the link is re-enabled at once, otherwise the appeal stays pending and is re-checked on every reputation scan.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Links"
+                ],
+                "summary": "Appeal a flagged link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link alias",
+                        "name": "alias",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Custom domain of the link, omitted for the service domain",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Appeal message",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/http.LinkAppealRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Link re-enabled",
+                        "schema": {
+                            "$ref": "#/definitions/http.LinkAppealResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Destination is still listed, appeal is re-checked on every scan",
+                        "schema": {
+                            "$ref": "#/definitions/http.LinkAppealResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Link is not flagged or already has a pending appeal",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/links/{alias}/metadata/refresh": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fetch the destination page again and store its title, description, OpenGraph and Twitter card tags and favicon. The link title and description are replaced only when empty or previously filled from the page. Allowed once a minute per link.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Links"
+                ],
+                "summary": "Refresh link metadata",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link alias",
+                        "name": "alias",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Custom domain of the link, omitted for the service domain",
+                        "name": "domain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Link with refreshed metadata",
+                        "schema": {
+                            "$ref": "#/definitions/http.LinkInfo"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Metadata was refreshed less than a minute ago",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "502": {
+                        "description": "Destination page could not be fetched",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/links/{alias}/qr": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Render a QR code of the short link as PNG or SVG. With track=true the encoded URL carries the ?qr=1 marker and scans are counted as the qr source in link stats.",
+                "produces": [
+                    "image/png",
+                    "image/svg+xml"
+                ],
+                "tags": [
+                    "Links"
+                ],
+                "summary": "Get link QR code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link alias",
+                        "name": "alias",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Custom domain of the link, omitted for the service domain",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Image format: png (default) or svg",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Image width and height in pixels, 64-2048 (default 512)",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Error correction level: L, M (default), Q or H",
+                        "name": "level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Quiet zone in modules, 0-16 (default 4)",
+                        "name": "margin",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Foreground color, hex RRGGBB (default 000000)",
+                        "name": "fg",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Background color, hex RRGGBB (default ffffff)",
+                        "name": "bg",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Append the QR scan marker to the encoded URL",
+                        "name": "track",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "QR code image",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/links/{alias}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move a link from the trash back to the active list",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Links"
+                ],
+                "summary": "Restore a deleted link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link alias",
+                        "name": "alias",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Custom domain of the link, omitted for the service domain",
+                        "name": "domain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Restored link",
+                        "schema": {
+                            "$ref": "#/definitions/http.LinkInfo"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found in trash",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/links/{alias}/revisions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the history of destination changes for a link",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Links"
+                ],
+                "summary": "List link revisions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link alias",
+                        "name": "alias",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Custom domain of the link, omitted for the service domain",
+                        "name": "domain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Revision history",
+                        "schema": {
+                            "$ref": "#/definitions/http.ListLinkRevisionsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/links/{alias}/revisions/{revision_id}/rollback": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restore the destination URL recorded in a previous revision. The rollback itself is recorded as a new revision.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Links"
+                ],
+                "summary": "Roll back link destination",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link alias",
+                        "name": "alias",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Custom domain of the link, omitted for the service domain",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision ID",
+                        "name": "revision_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated link",
+                        "schema": {
+                            "$ref": "#/definitions/http.LinkInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid revision ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Link or revision not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/links/{alias}/rules": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the link's redirect rules in evaluation order",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Links"
+                ],
+                "summary": "List redirect rules",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link alias",
+                        "name": "alias",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Custom domain of the link, omitted for the service domain",
+                        "name": "domain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Redirect rules",
+                        "schema": {
+                            "$ref": "#/definitions/http.ListRedirectRulesResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the link's redirect rules. Rules are evaluated in list order and the first rule whose conditions (countries, device types, OS families, browsers, active period, weekdays, time of day in the link's timezone) all match the visitor wins; visitors matching no rule go to the link destination. Pass the id of an existing rule to keep its click statistics.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Links"
+                ],
+                "summary": "Replace redirect rules",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link alias",
+                        "name": "alias",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Custom domain of the link, omitted for the service domain",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Redirect rules",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.ReplaceRedirectRulesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated redirect rules",
+                        "schema": {
+                            "$ref": "#/definitions/http.ListRedirectRulesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/links/{alias}/variants": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the link's weighted destination variants",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Links"
+                ],
+                "summary": "List A/B variants",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link alias",
+                        "name": "alias",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Custom domain of the link, omitted for the service domain",
+                        "name": "domain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "A/B variants",
+                        "schema": {
+                            "$ref": "#/definitions/http.ListLinkVariantsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the link's destination variants. Visitors matching no redirect rule are split between the variants by weight and keep their variant via a cookie. Pass the id of an existing variant to keep its visitors and click statistics.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Links"
+                ],
+                "summary": "Replace A/B variants",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Link alias",
+                        "name": "alias",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Custom domain of the link, omitted for the service domain",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "description": "A/B variants",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.ReplaceLinkVariantsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated A/B variants",
+                        "schema": {
+                            "$ref": "#/definitions/http.ListLinkVariantsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/shorten": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new shortened URL",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Links"
+                ],
+                "summary": "Create a short link",
+                "parameters": [
+                    {
+                        "description": "Link creation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.CreateLinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Link created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/http.CreateLinkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data or custom alias rejected by the alias policy (code: alias_too_short, alias_too_long, alias_invalid_characters, alias_reserved, alias_blocked, alias_profane) or destination rejected by the URL policy (code: url_invalid, url_scheme_not_allowed, url_credentials_not_allowed, url_self_reference, url_shortener_not_allowed, url_private_network, url_malicious)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Subscription limit reached",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Alias already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Failed to generate a unique alias",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tags": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the user's tags with the number of links in each",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "List tags",
+                "responses": {
+                    "200": {
+                        "description": "User tags",
+                        "schema": {
+                            "$ref": "#/definitions/http.ListTagsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a tag for grouping links. Names are case-insensitive and unique per user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Create a tag",
+                "parameters": [
+                    {
+                        "description": "Tag name",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.TagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Tag created",
+                        "schema": {
+                            "$ref": "#/definitions/http.TagInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid tag name",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Tag already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tags/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a tag and remove it from all links. The links themselves are not affected.",
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Delete a tag",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Tag deleted"
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Rename a tag. Links keep the tag under the new name.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Rename a tag",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New tag name",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.TagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tag renamed",
+                        "schema": {
+                            "$ref": "#/definitions/http.TagInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid tag name",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Tag already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tags/{id}/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get aggregated statistics for all links with the tag: link count, total clicks and clicks by device",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Get tag statistics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tag statistics",
+                        "schema": {
+                            "$ref": "#/definitions/http.TagStatsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "auth.AuthResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/auth.UserInfo"
+                }
+            }
+        },
+        "auth.LoginRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.RegisterRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.UserInfo": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "email_verified": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.LinkMetadata": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "error": {
+                    "description": "ошибка последней загрузки",
+                    "type": "string"
+                },
+                "favicon_url": {
+                    "type": "string"
+                },
+                "fetched_at": {
+                    "description": "NULL - страница еще не загружалась",
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "site_name": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "twitter_card": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.SocialPreview": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "description": "абсолютный http(s) адрес картинки",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.UTMParams": {
+            "type": "object",
+            "properties": {
+                "campaign": {
+                    "type": "string"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "medium": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "term": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.BrokenLinkInfo": {
+            "type": "object",
+            "properties": {
+                "alias": {
+                    "type": "string"
+                },
+                "click_count": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "domain": {
+                    "description": "собственный домен ссылки",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "fallback_url": {
+                    "type": "string"
+                },
+                "flag_reason": {
+                    "description": "категория угрозы",
+                    "type": "string"
+                },
+                "flagged_at": {
+                    "description": "ссылка отключена: адрес назначения в списках угроз",
+                    "type": "string"
+                },
+                "forward_path": {
+                    "type": "boolean"
+                },
+                "forward_query": {
+                    "type": "boolean"
+                },
+                "has_password": {
+                    "type": "boolean"
+                },
+                "health": {
+                    "$ref": "#/definitions/http.LinkHealthInfo"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "max_clicks": {
+                    "type": "integer"
+                },
+                "metadata": {
+                    "description": "Метаданные страницы назначения; нет, пока страница не загружалась",
+                    "$ref": "#/definitions/domain.LinkMetadata"
+                },
+                "original_url": {
+                    "type": "string"
+                },
+                "query_conflict": {
+                    "type": "string"
+                },
+                "redirect_mode": {
+                    "type": "string"
+                },
+                "short_url": {
+                    "type": "string"
+                },
+                "social_preview": {
+                    "description": "Карточка ссылки для мессенджеров и соцсетей, заданная владельцем",
+                    "$ref": "#/definitions/domain.SocialPreview"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "tracking_pixels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "utm": {
+                    "$ref": "#/definitions/domain.UTMParams"
+                }
+            }
+        },
+        "http.BulkCreateLinksResponse": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.BulkLinkResult"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "http.BulkJobResponse": {
+            "type": "object",
+            "properties": {
+                "job_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "status_url": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "http.CreateLinkRequest": {
+            "type": "object",
+            "properties": {
+                "custom_alias": {
+                    "type": "string"
+                },
+                "domain": {
+                    "description": "подтвержденный собственный домен, по умолчанию домен сервиса",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "fallback_url": {
+                    "type": "string"
+                },
+                "forward_path": {
+                    "type": "boolean"
+                },
+                "forward_query": {
+                    "description": "Передача query string и хвоста пути в адрес назначения",
+                    "type": "boolean"
+                },
+                "max_clicks": {
+                    "type": "integer"
+                },
+                "original_url": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "query_conflict": {
+                    "description": "keep (по умолчанию), override или append",
+                    "type": "string"
+                },
+                "redirect_mode": {
+                    "description": "Способ редиректа: 301, 302 (по умолчанию), 307, 308, meta_refresh или interstitial;\ntracking_pixels - адреса пикселей аналитики, которые загружает промежуточная страница",
+                    "type": "string"
+                },
+                "social_preview": {
+                    "description": "Карточка ссылки для мессенджеров и соцсетей вместо превью страницы назначения",
+                    "$ref": "#/definitions/domain.SocialPreview"
+                },
+                "starts_at": {
+                    "description": "до этого момента вместо редиректа показывается страница ожидания",
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "timezone": {
+                    "description": "IANA пояс для правил по времени, по умолчанию UTC",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "tracking_pixels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "utm": {
+                    "description": "UTM метки, добавляемые к адресу назначения; незаданные берутся из настроек аккаунта",
+                    "$ref": "#/definitions/domain.UTMParams"
+                }
+            }
+        },
+        "http.CreateLinkResponse": {
+            "type": "object",
+            "properties": {
+                "alias": {
+                    "type": "string"
+                },
+                "short_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DomainInfo": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "hostname": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "verification": {
+                    "$ref": "#/definitions/http.DomainVerificationInfo"
+                },
+                "verified": {
+                    "type": "boolean"
+                },
+                "verified_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DomainRequest": {
+            "type": "object",
+            "properties": {
+                "hostname": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.DomainVerificationInfo": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.LinkAppealRequest": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "description": "пояснение владельца для администратора",
+                    "type": "string"
+                }
+            }
+        },
+        "http.LinkAppealResponse": {
+            "type": "object",
+            "properties": {
+                "alias": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "resolved_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "approved - ссылка снова включена, pending - адрес еще в списках угроз",
+                    "type": "string"
+                },
+                "threat": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.LinkHealthInfo": {
+            "type": "object",
+            "properties": {
+                "broken_since": {
+                    "type": "string"
+                },
+                "checked_at": {
+                    "type": "string"
+                },
+                "consecutive_failures": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "final_url": {
+                    "description": "адрес после редиректов",
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "type": "integer"
+                },
+                "status_code": {
+                    "description": "0 - ответ не получен",
+                    "type": "integer"
+                }
+            }
+        },
+        "http.LinkInfo": {
+            "type": "object",
+            "properties": {
+                "alias": {
+                    "type": "string"
+                },
+                "click_count": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "domain": {
+                    "description": "собственный домен ссылки",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "fallback_url": {
+                    "type": "string"
+                },
+                "flag_reason": {
+                    "description": "категория угрозы",
+                    "type": "string"
+                },
+                "flagged_at": {
+                    "description": "ссылка отключена: адрес назначения в списках угроз",
+                    "type": "string"
+                },
+                "forward_path": {
+                    "type": "boolean"
+                },
+                "forward_query": {
+                    "type": "boolean"
+                },
+                "has_password": {
+                    "type": "boolean"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "max_clicks": {
+                    "type": "integer"
+                },
+                "metadata": {
+                    "description": "Метаданные страницы назначения; нет, пока страница не загружалась",
+                    "$ref": "#/definitions/domain.LinkMetadata"
+                },
+                "original_url": {
+                    "type": "string"
+                },
+                "query_conflict": {
+                    "type": "string"
+                },
+                "redirect_mode": {
+                    "type": "string"
+                },
+                "short_url": {
+                    "type": "string"
+                },
+                "social_preview": {
+                    "description": "Карточка ссылки для мессенджеров и соцсетей, заданная владельцем",
+                    "$ref": "#/definitions/domain.SocialPreview"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "tracking_pixels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "utm": {
+                    "$ref": "#/definitions/domain.UTMParams"
+                }
+            }
+        },
+        "http.LinkRevisionInfo": {
+            "type": "object",
+            "properties": {
+                "changed_by": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "original_url": {
+                    "type": "string"
+                },
+                "previous_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.LinkVariantInfo": {
+            "type": "object",
+            "properties": {
+                "destination_url": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "weight": {
+                    "type": "integer"
                 }
             }
         },
-        "http.CreateLinkRequest": {
+        "http.LinkVariantRequest": {
             "type": "object",
             "properties": {
-                "custom_alias": {
+                "destination_url": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID существующего варианта, чтобы сохранить его посетителей и статистику",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "weight": {
+                    "type": "integer"
+                }
+            }
+        },
+        "http.ListBrokenLinksResponse": {
+            "type": "object",
+            "properties": {
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http.BrokenLinkInfo"
+                    }
+                }
+            }
+        },
+        "http.ListDomainsResponse": {
+            "type": "object",
+            "properties": {
+                "domains": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http.DomainInfo"
+                    }
+                }
+            }
+        },
+        "http.ListLinkRevisionsResponse": {
+            "type": "object",
+            "properties": {
+                "alias": {
+                    "type": "string"
+                },
+                "revisions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http.LinkRevisionInfo"
+                    }
+                }
+            }
+        },
+        "http.ListLinkVariantsResponse": {
+            "type": "object",
+            "properties": {
+                "alias": {
+                    "type": "string"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http.LinkVariantInfo"
+                    }
+                }
+            }
+        },
+        "http.ListLinksResponse": {
+            "type": "object",
+            "properties": {
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http.LinkInfo"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "http.ListRedirectRulesResponse": {
+            "type": "object",
+            "properties": {
+                "alias": {
+                    "type": "string"
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http.RedirectRuleInfo"
+                    }
+                }
+            }
+        },
+        "http.ListTagsResponse": {
+            "type": "object",
+            "properties": {
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http.TagInfo"
+                    }
+                }
+            }
+        },
+        "http.RedirectRuleInfo": {
+            "type": "object",
+            "properties": {
+                "active_from": {
+                    "type": "string"
+                },
+                "active_until": {
+                    "type": "string"
+                },
+                "browsers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "countries": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "destination_url": {
+                    "type": "string"
+                },
+                "device_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "os_families": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "time_from": {
+                    "type": "string"
+                },
+                "time_to": {
+                    "type": "string"
+                },
+                "weekdays": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "http.RedirectRuleRequest": {
+            "type": "object",
+            "properties": {
+                "active_from": {
+                    "description": "RFC3339, начало периода действия правила",
+                    "type": "string"
+                },
+                "active_until": {
+                    "description": "RFC3339, конец периода действия правила",
+                    "type": "string"
+                },
+                "browsers": {
+                    "description": "Chrome, Mobile Safari, Firefox...",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "countries": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "destination_url": {
+                    "type": "string"
+                },
+                "device_types": {
+                    "description": "desktop, mobile, tablet, bot, unknown",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "description": "ID существующего правила, чтобы сохранить его статистику",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "os_families": {
+                    "description": "iOS, Android, Windows, Mac OS X...",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "time_from": {
+                    "description": "HH:MM, вместе с time_to",
+                    "type": "string"
+                },
+                "time_to": {
+                    "description": "HH:MM, раньше time_from - окно через полночь",
+                    "type": "string"
+                },
+                "weekdays": {
+                    "description": "mon, tue, wed, thu, fri, sat, sun",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "http.ReplaceLinkVariantsRequest": {
+            "type": "object",
+            "properties": {
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http.LinkVariantRequest"
+                    }
+                }
+            }
+        },
+        "http.ReplaceRedirectRulesRequest": {
+            "type": "object",
+            "properties": {
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http.RedirectRuleRequest"
+                    }
+                }
+            }
+        },
+        "http.TagInfo": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "link_count": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.TagRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.TagStatsResponse": {
+            "type": "object",
+            "properties": {
+                "clicks_by_device": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "link_count": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "total_clicks": {
+                    "type": "integer"
+                }
+            }
+        },
+        "http.UpdateLinkRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
                     "type": "string"
                 },
                 "expires_at": {
                     "type": "string"
                 },
+                "fallback_url": {
+                    "type": "string"
+                },
+                "forward_path": {
+                    "type": "boolean"
+                },
+                "forward_query": {
+                    "type": "boolean"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
                 "original_url": {
                     "type": "string"
                 },
+                "query_conflict": {
+                    "type": "string"
+                },
+                "redirect_mode": {
+                    "type": "string"
+                },
+                "social_preview": {
+                    "description": "заменяет карточку целиком",
+                    "$ref": "#/definitions/domain.SocialPreview"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "timezone": {
+                    "type": "string"
+                },
                 "title": {
                     "type": "string"
+                },
+                "tracking_pixels": {
+                    "description": "заменяет список целиком",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "utm": {
+                    "description": "заменяет набор меток целиком",
+                    "$ref": "#/definitions/domain.UTMParams"
                 }
             }
         },
-        "http.CreateLinkResponse": {
+        "service.BulkJob": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "processed": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.BulkLinkResult"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "succeeded": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "service.BulkLinkInput": {
+            "type": "object",
+            "properties": {
+                "custom_alias": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.BulkLinkResult": {
             "type": "object",
             "properties": {
                 "alias": {
                     "type": "string"
                 },
+                "code": {
+                    "description": "код отказа политики алиасов",
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "row": {
+                    "type": "integer"
+                },
                 "short_url": {
                     "type": "string"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         }
@@ -0,0 +1,166 @@
+package auth
+
+import (
+	"GURLS-Backend/internal/domain"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// LinkUnlockCookieName имя cookie, подтверждающей разблокировку защищенной ссылки
+	LinkUnlockCookieName = "gurls_unlock"
+
+	// attemptsPruneThreshold размер таблицы попыток, после которого удаляются устаревшие записи
+	attemptsPruneThreshold = 10000
+)
+
+var (
+	ErrTooManyUnlockAttempts = errors.New("too many unlock attempts")
+	ErrLinkNotProtected      = errors.New("link is not password protected")
+)
+
+// LinkUnlockConfig конфигурация разблокировки защищенных паролем ссылок
+type LinkUnlockConfig struct {
+	SecretKey      []byte
+	TokenDuration  time.Duration
+	MaxAttempts    int
+	AttemptsWindow time.Duration
+}
+
+// unlockAttempts счетчик неудачных попыток ввода пароля в пределах окна
+type unlockAttempts struct {
+	count       int
+	windowStart time.Time
+}
+
+// LinkUnlockService проверяет пароли защищенных ссылок и выдает подписанные токены разблокировки
+type LinkUnlockService struct {
+	config          *LinkUnlockConfig
+	passwordService *PasswordService
+
+	mu       sync.Mutex
+	attempts map[string]*unlockAttempts
+}
+
+// NewLinkUnlockService создает новый сервис разблокировки ссылок
+func NewLinkUnlockService(config *LinkUnlockConfig, passwordService *PasswordService) *LinkUnlockService {
+	return &LinkUnlockService{
+		config:          config,
+		passwordService: passwordService,
+		attempts:        make(map[string]*unlockAttempts),
+	}
+}
+
+// TokenDuration возвращает время жизни токена разблокировки
+func (s *LinkUnlockService) TokenDuration() time.Duration {
+	return s.config.TokenDuration
+}
+
+// Unlock проверяет пароль ссылки с учетом лимита неудачных попыток для пары (ссылка, IP)
+// и при успехе возвращает подписанный токен разблокировки
+func (s *LinkUnlockService) Unlock(link *domain.Link, ipAddress, password string) (string, error) {
+	if link.PasswordHash == nil {
+		return "", ErrLinkNotProtected
+	}
+
+	key := link.Alias + "|" + ipAddress
+	if !s.allowAttempt(key) {
+		return "", ErrTooManyUnlockAttempts
+	}
+
+	if err := s.passwordService.VerifyPassword(*link.PasswordHash, password); err != nil {
+		s.registerFailure(key)
+		return "", ErrInvalidPassword
+	}
+
+	s.resetAttempts(key)
+	return s.GenerateToken(link, time.Now().Add(s.config.TokenDuration)), nil
+}
+
+// GenerateToken создает токен разблокировки ссылки, действующий до expiresAt.
+// Подпись привязана к хешу пароля, поэтому смена пароля инвалидирует выданные токены.
+func (s *LinkUnlockService) GenerateToken(link *domain.Link, expiresAt time.Time) string {
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	return expires + "." + s.sign(link, expires)
+}
+
+// ValidateToken проверяет токен разблокировки для ссылки
+func (s *LinkUnlockService) ValidateToken(link *domain.Link, token string) bool {
+	if link.PasswordHash == nil {
+		return true
+	}
+
+	expires, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+
+	expiresUnix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresUnix {
+		return false
+	}
+
+	return hmac.Equal([]byte(signature), []byte(s.sign(link, expires)))
+}
+
+// sign вычисляет HMAC подпись токена для ссылки
+func (s *LinkUnlockService) sign(link *domain.Link, expires string) string {
+	mac := hmac.New(sha256.New, s.config.SecretKey)
+	mac.Write([]byte(link.Alias))
+	mac.Write([]byte{0})
+	mac.Write([]byte(*link.PasswordHash))
+	mac.Write([]byte{0})
+	mac.Write([]byte(expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// allowAttempt проверяет, не исчерпан ли лимит неудачных попыток
+func (s *LinkUnlockService) allowAttempt(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempts, ok := s.attempts[key]
+	if !ok {
+		return true
+	}
+	if time.Since(attempts.windowStart) > s.config.AttemptsWindow {
+		delete(s.attempts, key)
+		return true
+	}
+	return attempts.count < s.config.MaxAttempts
+}
+
+// registerFailure учитывает неудачную попытку ввода пароля
+func (s *LinkUnlockService) registerFailure(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if len(s.attempts) > attemptsPruneThreshold {
+		for k, a := range s.attempts {
+			if now.Sub(a.windowStart) > s.config.AttemptsWindow {
+				delete(s.attempts, k)
+			}
+		}
+	}
+
+	attempts, ok := s.attempts[key]
+	if !ok || now.Sub(attempts.windowStart) > s.config.AttemptsWindow {
+		s.attempts[key] = &unlockAttempts{count: 1, windowStart: now}
+		return
+	}
+	attempts.count++
+}
+
+// resetAttempts сбрасывает счетчик после успешной разблокировки
+func (s *LinkUnlockService) resetAttempts(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.attempts, key)
+}
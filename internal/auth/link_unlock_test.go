@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"GURLS-Backend/internal/domain"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestUnlockService(t *testing.T) (*LinkUnlockService, *domain.Link) {
+	t.Helper()
+	passwords := NewPasswordServiceWithCost(4)
+	hash, err := passwords.HashPassword("secret-password")
+	require.NoError(t, err)
+
+	service := NewLinkUnlockService(&LinkUnlockConfig{
+		SecretKey:      []byte("test-unlock-secret"),
+		TokenDuration:  time.Minute,
+		MaxAttempts:    3,
+		AttemptsWindow: time.Minute,
+	}, passwords)
+	return service, &domain.Link{Alias: "promo", PasswordHash: &hash}
+}
+
+func TestLinkUnlockService_Token(t *testing.T) {
+	service, link := newTestUnlockService(t)
+
+	token, err := service.Unlock(link, "203.0.113.1", "secret-password")
+	require.NoError(t, err)
+	assert.True(t, service.ValidateToken(link, token))
+
+	// токен привязан к алиасу, ключу подписи и хешу пароля
+	other := &domain.Link{Alias: "other", PasswordHash: link.PasswordHash}
+	assert.False(t, service.ValidateToken(other, token))
+
+	changedHash := *link.PasswordHash + "x"
+	assert.False(t, service.ValidateToken(&domain.Link{Alias: link.Alias, PasswordHash: &changedHash}, token))
+
+	otherKey := NewLinkUnlockService(&LinkUnlockConfig{SecretKey: []byte("another-secret")}, NewPasswordServiceWithCost(4))
+	assert.False(t, otherKey.ValidateToken(link, token))
+
+	// подделанный срок действия не проходит проверку подписи
+	expires, signature, _ := strings.Cut(token, ".")
+	assert.False(t, service.ValidateToken(link, expires+"0."+signature))
+
+	expired := service.GenerateToken(link, time.Now().Add(-time.Second))
+	assert.False(t, service.ValidateToken(link, expired))
+
+	for _, malformed := range []string{"", "no-dot", "abc.def"} {
+		assert.False(t, service.ValidateToken(link, malformed), malformed)
+	}
+}
+
+func TestLinkUnlockService_AttemptsLimit(t *testing.T) {
+	service, link := newTestUnlockService(t)
+
+	for i := 0; i < 3; i++ {
+		_, err := service.Unlock(link, "203.0.113.1", "wrong")
+		assert.ErrorIs(t, err, ErrInvalidPassword)
+	}
+
+	// после исчерпания лимита не принимается даже верный пароль
+	_, err := service.Unlock(link, "203.0.113.1", "secret-password")
+	assert.ErrorIs(t, err, ErrTooManyUnlockAttempts)
+
+	// лимит считается отдельно для каждого IP
+	_, err = service.Unlock(link, "203.0.113.2", "secret-password")
+	assert.NoError(t, err)
+
+	// по истечении окна попытки снова разрешены
+	service.config.AttemptsWindow = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	_, err = service.Unlock(link, "203.0.113.1", "secret-password")
+	assert.NoError(t, err)
+
+	_, err = service.Unlock(&domain.Link{Alias: "open"}, "203.0.113.1", "")
+	assert.ErrorIs(t, err, ErrLinkNotProtected)
+}
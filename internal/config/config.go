@@ -1,8 +1,10 @@
 package config
 
 import (
+	"errors"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/joho/godotenv"
@@ -10,11 +12,20 @@ import (
 
 // Config holds all the configuration for the application.
 type Config struct {
-	Env          string `yaml:"env" env:"ENV" env-default:"production"`
-	GRPCServer   `yaml:"grpc_server"`
-	URLShortener `yaml:"url_shortener"`
-	Database     `yaml:"database"`
-	Payment      `yaml:"payment"`
+	Env            string `yaml:"env" env:"ENV" env-default:"production"`
+	GRPCServer     `yaml:"grpc_server"`
+	HTTPServer     `yaml:"http_server"`
+	URLShortener   `yaml:"url_shortener"`
+	Database       `yaml:"database"`
+	Payment        `yaml:"payment"`
+	LinkProtection `yaml:"link_protection"`
+	Trash          `yaml:"trash"`
+	GeoIP          `yaml:"geoip"`
+	AliasPolicy    `yaml:"alias_policy"`
+	URLPolicy      `yaml:"url_policy"`
+	Reputation     `yaml:"reputation"`
+	HealthCheck    `yaml:"health_check"`
+	Metadata       `yaml:"metadata"`
 }
 
 // GRPCServer holds gRPC server specific configuration.
@@ -23,10 +34,23 @@ type GRPCServer struct {
 	WebPort int `yaml:"web_port" env:"GRPC_WEB_PORT" env-default:"50052"`
 }
 
+// HTTPServer holds HTTP server specific configuration.
+type HTTPServer struct {
+	// Proxies (IPs or CIDRs) allowed to pass the client address in X-Forwarded-For.
+	// Requests from other addresses use the connection address.
+	TrustedProxies []string `yaml:"trusted_proxies" env:"HTTP_TRUSTED_PROXIES" env-separator:","`
+}
+
 // URLShortener holds service-specific configuration.
 type URLShortener struct {
 	AliasLength int    `yaml:"alias_length" env:"ALIAS_LENGTH" env-default:"4"`
 	BaseURL     string `yaml:"base_url" env:"BASE_URL" env-default:"http://localhost:8080"`
+	// Alias generation: strategy is random, sequential, hashids or words.
+	// The length grows up to AliasMaxLength when collisions become frequent.
+	AliasStrategy  string `yaml:"alias_strategy" env:"ALIAS_STRATEGY" env-default:"random"`
+	AliasMaxLength int    `yaml:"alias_max_length" env:"ALIAS_MAX_LENGTH" env-default:"12"`
+	AliasAlphabet  string `yaml:"alias_alphabet" env:"ALIAS_ALPHABET"`
+	AliasSalt      string `yaml:"alias_salt" env:"ALIAS_SALT"`
 }
 
 // Database holds database specific configuration.
@@ -54,6 +78,80 @@ type Payment struct {
 	TestMode  bool   `yaml:"test_mode" env:"YOOKASSA_TEST_MODE" env-default:"true"`
 }
 
+// DefaultUnlockSecret is the placeholder unlock secret; it is accepted only in the local environment.
+const DefaultUnlockSecret = "change-me-link-unlock-secret"
+
+// LinkProtection holds configuration for password-protected links.
+type LinkProtection struct {
+	UnlockSecret   string `yaml:"unlock_secret" env:"LINK_UNLOCK_SECRET" env-default:"change-me-link-unlock-secret"`
+	UnlockTTL      string `yaml:"unlock_ttl" env:"LINK_UNLOCK_TTL" env-default:"30m"`
+	MaxAttempts    int    `yaml:"max_attempts" env:"LINK_UNLOCK_MAX_ATTEMPTS" env-default:"5"`
+	AttemptsWindow string `yaml:"attempts_window" env:"LINK_UNLOCK_ATTEMPTS_WINDOW" env-default:"15m"`
+}
+
+// Trash holds configuration for deleted links retention.
+type Trash struct {
+	RetentionDays int    `yaml:"retention_days" env:"TRASH_RETENTION_DAYS" env-default:"30"`
+	PurgeInterval string `yaml:"purge_interval" env:"TRASH_PURGE_INTERVAL" env-default:"1h"`
+}
+
+// GeoIP holds configuration for the offline MaxMind-format GeoIP database.
+type GeoIP struct {
+	DatabasePath   string `yaml:"database_path" env:"GEOIP_DATABASE_PATH" env-default:"assets/GeoLite2-City.mmdb"`
+	ReloadInterval string `yaml:"reload_interval" env:"GEOIP_RELOAD_INTERVAL" env-default:"1m"`
+}
+
+// AliasPolicy holds restrictions for custom aliases.
+type AliasPolicy struct {
+	MinLength     int      `yaml:"min_length" env:"ALIAS_POLICY_MIN_LENGTH" env-default:"3"`
+	MaxLength     int      `yaml:"max_length" env:"ALIAS_POLICY_MAX_LENGTH" env-default:"20"`
+	ProfanityFile string   `yaml:"profanity_file" env:"ALIAS_POLICY_PROFANITY_FILE" env-default:"assets/profanity.txt"`
+	Reserved      []string `yaml:"reserved" env:"ALIAS_POLICY_RESERVED" env-separator:","`
+}
+
+// URLPolicy holds destination URL restrictions.
+type URLPolicy struct {
+	BlockPrivateNetworks bool     `yaml:"block_private_networks" env:"URL_POLICY_BLOCK_PRIVATE_NETWORKS" env-default:"true"`
+	BlockShorteners      bool     `yaml:"block_shorteners" env:"URL_POLICY_BLOCK_SHORTENERS" env-default:"true"`
+	ShortenerHosts       []string `yaml:"shortener_hosts" env:"URL_POLICY_SHORTENER_HOSTS" env-separator:","`
+}
+
+// Reputation holds threat lists for destination screening and the periodic rescan of existing links.
+type Reputation struct {
+	DomainsFile      string `yaml:"domains_file" env:"REPUTATION_DOMAINS_FILE" env-default:"assets/threats/domains.txt"`
+	URLsFile         string `yaml:"urls_file" env:"REPUTATION_URLS_FILE" env-default:"assets/threats/urls.txt"`
+	HashPrefixesFile string `yaml:"hash_prefixes_file" env:"REPUTATION_HASH_PREFIXES_FILE" env-default:"assets/threats/hash_prefixes.txt"`
+	ScanInterval     string `yaml:"scan_interval" env:"REPUTATION_SCAN_INTERVAL" env-default:"6h"`
+	ScanBatchSize    int    `yaml:"scan_batch_size" env:"REPUTATION_SCAN_BATCH_SIZE" env-default:"500"`
+}
+
+// HealthCheck holds the background check of link destinations for broken links.
+type HealthCheck struct {
+	Enabled            bool   `yaml:"enabled" env:"HEALTH_CHECK_ENABLED" env-default:"true"`
+	Interval           string `yaml:"interval" env:"HEALTH_CHECK_INTERVAL" env-default:"1h"`
+	BatchSize          int    `yaml:"batch_size" env:"HEALTH_CHECK_BATCH_SIZE" env-default:"200"`
+	Workers            int    `yaml:"workers" env:"HEALTH_CHECK_WORKERS" env-default:"8"`
+	PerHostConcurrency int    `yaml:"per_host_concurrency" env:"HEALTH_CHECK_PER_HOST_CONCURRENCY" env-default:"2"`
+	Timeout            string `yaml:"timeout" env:"HEALTH_CHECK_TIMEOUT" env-default:"10s"`
+	FailureThreshold   int    `yaml:"failure_threshold" env:"HEALTH_CHECK_FAILURE_THRESHOLD" env-default:"3"`
+	BackoffBase        string `yaml:"backoff_base" env:"HEALTH_CHECK_BACKOFF_BASE" env-default:"1m"`
+	BackoffMax         string `yaml:"backoff_max" env:"HEALTH_CHECK_BACKOFF_MAX" env-default:"30m"`
+	UserAgent          string `yaml:"user_agent" env:"HEALTH_CHECK_USER_AGENT" env-default:"GURLS-LinkChecker/1.0"`
+	WebhookURL         string `yaml:"webhook_url" env:"HEALTH_CHECK_WEBHOOK_URL"`
+}
+
+// Metadata holds background fetching of destination page titles, descriptions and OpenGraph tags.
+type Metadata struct {
+	Enabled        bool   `yaml:"enabled" env:"METADATA_ENABLED" env-default:"true"`
+	Workers        int    `yaml:"workers" env:"METADATA_WORKERS" env-default:"4"`
+	QueueSize      int    `yaml:"queue_size" env:"METADATA_QUEUE_SIZE" env-default:"1000"`
+	Timeout        string `yaml:"timeout" env:"METADATA_TIMEOUT" env-default:"10s"`
+	MaxBytes       int64  `yaml:"max_bytes" env:"METADATA_MAX_BYTES" env-default:"524288"`
+	SweepInterval  string `yaml:"sweep_interval" env:"METADATA_SWEEP_INTERVAL" env-default:"10m"`
+	SweepBatchSize int    `yaml:"sweep_batch_size" env:"METADATA_SWEEP_BATCH_SIZE" env-default:"100"`
+	UserAgent      string `yaml:"user_agent" env:"METADATA_USER_AGENT" env-default:"Mozilla/5.0 (compatible; GURLS-Bot/1.0)"`
+}
+
 // MustLoad loads the application configuration.
 func MustLoad() *Config {
 	// Try to load .env file (ignore error in production)
@@ -82,5 +180,35 @@ func MustLoad() *Config {
 		}
 	}
 
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("invalid config: %s", err)
+	}
+
 	return &cfg
 }
+
+// Validate checks settings the service must not start without.
+func (c *Config) Validate() error {
+	if c.Env != "local" && !isSecretSet(c.LinkProtection.UnlockSecret) {
+		return errors.New("link_protection.unlock_secret (LINK_UNLOCK_SECRET) must be set outside the local environment")
+	}
+	if isPlaceholder(c.URLShortener.AliasSalt) ||
+		(c.Env != "local" && c.URLShortener.AliasStrategy == "hashids" && !isSecretSet(c.URLShortener.AliasSalt)) {
+		return errors.New("url_shortener.alias_salt (ALIAS_SALT) must be set for the hashids strategy outside the local environment")
+	}
+	return nil
+}
+
+// isSecretSet reports whether a secret holds a real value: not empty, not the
+// bundled default and not an unexpanded ${VAR} placeholder.
+func isSecretSet(value string) bool {
+	return value != "" && value != DefaultUnlockSecret && !isPlaceholder(value)
+}
+
+// isPlaceholder reports whether value looks like a ${VAR} reference. YAML values
+// are not expanded, so such a value means the environment variable was not set
+// and the public string from the config file would be used as is.
+func isPlaceholder(value string) bool {
+	value = strings.TrimSpace(value)
+	return strings.HasPrefix(value, "${") && strings.HasSuffix(value, "}")
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate_UnlockSecret(t *testing.T) {
+	cfg := Config{Env: "production"}
+	assert.Error(t, cfg.Validate(), "empty secret")
+
+	cfg.LinkProtection.UnlockSecret = DefaultUnlockSecret
+	assert.Error(t, cfg.Validate(), "placeholder secret")
+
+	cfg.LinkProtection.UnlockSecret = "a-real-secret"
+	assert.NoError(t, cfg.Validate())
+
+	local := Config{Env: "local", LinkProtection: LinkProtection{UnlockSecret: DefaultUnlockSecret}}
+	assert.NoError(t, local.Validate())
+}
+
+func TestValidate_RejectsUnexpandedPlaceholders(t *testing.T) {
+	cfg := Config{Env: "production", LinkProtection: LinkProtection{UnlockSecret: "${LINK_UNLOCK_SECRET}"}}
+	assert.Error(t, cfg.Validate(), "unlock secret placeholder")
+
+	cfg.LinkProtection.UnlockSecret = "a-real-secret"
+	cfg.URLShortener = URLShortener{AliasStrategy: "hashids", AliasSalt: "${ALIAS_SALT}"}
+	assert.Error(t, cfg.Validate(), "alias salt placeholder")
+
+	cfg.URLShortener.AliasSalt = ""
+	assert.Error(t, cfg.Validate(), "hashids without salt")
+
+	cfg.URLShortener.AliasSalt = "a-real-salt"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.URLShortener = URLShortener{AliasStrategy: "random"}
+	assert.NoError(t, cfg.Validate(), "salt is only used by hashids")
+}
+
+func TestProductionConfig_RequiresSecretsFromEnvironment(t *testing.T) {
+	for _, name := range []string{"LINK_UNLOCK_SECRET", "ALIAS_SALT", "ALIAS_STRATEGY"} {
+		t.Setenv(name, "")
+		require.NoError(t, os.Unsetenv(name))
+	}
+	t.Setenv("DATABASE_PASSWORD", "secret")
+
+	var cfg Config
+	require.NoError(t, cleanenv.ReadConfig("../../config/production.yml", &cfg))
+	assert.Error(t, cfg.Validate(), "unset LINK_UNLOCK_SECRET must not start the service")
+
+	t.Setenv("LINK_UNLOCK_SECRET", "a-real-secret")
+	cfg = Config{}
+	require.NoError(t, cleanenv.ReadConfig("../../config/production.yml", &cfg))
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, "a-real-secret", cfg.LinkProtection.UnlockSecret)
+}
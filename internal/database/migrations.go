@@ -16,11 +16,19 @@ func AutoMigrate(db *gorm.DB, log *zap.Logger) error {
 	models := []interface{}{
 		&domain.SubscriptionType{}, // Сначала справочники
 		&domain.User{},             // Затем пользователи
-		&domain.Link{},             // Ссылки (зависят от пользователей)
+		&domain.Tag{},              // Теги (зависят от пользователей)
+		&domain.CustomDomain{},     // Собственные домены (зависят от пользователей)
+		&domain.Link{},             // Ссылки (зависят от пользователей, связь link_tags - от тегов)
 		&domain.Click{},            // Клики (зависят от ссылок)
+		&domain.LinkRevision{},     // История изменений ссылок (зависит от ссылок)
+		&domain.RedirectRule{},     // Правила редиректа (зависят от ссылок)
+		&domain.LinkVariant{},      // Варианты A/B теста (зависят от ссылок)
+		&domain.LinkAppeal{},       // Апелляции отключенных ссылок (зависят от ссылок)
+		&domain.LinkHealth{},       // Проверки доступности адресов назначения (зависят от ссылок)
 		&domain.UserStats{},        // Статистика (зависит от пользователей)
 		&domain.Session{},          // Сессии (зависят от пользователей)
 		&domain.RefreshToken{},     // JWT токены (зависят от пользователей)
+		&domain.BlockedAlias{},     // Черный список алиасов
 	}
 
 	log.Info("migrating database models", zap.Int("total_models", len(models)))
@@ -42,6 +50,23 @@ func AutoMigrate(db *gorm.DB, log *zap.Logger) error {
 		log.Info("model migrated successfully", zap.String("model", modelName))
 	}
 
+	// Алиас уникален в пределах домена: глобальная уникальность алиаса из ранних версий схемы снимается
+	for _, statement := range []string{
+		"ALTER TABLE links DROP CONSTRAINT IF EXISTS links_alias_key",
+		"DROP INDEX IF EXISTS idx_links_alias",
+	} {
+		if err := db.Exec(statement).Error; err != nil {
+			log.Error("failed to drop global alias uniqueness", zap.String("statement", statement), zap.Error(err))
+			return fmt.Errorf("failed to drop global alias uniqueness: %w", err)
+		}
+	}
+
+	// Последовательность для стратегий генерации алиасов sequential и hashids
+	if err := db.Exec("CREATE SEQUENCE IF NOT EXISTS link_alias_seq").Error; err != nil {
+		log.Error("failed to create link alias sequence", zap.Error(err))
+		return fmt.Errorf("failed to create link alias sequence: %w", err)
+	}
+
 	log.Info("database auto-migration completed successfully", zap.Int("migrated_models", len(models)))
 	return nil
 }
@@ -76,6 +101,7 @@ func SeedData(db *gorm.DB, log *zap.Logger) error {
 			APIAccess:               false,
 			CustomDomains:           false,
 			PrioritySupport:         false,
+			InterstitialRedirects:   false,
 			IsActive:                true,
 		},
 		{
@@ -92,6 +118,7 @@ func SeedData(db *gorm.DB, log *zap.Logger) error {
 			APIAccess:               false,
 			CustomDomains:           false,
 			PrioritySupport:         false,
+			InterstitialRedirects:   true,
 			IsActive:                true,
 		},
 		{
@@ -108,6 +135,7 @@ func SeedData(db *gorm.DB, log *zap.Logger) error {
 			APIAccess:               true,
 			CustomDomains:           true,
 			PrioritySupport:         true,
+			InterstitialRedirects:   true,
 			IsActive:                true,
 		},
 	}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+const (
+	// BlockedAliasMatchExact запрещает алиас, совпадающий с шаблоном без учета регистра
+	BlockedAliasMatchExact = "exact"
+	// BlockedAliasMatchContains запрещает любой алиас, содержащий шаблон (например, название бренда)
+	BlockedAliasMatchContains = "contains"
+)
+
+// BlockedAlias запись черного списка алиасов. Список ведут администраторы сервиса напрямую в БД
+// (API управления появится вместе с ролями администраторов); изменения действуют сразу,
+// уже созданные ссылки не затрагиваются.
+type BlockedAlias struct {
+	ID        int64     `gorm:"primaryKey;column:id" json:"id"`
+	Pattern   string    `gorm:"column:pattern;size:20;not null;uniqueIndex" json:"pattern"`         // в нижнем регистре
+	MatchType string    `gorm:"column:match_type;size:16;not null;default:exact" json:"match_type"` // exact или contains
+	Reason    *string   `gorm:"column:reason;size:200" json:"reason,omitempty"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
@@ -5,6 +5,18 @@ import (
 	"time"
 )
 
+const (
+	// ClickSourceQR источник клика, перешедшего по QR коду ссылки
+	ClickSourceQR = "qr"
+	// ClickSourceDirect источник остальных кликов в статистике
+	ClickSourceDirect = "direct"
+
+	// QRMarkerParam и QRMarkerValue отмечают адрес короткой ссылки в QR коде: /{alias}?qr=1.
+	// Метка относит клик к сканированию QR кода и не передается в адрес назначения.
+	QRMarkerParam = "qr"
+	QRMarkerValue = "1"
+)
+
 // Click представляет клик по сокращенной ссылке
 type Click struct {
 	ID         int64     `gorm:"primaryKey;column:id" json:"id"`
@@ -19,6 +31,10 @@ type Click struct {
 	OS         *string   `gorm:"column:os;size:50" json:"os,omitempty"`
 	ClickedAt  time.Time `gorm:"column:clicked_at;autoCreateTime;index" json:"clicked_at"`
 	IsUnique   bool      `gorm:"column:is_unique;not null;default:true" json:"is_unique"` // уникальный клик от IP за день
+	UTM        UTMParams `gorm:"embedded;embeddedPrefix:utm_" json:"utm"`                 // метки из адреса перехода или referer
+	RuleID     *int64    `gorm:"column:rule_id" json:"rule_id,omitempty"`                 // сработавшее правило редиректа
+	VariantID  *int64    `gorm:"column:variant_id" json:"variant_id,omitempty"`           // вариант A/B теста
+	Source     *string   `gorm:"column:source;size:10" json:"source,omitempty"`           // qr - переход по QR коду
 
 	// Relationships
 	Link *Link `gorm:"foreignKey:LinkID" json:"link,omitempty"`
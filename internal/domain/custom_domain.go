@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"golang.org/x/net/idna"
+)
+
+const (
+	// DomainVerificationPrefix префикс имени TXT записи, в которой владелец домена размещает токен
+	DomainVerificationPrefix = "_gurls-verification."
+	// DomainVerificationValuePrefix префикс значения TXT записи перед токеном
+	DomainVerificationValuePrefix = "gurls-verification="
+	// MaxCustomDomainsPerUser максимальное количество доменов одного пользователя
+	MaxCustomDomainsPerUser = 20
+)
+
+var ErrInvalidHostname = errors.New("domain must be a fully qualified host name such as go.example.com, without scheme, port or path")
+
+// CustomDomain собственный домен пользователя для коротких ссылок.
+// Домен используется для ссылок и редиректов только после подтверждения владения через DNS TXT запись.
+// Неподтвержденный домен могут добавить несколько пользователей, подтвердить - только один.
+type CustomDomain struct {
+	ID                int64      `gorm:"primaryKey;column:id" json:"id"`
+	UserID            int64      `gorm:"column:user_id;not null;uniqueIndex:idx_custom_domains_user_hostname" json:"user_id"`
+	Hostname          string     `gorm:"column:hostname;size:253;not null;uniqueIndex:idx_custom_domains_user_hostname;uniqueIndex:idx_custom_domains_verified_hostname,where:verified_at IS NOT NULL" json:"hostname"` // в ASCII виде (punycode), нижний регистр
+	VerificationToken string     `gorm:"column:verification_token;size:64;not null" json:"-"`
+	VerifiedAt        *time.Time `gorm:"column:verified_at" json:"verified_at,omitempty"`
+	CreatedAt         time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TableName возвращает название таблицы для GORM
+func (CustomDomain) TableName() string {
+	return "custom_domains"
+}
+
+// IsVerified проверяет, подтверждено ли владение доменом
+func (d *CustomDomain) IsVerified() bool {
+	return d.VerifiedAt != nil
+}
+
+// VerificationRecordName возвращает имя TXT записи для подтверждения владения доменом
+func (d *CustomDomain) VerificationRecordName() string {
+	return DomainVerificationPrefix + d.Hostname
+}
+
+// VerificationRecordValue возвращает значение TXT записи для подтверждения владения доменом
+func (d *CustomDomain) VerificationRecordValue() string {
+	return DomainVerificationValuePrefix + d.VerificationToken
+}
+
+// NormalizeHostname приводит имя домена к ASCII виду в нижнем регистре без завершающей точки.
+// Интернационализированные имена преобразуются в punycode; IP адреса, порты и имена
+// без точки не допускаются.
+func NormalizeHostname(hostname string) (string, error) {
+	hostname = strings.TrimSuffix(strings.TrimSpace(hostname), ".")
+	if hostname == "" || net.ParseIP(hostname) != nil || !strings.Contains(hostname, ".") {
+		return "", ErrInvalidHostname
+	}
+
+	ascii, err := idna.Lookup.ToASCII(hostname)
+	if err != nil || len(ascii) > 253 {
+		return "", ErrInvalidHostname
+	}
+	for _, label := range strings.Split(ascii, ".") {
+		if !isHostnameLabel(label) {
+			return "", ErrInvalidHostname
+		}
+	}
+	return ascii, nil
+}
+
+// isHostnameLabel проверяет метку домена: 1-63 символа из букв, цифр и дефиса, не на краях
+func isHostnameLabel(label string) bool {
+	if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+	for _, c := range label {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeHostname(t *testing.T) {
+	valid := map[string]string{
+		"Go.Example.COM":  "go.example.com",
+		"go.example.com.": "go.example.com",
+		"ссылки.рф":       "xn--h1adcxa4d.xn--p1ai",
+	}
+	for input, want := range valid {
+		got, err := NormalizeHostname(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got)
+	}
+
+	for _, input := range []string{"", "localhost", "127.0.0.1", "example.com:8080", "https://example.com", "-bad.example.com", "a_b.example.com"} {
+		_, err := NormalizeHostname(input)
+		assert.ErrorIs(t, err, ErrInvalidHostname, input)
+	}
+}
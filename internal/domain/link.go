@@ -2,24 +2,44 @@ package domain
 
 import "time"
 
+// MaxAliasLength максимальная длина алиаса (размер колонки links.alias)
+const MaxAliasLength = 20
+
 type Link struct {
 	ID              int64      `gorm:"primaryKey;column:id" json:"id"`
 	UserID          int64      `gorm:"column:user_id;not null;index" json:"user_id"`
+	DomainID        *int64     `gorm:"column:domain_id;index:idx_links_domain_alias,unique,priority:1,where:domain_id IS NOT NULL" json:"domain_id,omitempty"` // NULL - основной домен сервиса
 	OriginalURL     string     `gorm:"column:original_url;type:text;not null" json:"original_url"`
-	Alias           string     `gorm:"column:alias;size:20;uniqueIndex;not null" json:"alias"`
+	Alias           string     `gorm:"column:alias;size:20;not null;index:idx_links_default_alias,unique,where:domain_id IS NULL;index:idx_links_domain_alias,unique,priority:2,where:domain_id IS NOT NULL" json:"alias"` // уникален в пределах домена
 	Title           *string    `gorm:"column:title;size:200" json:"title,omitempty"`
 	Description     *string    `gorm:"column:description;size:500" json:"description,omitempty"`
+	StartsAt        *time.Time `gorm:"column:starts_at" json:"starts_at,omitempty"` // до этого момента ссылка недоступна
 	ExpiresAt       *time.Time `gorm:"column:expires_at" json:"expires_at,omitempty"`
+	Timezone        string     `gorm:"column:timezone;size:64;not null;default:''" json:"timezone,omitempty"` // IANA пояс для правил по времени, пусто - UTC
 	MaxClicks       *int       `gorm:"column:max_clicks" json:"max_clicks,omitempty"`
+	FallbackURL     *string    `gorm:"column:fallback_url;type:text" json:"fallback_url,omitempty"` // куда вести после истечения или исчерпания лимита
+	ForwardQuery    bool       `gorm:"column:forward_query;default:false" json:"forward_query"`                   // передавать query string запроса в адрес назначения
+	QueryConflict   string     `gorm:"column:query_conflict;size:16;default:keep" json:"query_conflict,omitempty"` // keep, override или append
+	ForwardPath     bool       `gorm:"column:forward_path;default:false" json:"forward_path"`                     // /{alias}/extra -> destination/extra
+	RedirectMode    string     `gorm:"column:redirect_mode;size:16;not null;default:'302'" json:"redirect_mode"`  // 301, 302, 307, 308, meta_refresh, interstitial
+	TrackingPixels  string     `gorm:"column:tracking_pixels;type:text;not null;default:''" json:"-"`             // адреса пикселей промежуточной страницы через перевод строки
+	UTM             UTMParams  `gorm:"embedded;embeddedPrefix:utm_" json:"utm"`                                   // метки, добавляемые к адресу назначения при редиректе
 	ClickCount      int64      `gorm:"column:click_count;default:0" json:"click_count"`
 	PasswordHash    *string    `gorm:"column:password_hash;size:60" json:"-"` // скрываем пароль в JSON
 	CreatedAt       time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
 	UpdatedAt       time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
 	IsActive        bool       `gorm:"column:is_active;default:true" json:"is_active"`
+	DeletedAt       *time.Time `gorm:"column:deleted_at;index" json:"deleted_at,omitempty"` // NULL = не в корзине
+	FlaggedAt       *time.Time `gorm:"column:flagged_at" json:"flagged_at,omitempty"` // адрес назначения найден в списках угроз, ссылка отключена
+	FlagReason      *string    `gorm:"column:flag_reason;size:32" json:"flag_reason,omitempty"` // категория угрозы: phishing, malware, ...
+	Metadata        LinkMetadata `gorm:"embedded;embeddedPrefix:meta_" json:"metadata"`         // заголовок, описание и OpenGraph страницы назначения
+	SocialPreview   SocialPreview `gorm:"embedded;embeddedPrefix:og_" json:"social_preview"`    // карточка для мессенджеров и соцсетей, заданная владельцем
 
 	// Relationships
-	User   *User   `gorm:"foreignKey:UserID" json:"user,omitempty"`
-	Clicks []Click `gorm:"foreignKey:LinkID" json:"clicks,omitempty"`
+	User   *User         `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Domain *CustomDomain `gorm:"foreignKey:DomainID" json:"domain,omitempty"`
+	Clicks []Click       `gorm:"foreignKey:LinkID" json:"clicks,omitempty"`
+	Tags   []Tag         `gorm:"many2many:link_tags" json:"tags,omitempty"`
 
 	// Backward compatibility - это поле больше не сохраняется в БД,
 	// но может вычисляться динамически для совместимости
@@ -30,3 +50,32 @@ type Link struct {
 func (Link) TableName() string {
 	return "links"
 }
+
+// IsNotStarted проверяет, что время запуска ссылки еще не наступило
+func (l *Link) IsNotStarted() bool {
+	return l.StartsAt != nil && time.Now().Before(*l.StartsAt)
+}
+
+// Location возвращает часовой пояс ссылки; неизвестный пояс считается UTC
+func (l *Link) Location() *time.Location {
+	location, err := LoadTimezone(l.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// IsFlagged проверяет, что ссылка отключена проверкой репутации адреса назначения
+func (l *Link) IsFlagged() bool {
+	return l.FlaggedAt != nil
+}
+
+// IsExpired проверяет, истек ли срок действия ссылки
+func (l *Link) IsExpired() bool {
+	return l.ExpiresAt != nil && time.Now().After(*l.ExpiresAt)
+}
+
+// IsExhausted проверяет, исчерпан ли лимит переходов по ссылке
+func (l *Link) IsExhausted() bool {
+	return l.MaxClicks != nil && l.ClickCount >= int64(*l.MaxClicks)
+}
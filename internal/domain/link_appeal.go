@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// Апелляция решается повторной проверкой адресов ссылки по спискам угроз: при подаче и затем
+// при каждом проходе периодической проверки. Отдельного отказа нет - пока адрес в списках,
+// апелляция остается ожидающей.
+const (
+	// LinkAppealPending адрес назначения все еще в списках угроз
+	LinkAppealPending = "pending"
+	// LinkAppealApproved ссылка снова включена: адреса назначения больше нет в списках угроз
+	// (администратор убрал ложное срабатывание или владелец сменил адрес)
+	LinkAppealApproved = "approved"
+)
+
+// MaxLinkAppealMessageLength максимальная длина пояснения владельца к апелляции
+const MaxLinkAppealMessageLength = 1000
+
+// LinkAppeal апелляция владельца ссылки, отключенной проверкой репутации адреса назначения
+type LinkAppeal struct {
+	ID         int64      `gorm:"primaryKey;column:id" json:"id"`
+	LinkID     int64      `gorm:"column:link_id;not null;index" json:"link_id"`
+	UserID     int64      `gorm:"column:user_id;not null" json:"user_id"`
+	Threat     string     `gorm:"column:threat;size:32;not null" json:"threat"` // категория угрозы на момент апелляции
+	Message    string     `gorm:"column:message;type:text;not null;default:''" json:"message"`
+	Status     string     `gorm:"column:status;size:16;not null;default:pending;index" json:"status"` // pending или approved
+	CreatedAt  time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	ResolvedAt *time.Time `gorm:"column:resolved_at" json:"resolved_at,omitempty"`
+
+	// Relationships
+	Link *Link `gorm:"foreignKey:LinkID" json:"link,omitempty"`
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TableName возвращает название таблицы для GORM
+func (LinkAppeal) TableName() string {
+	return "link_appeals"
+}
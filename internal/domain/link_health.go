@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// MaxLinkHealthErrorLength максимальная длина сохраняемой ошибки проверки
+const MaxLinkHealthErrorLength = 500
+
+// LinkHealth результат последней проверки доступности адреса назначения ссылки
+type LinkHealth struct {
+	LinkID              int64      `gorm:"primaryKey;autoIncrement:false;column:link_id" json:"link_id"`
+	StatusCode          int        `gorm:"column:status_code;not null;default:0" json:"status_code"` // 0 - ответ не получен
+	LatencyMs           int64      `gorm:"column:latency_ms;not null;default:0" json:"latency_ms"`
+	FinalURL            string     `gorm:"column:final_url;type:text;not null;default:''" json:"final_url"` // адрес после редиректов
+	Error               string     `gorm:"column:error;size:500;not null;default:''" json:"error,omitempty"`
+	ConsecutiveFailures int        `gorm:"column:consecutive_failures;not null;default:0" json:"consecutive_failures"`
+	CheckedAt           time.Time  `gorm:"column:checked_at;not null" json:"checked_at"`
+	BrokenSince         *time.Time `gorm:"column:broken_since;index" json:"broken_since,omitempty"` // NULL - ссылка работает
+
+	// Relationships
+	Link *Link `gorm:"foreignKey:LinkID" json:"link,omitempty"`
+}
+
+// TableName возвращает название таблицы для GORM
+func (LinkHealth) TableName() string {
+	return "link_health"
+}
+
+// IsBroken проверяет, что адрес назначения не отвечает несколько проверок подряд
+func (h *LinkHealth) IsBroken() bool {
+	return h.BrokenSince != nil
+}
@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+// Размеры колонок метаданных страницы назначения
+const (
+	MaxMetadataTitleLength       = 200
+	MaxMetadataDescriptionLength = 500
+	MaxMetadataSiteNameLength    = 100
+	MaxMetadataTwitterCardLength = 32
+	MaxMetadataErrorLength       = 500
+)
+
+// LinkMetadata метаданные страницы назначения: заголовок, описание, OpenGraph и Twitter card, иконка.
+// Встраивается в ссылку с префиксом колонок meta_ и заполняется фоновой загрузкой страницы.
+type LinkMetadata struct {
+	Title       string     `gorm:"column:title;size:200;not null;default:''" json:"title,omitempty"`
+	Description string     `gorm:"column:description;size:500;not null;default:''" json:"description,omitempty"`
+	SiteName    string     `gorm:"column:site_name;size:100;not null;default:''" json:"site_name,omitempty"`
+	ImageURL    string     `gorm:"column:image_url;type:text;not null;default:''" json:"image_url,omitempty"`
+	FaviconURL  string     `gorm:"column:favicon_url;type:text;not null;default:''" json:"favicon_url,omitempty"`
+	TwitterCard string     `gorm:"column:twitter_card;size:32;not null;default:''" json:"twitter_card,omitempty"`
+	FetchedAt   *time.Time `gorm:"column:fetched_at" json:"fetched_at,omitempty"`                    // NULL - страница еще не загружалась
+	Error       string     `gorm:"column:error;size:500;not null;default:''" json:"error,omitempty"` // ошибка последней загрузки
+}
+
+// NeedsMetadata проверяет, что у ссылки не заполнен заголовок или описание и страница еще не загружалась
+func (l *Link) NeedsMetadata() bool {
+	if l.Metadata.FetchedAt != nil {
+		return false
+	}
+	return l.Title == nil || *l.Title == "" || l.Description == nil || *l.Description == ""
+}
+
+// ResetMetadata забывает метаданные прежней страницы назначения после смены адреса. Заголовок и
+// описание, заполненные из прежней страницы, тоже очищаются, чтобы их заполнила загрузка новой;
+// заданные пользователем остаются.
+func (l *Link) ResetMetadata() {
+	if l.Title != nil && *l.Title == l.Metadata.Title {
+		l.Title = nil
+	}
+	if l.Description != nil && *l.Description == l.Metadata.Description {
+		l.Description = nil
+	}
+	l.Metadata = LinkMetadata{}
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLink_ResetMetadata(t *testing.T) {
+	fetchedAt := time.Now()
+	fromPage := func(title, description string) Link {
+		return Link{
+			Title:       &title,
+			Description: &description,
+			Metadata: LinkMetadata{
+				Title:       "Old page",
+				Description: "About the old page",
+				ImageURL:    "https://old.example/og.png",
+				FetchedAt:   &fetchedAt,
+			},
+		}
+	}
+
+	link := fromPage("Old page", "About the old page")
+	link.ResetMetadata()
+	assert.Nil(t, link.Title, "title filled from the old page is cleared")
+	assert.Nil(t, link.Description)
+	assert.Equal(t, LinkMetadata{}, link.Metadata)
+	assert.True(t, link.NeedsMetadata())
+
+	link = fromPage("My campaign", "About the old page")
+	link.ResetMetadata()
+	assert.Equal(t, "My campaign", *link.Title, "title set by the user is kept")
+	assert.Nil(t, link.Description)
+	assert.Nil(t, link.Metadata.FetchedAt)
+}
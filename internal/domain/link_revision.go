@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// LinkRevision представляет запись истории изменения адреса назначения ссылки
+type LinkRevision struct {
+	ID          int64     `gorm:"primaryKey;column:id" json:"id"`
+	LinkID      int64     `gorm:"column:link_id;not null;index" json:"link_id"`
+	OriginalURL string    `gorm:"column:original_url;type:text;not null" json:"original_url"`  // адрес назначения после изменения
+	PreviousURL *string   `gorm:"column:previous_url;type:text" json:"previous_url,omitempty"` // NULL для первой ревизии
+	ChangedBy   int64     `gorm:"column:changed_by;not null" json:"changed_by"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime;index" json:"created_at"`
+
+	// Relationships
+	Link *Link `gorm:"foreignKey:LinkID" json:"link,omitempty"`
+	User *User `gorm:"foreignKey:ChangedBy" json:"user,omitempty"`
+}
+
+// TableName возвращает название таблицы для GORM
+func (LinkRevision) TableName() string {
+	return "link_revisions"
+}
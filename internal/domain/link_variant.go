@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// MaxLinkVariantsPerLink максимальное количество вариантов A/B теста у одной ссылки
+	MaxLinkVariantsPerLink = 10
+
+	// MaxLinkVariantWeight максимальный вес варианта
+	MaxLinkVariantWeight = 1000
+)
+
+var ErrInvalidVariantWeight = errors.New("variant weight must be between 1 and 1000")
+
+// LinkVariant вариант адреса назначения для A/B теста ссылки.
+// Если у ссылки есть варианты, посетитель, которому не подошло ни одно правило редиректа,
+// попадает на один из них с вероятностью, пропорциональной весу; адрес самой ссылки не используется.
+type LinkVariant struct {
+	ID             int64     `gorm:"primaryKey;column:id" json:"id"`
+	LinkID         int64     `gorm:"column:link_id;not null;index:idx_link_variants_link_position" json:"link_id"`
+	Position       int       `gorm:"column:position;not null;index:idx_link_variants_link_position" json:"position"`
+	Name           string    `gorm:"column:name;size:100;not null;default:''" json:"name,omitempty"` // подпись для статистики: "A", "new landing"
+	DestinationURL string    `gorm:"column:destination_url;type:text;not null" json:"destination_url"`
+	Weight         int       `gorm:"column:weight;not null" json:"weight"`
+	CreatedAt      time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	Link *Link `gorm:"foreignKey:LinkID" json:"link,omitempty"`
+}
+
+// TableName возвращает название таблицы для GORM
+func (LinkVariant) TableName() string {
+	return "link_variants"
+}
+
+// TotalVariantWeight возвращает сумму весов вариантов
+func TotalVariantWeight(variants []LinkVariant) int {
+	total := 0
+	for _, variant := range variants {
+		total += variant.Weight
+	}
+	return total
+}
+
+// PickLinkVariant выбирает вариант по числу n из [0, TotalVariantWeight):
+// каждому варианту соответствует отрезок длиной в его вес.
+func PickLinkVariant(variants []LinkVariant, n int) *LinkVariant {
+	for i := range variants {
+		if n < variants[i].Weight {
+			return &variants[i]
+		}
+		n -= variants[i].Weight
+	}
+	return nil
+}
+
+// FindLinkVariant возвращает вариант с указанным ID или nil
+func FindLinkVariant(variants []LinkVariant, id int64) *LinkVariant {
+	for i := range variants {
+		if variants[i].ID == id {
+			return &variants[i]
+		}
+	}
+	return nil
+}
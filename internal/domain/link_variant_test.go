@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPickLinkVariant_WeightDistribution(t *testing.T) {
+	variants := []LinkVariant{
+		{ID: 1, Weight: 70},
+		{ID: 2, Weight: 20},
+		{ID: 3, Weight: 10},
+	}
+	total := TotalVariantWeight(variants)
+	require.Equal(t, 100, total)
+
+	// Каждому варианту достается ровно столько значений n, каков его вес
+	picked := make(map[int64]int)
+	for n := 0; n < total; n++ {
+		variant := PickLinkVariant(variants, n)
+		require.NotNil(t, variant, n)
+		picked[variant.ID]++
+	}
+	assert.Equal(t, map[int64]int{1: 70, 2: 20, 3: 10}, picked)
+
+	// Отрезки идут в порядке вариантов
+	assert.Equal(t, int64(1), PickLinkVariant(variants, 69).ID)
+	assert.Equal(t, int64(2), PickLinkVariant(variants, 70).ID)
+	assert.Equal(t, int64(3), PickLinkVariant(variants, 99).ID)
+	assert.Nil(t, PickLinkVariant(variants, total))
+}
+
+func TestPickLinkVariant_Empty(t *testing.T) {
+	assert.Equal(t, 0, TotalVariantWeight(nil))
+	assert.Nil(t, PickLinkVariant(nil, 0))
+}
+
+func TestFindLinkVariant(t *testing.T) {
+	variants := []LinkVariant{{ID: 5, Name: "A"}, {ID: 9, Name: "B"}}
+
+	found := FindLinkVariant(variants, 9)
+	require.NotNil(t, found)
+	assert.Equal(t, "B", found.Name)
+	assert.Nil(t, FindLinkVariant(variants, 7))
+}
@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// RedirectMode способ, которым посетитель короткой ссылки отправляется на адрес назначения
+type RedirectMode string
+
+const (
+	RedirectMovedPermanently RedirectMode = "301"
+	RedirectFound            RedirectMode = "302" // по умолчанию
+	RedirectTemporary        RedirectMode = "307"
+	RedirectPermanent        RedirectMode = "308"
+	RedirectMetaRefresh      RedirectMode = "meta_refresh" // HTML страница с мгновенным meta refresh
+	RedirectInterstitial     RedirectMode = "interstitial" // промежуточная страница с пикселями аналитики
+
+	DefaultRedirectMode = RedirectFound
+)
+
+const (
+	// MaxTrackingPixelsPerLink максимальное количество пикселей аналитики на промежуточной странице
+	MaxTrackingPixelsPerLink = 5
+
+	// maxTrackingPixelURLLength максимальная длина адреса пикселя
+	maxTrackingPixelURLLength = 2048
+)
+
+var (
+	ErrInvalidRedirectMode  = errors.New("redirect_mode must be 301, 302, 307, 308, meta_refresh or interstitial")
+	ErrInvalidTrackingPixel = errors.New("tracking_pixels must be absolute http(s) URLs (max 5)")
+)
+
+// IsValid проверяет, поддерживается ли способ редиректа; пустое значение означает режим по умолчанию
+func (m RedirectMode) IsValid() bool {
+	switch m {
+	case "", RedirectMovedPermanently, RedirectFound, RedirectTemporary, RedirectPermanent,
+		RedirectMetaRefresh, RedirectInterstitial:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsPage проверяет, отдается ли вместо HTTP редиректа HTML страница
+func (m RedirectMode) IsPage() bool {
+	return m == RedirectMetaRefresh || m == RedirectInterstitial
+}
+
+// RequiresPlanFeature проверяет, нужна ли для способа редиректа функция подписки interstitial_redirects
+func (m RedirectMode) RequiresPlanFeature() bool {
+	return m.IsPage()
+}
+
+// StatusCode возвращает HTTP статус редиректа; для HTML страниц - 302
+func (m RedirectMode) StatusCode() int {
+	switch m {
+	case RedirectMovedPermanently:
+		return http.StatusMovedPermanently
+	case RedirectTemporary:
+		return http.StatusTemporaryRedirect
+	case RedirectPermanent:
+		return http.StatusPermanentRedirect
+	default:
+		return http.StatusFound
+	}
+}
+
+// TemporaryStatusCode возвращает временный аналог статуса: постоянный редирект кэшируется браузером,
+// поэтому не подходит для переходов, которые могут измениться (например, на резервный адрес)
+func (m RedirectMode) TemporaryStatusCode() int {
+	switch m {
+	case RedirectTemporary, RedirectPermanent:
+		return http.StatusTemporaryRedirect
+	default:
+		return http.StatusFound
+	}
+}
+
+// Redirect возвращает способ редиректа ссылки
+func (l *Link) Redirect() RedirectMode {
+	if l.RedirectMode == "" {
+		return DefaultRedirectMode
+	}
+	return RedirectMode(l.RedirectMode)
+}
+
+// TrackingPixelList возвращает адреса пикселей аналитики промежуточной страницы
+func (l *Link) TrackingPixelList() []string {
+	if l.TrackingPixels == "" {
+		return nil
+	}
+	return strings.Split(l.TrackingPixels, "\n")
+}
+
+// NormalizeTrackingPixels проверяет адреса пикселей аналитики и объединяет их для хранения
+func NormalizeTrackingPixels(pixels []string) (string, error) {
+	if len(pixels) > MaxTrackingPixelsPerLink {
+		return "", ErrInvalidTrackingPixel
+	}
+	normalized := make([]string, 0, len(pixels))
+	for _, pixel := range pixels {
+		pixel = strings.TrimSpace(pixel)
+		u, err := url.Parse(pixel)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+			len(pixel) > maxTrackingPixelURLLength || strings.ContainsAny(pixel, "\r\n") {
+			return "", ErrInvalidTrackingPixel
+		}
+		normalized = append(normalized, pixel)
+	}
+	return strings.Join(normalized, "\n"), nil
+}
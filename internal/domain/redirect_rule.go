@@ -0,0 +1,270 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// MaxRedirectRulesPerLink максимальное количество правил редиректа у одной ссылки
+const MaxRedirectRulesPerLink = 50
+
+var (
+	ErrInvalidCountryCode  = errors.New("countries must be ISO 3166-1 alpha-2 codes, e.g. RU or KZ")
+	ErrInvalidDeviceType   = errors.New("device_types must be desktop, mobile, tablet, bot or unknown")
+	ErrInvalidRuleValue    = errors.New("os_families and browsers must be non-empty names of at most 50 characters without commas")
+	ErrEmptyRuleConditions = errors.New("rule must have at least one condition: countries, device_types, os_families, browsers, active period, weekdays or time of day")
+	ErrInvalidWeekday      = errors.New("weekdays must be mon, tue, wed, thu, fri, sat or sun")
+	ErrInvalidTimeOfDay    = errors.New("time_from and time_to must be set together as different HH:MM times")
+	ErrInvalidActivePeriod = errors.New("active_until must be after active_from")
+)
+
+// weekdayCodes коды дней недели в условиях правил, индекс соответствует time.Weekday
+var weekdayCodes = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// DeviceTypes типы устройств, которые определяет парсер User-Agent
+var DeviceTypes = []string{"desktop", "mobile", "tablet", "bot", "unknown"}
+
+// maxRuleValueLength максимальная длина названия ОС или браузера в условии правила
+const maxRuleValueLength = 50
+
+// RedirectRule правило выбора адреса назначения для посетителя.
+// Правила ссылки проверяются по возрастанию Position; срабатывает первое подходящее,
+// если ни одно не подошло - используется адрес назначения самой ссылки.
+// Условия хранятся списками через запятую; правило подходит, если посетитель удовлетворяет
+// всем заданным условиям, а внутри одного условия - любому значению из списка.
+type RedirectRule struct {
+	ID             int64      `gorm:"primaryKey;column:id" json:"id"`
+	LinkID         int64      `gorm:"column:link_id;not null;index:idx_redirect_rules_link_position" json:"link_id"`
+	Position       int        `gorm:"column:position;not null;index:idx_redirect_rules_link_position" json:"position"`
+	Name           string     `gorm:"column:name;size:100;not null;default:''" json:"name,omitempty"` // подпись для статистики: "iOS -> App Store"
+	Countries      string     `gorm:"column:countries;size:255;not null;default:''" json:"countries"` // ISO коды стран: "KZ,BY"
+	DeviceTypes    string     `gorm:"column:device_types;size:100;not null;default:''" json:"device_types"`
+	OSFamilies     string     `gorm:"column:os_families;size:255;not null;default:''" json:"os_families"` // семейства ОС парсера User-Agent: "iOS,Android"
+	Browsers       string     `gorm:"column:browsers;size:255;not null;default:''" json:"browsers"`
+	ActiveFrom     *time.Time `gorm:"column:active_from" json:"active_from,omitempty"` // период действия: например, неделя распродажи
+	ActiveUntil    *time.Time `gorm:"column:active_until" json:"active_until,omitempty"`
+	Weekdays       string     `gorm:"column:weekdays;size:30;not null;default:''" json:"weekdays"`  // дни недели в поясе ссылки: "sat,sun"
+	TimeFrom       string     `gorm:"column:time_from;size:5;not null;default:''" json:"time_from"` // время суток в поясе ссылки "HH:MM";
+	TimeTo         string     `gorm:"column:time_to;size:5;not null;default:''" json:"time_to"`     // окно "18:00"-"09:00" переходит через полночь
+	DestinationURL string     `gorm:"column:destination_url;type:text;not null" json:"destination_url"`
+	CreatedAt      time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	Link *Link `gorm:"foreignKey:LinkID" json:"link,omitempty"`
+}
+
+// TableName возвращает название таблицы для GORM
+func (RedirectRule) TableName() string {
+	return "redirect_rules"
+}
+
+// Visitor сведения о посетителе короткой ссылки, по которым выбирается правило редиректа.
+// Пустая строка означает, что значение неизвестно.
+type Visitor struct {
+	Country    string // ISO код страны в верхнем регистре
+	City       string
+	DeviceType string    // desktop, mobile, tablet, bot, unknown
+	OS         string    // семейство ОС: iOS, Android, Windows, Mac OS X...
+	Browser    string    // семейство браузера: Chrome, Mobile Safari, Firefox...
+	Time       time.Time // момент перехода в часовом поясе ссылки
+}
+
+// CountryList возвращает коды стран правила
+func (r *RedirectRule) CountryList() []string {
+	return splitRuleList(r.Countries)
+}
+
+// DeviceTypeList возвращает типы устройств правила
+func (r *RedirectRule) DeviceTypeList() []string {
+	return splitRuleList(r.DeviceTypes)
+}
+
+// OSFamilyList возвращает семейства ОС правила
+func (r *RedirectRule) OSFamilyList() []string {
+	return splitRuleList(r.OSFamilies)
+}
+
+// BrowserList возвращает браузеры правила
+func (r *RedirectRule) BrowserList() []string {
+	return splitRuleList(r.Browsers)
+}
+
+// WeekdayList возвращает дни недели правила
+func (r *RedirectRule) WeekdayList() []string {
+	return splitRuleList(r.Weekdays)
+}
+
+// HasConditions проверяет, что у правила задано хотя бы одно условие
+func (r *RedirectRule) HasConditions() bool {
+	return r.Countries != "" || r.DeviceTypes != "" || r.OSFamilies != "" || r.Browsers != "" ||
+		r.ActiveFrom != nil || r.ActiveUntil != nil || r.Weekdays != "" || r.TimeFrom != ""
+}
+
+// Matches проверяет, подходит ли правило посетителю
+func (r *RedirectRule) Matches(visitor Visitor) bool {
+	return r.HasConditions() &&
+		matchesRuleList(r.Countries, visitor.Country) &&
+		matchesRuleList(r.DeviceTypes, visitor.DeviceType) &&
+		matchesRuleList(r.OSFamilies, visitor.OS) &&
+		matchesRuleList(r.Browsers, visitor.Browser) &&
+		r.matchesTime(visitor.Time)
+}
+
+// matchesTime проверяет период действия, день недели и время суток правила
+func (r *RedirectRule) matchesTime(t time.Time) bool {
+	if r.ActiveFrom != nil && t.Before(*r.ActiveFrom) {
+		return false
+	}
+	if r.ActiveUntil != nil && !t.Before(*r.ActiveUntil) {
+		return false
+	}
+	if !matchesRuleList(r.Weekdays, weekdayCodes[t.Weekday()]) {
+		return false
+	}
+	if r.TimeFrom == "" {
+		return true
+	}
+
+	from, errFrom := ParseTimeOfDay(r.TimeFrom)
+	to, errTo := ParseTimeOfDay(r.TimeTo)
+	if errFrom != nil || errTo != nil {
+		return false
+	}
+	minute := t.Hour()*60 + t.Minute()
+	if from < to {
+		return minute >= from && minute < to
+	}
+	return minute >= from || minute < to
+}
+
+// MatchRedirectRule возвращает первое подходящее посетителю правило или nil
+func MatchRedirectRule(rules []RedirectRule, visitor Visitor) *RedirectRule {
+	for i := range rules {
+		if rules[i].Matches(visitor) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// NormalizeCountries приводит список стран к виду "KZ,BY": верхний регистр, без повторов и пробелов.
+// Пустой список означает, что условие по стране не задано.
+func NormalizeCountries(countries []string) (string, error) {
+	normalized := make([]string, 0, len(countries))
+	seen := make(map[string]bool, len(countries))
+	for _, country := range countries {
+		code := strings.ToUpper(strings.TrimSpace(country))
+		if len(code) != 2 || code[0] < 'A' || code[0] > 'Z' || code[1] < 'A' || code[1] > 'Z' {
+			return "", ErrInvalidCountryCode
+		}
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		normalized = append(normalized, code)
+	}
+	return strings.Join(normalized, ","), nil
+}
+
+// NormalizeDeviceTypes приводит список типов устройств к виду "mobile,tablet"
+func NormalizeDeviceTypes(deviceTypes []string) (string, error) {
+	return normalizeRuleList(deviceTypes, func(value string) (string, error) {
+		value = strings.ToLower(value)
+		for _, deviceType := range DeviceTypes {
+			if value == deviceType {
+				return value, nil
+			}
+		}
+		return "", ErrInvalidDeviceType
+	})
+}
+
+// NormalizeRuleNames приводит список семейств ОС или браузеров к виду "iOS,Android".
+// Регистр сохраняется, сравнение при редиректе выполняется без учета регистра.
+func NormalizeRuleNames(names []string) (string, error) {
+	return normalizeRuleList(names, func(value string) (string, error) {
+		if value == "" || len(value) > maxRuleValueLength || strings.Contains(value, ",") {
+			return "", ErrInvalidRuleValue
+		}
+		return value, nil
+	})
+}
+
+// NormalizeWeekdays приводит список дней недели к виду "mon,fri" в порядке недели, начиная с понедельника
+func NormalizeWeekdays(weekdays []string) (string, error) {
+	selected := make(map[string]bool, len(weekdays))
+	for _, weekday := range weekdays {
+		code := strings.ToLower(strings.TrimSpace(weekday))
+		if len(code) > 3 {
+			code = code[:3] // monday -> mon
+		}
+		if !slices.Contains(weekdayCodes, code) {
+			return "", ErrInvalidWeekday
+		}
+		selected[code] = true
+	}
+
+	normalized := make([]string, 0, len(selected))
+	for i := range weekdayCodes {
+		code := weekdayCodes[(i+1)%len(weekdayCodes)]
+		if selected[code] {
+			normalized = append(normalized, code)
+		}
+	}
+	return strings.Join(normalized, ","), nil
+}
+
+// ParseTimeOfDay разбирает время суток "HH:MM" и возвращает количество минут от полуночи
+func ParseTimeOfDay(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, ErrInvalidTimeOfDay
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// normalizeRuleList обрезает пробелы, проверяет значения и убирает повторы без учета регистра
+func normalizeRuleList(values []string, normalize func(string) (string, error)) (string, error) {
+	normalized := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, value := range values {
+		value, err := normalize(strings.TrimSpace(value))
+		if err != nil {
+			return "", err
+		}
+		key := strings.ToLower(value)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, value)
+	}
+	return strings.Join(normalized, ","), nil
+}
+
+// splitRuleList разбивает список условия правила
+func splitRuleList(list string) []string {
+	if list == "" {
+		return nil
+	}
+	return strings.Split(list, ",")
+}
+
+// matchesRuleList проверяет значение посетителя по условию; пустое условие подходит всем
+func matchesRuleList(list, value string) bool {
+	if list == "" {
+		return true
+	}
+	if value == "" {
+		return false
+	}
+	for _, item := range splitRuleList(list) {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,232 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeCountries(t *testing.T) {
+	valid := map[string][]string{
+		"":      nil,
+		"KZ,BY": {"kz", " BY "},
+		"RU":    {"RU", "ru", "Ru"},
+		"US,DE": {"us", "de", "US"},
+	}
+	for want, input := range valid {
+		got, err := NormalizeCountries(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got)
+	}
+
+	for _, input := range [][]string{{""}, {"RUS"}, {"R"}, {"R1"}, {"KZ", "Russia"}, {"ру"}} {
+		_, err := NormalizeCountries(input)
+		assert.ErrorIs(t, err, ErrInvalidCountryCode, input)
+	}
+}
+
+func TestRedirectRule_MatchesCountry(t *testing.T) {
+	tests := []struct {
+		name      string
+		countries string
+		country   string
+		want      bool
+	}{
+		{"listed country", "KZ,BY", "BY", true},
+		{"case insensitive", "KZ", "kz", true},
+		{"other country", "KZ,BY", "RU", false},
+		{"unknown country", "KZ", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := RedirectRule{Countries: tt.countries}
+			assert.Equal(t, tt.want, rule.Matches(Visitor{Country: tt.country}))
+		})
+	}
+}
+
+func TestRedirectRule_NoConditionsNeverMatches(t *testing.T) {
+	rule := RedirectRule{DestinationURL: "https://example.com"}
+	assert.False(t, rule.Matches(Visitor{Country: "RU", DeviceType: "mobile"}))
+}
+
+func TestMatchRedirectRule_FirstMatchWins(t *testing.T) {
+	rules := []RedirectRule{
+		{ID: 1, Countries: "KZ"},
+		{ID: 2, Countries: "KZ,BY"},
+		{ID: 3, Countries: "BY"},
+	}
+
+	assert.Equal(t, int64(1), MatchRedirectRule(rules, Visitor{Country: "KZ"}).ID)
+	assert.Equal(t, int64(2), MatchRedirectRule(rules, Visitor{Country: "BY"}).ID)
+	assert.Nil(t, MatchRedirectRule(rules, Visitor{Country: "RU"}))
+	assert.Nil(t, MatchRedirectRule(nil, Visitor{Country: "KZ"}))
+}
+
+func TestRedirectRule_MatchesDeviceAndOS(t *testing.T) {
+	iphone := Visitor{DeviceType: "mobile", OS: "iOS", Browser: "Mobile Safari"}
+	pixel := Visitor{DeviceType: "mobile", OS: "Android", Browser: "Chrome Mobile"}
+	ipad := Visitor{DeviceType: "tablet", OS: "iOS", Browser: "Mobile Safari"}
+	laptop := Visitor{DeviceType: "desktop", OS: "Mac OS X", Browser: "Chrome"}
+
+	tests := []struct {
+		name    string
+		rule    RedirectRule
+		visitor Visitor
+		want    bool
+	}{
+		{"device type", RedirectRule{DeviceTypes: "mobile"}, iphone, true},
+		{"one of device types", RedirectRule{DeviceTypes: "mobile,tablet"}, ipad, true},
+		{"other device type", RedirectRule{DeviceTypes: "mobile,tablet"}, laptop, false},
+		{"unknown device type", RedirectRule{DeviceTypes: "mobile"}, Visitor{}, false},
+		{"os family", RedirectRule{OSFamilies: "iOS"}, ipad, true},
+		{"os family case insensitive", RedirectRule{OSFamilies: "ios,android"}, pixel, true},
+		{"other os family", RedirectRule{OSFamilies: "iOS"}, pixel, false},
+		{"browser", RedirectRule{Browsers: "Chrome"}, laptop, true},
+		{"browser is matched exactly", RedirectRule{Browsers: "Chrome"}, pixel, false},
+		{"device and os together", RedirectRule{DeviceTypes: "mobile", OSFamilies: "iOS"}, iphone, true},
+		{"device matches, os does not", RedirectRule{DeviceTypes: "mobile", OSFamilies: "iOS"}, pixel, false},
+		{"os matches, device does not", RedirectRule{DeviceTypes: "mobile", OSFamilies: "iOS"}, ipad, false},
+		{"all conditions", RedirectRule{Countries: "KZ", DeviceTypes: "desktop", OSFamilies: "Mac OS X", Browsers: "Chrome"}, Visitor{Country: "KZ", DeviceType: "desktop", OS: "Mac OS X", Browser: "Chrome"}, true},
+		{"all conditions, other country", RedirectRule{Countries: "KZ", DeviceTypes: "desktop", OSFamilies: "Mac OS X"}, laptop, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.rule.Matches(tt.visitor))
+		})
+	}
+}
+
+func TestNormalizeDeviceTypes(t *testing.T) {
+	tests := []struct {
+		input   []string
+		want    string
+		wantErr error
+	}{
+		{nil, "", nil},
+		{[]string{"Mobile", " tablet ", "mobile"}, "mobile,tablet", nil},
+		{[]string{"desktop", "bot", "unknown"}, "desktop,bot,unknown", nil},
+		{[]string{"phone"}, "", ErrInvalidDeviceType},
+		{[]string{"mobile", ""}, "", ErrInvalidDeviceType},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeDeviceTypes(tt.input)
+		if tt.wantErr != nil {
+			assert.ErrorIs(t, err, tt.wantErr, tt.input)
+			continue
+		}
+		require.NoError(t, err, tt.input)
+		assert.Equal(t, tt.want, got)
+	}
+}
+
+func TestNormalizeRuleNames(t *testing.T) {
+	tests := []struct {
+		input   []string
+		want    string
+		wantErr error
+	}{
+		{nil, "", nil},
+		{[]string{" iOS ", "Android", "ios"}, "iOS,Android", nil},
+		{[]string{"Mac OS X"}, "Mac OS X", nil},
+		{[]string{""}, "", ErrInvalidRuleValue},
+		{[]string{"iOS,Android"}, "", ErrInvalidRuleValue},
+		{[]string{strings.Repeat("x", maxRuleValueLength+1)}, "", ErrInvalidRuleValue},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeRuleNames(tt.input)
+		if tt.wantErr != nil {
+			assert.ErrorIs(t, err, tt.wantErr, tt.input)
+			continue
+		}
+		require.NoError(t, err, tt.input)
+		assert.Equal(t, tt.want, got)
+	}
+}
+
+func TestRedirectRule_MatchesTime(t *testing.T) {
+	// 2024-03-15 пятница
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 3, day, hour, minute, 0, 0, time.UTC)
+	}
+	saleFrom := at(11, 0, 0)
+	saleUntil := at(18, 0, 0)
+
+	tests := []struct {
+		name string
+		rule RedirectRule
+		time time.Time
+		want bool
+	}{
+		{"inside daytime window", RedirectRule{TimeFrom: "09:00", TimeTo: "18:00"}, at(15, 12, 0), true},
+		{"window start is inclusive", RedirectRule{TimeFrom: "09:00", TimeTo: "18:00"}, at(15, 9, 0), true},
+		{"window end is exclusive", RedirectRule{TimeFrom: "09:00", TimeTo: "18:00"}, at(15, 18, 0), false},
+		{"before daytime window", RedirectRule{TimeFrom: "09:00", TimeTo: "18:00"}, at(15, 8, 59), false},
+		{"overnight window before midnight", RedirectRule{TimeFrom: "22:00", TimeTo: "06:00"}, at(15, 23, 30), true},
+		{"overnight window after midnight", RedirectRule{TimeFrom: "22:00", TimeTo: "06:00"}, at(16, 2, 15), true},
+		{"overnight window end is exclusive", RedirectRule{TimeFrom: "22:00", TimeTo: "06:00"}, at(16, 6, 0), false},
+		{"outside overnight window", RedirectRule{TimeFrom: "22:00", TimeTo: "06:00"}, at(15, 12, 0), false},
+		{"invalid stored time never matches", RedirectRule{TimeFrom: "9am", TimeTo: "18:00"}, at(15, 12, 0), false},
+		{"weekday", RedirectRule{Weekdays: "fri"}, at(15, 12, 0), true},
+		{"other weekday", RedirectRule{Weekdays: "sat,sun"}, at(15, 12, 0), false},
+		{"weekend", RedirectRule{Weekdays: "sat,sun"}, at(17, 12, 0), true},
+		{"weekday and time", RedirectRule{Weekdays: "fri", TimeFrom: "18:00", TimeTo: "23:00"}, at(15, 19, 0), true},
+		{"weekday matches, time does not", RedirectRule{Weekdays: "fri", TimeFrom: "18:00", TimeTo: "23:00"}, at(15, 12, 0), false},
+		// окно через полночь относится к дню, в который наступило время перехода
+		{"overnight window after midnight on next weekday", RedirectRule{Weekdays: "fri", TimeFrom: "22:00", TimeTo: "06:00"}, at(16, 2, 0), false},
+		{"inside active period", RedirectRule{ActiveFrom: &saleFrom, ActiveUntil: &saleUntil}, at(15, 12, 0), true},
+		{"active from is inclusive", RedirectRule{ActiveFrom: &saleFrom}, saleFrom, true},
+		{"before active period", RedirectRule{ActiveFrom: &saleFrom}, at(10, 23, 59), false},
+		{"active until is exclusive", RedirectRule{ActiveUntil: &saleUntil}, saleUntil, false},
+		{"after active period", RedirectRule{ActiveFrom: &saleFrom, ActiveUntil: &saleUntil}, at(19, 0, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.rule.Matches(Visitor{Time: tt.time}))
+		})
+	}
+}
+
+func TestNormalizeWeekdays(t *testing.T) {
+	tests := []struct {
+		input   []string
+		want    string
+		wantErr error
+	}{
+		{nil, "", nil},
+		{[]string{"sun", "Mon", "sat"}, "mon,sat,sun", nil},
+		{[]string{"Friday", " wednesday ", "fri"}, "wed,fri", nil},
+		{[]string{"tue", "tue"}, "tue", nil},
+		{[]string{"funday"}, "", ErrInvalidWeekday},
+		{[]string{"mon", ""}, "", ErrInvalidWeekday},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeWeekdays(tt.input)
+		if tt.wantErr != nil {
+			assert.ErrorIs(t, err, tt.wantErr, tt.input)
+			continue
+		}
+		require.NoError(t, err, tt.input)
+		assert.Equal(t, tt.want, got)
+	}
+}
+
+func TestParseTimeOfDay(t *testing.T) {
+	minutes, err := ParseTimeOfDay("18:30")
+	require.NoError(t, err)
+	assert.Equal(t, 18*60+30, minutes)
+
+	for _, input := range []string{"", "24:00", "9:5", "18-30"} {
+		_, err := ParseTimeOfDay(input)
+		assert.ErrorIs(t, err, ErrInvalidTimeOfDay, input)
+	}
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+// Размеры колонок превью ссылки для соцсетей
+const (
+	MaxSocialPreviewTitleLength       = 200
+	MaxSocialPreviewDescriptionLength = 500
+)
+
+var ErrInvalidSocialPreview = errors.New("social preview title must be at most 200 characters and description at most 500")
+
+// SocialPreview карточка ссылки для мессенджеров и соцсетей, заданная владельцем. nil означает,
+// что поле не задано. Встраивается в ссылку с префиксом колонок og_.
+type SocialPreview struct {
+	Title       *string `gorm:"column:title;size:200" json:"title,omitempty"`
+	Description *string `gorm:"column:description;size:500" json:"description,omitempty"`
+	ImageURL    *string `gorm:"column:image_url;type:text" json:"image_url,omitempty"` // абсолютный http(s) адрес картинки
+}
+
+// IsEmpty проверяет, что владелец не задал ни одного поля превью
+func (p SocialPreview) IsEmpty() bool {
+	return p.Title == nil && p.Description == nil && p.ImageURL == nil
+}
+
+// Normalize обрезает пробелы по краям, превращает пустые значения в nil и проверяет длину.
+// Адрес картинки проверяется отдельно политикой адресов назначения.
+func (p SocialPreview) Normalize() (SocialPreview, error) {
+	result := p
+	limits := []int{MaxSocialPreviewTitleLength, MaxSocialPreviewDescriptionLength, 0}
+	for i, field := range []**string{&result.Title, &result.Description, &result.ImageURL} {
+		if *field == nil {
+			continue
+		}
+		value := strings.TrimSpace(**field)
+		if limits[i] > 0 && utf8.RuneCountInString(value) > limits[i] {
+			return SocialPreview{}, ErrInvalidSocialPreview
+		}
+		if value == "" {
+			*field = nil
+			continue
+		}
+		*field = &value
+	}
+	return result, nil
+}
+
+// PreviewCard возвращает заголовок, описание и картинку карточки ссылки. Незаданные владельцем поля
+// берутся из заголовка и описания ссылки, затем из метаданных страницы назначения; метаданные
+// защищенной паролем ссылки не используются, чтобы карточка не раскрывала адрес назначения.
+func (l *Link) PreviewCard() (title, description, imageURL string) {
+	var metadata LinkMetadata
+	if l.PasswordHash == nil {
+		metadata = l.Metadata
+	}
+	title = firstNonEmpty(l.SocialPreview.Title, l.Title, &metadata.Title)
+	description = firstNonEmpty(l.SocialPreview.Description, l.Description, &metadata.Description)
+	imageURL = firstNonEmpty(l.SocialPreview.ImageURL, &metadata.ImageURL)
+	return title, description, imageURL
+}
+
+// firstNonEmpty возвращает первое заданное непустое значение
+func firstNonEmpty(values ...*string) string {
+	for _, value := range values {
+		if value != nil && *value != "" {
+			return *value
+		}
+	}
+	return ""
+}
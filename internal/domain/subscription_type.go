@@ -18,6 +18,7 @@ type SubscriptionType struct {
 	APIAccess              bool    `gorm:"column:api_access;not null;default:false" json:"api_access"`
 	CustomDomains          bool    `gorm:"column:custom_domains;not null;default:false" json:"custom_domains"`
 	PrioritySupport        bool    `gorm:"column:priority_support;not null;default:false" json:"priority_support"`
+	InterstitialRedirects  bool    `gorm:"column:interstitial_redirects;not null;default:false" json:"interstitial_redirects"` // meta refresh и промежуточная страница
 	CreatedAt              time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
 	UpdatedAt              time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
 	IsActive               bool    `gorm:"column:is_active;not null;default:true" json:"is_active"`
@@ -49,6 +50,8 @@ func (st *SubscriptionType) HasFeature(feature string) bool {
 		return st.CustomDomains
 	case "priority_support":
 		return st.PrioritySupport
+	case "interstitial_redirects":
+		return st.InterstitialRedirects
 	default:
 		return false
 	}
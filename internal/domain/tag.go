@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MaxTagNameLength максимальная длина названия тега в символах
+	MaxTagNameLength = 50
+	// MaxTagsPerLink максимальное количество тегов у одной ссылки
+	MaxTagsPerLink = 20
+)
+
+var ErrInvalidTagName = errors.New("tag name must be 1-50 characters: letters, digits, '-' or '_'")
+
+// Tag представляет тег пользователя для группировки ссылок (кампания, клиент, канал)
+type Tag struct {
+	ID        int64     `gorm:"primaryKey;column:id" json:"id"`
+	UserID    int64     `gorm:"column:user_id;not null;uniqueIndex:idx_tags_user_name" json:"user_id"`
+	Name      string    `gorm:"column:name;size:50;not null;uniqueIndex:idx_tags_user_name" json:"name"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+
+	// LinkCount количество ссылок с тегом; заполняется только при выборке списка тегов
+	LinkCount int64 `gorm:"column:link_count;->;-:migration" json:"link_count"`
+
+	// Relationships
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TableName возвращает название таблицы для GORM
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// NormalizeTagName приводит название тега к каноническому виду (без пробелов по краям, в нижнем регистре)
+// и проверяет допустимость символов
+func NormalizeTagName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || utf8.RuneCountInString(name) > MaxTagNameLength {
+		return "", ErrInvalidTagName
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+			return "", ErrInvalidTagName
+		}
+	}
+	return name, nil
+}
+
+// NormalizeTagNames нормализует список тегов и убирает повторы, сохраняя порядок
+func NormalizeTagNames(names []string) ([]string, error) {
+	normalized := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		tagName, err := NormalizeTagName(name)
+		if err != nil {
+			return nil, err
+		}
+		if seen[tagName] {
+			continue
+		}
+		seen[tagName] = true
+		normalized = append(normalized, tagName)
+	}
+	if len(normalized) > MaxTagsPerLink {
+		return nil, errors.New("too many tags for one link (max 20)")
+	}
+	return normalized, nil
+}
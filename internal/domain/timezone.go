@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// MaxTimezoneLength максимальная длина названия часового пояса
+const MaxTimezoneLength = 64
+
+var ErrInvalidTimezone = errors.New("timezone must be an IANA time zone name, e.g. Europe/Moscow")
+
+// timezones кэш загруженных часовых поясов: загрузка читает базу часовых поясов
+var timezones sync.Map
+
+// LoadTimezone возвращает часовой пояс по названию IANA; пустое название означает UTC
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	if location, ok := timezones.Load(name); ok {
+		return location.(*time.Location), nil
+	}
+	if len(name) > MaxTimezoneLength {
+		return nil, ErrInvalidTimezone
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+	timezones.Store(name, location)
+	return location, nil
+}
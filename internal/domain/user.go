@@ -21,6 +21,9 @@ type User struct {
 	UpdatedAt              time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
 	IsActive               bool       `gorm:"column:is_active;default:true" json:"is_active"`
 
+	// UTMDefaults метки по умолчанию, которые получают новые ссылки пользователя
+	UTMDefaults UTMParams `gorm:"embedded;embeddedPrefix:utm_default_" json:"utm_defaults"`
+
 	// Relationships
 	SubscriptionType *SubscriptionType `gorm:"foreignKey:SubscriptionTypeID" json:"subscription_type,omitempty"`
 	Links            []Link            `gorm:"foreignKey:UserID" json:"links,omitempty"`
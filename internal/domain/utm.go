@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxUTMValueLength максимальная длина значения UTM метки в символах
+const MaxUTMValueLength = 100
+
+var ErrInvalidUTMValue = errors.New("UTM values must be at most 100 characters")
+
+// UTMParams набор UTM меток кампании. nil означает, что метка не задана.
+// Встраивается в ссылку, в настройки пользователя и в клик с разными префиксами колонок.
+type UTMParams struct {
+	Source   *string `gorm:"column:source;size:100" json:"source,omitempty"`
+	Medium   *string `gorm:"column:medium;size:100" json:"medium,omitempty"`
+	Campaign *string `gorm:"column:campaign;size:100" json:"campaign,omitempty"`
+	Term     *string `gorm:"column:term;size:100" json:"term,omitempty"`
+	Content  *string `gorm:"column:content;size:100" json:"content,omitempty"`
+}
+
+// IsEmpty проверяет, что ни одна метка не задана
+func (u UTMParams) IsEmpty() bool {
+	return u.Source == nil && u.Medium == nil && u.Campaign == nil && u.Term == nil && u.Content == nil
+}
+
+// WithDefaults возвращает метки, в которых незаданные значения взяты из defaults
+func (u UTMParams) WithDefaults(defaults UTMParams) UTMParams {
+	result := u
+	for i, field := range result.fields() {
+		if *field == nil {
+			*field = *defaults.fields()[i]
+		}
+	}
+	return result
+}
+
+// Normalize обрезает пробелы по краям, превращает пустые значения в nil и проверяет длину
+func (u UTMParams) Normalize() (UTMParams, error) {
+	result := u
+	for _, field := range result.fields() {
+		if *field == nil {
+			continue
+		}
+		value := strings.TrimSpace(**field)
+		if utf8.RuneCountInString(value) > MaxUTMValueLength {
+			return UTMParams{}, ErrInvalidUTMValue
+		}
+		if value == "" {
+			*field = nil
+			continue
+		}
+		*field = &value
+	}
+	return result, nil
+}
+
+// Query возвращает заданные метки в виде query string в порядке source, medium, campaign, term, content
+func (u UTMParams) Query() string {
+	parts := make([]string, 0, len(utmKeys))
+	for i, field := range u.fields() {
+		if *field != nil {
+			parts = append(parts, utmKeys[i]+"="+url.QueryEscape(**field))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// ParseUTM извлекает UTM метки из параметров запроса
+func ParseUTM(values url.Values) UTMParams {
+	var u UTMParams
+	for i, field := range u.fields() {
+		if value := strings.TrimSpace(values.Get(utmKeys[i])); value != "" {
+			if utf8.RuneCountInString(value) > MaxUTMValueLength {
+				value = string([]rune(value)[:MaxUTMValueLength])
+			}
+			*field = &value
+		}
+	}
+	return u
+}
+
+// ParseUTMFromURL извлекает UTM метки из query string адреса; некорректный адрес дает пустой набор
+func ParseUTMFromURL(rawURL string) UTMParams {
+	if rawURL == "" {
+		return UTMParams{}
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return UTMParams{}
+	}
+	return ParseUTM(u.Query())
+}
+
+// utmKeys названия параметров в порядке полей UTMParams
+var utmKeys = []string{"utm_source", "utm_medium", "utm_campaign", "utm_term", "utm_content"}
+
+// fields возвращает указатели на поля в порядке utmKeys
+func (u *UTMParams) fields() []**string {
+	return []**string{&u.Source, &u.Medium, &u.Campaign, &u.Term, &u.Content}
+}
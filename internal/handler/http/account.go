@@ -0,0 +1,114 @@
+package http
+
+import (
+	"GURLS-Backend/internal/auth"
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/repository"
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// AccountHandler обработчик настроек аккаунта пользователя
+type AccountHandler struct {
+	storage repository.Storage
+	log     *zap.Logger
+}
+
+// NewAccountHandler создает новый обработчик настроек аккаунта
+func NewAccountHandler(storage repository.Storage, log *zap.Logger) *AccountHandler {
+	return &AccountHandler{
+		storage: storage,
+		log:     log,
+	}
+}
+
+// GetUTMDefaults возвращает UTM метки по умолчанию для новых ссылок
+//
+//	@Summary		Get UTM defaults
+//	@Description	Get the account-level UTM tags applied to new links that do not set their own
+//	@Tags			Account
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	domain.UTMParams	"UTM defaults"
+//	@Failure		401	{object}	map[string]string	"Authentication required"
+//	@Router			/api/account/utm-defaults [get]
+func (h *AccountHandler) GetUTMDefaults(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.getUser(w, r)
+	if !ok {
+		return
+	}
+
+	h.writeJSON(w, user.UTMDefaults, http.StatusOK)
+}
+
+// UpdateUTMDefaults заменяет UTM метки по умолчанию; существующие ссылки не изменяются
+//
+//	@Summary		Update UTM defaults
+//	@Description	Replace the account-level UTM tags. Missing or empty fields are cleared. Existing links keep their tags.
+//	@Tags			Account
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		domain.UTMParams	true	"UTM defaults"
+//	@Success		200		{object}	domain.UTMParams	"Updated UTM defaults"
+//	@Failure		400		{object}	map[string]string	"Invalid request data"
+//	@Failure		401		{object}	map[string]string	"Authentication required"
+//	@Router			/api/account/utm-defaults [put]
+func (h *AccountHandler) UpdateUTMDefaults(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.getUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.UTMParams
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "Invalid request format", http.StatusBadRequest)
+		return
+	}
+	utm, err := req.Normalize()
+	if err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	user.UTMDefaults = utm
+	if err := h.storage.UpdateUser(r.Context(), user); err != nil {
+		h.log.Error("failed to update UTM defaults", zap.Int64("user_id", user.ID), zap.Error(err))
+		h.writeError(w, "Failed to update UTM defaults", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, user.UTMDefaults, http.StatusOK)
+}
+
+// getUser получает текущего пользователя из контекста запроса
+func (h *AccountHandler) getUser(w http.ResponseWriter, r *http.Request) (*domain.User, bool) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeError(w, "User ID not found in context", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	user, err := h.storage.GetUserByID(r.Context(), userID)
+	if err != nil {
+		h.log.Error("failed to get user", zap.Int64("user_id", userID), zap.Error(err))
+		h.writeError(w, "Failed to retrieve account", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	return user, true
+}
+
+func (h *AccountHandler) writeJSON(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *AccountHandler) writeError(w http.ResponseWriter, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,294 @@
+package http
+
+import (
+	"GURLS-Backend/internal/auth"
+	"GURLS-Backend/internal/service"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// maxBulkBodySize ограничение размера тела запроса пакетного создания (JSON или CSV)
+const maxBulkBodySize = 10 << 20
+
+// BulkLinksHandler обработчик пакетного создания ссылок
+type BulkLinksHandler struct {
+	bulkService *service.BulkLinkService
+	log         *zap.Logger
+}
+
+// NewBulkLinksHandler создает новый обработчик пакетного создания ссылок
+func NewBulkLinksHandler(bulkService *service.BulkLinkService, log *zap.Logger) *BulkLinksHandler {
+	return &BulkLinksHandler{
+		bulkService: bulkService,
+		log:         log,
+	}
+}
+
+// BulkCreateLinksResponse результат синхронного пакетного создания
+type BulkCreateLinksResponse struct {
+	Total     int                      `json:"total"`
+	Succeeded int                      `json:"succeeded"`
+	Failed    int                      `json:"failed"`
+	Results   []service.BulkLinkResult `json:"results"`
+}
+
+// BulkJobResponse ответ на запуск асинхронного пакетного создания
+type BulkJobResponse struct {
+	JobID     string                `json:"job_id"`
+	Status    service.BulkJobStatus `json:"status"`
+	Total     int                   `json:"total"`
+	StatusURL string                `json:"status_url"`
+}
+
+// CreateLinks обрабатывает POST /api/links/bulk
+//
+//	@Summary		Bulk create links
+//	@Description	Create links from a JSON array or a CSV file (columns: url, title, custom_alias, expires_at). The whole batch is checked against the plan before any link is created. Large batches or async=true are processed in the background.
+//	@Tags			Links
+//	@Accept			json
+//	@Accept			text/csv
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		[]service.BulkLinkInput		false	"Links to create (JSON)"
+//	@Param			file	formData	file						false	"CSV file with links"
+//	@Param			async	query		bool						false	"Process the batch in the background"
+//	@Success		200		{object}	BulkCreateLinksResponse		"Per-row results"
+//	@Success		202		{object}	BulkJobResponse				"Job accepted"
+//	@Failure		400		{object}	map[string]string			"Invalid request data"
+//	@Failure		401		{object}	map[string]string			"Authentication required"
+//	@Failure		403		{object}	map[string]string			"Batch exceeds the current plan"
+//	@Failure		413		{object}	map[string]string			"Request body too large"
+//	@Router			/api/links/bulk [post]
+func (h *BulkLinksHandler) CreateLinks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBulkBodySize)
+	inputs, err := readBulkInputs(r)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.writeError(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		h.log.Debug("invalid bulk create request", zap.Error(err))
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Проверяем тариф на весь пакет, чтобы не создавать его частично
+	if err := h.bulkService.CheckEntitlements(r.Context(), userID, inputs); err != nil {
+		switch {
+		case errors.Is(err, service.ErrBulkEmpty), errors.Is(err, service.ErrBulkTooLarge):
+			h.writeError(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrBulkQuotaExceeded):
+			h.writeError(w, "Batch exceeds your monthly link limit. Please upgrade your plan or split the batch.", http.StatusForbidden)
+		case errors.Is(err, service.ErrBulkCustomAliasNotAllowed):
+			h.writeError(w, "Custom aliases are not available in your current subscription plan. Please upgrade to use this feature.", http.StatusForbidden)
+		default:
+			h.log.Error("failed to check bulk entitlements", zap.Int64("user_id", userID), zap.Error(err))
+			h.writeError(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	async, _ := strconv.ParseBool(r.URL.Query().Get("async"))
+	if async || len(inputs) > service.MaxSyncBulkLinks {
+		job, err := h.bulkService.StartJob(userID, inputs)
+		if err != nil {
+			h.log.Error("failed to start bulk job", zap.Int64("user_id", userID), zap.Error(err))
+			h.writeError(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		statusURL := "/api/links/bulk/" + job.ID
+		w.Header().Set("Location", statusURL)
+		h.writeJSON(w, BulkJobResponse{
+			JobID:     job.ID,
+			Status:    job.Status,
+			Total:     job.Total,
+			StatusURL: statusURL,
+		}, http.StatusAccepted)
+		return
+	}
+
+	results := h.bulkService.CreateLinks(r.Context(), userID, inputs)
+
+	response := BulkCreateLinksResponse{Total: len(results), Results: results}
+	for _, result := range results {
+		if result.Error == "" {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+	}
+
+	h.log.Info("bulk links created",
+		zap.Int64("user_id", userID),
+		zap.Int("succeeded", response.Succeeded),
+		zap.Int("failed", response.Failed))
+
+	h.writeJSON(w, response, http.StatusOK)
+}
+
+// GetJob обрабатывает GET /api/links/bulk/{job_id}
+//
+//	@Summary		Get bulk job status
+//	@Description	Get progress and per-row results of an asynchronous bulk creation job
+//	@Tags			Links
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			job_id	path		string				true	"Job ID"
+//	@Success		200		{object}	service.BulkJob		"Job status"
+//	@Failure		401		{object}	map[string]string	"Authentication required"
+//	@Failure		404		{object}	map[string]string	"Job not found"
+//	@Router			/api/links/bulk/{job_id} [get]
+func (h *BulkLinksHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	// /api/links/bulk/{job_id}
+	pathParts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(pathParts) != 4 || pathParts[3] == "" {
+		h.writeError(w, "Job ID is required", http.StatusBadRequest)
+		return
+	}
+
+	job, found := h.bulkService.GetJob(userID, pathParts[3])
+	if !found {
+		h.writeError(w, "Job not found", http.StatusNotFound)
+		return
+	}
+
+	h.writeJSON(w, job, http.StatusOK)
+}
+
+// readBulkInputs читает строки пакета из JSON массива, CSV тела или загруженного CSV файла
+func readBulkInputs(r *http.Request) ([]service.BulkLinkInput, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	switch mediaType {
+	case "multipart/form-data":
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			if errors.Is(err, http.ErrMissingFile) {
+				return nil, errors.New("CSV file is required in the 'file' field")
+			}
+			return nil, err
+		}
+		defer file.Close()
+		return parseBulkCSV(file)
+	case "text/csv":
+		return parseBulkCSV(r.Body)
+	default:
+		var inputs []service.BulkLinkInput
+		if err := json.NewDecoder(r.Body).Decode(&inputs); err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return nil, err
+			}
+			return nil, errors.New("Invalid request format. Expected a JSON array of links")
+		}
+		for i := range inputs {
+			inputs[i].Row = i + 1
+		}
+		return inputs, nil
+	}
+}
+
+// parseBulkCSV разбирает CSV с заголовком; обязательна колонка url,
+// порядок колонок произвольный, неизвестные колонки игнорируются
+func parseBulkCSV(reader io.Reader) ([]service.BulkLinkInput, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if err == io.EOF {
+		return nil, service.ErrBulkEmpty
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+	}
+	if _, ok := columns["url"]; !ok {
+		return nil, errors.New("CSV header must contain a 'url' column")
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var inputs []service.BulkLinkInput
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		if len(inputs) >= service.MaxBulkLinks {
+			return nil, service.ErrBulkTooLarge
+		}
+
+		inputs = append(inputs, service.BulkLinkInput{
+			Row:         len(inputs) + 1,
+			OriginalURL: field(record, "url"),
+			Title:       field(record, "title"),
+			CustomAlias: field(record, "custom_alias"),
+			ExpiresAt:   field(record, "expires_at"),
+		})
+	}
+
+	return inputs, nil
+}
+
+func (h *BulkLinksHandler) writeJSON(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *BulkLinksHandler) writeError(w http.ResponseWriter, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,357 @@
+package http
+
+import (
+	"GURLS-Backend/internal/auth"
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/repository"
+	"GURLS-Backend/internal/service"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DomainsHandler обработчик собственных доменов пользователя
+type DomainsHandler struct {
+	storage       repository.Storage
+	domainService *service.DomainService
+	log           *zap.Logger
+	serviceHost   string // домен сервиса из baseURL, его нельзя добавить как собственный
+}
+
+// NewDomainsHandler создает новый обработчик собственных доменов
+func NewDomainsHandler(storage repository.Storage, domainService *service.DomainService, log *zap.Logger, baseURL string) *DomainsHandler {
+	return &DomainsHandler{
+		storage:       storage,
+		domainService: domainService,
+		log:           log,
+		serviceHost:   hostnameOf(baseURL),
+	}
+}
+
+// DomainRequest структура запроса добавления домена
+type DomainRequest struct {
+	Hostname string `json:"hostname"`
+}
+
+// DomainVerificationInfo DNS запись, которую нужно создать для подтверждения владения доменом
+type DomainVerificationInfo struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// DomainInfo информация о собственном домене
+type DomainInfo struct {
+	ID           int64                  `json:"id"`
+	Hostname     string                 `json:"hostname"`
+	Verified     bool                   `json:"verified"`
+	VerifiedAt   string                 `json:"verified_at,omitempty"`
+	CreatedAt    string                 `json:"created_at"`
+	Verification DomainVerificationInfo `json:"verification"`
+}
+
+// ListDomainsResponse структура ответа списка доменов
+type ListDomainsResponse struct {
+	Domains []DomainInfo `json:"domains"`
+}
+
+// ListDomains возвращает домены пользователя
+//
+//	@Summary		List custom domains
+//	@Description	Get the user's custom domains with their verification status
+//	@Tags			Domains
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	ListDomainsResponse	"User domains"
+//	@Failure		401	{object}	map[string]string	"Authentication required"
+//	@Router			/api/domains [get]
+func (h *DomainsHandler) ListDomains(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	domains, err := h.storage.ListCustomDomains(r.Context(), userID)
+	if err != nil {
+		h.log.Error("failed to list custom domains", zap.Int64("user_id", userID), zap.Error(err))
+		h.writeError(w, "Failed to retrieve domains", http.StatusInternalServerError)
+		return
+	}
+
+	domainInfos := make([]DomainInfo, len(domains))
+	for i, customDomain := range domains {
+		domainInfos[i] = newDomainInfo(customDomain)
+	}
+
+	h.writeJSON(w, ListDomainsResponse{Domains: domainInfos}, http.StatusOK)
+}
+
+// CreateDomain добавляет собственный домен
+//
+//	@Summary		Add a custom domain
+//	@Description	Register a domain for short links. Ownership is proven by publishing the returned TXT record and calling the verify endpoint; links can be created on the domain only after verification.
+//	@Tags			Domains
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		DomainRequest		true	"Domain name"
+//	@Success		201		{object}	DomainInfo			"Domain added"
+//	@Failure		400		{object}	map[string]string	"Invalid domain name"
+//	@Failure		401		{object}	map[string]string	"Authentication required"
+//	@Failure		403		{object}	map[string]string	"Feature not available in the plan"
+//	@Failure		409		{object}	map[string]string	"Domain already exists"
+//	@Router			/api/domains [post]
+func (h *DomainsHandler) CreateDomain(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	hasAccess, err := h.checkFeatureAccess(r.Context(), userID, "custom_domains")
+	if err != nil {
+		h.log.Error("failed to check custom domains access", zap.Int64("user_id", userID), zap.Error(err))
+		h.writeError(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if !hasAccess {
+		h.writeError(w, "Custom domains are not available in your current subscription plan. Please upgrade to use this feature.", http.StatusForbidden)
+		return
+	}
+
+	var req DomainRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "Invalid request format", http.StatusBadRequest)
+		return
+	}
+	hostname, err := domain.NormalizeHostname(req.Hostname)
+	if err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if hostname == h.serviceHost || strings.HasSuffix(hostname, "."+h.serviceHost) {
+		h.writeError(w, "The service domain and its subdomains cannot be added", http.StatusBadRequest)
+		return
+	}
+
+	customDomain, err := h.domainService.AddDomain(r.Context(), userID, hostname)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrDomainLimitReached):
+			h.writeError(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, repository.ErrDomainExists):
+			h.writeError(w, "Domain already exists", http.StatusConflict)
+		default:
+			h.log.Error("failed to add custom domain", zap.Int64("user_id", userID), zap.String("hostname", hostname), zap.Error(err))
+			h.writeError(w, "Failed to add domain", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.log.Info("added custom domain", zap.Int64("domain_id", customDomain.ID), zap.Int64("user_id", userID))
+	h.writeJSON(w, newDomainInfo(customDomain), http.StatusCreated)
+}
+
+// GetDomain возвращает домен пользователя
+//
+//	@Summary		Get a custom domain
+//	@Description	Get a custom domain with its verification record
+//	@Tags			Domains
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int					true	"Domain ID"
+//	@Success		200	{object}	DomainInfo			"Domain"
+//	@Failure		401	{object}	map[string]string	"Authentication required"
+//	@Failure		403	{object}	map[string]string	"Access denied"
+//	@Failure		404	{object}	map[string]string	"Domain not found"
+//	@Router			/api/domains/{id} [get]
+func (h *DomainsHandler) GetDomain(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	customDomain, ok := h.getOwnedDomain(w, r, userID)
+	if !ok {
+		return
+	}
+
+	h.writeJSON(w, newDomainInfo(customDomain), http.StatusOK)
+}
+
+// VerifyDomain проверяет TXT запись домена
+//
+//	@Summary		Verify a custom domain
+//	@Description	Look up the verification TXT record of the domain and mark the domain as verified when the token matches
+//	@Tags			Domains
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int					true	"Domain ID"
+//	@Success		200	{object}	DomainInfo			"Domain verified"
+//	@Failure		401	{object}	map[string]string	"Authentication required"
+//	@Failure		403	{object}	map[string]string	"Access denied"
+//	@Failure		404	{object}	map[string]string	"Domain not found"
+//	@Failure		409	{object}	map[string]string	"Domain already verified by another account"
+//	@Failure		422	{object}	map[string]string	"Verification record not found"
+//	@Failure		502	{object}	map[string]string	"DNS lookup failed"
+//	@Router			/api/domains/{id}/verify [post]
+func (h *DomainsHandler) VerifyDomain(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	customDomain, ok := h.getOwnedDomain(w, r, userID)
+	if !ok {
+		return
+	}
+
+	if err := h.domainService.VerifyDomain(r.Context(), customDomain); err != nil {
+		switch {
+		case errors.Is(err, service.ErrDomainNotVerified):
+			h.writeError(w, fmt.Sprintf("TXT record %s with value %s not found", customDomain.VerificationRecordName(), customDomain.VerificationRecordValue()), http.StatusUnprocessableEntity)
+		case errors.Is(err, service.ErrDomainDNSLookup):
+			h.writeError(w, "DNS lookup failed, try again later", http.StatusBadGateway)
+		case errors.Is(err, repository.ErrDomainExists):
+			h.writeError(w, "Domain is already verified by another account", http.StatusConflict)
+		case errors.Is(err, repository.ErrDomainNotFound):
+			h.writeError(w, "Domain not found", http.StatusNotFound)
+		default:
+			h.log.Error("failed to verify custom domain", zap.Int64("domain_id", customDomain.ID), zap.Error(err))
+			h.writeError(w, "Failed to verify domain", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.writeJSON(w, newDomainInfo(customDomain), http.StatusOK)
+}
+
+// DeleteDomain удаляет домен пользователя
+//
+//	@Summary		Delete a custom domain
+//	@Description	Delete a custom domain. Domains with links, including links in the trash, cannot be deleted.
+//	@Tags			Domains
+//	@Security		BearerAuth
+//	@Param			id	path	int	true	"Domain ID"
+//	@Success		204	"Domain deleted"
+//	@Failure		401	{object}	map[string]string	"Authentication required"
+//	@Failure		403	{object}	map[string]string	"Access denied"
+//	@Failure		404	{object}	map[string]string	"Domain not found"
+//	@Failure		409	{object}	map[string]string	"Domain has links"
+//	@Router			/api/domains/{id} [delete]
+func (h *DomainsHandler) DeleteDomain(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	customDomain, ok := h.getOwnedDomain(w, r, userID)
+	if !ok {
+		return
+	}
+
+	if err := h.storage.DeleteCustomDomain(r.Context(), customDomain.ID); err != nil {
+		switch err {
+		case repository.ErrDomainInUse:
+			h.writeError(w, "Domain has links. Delete them and empty the trash first", http.StatusConflict)
+		case repository.ErrDomainNotFound:
+			h.writeError(w, "Domain not found", http.StatusNotFound)
+		default:
+			h.log.Error("failed to delete custom domain", zap.Int64("domain_id", customDomain.ID), zap.Error(err))
+			h.writeError(w, "Failed to delete domain", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.log.Info("deleted custom domain", zap.Int64("domain_id", customDomain.ID), zap.Int64("user_id", userID))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getOwnedDomain получает домен из пути /api/domains/{id}/... и проверяет, что он принадлежит пользователю
+func (h *DomainsHandler) getOwnedDomain(w http.ResponseWriter, r *http.Request, userID int64) (*domain.CustomDomain, bool) {
+	pathParts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(pathParts) < 3 {
+		h.writeError(w, "Domain ID is required", http.StatusBadRequest)
+		return nil, false
+	}
+	domainID, err := strconv.ParseInt(pathParts[2], 10, 64)
+	if err != nil {
+		h.writeError(w, "Invalid domain ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	customDomain, err := h.storage.GetCustomDomain(r.Context(), domainID)
+	if err != nil {
+		if err == repository.ErrDomainNotFound {
+			h.writeError(w, "Domain not found", http.StatusNotFound)
+			return nil, false
+		}
+		h.log.Error("failed to get custom domain", zap.Int64("domain_id", domainID), zap.Error(err))
+		h.writeError(w, "Failed to retrieve domain", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	if customDomain.UserID != userID {
+		h.writeError(w, "Access denied", http.StatusForbidden)
+		return nil, false
+	}
+
+	return customDomain, true
+}
+
+// checkFeatureAccess проверяет, доступна ли функция в подписке пользователя
+func (h *DomainsHandler) checkFeatureAccess(ctx context.Context, userID int64, feature string) (bool, error) {
+	user, err := h.storage.GetUserByID(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	subscription, err := h.storage.GetSubscriptionType(ctx, user.SubscriptionTypeID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get subscription: %w", err)
+	}
+
+	return subscription.HasFeature(feature), nil
+}
+
+// newDomainInfo преобразует доменную модель в ответ API
+func newDomainInfo(customDomain *domain.CustomDomain) DomainInfo {
+	info := DomainInfo{
+		ID:        customDomain.ID,
+		Hostname:  customDomain.Hostname,
+		Verified:  customDomain.IsVerified(),
+		CreatedAt: customDomain.CreatedAt.Format(time.RFC3339),
+		Verification: DomainVerificationInfo{
+			Type:  "TXT",
+			Name:  customDomain.VerificationRecordName(),
+			Value: customDomain.VerificationRecordValue(),
+		},
+	}
+	if customDomain.VerifiedAt != nil {
+		info.VerifiedAt = customDomain.VerifiedAt.Format(time.RFC3339)
+	}
+	return info
+}
+
+func (h *DomainsHandler) writeJSON(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *DomainsHandler) writeError(w http.ResponseWriter, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
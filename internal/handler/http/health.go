@@ -44,7 +44,7 @@ func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
 	dbStatus := "healthy"
 	
 	// Простая проверка - пытаемся получить несуществующую ссылку
-	_, err := h.storage.GetLink(ctx, "health-check-non-existent")
+	_, err := h.storage.GetLink(ctx, nil, "health-check-non-existent")
 	if err != nil && err != repository.ErrAliasNotFound {
 		// Если ошибка не "не найдено", значит проблемы с БД
 		dbStatus = "unhealthy"
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"GURLS-Backend/internal/auth"
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/service"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// LinkAppealRequest структура запроса апелляции отключенной ссылки
+type LinkAppealRequest struct {
+	Message string `json:"message,omitempty"` // пояснение владельца для администратора
+}
+
+// LinkAppealResponse результат апелляции
+type LinkAppealResponse struct {
+	ID         int64  `json:"id"`
+	Alias      string `json:"alias"`
+	Status     string `json:"status"` // approved - ссылка снова включена, pending - адрес еще в списках угроз
+	Threat     string `json:"threat"`
+	Message    string `json:"message,omitempty"`
+	IsActive   bool   `json:"is_active"`
+	CreatedAt  string `json:"created_at"`
+	ResolvedAt string `json:"resolved_at,omitempty"`
+}
+
+// AppealLink обрабатывает POST /api/links/{alias}/appeal
+//
+//	@Summary		Appeal a flagged link
+//	@Description	Ask to re-enable a link that was disabled because its destination was found in threat lists. Destinations are re-checked against the current lists: if none is listed anymore the link is re-enabled at once, otherwise the appeal stays pending and is re-checked on every reputation scan.
+//	@Tags			Links
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			alias	path		string				true	"Link alias"
+//	@Param			domain	query		string				false	"Custom domain of the link, omitted for the service domain"
+//	@Param			request	body		LinkAppealRequest	false	"Appeal message"
+//	@Success		200		{object}	LinkAppealResponse	"Link re-enabled"
+//	@Success		202		{object}	LinkAppealResponse	"Destination is still listed, appeal is re-checked on every scan"
+//	@Failure		400		{object}	map[string]string	"Invalid request data"
+//	@Failure		401		{object}	map[string]string	"Authentication required"
+//	@Failure		403		{object}	map[string]string	"Access denied"
+//	@Failure		404		{object}	map[string]string	"Link not found"
+//	@Failure		409		{object}	map[string]string	"Link is not flagged or already has a pending appeal"
+//	@Router			/api/links/{alias}/appeal [post]
+func (h *LinksHandler) AppealLink(w http.ResponseWriter, r *http.Request) {
+	alias := extractAlias(r)
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	var req LinkAppealRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.writeError(w, "Invalid request format", http.StatusBadRequest)
+			return
+		}
+	}
+
+	link, ok := h.getOwnedLink(w, r, alias, userID)
+	if !ok {
+		return
+	}
+
+	appeal, err := h.reputation.Appeal(r.Context(), link, userID, strings.TrimSpace(req.Message))
+	switch err {
+	case nil:
+	case service.ErrLinkAppealTooLong:
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	case service.ErrLinkNotFlagged:
+		h.writeError(w, "Link is not flagged", http.StatusConflict)
+		return
+	case service.ErrLinkAppealPending:
+		h.writeError(w, "Link already has a pending appeal", http.StatusConflict)
+		return
+	default:
+		h.log.Error("failed to appeal link", zap.String("alias", alias), zap.Error(err))
+		h.writeError(w, "Failed to submit appeal", http.StatusInternalServerError)
+		return
+	}
+
+	response := LinkAppealResponse{
+		ID:        appeal.ID,
+		Alias:     link.Alias,
+		Status:    appeal.Status,
+		Threat:    appeal.Threat,
+		Message:   appeal.Message,
+		IsActive:  link.IsActive,
+		CreatedAt: appeal.CreatedAt.Format(time.RFC3339),
+	}
+	if appeal.ResolvedAt != nil {
+		response.ResolvedAt = appeal.ResolvedAt.Format(time.RFC3339)
+	}
+
+	statusCode := http.StatusAccepted
+	if appeal.Status == domain.LinkAppealApproved {
+		statusCode = http.StatusOK
+	}
+	h.log.Info("link appeal submitted", zap.String("alias", alias), zap.Int64("user_id", userID), zap.String("status", appeal.Status))
+	h.writeJSON(w, response, statusCode)
+}
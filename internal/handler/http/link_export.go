@@ -0,0 +1,237 @@
+package http
+
+import (
+	"GURLS-Backend/internal/auth"
+	"GURLS-Backend/internal/domain"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	exportFormatCSV    = "csv"
+	exportFormatNDJSON = "ndjson"
+
+	// exportFlushEvery через сколько строк отправлять накопленные данные клиенту
+	exportFlushEvery = 100
+)
+
+// exportDeviceTypes типы устройств, для которых в CSV есть отдельные колонки;
+// остальные типы учитываются в колонке unknown
+var exportDeviceTypes = []string{"desktop", "mobile", "tablet", "bot", "unknown"}
+
+// LinkExportRecord строка выгрузки ссылки в формате NDJSON
+type LinkExportRecord struct {
+	Alias          string           `json:"alias"`
+	ShortURL       string           `json:"short_url"`
+	OriginalURL    string           `json:"original_url"`
+	Title          string           `json:"title,omitempty"`
+	CreatedAt      string           `json:"created_at"`
+	ExpiresAt      *string          `json:"expires_at,omitempty"`
+	ClickCount     int64            `json:"click_count"`
+	ClicksByDevice map[string]int64 `json:"clicks_by_device"`
+}
+
+// ExportLinks обрабатывает GET /api/links/export
+//
+//	@Summary		Export links
+//	@Description	Stream the user's links with click counts and per-device totals as CSV or NDJSON. Supports the same filters and sorting as the link list.
+//	@Tags			Links
+//	@Produce		text/csv
+//	@Produce		application/x-ndjson
+//	@Security		BearerAuth
+//	@Param			format			query		string				false	"Export format: csv (default) or ndjson"
+//	@Param			created_from	query		string				false	"Created at or after (RFC3339)"
+//	@Param			created_to		query		string				false	"Created before (RFC3339)"
+//	@Param			status			query		string				false	"Link status: active, scheduled, paused, expired, exhausted"
+//	@Param			has_password	query		bool				false	"Filter by password protection"
+//	@Param			q				query		string				false	"Search in alias, URL, title and description"
+//	@Param			tag				query		string				false	"Only links with the tag"
+//	@Param			sort			query		string				false	"Sort field: created_at, click_count, title"
+//	@Param			order			query		string				false	"Sort order: asc or desc (default desc)"
+//	@Success		200				{string}	string				"Exported links"
+//	@Failure		400				{object}	map[string]string	"Invalid query parameters"
+//	@Failure		401				{object}	map[string]string	"Authentication required"
+//	@Router			/api/links/export [get]
+func (h *LinksHandler) ExportLinks(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = exportFormatCSV
+	}
+	if format != exportFormatCSV && format != exportFormatNDJSON {
+		h.writeError(w, "Invalid format. Use csv or ndjson", http.StatusBadRequest)
+		return
+	}
+
+	opts, err := parseLinkListOptions(r)
+	if err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Выгрузка большого аккаунта длится дольше WriteTimeout сервера: снимаем дедлайн записи
+	// для этого ответа, иначе соединение оборвется посреди файла
+	controller := http.NewResponseController(w)
+	if err := controller.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		h.log.Warn("failed to reset export write deadline", zap.Error(err))
+	}
+
+	csvWriter := csv.NewWriter(w)
+	encoder := json.NewEncoder(w)
+
+	// Заголовки отправляются с первой строкой, чтобы ошибку запроса к БД
+	// можно было вернуть обычным ответом
+	started := false
+	begin := func() error {
+		if started {
+			return nil
+		}
+		started = true
+
+		filename := fmt.Sprintf("links-%s.%s", time.Now().UTC().Format("20060102"), format)
+		if format == exportFormatCSV {
+			w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		} else {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+		}
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+		w.WriteHeader(http.StatusOK)
+
+		if format == exportFormatCSV {
+			return csvWriter.Write(linkExportCSVHeader())
+		}
+		return nil
+	}
+
+	exported := 0
+	err = h.storage.ExportUserLinks(r.Context(), userID, opts, func(link *domain.Link) error {
+		if err := begin(); err != nil {
+			return err
+		}
+
+		if format == exportFormatCSV {
+			if err := csvWriter.Write(h.linkExportCSVRecord(link)); err != nil {
+				return err
+			}
+		} else if err := encoder.Encode(h.newLinkExportRecord(link)); err != nil {
+			return err
+		}
+
+		exported++
+		if exported%exportFlushEvery == 0 {
+			csvWriter.Flush()
+			_ = controller.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		if !started {
+			h.log.Error("failed to export links", zap.Int64("user_id", userID), zap.Error(err))
+			h.writeError(w, "Failed to export links", http.StatusInternalServerError)
+			return
+		}
+		// Ответ уже начат, статус изменить нельзя — обрываем выгрузку
+		h.log.Error("link export interrupted", zap.Int64("user_id", userID), zap.Int("exported", exported), zap.Error(err))
+		return
+	}
+
+	if err := begin(); err != nil {
+		h.log.Error("failed to write export header", zap.Int64("user_id", userID), zap.Error(err))
+		return
+	}
+	csvWriter.Flush()
+
+	h.log.Info("links exported", zap.Int64("user_id", userID), zap.String("format", format), zap.Int("count", exported))
+}
+
+// newLinkExportRecord преобразует ссылку в строку выгрузки NDJSON
+func (h *LinksHandler) newLinkExportRecord(link *domain.Link) LinkExportRecord {
+	record := LinkExportRecord{
+		Alias:          link.Alias,
+		ShortURL:       h.shortURL(link),
+		OriginalURL:    link.OriginalURL,
+		CreatedAt:      link.CreatedAt.Format(time.RFC3339),
+		ClickCount:     link.ClickCount,
+		ClicksByDevice: link.ClicksByDevice,
+	}
+	if link.Title != nil {
+		record.Title = *link.Title
+	}
+	if link.ExpiresAt != nil {
+		expiresAt := link.ExpiresAt.Format(time.RFC3339)
+		record.ExpiresAt = &expiresAt
+	}
+	return record
+}
+
+// linkExportCSVRecord преобразует ссылку в строку CSV
+func (h *LinksHandler) linkExportCSVRecord(link *domain.Link) []string {
+	record := h.newLinkExportRecord(link)
+
+	expiresAt := ""
+	if record.ExpiresAt != nil {
+		expiresAt = *record.ExpiresAt
+	}
+
+	row := []string{
+		record.Alias,
+		record.ShortURL,
+		record.OriginalURL,
+		record.Title,
+		record.CreatedAt,
+		expiresAt,
+		strconv.FormatInt(record.ClickCount, 10),
+	}
+
+	devices := make(map[string]int64, len(exportDeviceTypes))
+	for deviceType, count := range record.ClicksByDevice {
+		if !slices.Contains(exportDeviceTypes, deviceType) {
+			deviceType = "unknown"
+		}
+		devices[deviceType] += count
+	}
+	for _, deviceType := range exportDeviceTypes {
+		row = append(row, strconv.FormatInt(devices[deviceType], 10))
+	}
+
+	for i, cell := range row {
+		row[i] = escapeCSVFormula(cell)
+	}
+	return row
+}
+
+// escapeCSVFormula защищает ячейку от CSV-инъекции: значения, которые табличные редакторы
+// выполнят как формулу (=, +, -, @, табуляция, возврат каретки или перевод строки в начале),
+// предваряются апострофом
+func escapeCSVFormula(value string) string {
+	if value == "" {
+		return value
+	}
+	switch value[0] {
+	case '=', '+', '-', '@', '\t', '\r', '\n':
+		return "'" + value
+	}
+	return value
+}
+
+// linkExportCSVHeader возвращает заголовок CSV выгрузки
+func linkExportCSVHeader() []string {
+	header := []string{"alias", "short_url", "original_url", "title", "created_at", "expires_at", "click_count"}
+	for _, deviceType := range exportDeviceTypes {
+		header = append(header, "clicks_"+deviceType)
+	}
+	return header
+}
@@ -0,0 +1,161 @@
+package http
+
+import (
+	"GURLS-Backend/internal/auth"
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/repository"
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// exportStorage отдает ссылки для выгрузки из памяти, делая паузу перед каждой строкой
+type exportStorage struct {
+	repository.Storage
+	links []*domain.Link
+	delay time.Duration
+}
+
+func (s *exportStorage) ExportUserLinks(ctx context.Context, userID int64, opts repository.LinkListOptions, fn func(link *domain.Link) error) error {
+	for _, link := range s.links {
+		time.Sleep(s.delay)
+		if err := fn(link); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newExportRequest(target string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	return r.WithContext(context.WithValue(r.Context(), auth.UserIDKey, int64(1)))
+}
+
+func exportTestLinks() []*domain.Link {
+	title := "=HYPERLINK(\"https://evil.example\")"
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	return []*domain.Link{
+		{
+			Alias:          "promo",
+			OriginalURL:    "https://example.com/a",
+			Title:          &title,
+			CreatedAt:      created,
+			ClickCount:     5,
+			ClicksByDevice: map[string]int64{"mobile": 3, "desktop": 1, "tv": 1},
+		},
+		{
+			Alias:       "-cmd",
+			OriginalURL: "https://example.com/b",
+			CreatedAt:   created,
+		},
+	}
+}
+
+func TestExportLinks_CSV(t *testing.T) {
+	h := &LinksHandler{storage: &exportStorage{links: exportTestLinks()}, log: zap.NewNop(), baseURL: "https://sho.rt"}
+
+	rec := httptest.NewRecorder()
+	h.ExportLinks(rec, newExportRequest("/api/links/export?format=csv"))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Header().Get("Content-Disposition"), ".csv")
+
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, linkExportCSVHeader(), rows[0])
+
+	assert.Equal(t, []string{
+		"promo", "https://sho.rt/promo", "https://example.com/a", `'=HYPERLINK("https://evil.example")`,
+		"2024-05-01T12:00:00Z", "", "5", "1", "3", "0", "0", "1",
+	}, rows[1])
+	assert.Equal(t, "'-cmd", rows[2][0])
+	assert.Equal(t, "https://sho.rt/-cmd", rows[2][1])
+}
+
+func TestExportLinks_NDJSON(t *testing.T) {
+	h := &LinksHandler{storage: &exportStorage{links: exportTestLinks()}, log: zap.NewNop(), baseURL: "https://sho.rt"}
+
+	rec := httptest.NewRecorder()
+	h.ExportLinks(rec, newExportRequest("/api/links/export?format=ndjson"))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+
+	var records []LinkExportRecord
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		var record LinkExportRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	require.Len(t, records, 2)
+
+	// В NDJSON значения не экранируются: формулы опасны только в табличных редакторах
+	assert.Equal(t, `=HYPERLINK("https://evil.example")`, records[0].Title)
+	assert.Equal(t, int64(3), records[0].ClicksByDevice["mobile"])
+	assert.Equal(t, "-cmd", records[1].Alias)
+}
+
+func TestExportLinks_InvalidFormat(t *testing.T) {
+	h := &LinksHandler{storage: &exportStorage{}, log: zap.NewNop(), baseURL: "https://sho.rt"}
+
+	rec := httptest.NewRecorder()
+	h.ExportLinks(rec, newExportRequest("/api/links/export?format=xlsx"))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestExportLinks_OutlivesWriteTimeout(t *testing.T) {
+	links := make([]*domain.Link, 5)
+	for i := range links {
+		links[i] = &domain.Link{Alias: "slow", OriginalURL: "https://example.com", CreatedAt: time.Now()}
+	}
+	h := &LinksHandler{storage: &exportStorage{links: links, delay: 50 * time.Millisecond}, log: zap.NewNop(), baseURL: "https://sho.rt"}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ExportLinks(w, r.WithContext(context.WithValue(r.Context(), auth.UserIDKey, int64(1))))
+	}))
+	server.Config.WriteTimeout = 100 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/links/export?format=ndjson")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, len(links), strings.Count(string(body), "\n"))
+}
+
+func TestEscapeCSVFormula(t *testing.T) {
+	tests := map[string]string{
+		"=1+2":         "'=1+2",
+		"+7 999":       "'+7 999",
+		"-5":           "'-5",
+		"@SUM(A1)":     "'@SUM(A1)",
+		"\tcmd":        "'\tcmd",
+		"\rcmd":        "'\rcmd",
+		"\n=1+2":       "'\n=1+2",
+		"":             "",
+		"plain":        "plain",
+		"a=b":          "a=b",
+		"https://x.io": "https://x.io",
+	}
+	for input, want := range tests {
+		assert.Equal(t, want, escapeCSVFormula(input), "input %q", input)
+	}
+}
@@ -0,0 +1,77 @@
+package http
+
+import (
+	"GURLS-Backend/internal/auth"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// LinkHealthInfo результат последней проверки адреса назначения
+type LinkHealthInfo struct {
+	StatusCode          int    `json:"status_code"` // 0 - ответ не получен
+	LatencyMs           int64  `json:"latency_ms"`
+	FinalURL            string `json:"final_url"` // адрес после редиректов
+	Error               string `json:"error,omitempty"`
+	ConsecutiveFailures int    `json:"consecutive_failures"`
+	CheckedAt           string `json:"checked_at"`
+	BrokenSince         string `json:"broken_since,omitempty"`
+}
+
+// BrokenLinkInfo нерабочая ссылка с результатом последней проверки
+type BrokenLinkInfo struct {
+	LinkInfo
+	Health LinkHealthInfo `json:"health"`
+}
+
+// ListBrokenLinksResponse структура ответа списка нерабочих ссылок
+type ListBrokenLinksResponse struct {
+	Links []BrokenLinkInfo `json:"links"`
+}
+
+// ListBrokenLinks возвращает ссылки пользователя, адрес назначения которых не отвечает
+//
+//	@Summary		List broken links
+//	@Description	Get active links whose destination failed several background checks in a row: unreachable, 404, 410 or 5xx. A link leaves the list after the next successful check.
+//	@Tags			Links
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	ListBrokenLinksResponse	"Broken links"
+//	@Failure		401	{object}	map[string]string		"Authentication required"
+//	@Router			/api/links/broken [get]
+func (h *LinksHandler) ListBrokenLinks(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	records, err := h.storage.ListBrokenLinks(r.Context(), userID)
+	if err != nil {
+		h.log.Error("failed to list broken links", zap.Int64("user_id", userID), zap.Error(err))
+		h.writeError(w, "Failed to retrieve links", http.StatusInternalServerError)
+		return
+	}
+
+	links := make([]BrokenLinkInfo, 0, len(records))
+	for _, record := range records {
+		if record.Link == nil {
+			continue
+		}
+		health := LinkHealthInfo{
+			StatusCode:          record.StatusCode,
+			LatencyMs:           record.LatencyMs,
+			FinalURL:            record.FinalURL,
+			Error:               record.Error,
+			ConsecutiveFailures: record.ConsecutiveFailures,
+			CheckedAt:           record.CheckedAt.Format(time.RFC3339),
+		}
+		if record.BrokenSince != nil {
+			health.BrokenSince = record.BrokenSince.Format(time.RFC3339)
+		}
+		links = append(links, BrokenLinkInfo{LinkInfo: h.newLinkInfo(record.Link), Health: health})
+	}
+
+	h.writeJSON(w, ListBrokenLinksResponse{Links: links}, http.StatusOK)
+}
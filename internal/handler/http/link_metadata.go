@@ -0,0 +1,66 @@
+package http
+
+import (
+	"GURLS-Backend/internal/auth"
+	"GURLS-Backend/internal/service"
+	"errors"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// RefreshLinkMetadata обрабатывает POST /api/links/{alias}/metadata/refresh
+//
+//	@Summary		Refresh link metadata
+//	@Description	Fetch the destination page again and store its title, description, OpenGraph and Twitter card tags and favicon. The link title and description are replaced only when empty or previously filled from the page. Allowed once a minute per link.
+//	@Tags			Links
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			alias	path		string				true	"Link alias"
+//	@Param			domain	query		string				false	"Custom domain of the link, omitted for the service domain"
+//	@Success		200		{object}	LinkInfo			"Link with refreshed metadata"
+//	@Failure		401		{object}	map[string]string	"Authentication required"
+//	@Failure		403		{object}	map[string]string	"Access denied"
+//	@Failure		404		{object}	map[string]string	"Link not found"
+//	@Failure		429		{object}	map[string]string	"Metadata was refreshed less than a minute ago"
+//	@Failure		502		{object}	map[string]string	"Destination page could not be fetched"
+//	@Router			/api/links/{alias}/metadata/refresh [post]
+func (h *LinksHandler) RefreshLinkMetadata(w http.ResponseWriter, r *http.Request) {
+	alias := extractAlias(r)
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	link, ok := h.getOwnedLink(w, r, alias, userID)
+	if !ok {
+		return
+	}
+
+	if _, err := h.metadata.Refresh(r.Context(), link); err != nil {
+		switch {
+		case errors.Is(err, service.ErrMetadataRefreshTooSoon):
+			h.writeError(w, err.Error(), http.StatusTooManyRequests)
+		case errors.Is(err, service.ErrMetadataFetchFailed):
+			// текст ошибки загрузки (адреса, ответы DNS) остается в журнале
+			h.log.Info("failed to fetch destination page", zap.String("alias", alias), zap.Error(err))
+			h.writeError(w, "Failed to fetch destination page", http.StatusBadGateway)
+		default:
+			h.log.Error("failed to refresh link metadata", zap.String("alias", alias), zap.Error(err))
+			h.writeError(w, "Failed to refresh metadata", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// заголовок и описание ссылки могли измениться вместе с метаданными
+	link, err := h.storage.FindLink(r.Context(), link.DomainID, link.Alias)
+	if err != nil {
+		h.log.Error("failed to reload link", zap.String("alias", alias), zap.Error(err))
+		h.writeError(w, "Failed to retrieve link", http.StatusInternalServerError)
+		return
+	}
+
+	h.log.Info("refreshed link metadata", zap.String("alias", alias), zap.Int64("user_id", userID))
+	h.writeJSON(w, h.newLinkInfo(link), http.StatusOK)
+}
@@ -0,0 +1,173 @@
+package http
+
+import (
+	"GURLS-Backend/internal/auth"
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/pkg/qrcode"
+	"bytes"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+const (
+	qrFormatPNG = "png"
+	qrFormatSVG = "svg"
+
+	// ограничения параметров QR кода
+	qrDefaultSize = 512
+	qrMinSize     = 64
+	qrMaxSize     = 2048
+	qrMaxMargin   = 16
+)
+
+// qrCodeOptions параметры QR кода из query string запроса
+type qrCodeOptions struct {
+	Format  string
+	Level   qrcode.Level
+	Render  qrcode.RenderOptions
+	Tracked bool // добавлять метку QR кода, чтобы сканирования учитывались отдельно
+}
+
+// GetLinkQRCode обрабатывает GET /api/links/{alias}/qr
+//
+//	@Summary		Get link QR code
+//	@Description	Render a QR code of the short link as PNG or SVG. With track=true the encoded URL carries the ?qr=1 marker and scans are counted as the qr source in link stats.
+//	@Tags			Links
+//	@Produce		image/png
+//	@Produce		image/svg+xml
+//	@Security		BearerAuth
+//	@Param			alias	path		string				true	"Link alias"
+//	@Param			domain	query		string				false	"Custom domain of the link, omitted for the service domain"
+//	@Param			format	query		string				false	"Image format: png (default) or svg"
+//	@Param			size	query		int					false	"Image width and height in pixels, 64-2048 (default 512)"
+//	@Param			level	query		string				false	"Error correction level: L, M (default), Q or H"
+//	@Param			margin	query		int					false	"Quiet zone in modules, 0-16 (default 4)"
+//	@Param			fg		query		string				false	"Foreground color, hex RRGGBB (default 000000)"
+//	@Param			bg		query		string				false	"Background color, hex RRGGBB (default ffffff)"
+//	@Param			track	query		bool				false	"Append the QR scan marker to the encoded URL"
+//	@Success		200		{file}		file				"QR code image"
+//	@Failure		400		{object}	map[string]string	"Invalid query parameters"
+//	@Failure		401		{object}	map[string]string	"Authentication required"
+//	@Failure		403		{object}	map[string]string	"Access denied"
+//	@Failure		404		{object}	map[string]string	"Link not found"
+//	@Router			/api/links/{alias}/qr [get]
+func (h *LinksHandler) GetLinkQRCode(w http.ResponseWriter, r *http.Request) {
+	alias := extractAlias(r)
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	opts, err := parseQRCodeOptions(r.URL.Query())
+	if err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	link, ok := h.getOwnedLink(w, r, alias, userID)
+	if !ok {
+		return
+	}
+
+	content := h.shortURL(link)
+	if opts.Tracked {
+		content += "?" + domain.QRMarkerParam + "=" + domain.QRMarkerValue
+	}
+
+	code, err := qrcode.Encode([]byte(content), opts.Level)
+	if err != nil {
+		h.log.Error("failed to encode QR code", zap.String("alias", alias), zap.Error(err))
+		h.writeError(w, "Failed to generate QR code", http.StatusInternalServerError)
+		return
+	}
+
+	var buf bytes.Buffer
+	contentType := "image/png"
+	if opts.Format == qrFormatSVG {
+		contentType = "image/svg+xml"
+		err = code.SVG(&buf, opts.Render)
+	} else {
+		err = code.PNG(&buf, opts.Render)
+	}
+	if err != nil {
+		h.log.Error("failed to render QR code", zap.String("alias", alias), zap.Error(err))
+		h.writeError(w, "Failed to generate QR code", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": link.Alias + "." + opts.Format}))
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// parseQRCodeOptions разбирает параметры QR кода из query string
+func parseQRCodeOptions(query url.Values) (qrCodeOptions, error) {
+	opts := qrCodeOptions{
+		Format: qrFormatPNG,
+		Level:  qrcode.LevelM,
+		Render: qrcode.DefaultRenderOptions(qrDefaultSize),
+	}
+
+	if value := query.Get("format"); value != "" {
+		if value != qrFormatPNG && value != qrFormatSVG {
+			return opts, fmt.Errorf("invalid format. Use png or svg")
+		}
+		opts.Format = value
+	}
+	if value := query.Get("size"); value != "" {
+		size, err := strconv.Atoi(value)
+		if err != nil || size < qrMinSize || size > qrMaxSize {
+			return opts, fmt.Errorf("invalid size. Use %d-%d pixels", qrMinSize, qrMaxSize)
+		}
+		opts.Render.Size = size
+	}
+	if value := query.Get("level"); value != "" {
+		level, err := qrcode.ParseLevel(value)
+		if err != nil {
+			return opts, fmt.Errorf("invalid level. Use L, M, Q or H")
+		}
+		opts.Level = level
+	}
+	if value := query.Get("margin"); value != "" {
+		margin, err := strconv.Atoi(value)
+		if err != nil || margin < 0 || margin > qrMaxMargin {
+			return opts, fmt.Errorf("invalid margin. Use 0-%d modules", qrMaxMargin)
+		}
+		opts.Render.Margin = margin
+	}
+	if value := query.Get("fg"); value != "" {
+		color, err := qrcode.ParseColor(value)
+		if err != nil {
+			return opts, fmt.Errorf("invalid fg: %w", err)
+		}
+		opts.Render.Foreground = color
+	}
+	if value := query.Get("bg"); value != "" {
+		color, err := qrcode.ParseColor(value)
+		if err != nil {
+			return opts, fmt.Errorf("invalid bg: %w", err)
+		}
+		opts.Render.Background = color
+	}
+	if opts.Render.Foreground == opts.Render.Background {
+		return opts, fmt.Errorf("fg and bg must be different colors")
+	}
+	if value := query.Get("track"); value != "" {
+		tracked, err := strconv.ParseBool(value)
+		if err != nil {
+			return opts, fmt.Errorf("invalid track. Use true or false")
+		}
+		opts.Tracked = tracked
+	}
+
+	return opts, nil
+}
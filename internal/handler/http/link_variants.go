@@ -0,0 +1,186 @@
+package http
+
+import (
+	"GURLS-Backend/internal/auth"
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/repository"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// LinkVariantRequest вариант A/B теста в запросе замены вариантов
+type LinkVariantRequest struct {
+	ID             int64  `json:"id,omitempty"` // ID существующего варианта, чтобы сохранить его посетителей и статистику
+	Name           string `json:"name,omitempty"`
+	DestinationURL string `json:"destination_url"`
+	Weight         int    `json:"weight"`
+}
+
+// ReplaceLinkVariantsRequest структура запроса замены вариантов A/B теста ссылки.
+// Пустой список завершает тест: посетители снова идут на адрес назначения ссылки.
+type ReplaceLinkVariantsRequest struct {
+	Variants []LinkVariantRequest `json:"variants"`
+}
+
+// LinkVariantInfo информация о варианте A/B теста
+type LinkVariantInfo struct {
+	ID             int64  `json:"id"`
+	Name           string `json:"name,omitempty"`
+	DestinationURL string `json:"destination_url"`
+	Weight         int    `json:"weight"`
+}
+
+// ListLinkVariantsResponse структура ответа со списком вариантов A/B теста ссылки
+type ListLinkVariantsResponse struct {
+	Alias    string            `json:"alias"`
+	Variants []LinkVariantInfo `json:"variants"`
+}
+
+// ListLinkVariants возвращает варианты A/B теста ссылки
+//
+//	@Summary		List A/B variants
+//	@Description	Get the link's weighted destination variants
+//	@Tags			Links
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			alias	path		string						true	"Link alias"
+//	@Param			domain	query		string						false	"Custom domain of the link, omitted for the service domain"
+//	@Success		200		{object}	ListLinkVariantsResponse	"A/B variants"
+//	@Failure		401		{object}	map[string]string			"Authentication required"
+//	@Failure		403		{object}	map[string]string			"Access denied"
+//	@Failure		404		{object}	map[string]string			"Link not found"
+//	@Router			/api/links/{alias}/variants [get]
+func (h *LinksHandler) ListLinkVariants(w http.ResponseWriter, r *http.Request) {
+	alias := extractAlias(r)
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	link, ok := h.getOwnedLink(w, r, alias, userID)
+	if !ok {
+		return
+	}
+
+	variants, err := h.storage.ListLinkVariants(r.Context(), link.ID)
+	if err != nil {
+		h.log.Error("failed to list link variants", zap.String("alias", alias), zap.Error(err))
+		h.writeError(w, "Failed to retrieve variants", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, newListLinkVariantsResponse(link.Alias, variants), http.StatusOK)
+}
+
+// ReplaceLinkVariants заменяет варианты A/B теста ссылки
+//
+//	@Summary		Replace A/B variants
+//	@Description	Replace the link's destination variants. Visitors matching no redirect rule are split between the variants by weight and keep their variant via a cookie. Pass the id of an existing variant to keep its visitors and click statistics.
+//	@Tags			Links
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			alias	path		string						true	"Link alias"
+//	@Param			domain	query		string						false	"Custom domain of the link, omitted for the service domain"
+//	@Param			request	body		ReplaceLinkVariantsRequest	true	"A/B variants"
+//	@Success		200		{object}	ListLinkVariantsResponse	"Updated A/B variants"
+//	@Failure		400		{object}	map[string]string			"Invalid request data"
+//	@Failure		401		{object}	map[string]string			"Authentication required"
+//	@Failure		403		{object}	map[string]string			"Access denied"
+//	@Failure		404		{object}	map[string]string			"Link not found"
+//	@Router			/api/links/{alias}/variants [put]
+func (h *LinksHandler) ReplaceLinkVariants(w http.ResponseWriter, r *http.Request) {
+	alias := extractAlias(r)
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	var req ReplaceLinkVariantsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "Invalid request format", http.StatusBadRequest)
+		return
+	}
+
+	link, ok := h.getOwnedLink(w, r, alias, userID)
+	if !ok {
+		return
+	}
+
+	variants, err := newLinkVariants(req.Variants)
+	if err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	for i := range variants {
+		destinationURL, ok := h.checkDestination(w, r, fmt.Sprintf("destination_url in variant %d", i+1), variants[i].DestinationURL)
+		if !ok {
+			return
+		}
+		variants[i].DestinationURL = destinationURL
+	}
+
+	if err := h.storage.ReplaceLinkVariants(r.Context(), link.ID, variants); err != nil {
+		if err == repository.ErrLinkVariantNotFound {
+			h.writeError(w, "Variant not found", http.StatusBadRequest)
+			return
+		}
+		h.log.Error("failed to replace link variants", zap.String("alias", alias), zap.Error(err))
+		h.writeError(w, "Failed to update variants", http.StatusInternalServerError)
+		return
+	}
+
+	h.log.Info("updated link variants", zap.String("alias", alias), zap.Int("count", len(variants)))
+	h.writeJSON(w, newListLinkVariantsResponse(link.Alias, variants), http.StatusOK)
+}
+
+// newLinkVariants проверяет варианты из запроса и преобразует их в доменные
+func newLinkVariants(requests []LinkVariantRequest) ([]domain.LinkVariant, error) {
+	if len(requests) > domain.MaxLinkVariantsPerLink {
+		return nil, fmt.Errorf("too many variants (max %d)", domain.MaxLinkVariantsPerLink)
+	}
+
+	variants := make([]domain.LinkVariant, 0, len(requests))
+	for i, req := range requests {
+		name := strings.TrimSpace(req.Name)
+		if len(name) > 100 {
+			return nil, fmt.Errorf("variant %d: name is too long (max 100 characters)", i+1)
+		}
+		if req.Weight < 1 || req.Weight > domain.MaxLinkVariantWeight {
+			return nil, fmt.Errorf("variant %d: %w", i+1, domain.ErrInvalidVariantWeight)
+		}
+		if !isValidRedirectURL(req.DestinationURL) {
+			return nil, fmt.Errorf("variant %d: invalid destination_url, use an absolute http(s) URL", i+1)
+		}
+		variants = append(variants, domain.LinkVariant{
+			ID:             req.ID,
+			Name:           name,
+			DestinationURL: req.DestinationURL,
+			Weight:         req.Weight,
+		})
+	}
+	return variants, nil
+}
+
+// newListLinkVariantsResponse преобразует варианты A/B теста в формат ответа API
+func newListLinkVariantsResponse(alias string, variants []domain.LinkVariant) ListLinkVariantsResponse {
+	response := ListLinkVariantsResponse{
+		Alias:    alias,
+		Variants: make([]LinkVariantInfo, len(variants)),
+	}
+	for i, variant := range variants {
+		response.Variants[i] = LinkVariantInfo{
+			ID:             variant.ID,
+			Name:           variant.Name,
+			DestinationURL: variant.DestinationURL,
+			Weight:         variant.Weight,
+		}
+	}
+	return response
+}
@@ -5,10 +5,15 @@ import (
 	"GURLS-Backend/internal/domain"
 	"GURLS-Backend/internal/repository"
 	"GURLS-Backend/internal/service"
+	"GURLS-Backend/pkg/urlforward"
+	"GURLS-Backend/pkg/urlpolicy"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,26 +24,53 @@ import (
 type LinksHandler struct {
 	storage           repository.Storage
 	urlShortener      *service.URLShortenerService
+	urlPolicy         *urlpolicy.Policy
+	reputation        *service.ReputationService
+	metadata          *service.MetadataService
+	passwordService   *auth.PasswordService
 	log               *zap.Logger
 	baseURL           string
 }
 
 // NewLinksHandler создает новый обработчик ссылок
-func NewLinksHandler(storage repository.Storage, urlShortener *service.URLShortenerService, log *zap.Logger, baseURL string) *LinksHandler {
+func NewLinksHandler(storage repository.Storage, urlShortener *service.URLShortenerService, urlPolicy *urlpolicy.Policy, reputation *service.ReputationService, metadata *service.MetadataService, passwordService *auth.PasswordService, log *zap.Logger, baseURL string) *LinksHandler {
 	return &LinksHandler{
-		storage:      storage,
-		urlShortener: urlShortener,
-		log:          log,
-		baseURL:      baseURL,
+		storage:         storage,
+		urlShortener:    urlShortener,
+		urlPolicy:       urlPolicy,
+		reputation:      reputation,
+		metadata:        metadata,
+		passwordService: passwordService,
+		log:             log,
+		baseURL:         baseURL,
 	}
 }
 
 // CreateLinkRequest структура запроса создания ссылки
 type CreateLinkRequest struct {
-	OriginalURL string `json:"original_url"`
-	Title       string `json:"title,omitempty"`
-	CustomAlias string `json:"custom_alias,omitempty"`
-	ExpiresAt   string `json:"expires_at,omitempty"`
+	OriginalURL string   `json:"original_url"`
+	Title       string   `json:"title,omitempty"`
+	CustomAlias string   `json:"custom_alias,omitempty"`
+	Domain      string   `json:"domain,omitempty"`    // подтвержденный собственный домен, по умолчанию домен сервиса
+	StartsAt    string   `json:"starts_at,omitempty"` // до этого момента вместо редиректа показывается страница ожидания
+	ExpiresAt   string   `json:"expires_at,omitempty"`
+	Timezone    string   `json:"timezone,omitempty"` // IANA пояс для правил по времени, по умолчанию UTC
+	Password    string   `json:"password,omitempty"`
+	MaxClicks   *int     `json:"max_clicks,omitempty"`
+	FallbackURL string   `json:"fallback_url,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	// Передача query string и хвоста пути в адрес назначения
+	ForwardQuery  bool   `json:"forward_query,omitempty"`
+	QueryConflict string `json:"query_conflict,omitempty"` // keep (по умолчанию), override или append
+	ForwardPath   bool   `json:"forward_path,omitempty"`
+	// Способ редиректа: 301, 302 (по умолчанию), 307, 308, meta_refresh или interstitial;
+	// tracking_pixels - адреса пикселей аналитики, которые загружает промежуточная страница
+	RedirectMode   string   `json:"redirect_mode,omitempty"`
+	TrackingPixels []string `json:"tracking_pixels,omitempty"`
+	// UTM метки, добавляемые к адресу назначения; незаданные берутся из настроек аккаунта
+	UTM domain.UTMParams `json:"utm,omitempty"`
+	// Карточка ссылки для мессенджеров и соцсетей вместо превью страницы назначения
+	SocialPreview domain.SocialPreview `json:"social_preview,omitempty"`
 }
 
 // CreateLinkResponse структура ответа создания ссылки
@@ -49,28 +81,102 @@ type CreateLinkResponse struct {
 
 // LinkInfo информация о ссылке
 type LinkInfo struct {
-	Alias       string `json:"alias"`
-	OriginalURL string `json:"original_url"`
-	Title       string `json:"title,omitempty"`
-	ClickCount  int64  `json:"click_count"`
-	CreatedAt   string `json:"created_at"`
-	ExpiresAt   string `json:"expires_at,omitempty"`
+	Alias          string           `json:"alias"`
+	Domain         string           `json:"domain,omitempty"` // собственный домен ссылки
+	ShortURL       string           `json:"short_url"`
+	OriginalURL    string           `json:"original_url"`
+	Title          string           `json:"title,omitempty"`
+	Description    string           `json:"description,omitempty"`
+	ClickCount     int64            `json:"click_count"`
+	CreatedAt      string           `json:"created_at"`
+	StartsAt       string           `json:"starts_at,omitempty"`
+	ExpiresAt      string           `json:"expires_at,omitempty"`
+	Timezone       string           `json:"timezone,omitempty"`
+	HasPassword    bool             `json:"has_password"`
+	MaxClicks      *int             `json:"max_clicks,omitempty"`
+	FallbackURL    string           `json:"fallback_url,omitempty"`
+	IsActive       bool             `json:"is_active"`
+	FlaggedAt      string           `json:"flagged_at,omitempty"`  // ссылка отключена: адрес назначения в списках угроз
+	FlagReason     string           `json:"flag_reason,omitempty"` // категория угрозы
+	DeletedAt      string           `json:"deleted_at,omitempty"`
+	Tags           []string         `json:"tags,omitempty"`
+	ForwardQuery   bool             `json:"forward_query"`
+	QueryConflict  string           `json:"query_conflict,omitempty"`
+	ForwardPath    bool             `json:"forward_path"`
+	RedirectMode   string           `json:"redirect_mode"`
+	TrackingPixels []string         `json:"tracking_pixels,omitempty"`
+	UTM            domain.UTMParams `json:"utm"`
+	// Карточка ссылки для мессенджеров и соцсетей, заданная владельцем
+	SocialPreview *domain.SocialPreview `json:"social_preview,omitempty"`
+	// Метаданные страницы назначения; нет, пока страница не загружалась
+	Metadata *domain.LinkMetadata `json:"metadata,omitempty"`
 }
 
 // ListLinksResponse структура ответа списка ссылок
 type ListLinksResponse struct {
-	Links []LinkInfo `json:"links"`
+	Links      []LinkInfo `json:"links"`
+	Total      int64      `json:"total"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+// UpdateLinkRequest структура запроса частичного обновления ссылки.
+// Отсутствующие поля не изменяются; пустая строка в starts_at, expires_at или fallback_url снимает ограничение,
+// пустая строка в timezone возвращает UTC, пустой список tags снимает все теги,
+// пустой объект social_preview возвращает карточку для мессенджеров по умолчанию.
+type UpdateLinkRequest struct {
+	OriginalURL    *string               `json:"original_url,omitempty"`
+	Title          *string               `json:"title,omitempty"`
+	Description    *string               `json:"description,omitempty"`
+	StartsAt       *string               `json:"starts_at,omitempty"`
+	ExpiresAt      *string               `json:"expires_at,omitempty"`
+	FallbackURL    *string               `json:"fallback_url,omitempty"`
+	Timezone       *string               `json:"timezone,omitempty"`
+	IsActive       *bool                 `json:"is_active,omitempty"`
+	Tags           *[]string             `json:"tags,omitempty"`
+	ForwardQuery   *bool                 `json:"forward_query,omitempty"`
+	QueryConflict  *string               `json:"query_conflict,omitempty"`
+	ForwardPath    *bool                 `json:"forward_path,omitempty"`
+	RedirectMode   *string               `json:"redirect_mode,omitempty"`
+	TrackingPixels *[]string             `json:"tracking_pixels,omitempty"` // заменяет список целиком
+	UTM            *domain.UTMParams     `json:"utm,omitempty"`             // заменяет набор меток целиком
+	SocialPreview  *domain.SocialPreview `json:"social_preview,omitempty"`  // заменяет карточку целиком
+}
+
+// LinkRevisionInfo информация о ревизии адреса назначения
+type LinkRevisionInfo struct {
+	ID          int64  `json:"id"`
+	OriginalURL string `json:"original_url"`
+	PreviousURL string `json:"previous_url,omitempty"`
+	ChangedBy   int64  `json:"changed_by"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// ListLinkRevisionsResponse структура ответа истории изменений ссылки
+type ListLinkRevisionsResponse struct {
+	Alias     string             `json:"alias"`
+	Revisions []LinkRevisionInfo `json:"revisions"`
 }
 
 // GetStatsResponse структура ответа статистики
 type GetStatsResponse struct {
-	Alias           string            `json:"alias"`
-	OriginalURL     string            `json:"original_url"`
-	ClickCount      int64             `json:"click_count"`
-	Title           string            `json:"title,omitempty"`
-	ExpiresAt       string            `json:"expires_at,omitempty"`
-	ClicksByDevice  map[string]int64  `json:"clicks_by_device"`
-	CreatedAt       string            `json:"created_at"`
+	Alias          string           `json:"alias"`
+	OriginalURL    string           `json:"original_url"`
+	ClickCount     int64            `json:"click_count"`
+	Title          string           `json:"title,omitempty"`
+	ExpiresAt      string           `json:"expires_at,omitempty"`
+	ClicksByDevice map[string]int64 `json:"clicks_by_device"`
+	ClicksBySource map[string]int64 `json:"clicks_by_source"` // qr - переходы по QR коду, direct - остальные
+	CreatedAt      string           `json:"created_at"`
+	MaxClicks      *int             `json:"max_clicks,omitempty"`
+	IsScheduled    bool             `json:"is_scheduled"` // время запуска еще не наступило
+	IsExpired      bool             `json:"is_expired"`
+	IsExhausted    bool             `json:"is_exhausted"`
+	// ClicksByCampaign клики по UTM меткам входящих переходов (source/medium/campaign)
+	ClicksByCampaign []repository.CampaignClicks `json:"clicks_by_campaign"`
+	// ClicksByRule клики по сработавшим правилам редиректа; rule_id null - основной адрес ссылки
+	ClicksByRule []repository.RuleClicks `json:"clicks_by_rule"`
+	// ClicksByVariant клики по вариантам A/B теста; variant_id null - переходы без варианта
+	ClicksByVariant []repository.VariantClicks `json:"clicks_by_variant"`
 }
 
 // CreateLink создает новую короткую ссылку
@@ -83,10 +189,11 @@ type GetStatsResponse struct {
 //	@Security		BearerAuth
 //	@Param			request	body		CreateLinkRequest	true	"Link creation request"
 //	@Success		201		{object}	CreateLinkResponse	"Link created successfully"
-//	@Failure		400		{object}	map[string]string	"Invalid request data"
+//	@Failure		400		{object}	map[string]string	"Invalid request data or custom alias rejected by the alias policy (code: alias_too_short, alias_too_long, alias_invalid_characters, alias_reserved, alias_blocked, alias_profane) or destination rejected by the URL policy (code: url_invalid, url_scheme_not_allowed, url_credentials_not_allowed, url_self_reference, url_shortener_not_allowed, url_private_network, url_malicious)"
 //	@Failure		401		{object}	map[string]string	"Authentication required"
 //	@Failure		403		{object}	map[string]string	"Subscription limit reached"
 //	@Failure		409		{object}	map[string]string	"Alias already exists"
+//	@Failure		503		{object}	map[string]string	"Failed to generate a unique alias"
 //	@Router			/api/shorten [post]
 func (h *LinksHandler) CreateLink(w http.ResponseWriter, r *http.Request) {
 	// Получаем ID пользователя из контекста (установлен JWT middleware)
@@ -120,14 +227,28 @@ func (h *LinksHandler) CreateLink(w http.ResponseWriter, r *http.Request) {
 		h.writeError(w, "Original URL is required", http.StatusBadRequest)
 		return
 	}
+	originalURL, ok := h.checkDestination(w, r, "original_url", req.OriginalURL)
+	if !ok {
+		return
+	}
 
 	// Создаем объект ссылки
 	link := &domain.Link{
 		UserID:      userID,
-		OriginalURL: req.OriginalURL,
+		OriginalURL: originalURL,
 		IsActive:    true,
 	}
 
+	// Ссылка на собственном домене: алиас проверяется на уникальность в пределах домена
+	var customDomain *domain.CustomDomain
+	if req.Domain != "" {
+		var ok bool
+		if customDomain, ok = h.getLinkDomain(w, r, userID, req.Domain); !ok {
+			return
+		}
+		link.DomainID = &customDomain.ID
+	}
+
 	// Устанавливаем title если он предоставлен
 	if req.Title != "" {
 		link.Title = &req.Title
@@ -149,7 +270,40 @@ func (h *LinksHandler) CreateLink(w http.ResponseWriter, r *http.Request) {
 		link.Alias = req.CustomAlias
 	}
 
-	// Обрабатываем дату истечения
+	// Обрабатываем пароль для защищенной ссылки
+	if req.Password != "" {
+		hasPasswordAccess, err := h.checkFeatureAccess(r.Context(), userID, "password_protected_links")
+		if err != nil {
+			h.log.Error("failed to check password protection access", zap.Int64("user_id", userID), zap.Error(err))
+			h.writeError(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		if !hasPasswordAccess {
+			h.writeError(w, "Password-protected links are not available in your current subscription plan. Please upgrade to use this feature.", http.StatusForbidden)
+			return
+		}
+		if err := auth.IsValidPassword(req.Password); err != nil {
+			h.writeError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		passwordHash, err := h.passwordService.HashPassword(req.Password)
+		if err != nil {
+			h.log.Error("failed to hash link password", zap.Error(err))
+			h.writeError(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		link.PasswordHash = &passwordHash
+	}
+
+	// Обрабатываем время запуска и дату истечения
+	if req.StartsAt != "" {
+		startsAt, err := time.Parse(time.RFC3339, req.StartsAt)
+		if err != nil {
+			h.writeError(w, "Invalid starts_at format. Use RFC3339 format", http.StatusBadRequest)
+			return
+		}
+		link.StartsAt = &startsAt
+	}
 	if req.ExpiresAt != "" {
 		expiresAt, err := time.Parse(time.RFC3339, req.ExpiresAt)
 		if err != nil {
@@ -158,6 +312,95 @@ func (h *LinksHandler) CreateLink(w http.ResponseWriter, r *http.Request) {
 		}
 		link.ExpiresAt = &expiresAt
 	}
+	if !isValidSchedule(link) {
+		h.writeError(w, "expires_at must be after starts_at", http.StatusBadRequest)
+		return
+	}
+
+	// Часовой пояс ссылки для правил редиректа по времени
+	if _, err := domain.LoadTimezone(req.Timezone); err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	link.Timezone = req.Timezone
+
+	// Обрабатываем лимит переходов
+	if req.MaxClicks != nil {
+		if *req.MaxClicks <= 0 {
+			h.writeError(w, "max_clicks must be a positive number", http.StatusBadRequest)
+			return
+		}
+		link.MaxClicks = req.MaxClicks
+	}
+
+	// Обрабатываем резервный URL для истекших и исчерпанных ссылок
+	if req.FallbackURL != "" {
+		fallbackURL, ok := h.checkDestination(w, r, "fallback_url", req.FallbackURL)
+		if !ok {
+			return
+		}
+		link.FallbackURL = &fallbackURL
+	}
+
+	// Настраиваем передачу query string и пути в адрес назначения
+	if req.QueryConflict != "" && !urlforward.ConflictPolicy(req.QueryConflict).IsValid() {
+		h.writeError(w, "Invalid query_conflict. Use keep, override or append", http.StatusBadRequest)
+		return
+	}
+	link.ForwardQuery = req.ForwardQuery
+	link.QueryConflict = string(urlforward.ConflictKeepDestination)
+	if req.QueryConflict != "" {
+		link.QueryConflict = req.QueryConflict
+	}
+	link.ForwardPath = req.ForwardPath
+
+	// Способ редиректа и пиксели аналитики промежуточной страницы
+	if !h.checkRedirectMode(w, r, userID, domain.RedirectMode(req.RedirectMode)) {
+		return
+	}
+	link.RedirectMode = string(domain.DefaultRedirectMode)
+	if req.RedirectMode != "" {
+		link.RedirectMode = req.RedirectMode
+	}
+	trackingPixels, err := domain.NormalizeTrackingPixels(req.TrackingPixels)
+	if err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	link.TrackingPixels = trackingPixels
+
+	// UTM метки ссылки дополняются метками по умолчанию из настроек аккаунта
+	utm, err := req.UTM.Normalize()
+	if err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	user, err := h.storage.GetUserByID(r.Context(), userID)
+	if err != nil {
+		h.log.Error("failed to get user for UTM defaults", zap.Int64("user_id", userID), zap.Error(err))
+		h.writeError(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	link.UTM = utm.WithDefaults(user.UTMDefaults)
+
+	// Карточка для мессенджеров; картинка проверяется как адрес назначения
+	socialPreview, ok := h.normalizeSocialPreview(w, r, req.SocialPreview)
+	if !ok {
+		return
+	}
+	link.SocialPreview = socialPreview
+
+	// Привязываем теги; недостающие теги создаются вместе со ссылкой, а не до ее проверок
+	if len(req.Tags) > 0 {
+		names, err := domain.NormalizeTagNames(req.Tags)
+		if err != nil {
+			h.writeError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, name := range names {
+			link.Tags = append(link.Tags, domain.Tag{UserID: userID, Name: name})
+		}
+	}
 
 	// Используем сервис для создания ссылки
 	var customAlias *string
@@ -171,22 +414,63 @@ func (h *LinksHandler) CreateLink(w http.ResponseWriter, r *http.Request) {
 			h.writeError(w, "Alias already exists", http.StatusConflict)
 			return
 		}
+		if errors.Is(err, repository.ErrLinkLimitReached) {
+			h.writeError(w, "Subscription limit reached. Please upgrade your plan to create more links.", http.StatusForbidden)
+			return
+		}
+		var policyErr *service.AliasPolicyError
+		if errors.As(err, &policyErr) {
+			h.writeErrorCode(w, policyErr.Message, policyErr.Code, http.StatusBadRequest)
+			return
+		}
+		if err == service.ErrAliasGenerationFailed {
+			h.log.Warn("alias generation exhausted retries", zap.Error(err))
+			h.writeError(w, "Failed to generate a unique alias, please try again", http.StatusServiceUnavailable)
+			return
+		}
 		h.log.Error("failed to create link", zap.Error(err))
 		h.writeError(w, "Failed to create link", http.StatusInternalServerError)
 		return
 	}
 
+	link.Domain = customDomain
+
+	// Пустые заголовок и описание заполнятся из страницы назначения в фоне
+	if link.NeedsMetadata() {
+		h.metadata.Enqueue(link)
+	}
+
 	// Отправляем ответ
 	response := CreateLinkResponse{
 		Alias:    alias,
-		ShortURL: h.baseURL + "/" + alias,
+		ShortURL: h.shortURL(link),
 	}
 
 	h.log.Info("created link", zap.String("alias", alias), zap.Int64("user_id", userID))
 	h.writeJSON(w, response, http.StatusCreated)
 }
 
-// ListLinks возвращает список ссылок пользователя
+// ListLinks возвращает страницу ссылок пользователя
+//
+//	@Summary		List links
+//	@Description	Get a page of the user's links with optional filters and sorting
+//	@Tags			Links
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			created_from	query		string				false	"Created at or after (RFC3339)"
+//	@Param			created_to		query		string				false	"Created before (RFC3339)"
+//	@Param			status			query		string				false	"active, scheduled, paused, expired or exhausted"
+//	@Param			has_password	query		bool				false	"Only links with (true) or without (false) a password"
+//	@Param			q				query		string				false	"Search in alias, destination, title and description"
+//	@Param			tag				query		string				false	"Only links with the tag"
+//	@Param			sort			query		string				false	"created_at (default), click_count or title"
+//	@Param			order			query		string				false	"asc or desc (default desc)"
+//	@Param			cursor			query		string				false	"Cursor from the previous page"
+//	@Param			limit			query		int					false	"Page size (default 20, max 100)"
+//	@Success		200				{object}	ListLinksResponse	"Links page"
+//	@Failure		400				{object}	map[string]string	"Invalid query parameters"
+//	@Failure		401				{object}	map[string]string	"Authentication required"
+//	@Router			/api/links [get]
 func (h *LinksHandler) ListLinks(w http.ResponseWriter, r *http.Request) {
 	// Получаем ID пользователя из контекста
 	userID, ok := auth.GetUserIDFromContext(r.Context())
@@ -195,34 +479,34 @@ func (h *LinksHandler) ListLinks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	opts, err := parseLinkListOptions(r)
+	if err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// Получаем ссылки пользователя
-	links, err := h.storage.ListUserLinks(r.Context(), userID)
+	page, err := h.storage.ListUserLinks(r.Context(), userID, opts)
 	if err != nil {
+		if err == repository.ErrInvalidCursor {
+			h.writeError(w, "Invalid cursor", http.StatusBadRequest)
+			return
+		}
 		h.log.Error("failed to list user links", zap.Int64("user_id", userID), zap.Error(err))
 		h.writeError(w, "Failed to retrieve links", http.StatusInternalServerError)
 		return
 	}
 
 	// Преобразуем в ответ
-	linkInfos := make([]LinkInfo, len(links))
-	for i, link := range links {
-		linkInfo := LinkInfo{
-			Alias:       link.Alias,
-			OriginalURL: link.OriginalURL,
-			ClickCount:  int64(link.ClickCount),
-			CreatedAt:   link.CreatedAt.Format(time.RFC3339),
-		}
-		if link.Title != nil {
-			linkInfo.Title = *link.Title
-		}
-		if link.ExpiresAt != nil {
-			linkInfo.ExpiresAt = link.ExpiresAt.Format(time.RFC3339)
-		}
-		linkInfos[i] = linkInfo
+	linkInfos := make([]LinkInfo, len(page.Links))
+	for i, link := range page.Links {
+		linkInfos[i] = h.newLinkInfo(link)
 	}
 
 	response := ListLinksResponse{
-		Links: linkInfos,
+		Links:      linkInfos,
+		Total:      page.Total,
+		NextCursor: page.NextCursor,
 	}
 
 	h.writeJSON(w, response, http.StatusOK)
@@ -238,8 +522,14 @@ func (h *LinksHandler) GetStats(w http.ResponseWriter, r *http.Request) {
 	}
 	alias := pathParts[2]
 
+	// Ссылка на собственном домене указывается параметром ?domain=
+	domainID, ok := h.requestDomainID(w, r)
+	if !ok {
+		return
+	}
+
 	// Получаем ссылку
-	link, err := h.storage.GetLink(r.Context(), alias)
+	link, err := h.storage.GetLink(r.Context(), domainID, alias)
 	if err != nil {
 		if err == repository.ErrAliasNotFound {
 			h.writeError(w, "Link not found", http.StatusNotFound)
@@ -264,13 +554,49 @@ func (h *LinksHandler) GetStats(w http.ResponseWriter, r *http.Request) {
 		clicksByDevice = make(map[string]int64) // Возвращаем пустую карту в случае ошибки
 	}
 
+	// Получаем статистику по источникам: QR код или прямой переход
+	clicksBySource, err := h.storage.GetClicksBySource(r.Context(), link.ID)
+	if err != nil {
+		h.log.Error("failed to get clicks by source", zap.Int64("link_id", link.ID), zap.Error(err))
+		clicksBySource = make(map[string]int64)
+	}
+
+	// Получаем статистику по кампаниям входящих переходов
+	clicksByCampaign, err := h.storage.GetClicksByCampaign(r.Context(), link.ID)
+	if err != nil {
+		h.log.Error("failed to get clicks by campaign", zap.Int64("link_id", link.ID), zap.Error(err))
+		clicksByCampaign = []repository.CampaignClicks{}
+	}
+
+	// Получаем статистику по правилам редиректа
+	clicksByRule, err := h.storage.GetClicksByRule(r.Context(), link.ID)
+	if err != nil {
+		h.log.Error("failed to get clicks by rule", zap.Int64("link_id", link.ID), zap.Error(err))
+		clicksByRule = []repository.RuleClicks{}
+	}
+
+	// Получаем статистику по вариантам A/B теста
+	clicksByVariant, err := h.storage.GetClicksByVariant(r.Context(), link.ID)
+	if err != nil {
+		h.log.Error("failed to get clicks by variant", zap.Int64("link_id", link.ID), zap.Error(err))
+		clicksByVariant = []repository.VariantClicks{}
+	}
+
 	// Формируем ответ
 	response := GetStatsResponse{
-		Alias:           link.Alias,
-		OriginalURL:     link.OriginalURL,
-		ClickCount:      int64(link.ClickCount),
-		ClicksByDevice:  clicksByDevice,
-		CreatedAt:       link.CreatedAt.Format(time.RFC3339),
+		Alias:            link.Alias,
+		OriginalURL:      link.OriginalURL,
+		ClickCount:       int64(link.ClickCount),
+		ClicksByDevice:   clicksByDevice,
+		ClicksBySource:   clicksBySource,
+		CreatedAt:        link.CreatedAt.Format(time.RFC3339),
+		MaxClicks:        link.MaxClicks,
+		IsScheduled:      link.IsNotStarted(),
+		IsExpired:        link.IsExpired(),
+		IsExhausted:      link.IsExhausted(),
+		ClicksByCampaign: clicksByCampaign,
+		ClicksByRule:     clicksByRule,
+		ClicksByVariant:  clicksByVariant,
 	}
 	
 	if link.Title != nil {
@@ -284,13 +610,14 @@ func (h *LinksHandler) GetStats(w http.ResponseWriter, r *http.Request) {
 	h.writeJSON(w, response, http.StatusOK)
 }
 
-// DeleteLink удаляет ссылку
+// DeleteLink перемещает ссылку в корзину
 //
 //	@Summary		Delete a link
-//	@Description	Delete a specific link by alias
+//	@Description	Move a link to the trash. The alias stays reserved until the trash retention window passes.
 //	@Tags			Links
 //	@Security		BearerAuth
 //	@Param			alias	path	string	true	"Link alias"
+//	@Param			domain	query	string	false	"Custom domain of the link, omitted for the service domain"
 //	@Success		204		"Link deleted successfully"
 //	@Failure		401		{object}	map[string]string	"Authentication required"
 //	@Failure		403		{object}	map[string]string	"Access denied"
@@ -313,36 +640,561 @@ func (h *LinksHandler) DeleteLink(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Проверяем, что ссылка существует и принадлежит пользователю
-	link, err := h.storage.GetLink(r.Context(), alias)
+	link, ok := h.getOwnedLink(w, r, alias, userID)
+	if !ok {
+		return
+	}
+
+	// Перемещаем ссылку в корзину
+	if err := h.storage.DeleteLink(r.Context(), link.DomainID, alias); err != nil {
+		h.log.Error("failed to delete link", zap.String("alias", alias), zap.Error(err))
+		h.writeError(w, "Failed to delete link", http.StatusInternalServerError)
+		return
+	}
+
+	h.log.Info("deleted link", zap.String("alias", alias), zap.Int64("user_id", userID))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListTrash возвращает ссылки пользователя, находящиеся в корзине
+//
+//	@Summary		List deleted links
+//	@Description	Get links in the trash that can still be restored
+//	@Tags			Links
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	ListLinksResponse	"Deleted links"
+//	@Failure		401	{object}	map[string]string	"Authentication required"
+//	@Router			/api/links/trash [get]
+func (h *LinksHandler) ListTrash(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	links, err := h.storage.ListDeletedLinks(r.Context(), userID)
+	if err != nil {
+		h.log.Error("failed to list deleted links", zap.Int64("user_id", userID), zap.Error(err))
+		h.writeError(w, "Failed to retrieve links", http.StatusInternalServerError)
+		return
+	}
+
+	linkInfos := make([]LinkInfo, len(links))
+	for i, link := range links {
+		linkInfos[i] = h.newLinkInfo(link)
+	}
+
+	h.writeJSON(w, ListLinksResponse{Links: linkInfos}, http.StatusOK)
+}
+
+// RestoreLink восстанавливает ссылку из корзины
+//
+//	@Summary		Restore a deleted link
+//	@Description	Move a link from the trash back to the active list
+//	@Tags			Links
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			alias	path		string				true	"Link alias"
+//	@Param			domain	query		string				false	"Custom domain of the link, omitted for the service domain"
+//	@Success		200		{object}	LinkInfo			"Restored link"
+//	@Failure		401		{object}	map[string]string	"Authentication required"
+//	@Failure		403		{object}	map[string]string	"Access denied"
+//	@Failure		404		{object}	map[string]string	"Link not found in trash"
+//	@Router			/api/links/{alias}/restore [post]
+func (h *LinksHandler) RestoreLink(w http.ResponseWriter, r *http.Request) {
+	alias := extractAlias(r)
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	domainID, ok := h.requestDomainID(w, r)
+	if !ok {
+		return
+	}
+
+	link, err := h.storage.GetDeletedLink(r.Context(), domainID, alias)
 	if err != nil {
 		if err == repository.ErrAliasNotFound {
-			h.writeError(w, "Link not found", http.StatusNotFound)
+			h.writeError(w, "Link not found in trash", http.StatusNotFound)
 			return
 		}
-		h.log.Error("failed to get link for deletion", zap.String("alias", alias), zap.Error(err))
+		h.log.Error("failed to get deleted link", zap.String("alias", alias), zap.Error(err))
 		h.writeError(w, "Failed to retrieve link", http.StatusInternalServerError)
 		return
 	}
 
-	// Проверяем права доступа
 	if link.UserID != userID {
 		h.writeError(w, "Access denied", http.StatusForbidden)
 		return
 	}
 
-	// Удаляем ссылку
-	if err := h.storage.DeleteLink(r.Context(), alias); err != nil {
-		h.log.Error("failed to delete link", zap.String("alias", alias), zap.Error(err))
-		h.writeError(w, "Failed to delete link", http.StatusInternalServerError)
+	if err := h.storage.RestoreLink(r.Context(), domainID, alias); err != nil {
+		if err == repository.ErrAliasNotFound {
+			h.writeError(w, "Link not found in trash", http.StatusNotFound)
+			return
+		}
+		h.log.Error("failed to restore link", zap.String("alias", alias), zap.Error(err))
+		h.writeError(w, "Failed to restore link", http.StatusInternalServerError)
 		return
 	}
+	link.DeletedAt = nil
 
-	h.log.Info("deleted link", zap.String("alias", alias), zap.Int64("user_id", userID))
-	w.WriteHeader(http.StatusNoContent)
+	h.log.Info("restored link", zap.String("alias", alias), zap.Int64("user_id", userID))
+	h.writeJSON(w, h.newLinkInfo(link), http.StatusOK)
+}
+
+// UpdateLink частично обновляет ссылку
+//
+//	@Summary		Update a link
+//	@Description	Change destination, title, description, expiration or pause a link. Destination changes are recorded in the revision history.
+//	@Tags			Links
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			alias	path		string				true	"Link alias"
+//	@Param			domain	query		string				false	"Custom domain of the link, omitted for the service domain"
+//	@Param			request	body		UpdateLinkRequest	true	"Fields to update"
+//	@Success		200		{object}	LinkInfo			"Updated link"
+//	@Failure		400		{object}	map[string]string	"Invalid request data or destination rejected by the URL policy or listed as unsafe"
+//	@Failure		401		{object}	map[string]string	"Authentication required"
+//	@Failure		403		{object}	map[string]string	"Access denied or enabling a link disabled after a threat-list match (code: link_flagged)"
+//	@Failure		404		{object}	map[string]string	"Link not found"
+//	@Router			/api/links/{alias} [patch]
+func (h *LinksHandler) UpdateLink(w http.ResponseWriter, r *http.Request) {
+	alias := extractAlias(r)
+	if alias == "" {
+		h.writeError(w, "Alias is required", http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	var req UpdateLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Debug("invalid update link request", zap.Error(err))
+		h.writeError(w, "Invalid request format", http.StatusBadRequest)
+		return
+	}
+
+	link, ok := h.getOwnedLink(w, r, alias, userID)
+	if !ok {
+		return
+	}
+	previousURL := link.OriginalURL
+
+	if req.OriginalURL != nil {
+		if *req.OriginalURL == "" {
+			h.writeError(w, "Original URL cannot be empty", http.StatusBadRequest)
+			return
+		}
+		originalURL, ok := h.checkDestination(w, r, "original_url", *req.OriginalURL)
+		if !ok {
+			return
+		}
+		link.OriginalURL = originalURL
+	}
+	if req.Title != nil {
+		link.Title = optionalString(*req.Title)
+	}
+	if req.Description != nil {
+		link.Description = optionalString(*req.Description)
+	}
+	if req.StartsAt != nil {
+		if *req.StartsAt == "" {
+			link.StartsAt = nil
+		} else {
+			startsAt, err := time.Parse(time.RFC3339, *req.StartsAt)
+			if err != nil {
+				h.writeError(w, "Invalid starts_at format. Use RFC3339 format", http.StatusBadRequest)
+				return
+			}
+			link.StartsAt = &startsAt
+		}
+	}
+	if req.ExpiresAt != nil {
+		if *req.ExpiresAt == "" {
+			link.ExpiresAt = nil
+		} else {
+			expiresAt, err := time.Parse(time.RFC3339, *req.ExpiresAt)
+			if err != nil {
+				h.writeError(w, "Invalid expires_at format. Use RFC3339 format", http.StatusBadRequest)
+				return
+			}
+			link.ExpiresAt = &expiresAt
+		}
+	}
+	if !isValidSchedule(link) {
+		h.writeError(w, "expires_at must be after starts_at", http.StatusBadRequest)
+		return
+	}
+	if req.FallbackURL != nil {
+		if *req.FallbackURL == "" {
+			link.FallbackURL = nil
+		} else {
+			fallbackURL, ok := h.checkDestination(w, r, "fallback_url", *req.FallbackURL)
+			if !ok {
+				return
+			}
+			link.FallbackURL = &fallbackURL
+		}
+	}
+	if req.Timezone != nil {
+		if _, err := domain.LoadTimezone(*req.Timezone); err != nil {
+			h.writeError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		link.Timezone = *req.Timezone
+	}
+	if req.IsActive != nil {
+		if *req.IsActive && link.IsFlagged() {
+			h.writeErrorCode(w, "Link is disabled because its destination was flagged as unsafe. Submit an appeal to re-enable it", service.CodeLinkFlagged, http.StatusForbidden)
+			return
+		}
+		link.IsActive = *req.IsActive
+	}
+	if req.ForwardQuery != nil {
+		link.ForwardQuery = *req.ForwardQuery
+	}
+	if req.QueryConflict != nil {
+		if !urlforward.ConflictPolicy(*req.QueryConflict).IsValid() {
+			h.writeError(w, "Invalid query_conflict. Use keep, override or append", http.StatusBadRequest)
+			return
+		}
+		link.QueryConflict = *req.QueryConflict
+	}
+	if req.ForwardPath != nil {
+		link.ForwardPath = *req.ForwardPath
+	}
+	if req.RedirectMode != nil && *req.RedirectMode != link.RedirectMode {
+		if !h.checkRedirectMode(w, r, userID, domain.RedirectMode(*req.RedirectMode)) {
+			return
+		}
+		link.RedirectMode = string(domain.DefaultRedirectMode)
+		if *req.RedirectMode != "" {
+			link.RedirectMode = *req.RedirectMode
+		}
+	}
+	if req.TrackingPixels != nil {
+		trackingPixels, err := domain.NormalizeTrackingPixels(*req.TrackingPixels)
+		if err != nil {
+			h.writeError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		link.TrackingPixels = trackingPixels
+	}
+	if req.UTM != nil {
+		utm, err := req.UTM.Normalize()
+		if err != nil {
+			h.writeError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		link.UTM = utm
+	}
+	if req.SocialPreview != nil {
+		socialPreview, ok := h.normalizeSocialPreview(w, r, *req.SocialPreview)
+		if !ok {
+			return
+		}
+		link.SocialPreview = socialPreview
+	}
+
+	var tags []domain.Tag
+	if req.Tags != nil {
+		var ok bool
+		if tags, ok = h.resolveTags(w, r, userID, *req.Tags); !ok {
+			return
+		}
+	}
+
+	if err := h.storage.UpdateLink(r.Context(), link, userID); err != nil {
+		if err == repository.ErrAliasNotFound {
+			h.writeError(w, "Link not found", http.StatusNotFound)
+			return
+		}
+		h.log.Error("failed to update link", zap.String("alias", alias), zap.Error(err))
+		h.writeError(w, "Failed to update link", http.StatusInternalServerError)
+		return
+	}
+
+	if req.Tags != nil {
+		if err := h.storage.ReplaceLinkTags(r.Context(), link.ID, tags); err != nil {
+			h.log.Error("failed to update link tags", zap.String("alias", alias), zap.Error(err))
+			h.writeError(w, "Failed to update link tags", http.StatusInternalServerError)
+			return
+		}
+		link.Tags = tags
+	}
+
+	if link.OriginalURL != previousURL {
+		// метаданные прежней страницы сброшены при сохранении, загружаем новую
+		h.metadata.Enqueue(link)
+	}
+
+	h.log.Info("updated link", zap.String("alias", alias), zap.Int64("user_id", userID))
+	h.writeJSON(w, h.newLinkInfo(link), http.StatusOK)
+}
+
+// ListRevisions возвращает историю изменений адреса назначения ссылки
+//
+//	@Summary		List link revisions
+//	@Description	Get the history of destination changes for a link
+//	@Tags			Links
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			alias	path		string						true	"Link alias"
+//	@Param			domain	query		string						false	"Custom domain of the link, omitted for the service domain"
+//	@Success		200		{object}	ListLinkRevisionsResponse	"Revision history"
+//	@Failure		401		{object}	map[string]string			"Authentication required"
+//	@Failure		403		{object}	map[string]string			"Access denied"
+//	@Failure		404		{object}	map[string]string			"Link not found"
+//	@Router			/api/links/{alias}/revisions [get]
+func (h *LinksHandler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	alias := extractAlias(r)
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	link, ok := h.getOwnedLink(w, r, alias, userID)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.storage.ListLinkRevisions(r.Context(), link.ID)
+	if err != nil {
+		h.log.Error("failed to list link revisions", zap.String("alias", alias), zap.Error(err))
+		h.writeError(w, "Failed to retrieve revisions", http.StatusInternalServerError)
+		return
+	}
+
+	revisionInfos := make([]LinkRevisionInfo, len(revisions))
+	for i, revision := range revisions {
+		revisionInfos[i] = LinkRevisionInfo{
+			ID:          revision.ID,
+			OriginalURL: revision.OriginalURL,
+			ChangedBy:   revision.ChangedBy,
+			CreatedAt:   revision.CreatedAt.Format(time.RFC3339),
+		}
+		if revision.PreviousURL != nil {
+			revisionInfos[i].PreviousURL = *revision.PreviousURL
+		}
+	}
+
+	h.writeJSON(w, ListLinkRevisionsResponse{Alias: link.Alias, Revisions: revisionInfos}, http.StatusOK)
+}
+
+// RollbackLink возвращает адрес назначения ссылки к указанной ревизии
+//
+//	@Summary		Roll back link destination
+//	@Description	Restore the destination URL recorded in a previous revision. The rollback itself is recorded as a new revision.
+//	@Tags			Links
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			alias		path		string				true	"Link alias"
+//	@Param			domain		query		string				false	"Custom domain of the link, omitted for the service domain"
+//	@Param			revision_id	path		int					true	"Revision ID"
+//	@Success		200			{object}	LinkInfo			"Updated link"
+//	@Failure		400			{object}	map[string]string	"Invalid revision ID"
+//	@Failure		401			{object}	map[string]string	"Authentication required"
+//	@Failure		403			{object}	map[string]string	"Access denied"
+//	@Failure		404			{object}	map[string]string	"Link or revision not found"
+//	@Router			/api/links/{alias}/revisions/{revision_id}/rollback [post]
+func (h *LinksHandler) RollbackLink(w http.ResponseWriter, r *http.Request) {
+	alias := extractAlias(r)
+	pathParts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(pathParts) < 5 {
+		h.writeError(w, "Revision ID is required", http.StatusBadRequest)
+		return
+	}
+	revisionID, err := strconv.ParseInt(pathParts[4], 10, 64)
+	if err != nil {
+		h.writeError(w, "Invalid revision ID", http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	link, ok := h.getOwnedLink(w, r, alias, userID)
+	if !ok {
+		return
+	}
+
+	revision, err := h.storage.GetLinkRevision(r.Context(), link.ID, revisionID)
+	if err != nil {
+		if err == repository.ErrRevisionNotFound {
+			h.writeError(w, "Revision not found", http.StatusNotFound)
+			return
+		}
+		h.log.Error("failed to get link revision", zap.String("alias", alias), zap.Int64("revision_id", revisionID), zap.Error(err))
+		h.writeError(w, "Failed to retrieve revision", http.StatusInternalServerError)
+		return
+	}
+
+	// Ревизия могла быть сохранена до появления проверок или попасть в списки угроз позже,
+	// поэтому адрес проверяется так же, как новый адрес назначения
+	originalURL, ok := h.checkDestination(w, r, "original_url", revision.OriginalURL)
+	if !ok {
+		return
+	}
+
+	previousURL := link.OriginalURL
+	link.OriginalURL = originalURL
+	if err := h.storage.UpdateLink(r.Context(), link, userID); err != nil {
+		if err == repository.ErrAliasNotFound {
+			h.writeError(w, "Link not found", http.StatusNotFound)
+			return
+		}
+		h.log.Error("failed to roll back link", zap.String("alias", alias), zap.Int64("revision_id", revisionID), zap.Error(err))
+		h.writeError(w, "Failed to roll back link", http.StatusInternalServerError)
+		return
+	}
+	if link.OriginalURL != previousURL {
+		h.metadata.Enqueue(link)
+	}
+
+	h.log.Info("rolled back link", zap.String("alias", alias), zap.Int64("revision_id", revisionID), zap.Int64("user_id", userID))
+	h.writeJSON(w, h.newLinkInfo(link), http.StatusOK)
 }
 
 // Helper methods
 
+// resolveTags нормализует названия тегов и находит (или создает) соответствующие теги пользователя
+func (h *LinksHandler) resolveTags(w http.ResponseWriter, r *http.Request, userID int64, names []string) ([]domain.Tag, bool) {
+	normalized, err := domain.NormalizeTagNames(names)
+	if err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+
+	tags, err := h.storage.ResolveTags(r.Context(), userID, normalized)
+	if err != nil {
+		h.log.Error("failed to resolve tags", zap.Int64("user_id", userID), zap.Error(err))
+		h.writeError(w, "Internal server error", http.StatusInternalServerError)
+		return nil, false
+	}
+	return tags, true
+}
+
+// getOwnedLink получает ссылку (включая приостановленные) и проверяет, что она принадлежит пользователю.
+// Ссылка на собственном домене ищется в домене из параметра ?domain=.
+// При ошибке сам отправляет ответ и возвращает false.
+func (h *LinksHandler) getOwnedLink(w http.ResponseWriter, r *http.Request, alias string, userID int64) (*domain.Link, bool) {
+	domainID, ok := h.requestDomainID(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	link, err := h.storage.FindLink(r.Context(), domainID, alias)
+	if err != nil {
+		if err == repository.ErrAliasNotFound {
+			h.writeError(w, "Link not found", http.StatusNotFound)
+			return nil, false
+		}
+		h.log.Error("failed to get link", zap.String("alias", alias), zap.Error(err))
+		h.writeError(w, "Failed to retrieve link", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	if link.UserID != userID {
+		h.writeError(w, "Access denied", http.StatusForbidden)
+		return nil, false
+	}
+
+	return link, true
+}
+
+// requestDomainID возвращает ID собственного домена пользователя из параметра ?domain=;
+// без параметра возвращает nil (основной домен сервиса). При ошибке сам отправляет ответ и возвращает false.
+func (h *LinksHandler) requestDomainID(w http.ResponseWriter, r *http.Request) (*int64, bool) {
+	hostname := r.URL.Query().Get("domain")
+	if hostname == "" {
+		return nil, true
+	}
+
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeError(w, "User ID not found in context", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	customDomain, ok := h.findUserDomain(w, r, userID, hostname)
+	if !ok {
+		return nil, false
+	}
+	return &customDomain.ID, true
+}
+
+// getLinkDomain возвращает подтвержденный домен пользователя для создания ссылки на нем.
+// При ошибке сам отправляет ответ и возвращает false.
+func (h *LinksHandler) getLinkDomain(w http.ResponseWriter, r *http.Request, userID int64, hostname string) (*domain.CustomDomain, bool) {
+	hasAccess, err := h.checkFeatureAccess(r.Context(), userID, "custom_domains")
+	if err != nil {
+		h.log.Error("failed to check custom domains access", zap.Int64("user_id", userID), zap.Error(err))
+		h.writeError(w, "Internal server error", http.StatusInternalServerError)
+		return nil, false
+	}
+	if !hasAccess {
+		h.writeError(w, "Custom domains are not available in your current subscription plan. Please upgrade to use this feature.", http.StatusForbidden)
+		return nil, false
+	}
+
+	customDomain, ok := h.findUserDomain(w, r, userID, hostname)
+	if !ok {
+		return nil, false
+	}
+	if !customDomain.IsVerified() {
+		h.writeError(w, "Domain is not verified. Publish the verification TXT record and verify the domain first", http.StatusBadRequest)
+		return nil, false
+	}
+	return customDomain, true
+}
+
+// findUserDomain находит домен пользователя по имени (в любом регистре, в том числе в юникоде).
+// При ошибке сам отправляет ответ и возвращает false.
+func (h *LinksHandler) findUserDomain(w http.ResponseWriter, r *http.Request, userID int64, hostname string) (*domain.CustomDomain, bool) {
+	hostname, err := domain.NormalizeHostname(hostname)
+	if err != nil {
+		h.writeError(w, "Invalid domain", http.StatusBadRequest)
+		return nil, false
+	}
+
+	customDomain, err := h.storage.GetUserDomainByHostname(r.Context(), userID, hostname)
+	if err != nil {
+		if err == repository.ErrDomainNotFound {
+			h.writeError(w, "Domain not found", http.StatusNotFound)
+			return nil, false
+		}
+		h.log.Error("failed to get custom domain", zap.String("hostname", hostname), zap.Error(err))
+		h.writeError(w, "Failed to retrieve domain", http.StatusInternalServerError)
+		return nil, false
+	}
+	return customDomain, true
+}
+
+// shortURL возвращает короткий URL ссылки: на собственном домене (со схемой из baseURL) или на домене сервиса
+func (h *LinksHandler) shortURL(link *domain.Link) string {
+	if link.Domain == nil {
+		return h.baseURL + "/" + link.Alias
+	}
+	scheme := "https"
+	if u, err := url.Parse(h.baseURL); err == nil && u.Scheme != "" {
+		scheme = u.Scheme
+	}
+	return scheme + "://" + link.Domain.Hostname + "/" + link.Alias
+}
+
 func (h *LinksHandler) writeJSON(w http.ResponseWriter, data interface{}, statusCode int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
@@ -355,6 +1207,13 @@ func (h *LinksHandler) writeError(w http.ResponseWriter, message string, statusC
 	json.NewEncoder(w).Encode(map[string]string{"error": message})
 }
 
+// writeErrorCode отправляет ошибку с машиночитаемым кодом
+func (h *LinksHandler) writeErrorCode(w http.ResponseWriter, message, code string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]string{"error": message, "code": code})
+}
+
 // checkSubscriptionLimits проверяет лимиты подписки пользователя
 func (h *LinksHandler) checkSubscriptionLimits(ctx context.Context, userID int64) (bool, error) {
 	// Получаем пользователя с подпиской
@@ -374,29 +1233,50 @@ func (h *LinksHandler) checkSubscriptionLimits(ctx context.Context, userID int64
 		return true, nil
 	}
 
-	// Получаем количество созданных ссылок в этом месяце
-	links, err := h.storage.ListUserLinks(ctx, userID)
-	if err != nil {
-		return false, fmt.Errorf("failed to get user links: %w", err)
-	}
-
 	// Подсчитываем ссылки, созданные в текущем месяце
 	now := time.Now()
 	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
-	
-	linksThisMonth := 0
-	for _, link := range links {
-		if link.CreatedAt.After(monthStart) {
-			linksThisMonth++
-		}
+
+	created, err := h.storage.CountLinksCreatedSince(ctx, userID, monthStart)
+	if err != nil {
+		return false, fmt.Errorf("failed to count user links: %w", err)
 	}
 
 	// Проверяем лимит
-	return linksThisMonth < *subscription.MaxLinksPerMonth, nil
+	return created < int64(*subscription.MaxLinksPerMonth), nil
 }
 
 // checkCustomAliasAccess проверяет доступ к кастомным алиасам
 func (h *LinksHandler) checkCustomAliasAccess(ctx context.Context, userID int64) (bool, error) {
+	return h.checkFeatureAccess(ctx, userID, "custom_aliases")
+}
+
+// checkRedirectMode проверяет способ редиректа и его доступность в подписке пользователя.
+// Возвращает false, если ответ с ошибкой уже отправлен.
+func (h *LinksHandler) checkRedirectMode(w http.ResponseWriter, r *http.Request, userID int64, mode domain.RedirectMode) bool {
+	if !mode.IsValid() {
+		h.writeError(w, domain.ErrInvalidRedirectMode.Error(), http.StatusBadRequest)
+		return false
+	}
+	if !mode.RequiresPlanFeature() {
+		return true
+	}
+
+	hasAccess, err := h.checkFeatureAccess(r.Context(), userID, "interstitial_redirects")
+	if err != nil {
+		h.log.Error("failed to check redirect mode access", zap.Int64("user_id", userID), zap.Error(err))
+		h.writeError(w, "Internal server error", http.StatusInternalServerError)
+		return false
+	}
+	if !hasAccess {
+		h.writeError(w, "Meta refresh and interstitial redirects are not available in your current subscription plan. Please upgrade to use this feature.", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// checkFeatureAccess проверяет, доступна ли функция в подписке пользователя
+func (h *LinksHandler) checkFeatureAccess(ctx context.Context, userID int64, feature string) (bool, error) {
 	// Получаем пользователя с подпиской
 	user, err := h.storage.GetUserByID(ctx, userID)
 	if err != nil {
@@ -409,5 +1289,222 @@ func (h *LinksHandler) checkCustomAliasAccess(ctx context.Context, userID int64)
 		return false, fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	return subscription.CustomAliases, nil
-}
\ No newline at end of file
+	return subscription.HasFeature(feature), nil
+}
+
+// checkDestination проверяет адрес назначения политикой URL и по спискам угроз и возвращает
+// его нормализованную форму. При отказе отвечает 400 с кодом причины
+func (h *LinksHandler) checkDestination(w http.ResponseWriter, r *http.Request, field, rawURL string) (string, bool) {
+	normalized, err := h.urlPolicy.Check(r.Context(), rawURL)
+	if err != nil {
+		var policyErr *urlpolicy.Error
+		if errors.As(err, &policyErr) {
+			h.writeErrorCode(w, fmt.Sprintf("Invalid %s: %s", field, policyErr.Message), policyErr.Code, http.StatusBadRequest)
+			return "", false
+		}
+		h.log.Error("failed to check destination url", zap.String("field", field), zap.Error(err))
+		h.writeError(w, "Internal server error", http.StatusInternalServerError)
+		return "", false
+	}
+
+	if verdict := h.reputation.CheckURL(r.Context(), normalized); verdict != nil {
+		h.log.Warn("rejected listed destination",
+			zap.String("field", field),
+			zap.String("url", normalized),
+			zap.String("threat", string(verdict.Threat)),
+			zap.String("source", verdict.Source))
+		h.writeErrorCode(w, fmt.Sprintf("Invalid %s: the destination is listed as unsafe (%s)", field, verdict.Threat), service.CodeMaliciousURL, http.StatusBadRequest)
+		return "", false
+	}
+	return normalized, true
+}
+
+// normalizeSocialPreview проверяет карточку ссылки для мессенджеров. Адрес картинки проходит
+// политику адресов и проверку репутации, как адрес назначения.
+func (h *LinksHandler) normalizeSocialPreview(w http.ResponseWriter, r *http.Request, preview domain.SocialPreview) (domain.SocialPreview, bool) {
+	normalized, err := preview.Normalize()
+	if err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return domain.SocialPreview{}, false
+	}
+	if normalized.ImageURL != nil {
+		imageURL, ok := h.checkDestination(w, r, "social_preview.image_url", *normalized.ImageURL)
+		if !ok {
+			return domain.SocialPreview{}, false
+		}
+		normalized.ImageURL = &imageURL
+	}
+	return normalized, true
+}
+
+// isValidRedirectURL проверяет, что URL абсолютный и использует http(s)
+func isValidRedirectURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// hostnameOf возвращает имя хоста из URL в нижнем регистре, без порта
+func hostnameOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// isValidSchedule проверяет, что срок действия ссылки заканчивается после ее запуска
+func isValidSchedule(link *domain.Link) bool {
+	return link.StartsAt == nil || link.ExpiresAt == nil || link.ExpiresAt.After(*link.StartsAt)
+}
+
+// newLinkInfo преобразует доменную ссылку в формат ответа API
+func (h *LinksHandler) newLinkInfo(link *domain.Link) LinkInfo {
+	linkInfo := LinkInfo{
+		Alias:          link.Alias,
+		ShortURL:       h.shortURL(link),
+		OriginalURL:    link.OriginalURL,
+		ClickCount:     int64(link.ClickCount),
+		CreatedAt:      link.CreatedAt.Format(time.RFC3339),
+		HasPassword:    link.PasswordHash != nil,
+		MaxClicks:      link.MaxClicks,
+		IsActive:       link.IsActive,
+		ForwardQuery:   link.ForwardQuery,
+		QueryConflict:  link.QueryConflict,
+		ForwardPath:    link.ForwardPath,
+		Timezone:       link.Timezone,
+		RedirectMode:   string(link.Redirect()),
+		TrackingPixels: link.TrackingPixelList(),
+		UTM:            link.UTM,
+	}
+	if link.Domain != nil {
+		linkInfo.Domain = link.Domain.Hostname
+	}
+	if link.Title != nil {
+		linkInfo.Title = *link.Title
+	}
+	if link.Description != nil {
+		linkInfo.Description = *link.Description
+	}
+	if link.FallbackURL != nil {
+		linkInfo.FallbackURL = *link.FallbackURL
+	}
+	if link.StartsAt != nil {
+		linkInfo.StartsAt = link.StartsAt.Format(time.RFC3339)
+	}
+	if link.ExpiresAt != nil {
+		linkInfo.ExpiresAt = link.ExpiresAt.Format(time.RFC3339)
+	}
+	if link.DeletedAt != nil {
+		linkInfo.DeletedAt = link.DeletedAt.Format(time.RFC3339)
+	}
+	if link.FlaggedAt != nil {
+		linkInfo.FlaggedAt = link.FlaggedAt.Format(time.RFC3339)
+	}
+	if !link.SocialPreview.IsEmpty() {
+		socialPreview := link.SocialPreview
+		linkInfo.SocialPreview = &socialPreview
+	}
+	if link.FlagReason != nil {
+		linkInfo.FlagReason = *link.FlagReason
+	}
+	if link.Metadata.FetchedAt != nil {
+		metadata := link.Metadata
+		linkInfo.Metadata = &metadata
+	}
+	for _, tag := range link.Tags {
+		linkInfo.Tags = append(linkInfo.Tags, tag.Name)
+	}
+	return linkInfo
+}
+
+// extractAlias извлекает alias из пути вида /api/links/{alias}/...
+func extractAlias(r *http.Request) string {
+	pathParts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(pathParts) < 3 {
+		return ""
+	}
+	return pathParts[2]
+}
+
+// optionalString возвращает nil для пустой строки
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// parseLinkListOptions разбирает параметры фильтрации, сортировки и пагинации списка ссылок
+func parseLinkListOptions(r *http.Request) (repository.LinkListOptions, error) {
+	query := r.URL.Query()
+	opts := repository.LinkListOptions{
+		Status: repository.LinkStatus(query.Get("status")),
+		Search: query.Get("q"),
+		SortBy: repository.LinkSortField(query.Get("sort")),
+		Cursor: query.Get("cursor"),
+	}
+
+	if value := query.Get("created_from"); value != "" {
+		createdFrom, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return opts, fmt.Errorf("invalid created_from format. Use RFC3339 format")
+		}
+		opts.CreatedFrom = &createdFrom
+	}
+	if value := query.Get("created_to"); value != "" {
+		createdTo, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return opts, fmt.Errorf("invalid created_to format. Use RFC3339 format")
+		}
+		opts.CreatedTo = &createdTo
+	}
+
+	if !opts.Status.IsValid() {
+		return opts, fmt.Errorf("invalid status. Use active, scheduled, paused, expired or exhausted")
+	}
+
+	if value := query.Get("tag"); value != "" {
+		tag, err := domain.NormalizeTagName(value)
+		if err != nil {
+			return opts, fmt.Errorf("invalid tag")
+		}
+		opts.Tag = tag
+	}
+
+	if value := query.Get("has_password"); value != "" {
+		hasPassword, err := strconv.ParseBool(value)
+		if err != nil {
+			return opts, fmt.Errorf("invalid has_password value")
+		}
+		opts.HasPassword = &hasPassword
+	}
+
+	if opts.SortBy == "" {
+		opts.SortBy = repository.LinkSortCreatedAt
+	}
+	if !opts.SortBy.IsValid() {
+		return opts, fmt.Errorf("invalid sort. Use created_at, click_count or title")
+	}
+
+	switch query.Get("order") {
+	case "", "desc":
+		opts.SortDesc = true
+	case "asc":
+		opts.SortDesc = false
+	default:
+		return opts, fmt.Errorf("invalid order. Use asc or desc")
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return opts, fmt.Errorf("invalid limit")
+		}
+		opts.Limit = limit
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,139 @@
+package http
+
+import (
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/repository"
+	"GURLS-Backend/pkg/urlforward"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// previewSuffix суффикс алиаса, открывающий предпросмотр ссылки вместо редиректа: /{alias}+
+const previewSuffix = "+"
+
+// LinkPreviewResponse структура ответа предпросмотра короткой ссылки.
+// Адрес назначения не раскрывается для защищенных паролем и недоступных ссылок.
+type LinkPreviewResponse struct {
+	Alias           string `json:"alias"`
+	Status          string `json:"status"` // active, scheduled, expired или exhausted
+	DestinationURL  string `json:"destination_url,omitempty"`
+	DestinationHost string `json:"destination_host,omitempty"`
+	Title           string `json:"title,omitempty"`
+	Description     string `json:"description,omitempty"`
+	HasPassword     bool   `json:"has_password"`
+	IsDynamic       bool   `json:"is_dynamic"` // адрес назначения зависит от посетителя: правила редиректа или A/B тест
+	StartsAt        string `json:"starts_at,omitempty"`
+	ExpiresAt       string `json:"expires_at,omitempty"`
+	ContinueURL     string `json:"continue_url"` // короткая ссылка для перехода
+}
+
+// isPreviewPath проверяет, что запрошен предпросмотр ссылки, и возвращает ее алиас
+func isPreviewPath(alias string, forward urlforward.Request) (string, bool) {
+	if forward.HasPathSuffix || !strings.HasSuffix(alias, previewSuffix) {
+		return "", false
+	}
+	alias = strings.TrimSuffix(alias, previewSuffix)
+	return alias, alias != ""
+}
+
+// handlePreview показывает, куда ведет короткая ссылка, не выполняя редирект и не записывая клик.
+// JSON клиенты получают LinkPreviewResponse, браузеры - HTML страницу с кнопкой перехода.
+func (h *RedirectHandler) handlePreview(w http.ResponseWriter, r *http.Request, domainID *int64, alias string, forward urlforward.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	link, err := h.storage.GetLink(r.Context(), domainID, alias)
+	if err != nil {
+		if err == repository.ErrAliasNotFound {
+			h.handleNotFound(w, r, domainID, alias)
+			return
+		}
+		h.log.Error("failed to get link for preview", zap.String("alias", alias), zap.Error(err))
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	preview := h.newLinkPreview(r, link, forward)
+
+	w.Header().Set("Cache-Control", "no-store")
+	if isJSONRequest(r) {
+		h.writeJSON(w, preview, http.StatusOK)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if err := pageTemplates.ExecuteTemplate(w, "preview.html", preview); err != nil {
+		h.log.Error("failed to render preview page", zap.String("alias", alias), zap.Error(err))
+	}
+}
+
+// newLinkPreview собирает данные предпросмотра. Показывается основной адрес назначения ссылки
+// с UTM метками и параметрами запроса, которые получит посетитель без правил и A/B вариантов.
+func (h *RedirectHandler) newLinkPreview(r *http.Request, link *domain.Link, forward urlforward.Request) LinkPreviewResponse {
+	preview := LinkPreviewResponse{
+		Alias:       link.Alias,
+		Status:      string(linkPreviewStatus(link)),
+		HasPassword: link.PasswordHash != nil,
+		ContinueURL: "/" + url.PathEscape(link.Alias),
+	}
+	// Переход сохраняет исходную query string, включая метку QR кода
+	if r.URL.RawQuery != "" {
+		preview.ContinueURL += "?" + r.URL.RawQuery
+	}
+	if link.Title != nil {
+		preview.Title = *link.Title
+	}
+	if link.Description != nil {
+		preview.Description = *link.Description
+	}
+	if link.StartsAt != nil {
+		preview.StartsAt = link.StartsAt.Format(time.RFC3339)
+	}
+	if link.ExpiresAt != nil {
+		preview.ExpiresAt = link.ExpiresAt.Format(time.RFC3339)
+	}
+
+	if preview.HasPassword || preview.Status != string(repository.LinkStatusActive) {
+		return preview
+	}
+
+	rules, err := h.storage.ListRedirectRules(r.Context(), link.ID)
+	if err != nil {
+		h.log.Error("failed to get redirect rules", zap.String("alias", link.Alias), zap.Error(err))
+	}
+	variants, err := h.storage.ListLinkVariants(r.Context(), link.ID)
+	if err != nil {
+		h.log.Error("failed to get link variants", zap.String("alias", link.Alias), zap.Error(err))
+	}
+	preview.IsDynamic = len(rules) > 0 || len(variants) > 0
+
+	if destination, err := buildTargetURL(link, nil, nil, forward); err == nil {
+		preview.DestinationURL = destination
+		if parsed, err := url.Parse(destination); err == nil {
+			preview.DestinationHost = parsed.Hostname()
+		}
+	}
+	return preview
+}
+
+// linkPreviewStatus определяет состояние ссылки для предпросмотра
+func linkPreviewStatus(link *domain.Link) repository.LinkStatus {
+	switch {
+	case link.IsNotStarted():
+		return repository.LinkStatusScheduled
+	case link.IsExpired():
+		return repository.LinkStatusExpired
+	case link.IsExhausted():
+		return repository.LinkStatusExhausted
+	default:
+		return repository.LinkStatusActive
+	}
+}
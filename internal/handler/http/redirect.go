@@ -1,99 +1,733 @@
 package http
 
 import (
+	"GURLS-Backend/internal/auth"
+	"GURLS-Backend/internal/domain"
 	"GURLS-Backend/internal/repository"
+	"GURLS-Backend/pkg/geoip"
+	"GURLS-Backend/pkg/urlforward"
+	"GURLS-Backend/pkg/useragent"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
 	"net"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 )
 
+// maxUnlockBodySize ограничение размера тела запроса разблокировки
+const maxUnlockBodySize = 4 << 10
+
+const (
+	// variantCookieName имя cookie с вариантом A/B теста, на который попал посетитель
+	variantCookieName = "gurls_variant"
+
+	// variantCookieDuration срок, в течение которого посетитель остается на своем варианте
+	variantCookieDuration = 90 * 24 * time.Hour
+
+	// interstitialDelay время в секундах, которое промежуточная страница дает пикселям аналитики
+	interstitialDelay = 1
+)
+
 // RedirectHandler обработчик редиректов
 type RedirectHandler struct {
-	storage repository.Storage
-	log     *zap.Logger
+	storage     repository.Storage
+	linkUnlock  *auth.LinkUnlockService
+	geo         *geoip.Database
+	log         *zap.Logger
+	serviceHost string // домен сервиса из baseURL; запросы на него не ищут собственный домен
+
+	trustedProxies []*net.IPNet // прокси, которым разрешено передавать адрес клиента в заголовках
 }
 
 // NewRedirectHandler создает новый обработчик редиректов
-func NewRedirectHandler(storage repository.Storage, log *zap.Logger) *RedirectHandler {
+func NewRedirectHandler(storage repository.Storage, linkUnlock *auth.LinkUnlockService, geo *geoip.Database, trustedProxies []*net.IPNet, log *zap.Logger, baseURL string) *RedirectHandler {
 	return &RedirectHandler{
-		storage: storage,
-		log:     log,
+		storage:        storage,
+		linkUnlock:     linkUnlock,
+		geo:            geo,
+		log:            log,
+		serviceHost:    hostnameOf(baseURL),
+		trustedProxies: trustedProxies,
 	}
 }
 
+// UnlockLinkRequest структура JSON запроса разблокировки защищенной ссылки
+type UnlockLinkRequest struct {
+	Password string `json:"password"`
+}
+
+// UnlockLinkResponse структура JSON ответа успешной разблокировки
+type UnlockLinkResponse struct {
+	OriginalURL string `json:"original_url"`
+}
+
 // HandleRedirect обрабатывает редирект по alias
 func (h *RedirectHandler) HandleRedirect(w http.ResponseWriter, r *http.Request) {
-	// Извлекаем alias из URL path
-	alias := strings.TrimPrefix(r.URL.Path, "/")
-	
-	// Проверяем, что это не системные endpoints
-	if alias == "" || strings.HasPrefix(alias, "api/") || 
-	   strings.HasPrefix(alias, "health") || strings.HasPrefix(alias, "ready") ||
-	   strings.HasPrefix(alias, "metrics") {
+	// Проверяем, что это не системные endpoints; такие алиасы запрещены политикой алиасов
+	if r.URL.Path == "/" || isSystemPath(r.URL.Path) {
+		http.NotFound(w, r)
+		return
+	}
+
+	// Извлекаем alias из первого сегмента пути; остаток пути может передаваться в адрес назначения
+	alias, forward, ok := parseRedirectPath(r)
+	if !ok {
 		http.NotFound(w, r)
 		return
 	}
 
+	// Алиас ищется в домене из заголовка Host: подтвержденный собственный домен или домен сервиса
+	domainID, err := h.resolveHostDomain(r)
+	if err != nil {
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Предпросмотр /{alias}+ показывает адрес назначения без редиректа и записи клика
+	if previewAlias, ok := isPreviewPath(alias, forward); ok {
+		h.handlePreview(w, r, domainID, previewAlias, forward)
+		return
+	}
+
 	// Извлекаем информацию для аналитики
-	ipAddress := extractIPAddress(r)
+	ipAddress := extractIPAddress(r, h.trustedProxies)
 	userAgent := r.UserAgent()
 	referer := r.Referer()
 
-	// Используем atomic метод для получения ссылки и записи клика
-	link, err := h.storage.GetLinkAndRecordClick(r.Context(), alias, &ipAddress, &userAgent, &referer)
+	// Защищенные паролем ссылки требуют разблокировки до записи клика
+	link, err := h.storage.GetLink(r.Context(), domainID, alias)
 	if err != nil {
 		if err == repository.ErrAliasNotFound {
 			h.log.Debug("alias not found", zap.String("alias", alias))
-			http.NotFound(w, r)
+			h.handleNotFound(w, r, domainID, alias)
 			return
 		}
-		h.log.Error("failed to process redirect", zap.String("alias", alias), zap.Error(err))
+		h.log.Error("failed to get link for redirect", zap.String("alias", alias), zap.Error(err))
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	// Определяем тип устройства для дополнительной аналитики
-	deviceType := detectDeviceType(userAgent)
-	
+	// Хвост пути допустим только для ссылок, которые его передают
+	if forward.HasPathSuffix && !link.ForwardPath {
+		http.NotFound(w, r)
+		return
+	}
+
+	// Выбираем адрес назначения по правилам редиректа для посетителя,
+	// а если ни одно правило не подошло - вариант A/B теста
+	visitor := h.newVisitor(link, ipAddress, userAgent)
+	rule := h.matchRule(r, link, visitor)
+	var variant *domain.LinkVariant
+	if rule == nil {
+		variant = h.chooseVariant(r, link)
+	}
+	if _, err := buildTargetURL(link, rule, variant, forward); err != nil {
+		h.log.Debug("failed to build redirect target", zap.String("alias", alias), zap.Error(err))
+		http.Error(w, "Invalid link path", http.StatusBadRequest)
+		return
+	}
+
+	// Ссылки до запуска, истекшие и исчерпанные ссылки не требуют ввода пароля
+	if link.IsNotStarted() {
+		h.handleNotStarted(w, r, link)
+		return
+	}
+	if link.IsExpired() {
+		h.handleUnavailable(w, r, link, repository.ErrLinkExpired)
+		return
+	}
+	if link.IsExhausted() {
+		h.handleUnavailable(w, r, link, repository.ErrLinkExhausted)
+		return
+	}
+
+	if link.PasswordHash != nil && !h.isUnlocked(r, link) {
+		if !h.handleUnlock(w, r, link, ipAddress) {
+			return
+		}
+	}
+
+	// Боты мессенджеров и соцсетей, разворачивающие ссылку в карточку, получают карточку ссылки
+	// вместо редиректа; такой запрос не считается переходом. Без карточки владельца она собирается
+	// из заголовка, описания и метаданных страницы назначения. Недоступные и закрытые паролем
+	// ссылки отвечают ботам так же, как посетителям, и карточку не раскрывают.
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		if bot := useragent.DetectUnfurlBot(userAgent); bot != "" {
+			h.handleSocialPreview(w, link, bot)
+			return
+		}
+	}
+
+	// Используем atomic метод для получения ссылки и записи клика
+	click := newClick(r, ipAddress, userAgent, referer, visitor, rule, variant)
+	recorded, err := h.storage.GetLinkAndRecordClick(r.Context(), domainID, alias, click)
+	if err != nil {
+		switch err {
+		case repository.ErrAliasNotFound:
+			h.log.Debug("alias not found", zap.String("alias", alias))
+			h.handleNotFound(w, r, domainID, alias)
+		case repository.ErrLinkNotStarted:
+			h.handleNotStarted(w, r, link)
+		case repository.ErrLinkExpired, repository.ErrLinkExhausted:
+			h.handleUnavailable(w, r, link, err)
+		default:
+			h.log.Error("failed to process redirect", zap.String("alias", alias), zap.Error(err))
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+	link = recorded
+
+	targetURL, err := buildTargetURL(link, rule, variant, forward)
+	if err != nil {
+		h.log.Error("failed to build redirect target", zap.String("alias", alias), zap.Error(err))
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Запоминаем вариант, чтобы посетитель и дальше видел его
+	if variant != nil {
+		setVariantCookie(w, r, link, variant)
+	}
+
 	// Логируем успешный редирект
 	h.log.Info("successful redirect", 
 		zap.String("alias", alias),
-		zap.String("original_url", link.OriginalURL),
+		zap.String("target_url", targetURL),
 		zap.String("ip", ipAddress),
-		zap.String("device_type", deviceType),
+		zap.String("device_type", visitor.DeviceType),
 		zap.String("user_agent", userAgent))
 
-	// Выполняем редирект
-	http.Redirect(w, r, link.OriginalURL, http.StatusFound)
+	// JSON клиент, разблокировавший ссылку, получает адрес назначения в теле ответа
+	if r.Method == http.MethodPost && isJSONRequest(r) {
+		h.writeJSON(w, UnlockLinkResponse{OriginalURL: targetURL}, http.StatusOK)
+		return
+	}
+
+	// Выполняем редирект способом, выбранным для ссылки
+	h.sendRedirect(w, r, link, targetURL, link.Redirect().StatusCode())
+}
+
+// resolveHostDomain определяет домен ссылки по заголовку Host: nil для домена сервиса,
+// ID подтвержденного собственного домена или nil для неизвестных хостов (прямое обращение по IP, прокси)
+func (h *RedirectHandler) resolveHostDomain(r *http.Request) (*int64, error) {
+	host := requestHostname(r)
+	if host == "" || host == h.serviceHost {
+		return nil, nil
+	}
+
+	customDomain, err := h.storage.GetVerifiedDomain(r.Context(), host)
+	if err == repository.ErrDomainNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		h.log.Error("failed to resolve custom domain", zap.String("host", host), zap.Error(err))
+		return nil, err
+	}
+	return &customDomain.ID, nil
+}
+
+// requestHostname возвращает имя хоста запроса в нижнем регистре, без порта и завершающей точки
+func requestHostname(r *http.Request) string {
+	host := r.Host
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
+
+// parseRedirectPath разбирает путь вида /{alias}[/{suffix}] и query string запроса.
+// Хвост пути берется в экранированном виде, чтобы сохранить кодирование при передаче.
+func parseRedirectPath(r *http.Request) (string, urlforward.Request, bool) {
+	rawAlias, rawSuffix, hasSuffix := strings.Cut(strings.TrimPrefix(r.URL.EscapedPath(), "/"), "/")
+	alias, err := url.PathUnescape(rawAlias)
+	if err != nil || alias == "" {
+		return "", urlforward.Request{}, false
+	}
+
+	return alias, urlforward.Request{
+		PathSuffix:    rawSuffix,
+		HasPathSuffix: hasSuffix,
+		RawQuery:      stripQRMarker(r.URL.RawQuery),
+	}, true
+}
+
+// stripQRMarker убирает метку QR кода из query string, сохраняя кодирование остальных параметров
+func stripQRMarker(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	marker := domain.QRMarkerParam + "=" + domain.QRMarkerValue
+	params := strings.Split(rawQuery, "&")
+	kept := params[:0]
+	for _, param := range params {
+		if param != marker {
+			kept = append(kept, param)
+		}
+	}
+	return strings.Join(kept, "&")
+}
+
+// isQRScan проверяет, что переход выполнен по адресу из QR кода ссылки
+func isQRScan(r *http.Request) bool {
+	return r.URL.Query().Get(domain.QRMarkerParam) == domain.QRMarkerValue
+}
+
+// newVisitor определяет страну и город посетителя по IP адресу, а устройство, ОС и браузер - по User-Agent.
+// Без парсера User-Agent определяется только тип устройства. Время перехода берется в часовом поясе ссылки.
+func (h *RedirectHandler) newVisitor(link *domain.Link, ipAddress, userAgent string) domain.Visitor {
+	location := h.geo.Lookup(net.ParseIP(ipAddress))
+	visitor := domain.Visitor{
+		Country: location.Country,
+		City:    location.City,
+		Time:    time.Now().In(link.Location()),
+	}
+
+	parser := useragent.GetGlobalParser()
+	if parser == nil {
+		visitor.DeviceType = detectDeviceType(userAgent)
+		return visitor
+	}
+
+	deviceInfo := parser.ParseUserAgent(userAgent)
+	visitor.DeviceType = deviceInfo.DeviceType
+	visitor.OS = knownValue(deviceInfo.OS)
+	visitor.Browser = knownValue(deviceInfo.Browser)
+	return visitor
+}
+
+// knownValue заменяет "unknown" парсера User-Agent пустой строкой
+func knownValue(value string) string {
+	if value == "unknown" {
+		return ""
+	}
+	return value
+}
+
+// matchRule возвращает подходящее посетителю правило редиректа ссылки или nil.
+// При ошибке чтения правил посетитель ведется на основной адрес назначения.
+func (h *RedirectHandler) matchRule(r *http.Request, link *domain.Link, visitor domain.Visitor) *domain.RedirectRule {
+	rules, err := h.storage.ListRedirectRules(r.Context(), link.ID)
+	if err != nil {
+		h.log.Error("failed to get redirect rules", zap.String("alias", link.Alias), zap.Error(err))
+		return nil
+	}
+	return domain.MatchRedirectRule(rules, visitor)
+}
+
+// chooseVariant возвращает вариант A/B теста для посетителя или nil, если у ссылки нет вариантов.
+// Посетитель с cookie варианта остается на нем, пока вариант существует; остальным вариант
+// выбирается случайно пропорционально весам. При ошибке чтения вариантов используется адрес ссылки.
+func (h *RedirectHandler) chooseVariant(r *http.Request, link *domain.Link) *domain.LinkVariant {
+	variants, err := h.storage.ListLinkVariants(r.Context(), link.ID)
+	if err != nil {
+		h.log.Error("failed to get link variants", zap.String("alias", link.Alias), zap.Error(err))
+		return nil
+	}
+	if len(variants) == 0 {
+		return nil
+	}
+
+	if cookie, err := r.Cookie(variantCookieName); err == nil {
+		if id, err := strconv.ParseInt(cookie.Value, 10, 64); err == nil {
+			if variant := domain.FindLinkVariant(variants, id); variant != nil {
+				return variant
+			}
+		}
+	}
+
+	total := domain.TotalVariantWeight(variants)
+	if total <= 0 {
+		return nil
+	}
+	return domain.PickLinkVariant(variants, rand.IntN(total))
+}
+
+// setVariantCookie сохраняет вариант A/B теста посетителя в cookie, действующей только для этой ссылки
+func setVariantCookie(w http.ResponseWriter, r *http.Request, link *domain.Link, variant *domain.LinkVariant) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     variantCookieName,
+		Value:    strconv.FormatInt(variant.ID, 10),
+		Path:     "/" + link.Alias,
+		Expires:  time.Now().Add(variantCookieDuration),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// buildTargetURL строит адрес редиректа: берет адрес назначения сработавшего правила, варианта
+// A/B теста или ссылки, добавляет UTM метки ссылки, которых еще нет в адресе, и передает
+// query string и путь запроса согласно настройкам ссылки
+func buildTargetURL(link *domain.Link, rule *domain.RedirectRule, variant *domain.LinkVariant, forward urlforward.Request) (string, error) {
+	destination := link.OriginalURL
+	switch {
+	case rule != nil:
+		destination = rule.DestinationURL
+	case variant != nil:
+		destination = variant.DestinationURL
+	}
+	return urlforward.Build(destination, forward, urlforward.Options{
+		ForwardQuery: link.ForwardQuery,
+		ForwardPath:  link.ForwardPath,
+		Policy:       urlforward.ConflictPolicy(link.QueryConflict),
+		Params:       link.UTM.Query(),
+	})
+}
+
+// newClick собирает данные клика для аналитики. UTM метки берутся из адреса короткой ссылки,
+// а недостающие - из адреса страницы, с которой пришел переход. Переходы по адресу из QR кода
+// отмечаются источником qr.
+func newClick(r *http.Request, ipAddress, userAgent, referer string, visitor domain.Visitor, rule *domain.RedirectRule, variant *domain.LinkVariant) *domain.Click {
+	click := &domain.Click{
+		UserAgent:  &userAgent,
+		Referer:    &referer,
+		DeviceType: optionalString(visitor.DeviceType),
+		Browser:    optionalString(visitor.Browser),
+		OS:         optionalString(visitor.OS),
+		Country:    optionalString(visitor.Country),
+		City:       optionalString(visitor.City),
+		UTM:        domain.ParseUTM(r.URL.Query()).WithDefaults(domain.ParseUTMFromURL(referer)),
+	}
+	if ip := net.ParseIP(ipAddress); ip != nil {
+		click.IPAddress = &ip
+	}
+	if rule != nil {
+		click.RuleID = &rule.ID
+	}
+	if variant != nil {
+		click.VariantID = &variant.ID
+	}
+	if isQRScan(r) {
+		source := domain.ClickSourceQR
+		click.Source = &source
+	}
+	return click
+}
+
+// sendRedirect отправляет посетителя на targetURL способом, выбранным для ссылки: HTTP редиректом
+// с кодом statusCode или HTML страницей. После отправки формы пароля HTTP редирект выполняется
+// с кодом 303, чтобы браузер перешел по ссылке методом GET.
+func (h *RedirectHandler) sendRedirect(w http.ResponseWriter, r *http.Request, link *domain.Link, targetURL string, statusCode int) {
+	mode := link.Redirect()
+	if mode.IsPage() && isValidRedirectURL(targetURL) {
+		h.renderRedirectPage(w, link, mode, targetURL)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		statusCode = http.StatusSeeOther
+	}
+	http.Redirect(w, r, targetURL, statusCode)
+}
+
+// renderRedirectPage отдает HTML страницу редиректа: meta refresh сразу, промежуточная страница -
+// после загрузки пикселей аналитики ссылки
+func (h *RedirectHandler) renderRedirectPage(w http.ResponseWriter, link *domain.Link, mode domain.RedirectMode, targetURL string) {
+	data := redirectPageData{URL: targetURL}
+	if mode == domain.RedirectInterstitial {
+		data.Interstitial = true
+		data.Delay = interstitialDelay
+		data.Pixels = link.TrackingPixelList()
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	if err := pageTemplates.ExecuteTemplate(w, "redirect.html", data); err != nil {
+		h.log.Error("failed to render redirect page", zap.String("alias", link.Alias), zap.Error(err))
+	}
+}
+
+// handleNotFound отвечает на переход по ненайденной ссылке. Ссылка, отключенная проверкой
+// репутации адреса назначения, показывает предупреждение вместо 404.
+func (h *RedirectHandler) handleNotFound(w http.ResponseWriter, r *http.Request, domainID *int64, alias string) {
+	link, err := h.storage.FindLink(r.Context(), domainID, alias)
+	if err != nil && err != repository.ErrAliasNotFound {
+		h.log.Error("failed to check flagged link", zap.String("alias", alias), zap.Error(err))
+	}
+	if err != nil || !link.IsFlagged() {
+		http.NotFound(w, r)
+		return
+	}
+
+	threat := ""
+	if link.FlagReason != nil {
+		threat = *link.FlagReason
+	}
+	h.log.Info("blocked visit to flagged link", zap.String("alias", alias), zap.String("threat", threat))
+
+	w.Header().Set("Cache-Control", "no-store")
+	if isJSONRequest(r) {
+		h.writeJSON(w, map[string]string{
+			"error":  "This link has been disabled because its destination was flagged as unsafe",
+			"threat": threat,
+		}, http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusForbidden)
+	if err := pageTemplates.ExecuteTemplate(w, "flagged.html", flaggedPageData{Threat: threat}); err != nil {
+		h.log.Error("failed to render flagged link page", zap.String("alias", alias), zap.Error(err))
+	}
+}
+
+// handleNotStarted отвечает на переход по ссылке, время запуска которой еще не наступило
+func (h *RedirectHandler) handleNotStarted(w http.ResponseWriter, r *http.Request, link *domain.Link) {
+	h.log.Debug("link not started yet", zap.String("alias", link.Alias), zap.Timep("starts_at", link.StartsAt))
+
+	w.Header().Set("Cache-Control", "no-store")
+	if isJSONRequest(r) {
+		h.writeJSON(w, map[string]string{
+			"error":     "Link is not available yet",
+			"starts_at": link.StartsAt.Format(time.RFC3339),
+		}, http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusNotFound)
+	startsAt := link.StartsAt.In(link.Location())
+	data := notStartedPageData{
+		StartsAt: startsAt.Format("02.01.2006 15:04"),
+		Timezone: startsAt.Location().String(),
+	}
+	if err := pageTemplates.ExecuteTemplate(w, "not_started.html", data); err != nil {
+		h.log.Error("failed to render not started page", zap.String("alias", link.Alias), zap.Error(err))
+	}
+}
+
+// handleSocialPreview отдает боту мессенджера страницу с OpenGraph тегами карточки ссылки.
+// Страница не содержит адреса назначения и не переадресует.
+func (h *RedirectHandler) handleSocialPreview(w http.ResponseWriter, link *domain.Link, bot string) {
+	h.log.Debug("serving social preview", zap.String("alias", link.Alias), zap.String("bot", bot))
+
+	data := socialPreviewPageData{Card: "summary"}
+	data.Title, data.Description, data.ImageURL = link.PreviewCard()
+	if data.Title == "" {
+		data.Title = link.Alias
+	}
+	if data.ImageURL != "" {
+		data.Card = "summary_large_image"
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if err := pageTemplates.ExecuteTemplate(w, "social_preview.html", data); err != nil {
+		h.log.Error("failed to render social preview page", zap.String("alias", link.Alias), zap.Error(err))
+	}
 }
 
-// extractIPAddress извлекает IP адрес из запроса с учетом прокси
-func extractIPAddress(r *http.Request) string {
-	// Проверяем заголовки прокси в порядке приоритета
-	if ip := r.Header.Get("X-Forwarded-For"); ip != "" {
-		// X-Forwarded-For может содержать список IP через запятую
-		ips := strings.Split(ip, ",")
-		if len(ips) > 0 {
-			return strings.TrimSpace(ips[0])
+// handleUnavailable обрабатывает истекшую или исчерпавшую лимит переходов ссылку:
+// ведет на резервный URL владельца, если он задан, иначе отвечает 410 Gone
+func (h *RedirectHandler) handleUnavailable(w http.ResponseWriter, r *http.Request, link *domain.Link, reason error) {
+	h.log.Debug("link unavailable", zap.String("alias", link.Alias), zap.Error(reason))
+
+	// Резервный адрес может смениться, поэтому постоянный редирект на него не используется
+	if link.FallbackURL != nil && *link.FallbackURL != "" {
+		h.sendRedirect(w, r, link, *link.FallbackURL, link.Redirect().TemporaryStatusCode())
+		return
+	}
+
+	message := "This link has expired"
+	if reason == repository.ErrLinkExhausted {
+		message = "This link has reached its click limit"
+	}
+
+	if isJSONRequest(r) {
+		h.writeJSONError(w, message, http.StatusGone)
+		return
+	}
+	http.Error(w, message, http.StatusGone)
+}
+
+// isUnlocked проверяет наличие действующей cookie разблокировки для ссылки
+func (h *RedirectHandler) isUnlocked(r *http.Request, link *domain.Link) bool {
+	cookie, err := r.Cookie(auth.LinkUnlockCookieName)
+	if err != nil {
+		return false
+	}
+	return h.linkUnlock.ValidateToken(link, cookie.Value)
+}
+
+// handleUnlock показывает форму ввода пароля или проверяет присланный пароль.
+// Возвращает true, если ссылка разблокирована и можно выполнять редирект.
+func (h *RedirectHandler) handleUnlock(w http.ResponseWriter, r *http.Request, link *domain.Link, ipAddress string) bool {
+	if r.Method != http.MethodPost {
+		if isJSONRequest(r) {
+			h.writeJSONError(w, "Password required", http.StatusUnauthorized)
+			return false
 		}
+		h.renderUnlockPage(w, r, link, "", http.StatusOK)
+		return false
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUnlockBodySize)
+	password, err := readUnlockPassword(r)
+	if err != nil {
+		h.respondUnlockError(w, r, link, "Invalid request format", http.StatusBadRequest)
+		return false
 	}
 
-	if ip := r.Header.Get("X-Real-IP"); ip != "" {
-		return strings.TrimSpace(ip)
+	token, err := h.linkUnlock.Unlock(link, ipAddress, password)
+	switch err {
+	case nil:
+	case auth.ErrTooManyUnlockAttempts:
+		h.log.Warn("too many unlock attempts", zap.String("alias", link.Alias), zap.String("ip", ipAddress))
+		h.respondUnlockError(w, r, link, "Too many attempts. Please try again later.", http.StatusTooManyRequests)
+		return false
+	case auth.ErrInvalidPassword:
+		h.log.Debug("invalid link password", zap.String("alias", link.Alias), zap.String("ip", ipAddress))
+		h.respondUnlockError(w, r, link, "Invalid password", http.StatusUnauthorized)
+		return false
+	default:
+		h.log.Error("failed to unlock link", zap.String("alias", link.Alias), zap.Error(err))
+		h.respondUnlockError(w, r, link, "Internal server error", http.StatusInternalServerError)
+		return false
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     auth.LinkUnlockCookieName,
+		Value:    token,
+		Path:     "/" + link.Alias,
+		Expires:  time.Now().Add(h.linkUnlock.TokenDuration()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	h.log.Info("link unlocked", zap.String("alias", link.Alias), zap.String("ip", ipAddress))
+	return true
+}
+
+// respondUnlockError отправляет ошибку разблокировки в формате, который ожидает клиент
+func (h *RedirectHandler) respondUnlockError(w http.ResponseWriter, r *http.Request, link *domain.Link, message string, statusCode int) {
+	if isJSONRequest(r) {
+		h.writeJSONError(w, message, statusCode)
+		return
+	}
+	h.renderUnlockPage(w, r, link, message, statusCode)
+}
+
+// renderUnlockPage отдает HTML форму ввода пароля
+func (h *RedirectHandler) renderUnlockPage(w http.ResponseWriter, r *http.Request, link *domain.Link, message string, statusCode int) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	// Форма отправляется на тот же адрес, чтобы сохранить хвост пути и query string
+	data := unlockPageData{Action: r.URL.RequestURI(), Error: message}
+	if err := pageTemplates.ExecuteTemplate(w, "unlock.html", data); err != nil {
+		h.log.Error("failed to render unlock page", zap.String("alias", link.Alias), zap.Error(err))
 	}
+}
+
+func (h *RedirectHandler) writeJSON(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
 
-	if ip := r.Header.Get("X-Client-IP"); ip != "" {
-		return strings.TrimSpace(ip)
+func (h *RedirectHandler) writeJSONError(w http.ResponseWriter, message string, statusCode int) {
+	h.writeJSON(w, map[string]string{"error": message}, statusCode)
+}
+
+// readUnlockPassword извлекает пароль из JSON тела или из HTML формы
+func readUnlockPassword(r *http.Request) (string, error) {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		var req UnlockLinkRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return "", err
+		}
+		return req.Password, nil
+	}
+	if err := r.ParseForm(); err != nil {
+		return "", err
 	}
+	return r.PostFormValue("password"), nil
+}
+
+// isJSONRequest определяет, ожидает ли клиент JSON вместо HTML
+func isJSONRequest(r *http.Request) bool {
+	return strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") ||
+		strings.Contains(r.Header.Get("Accept"), "application/json")
+}
 
-	// Fallback к RemoteAddr
-	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+// extractIPAddress извлекает IP адрес клиента. X-Forwarded-For учитывается только для запросов
+// от доверенных прокси: иначе клиент может подставить любой адрес и обойти лимит попыток ввода пароля.
+func extractIPAddress(r *http.Request, trustedProxies []*net.IPNet) string {
+	remoteIP, _, err := net.SplitHostPort(r.RemoteAddr)
 	if err != nil {
-		return r.RemoteAddr
+		remoteIP = r.RemoteAddr
+	}
+	if !isTrustedProxy(net.ParseIP(remoteIP), trustedProxies) {
+		return remoteIP
+	}
+
+	// X-Forwarded-For дополняется каждым прокси справа: клиент - первый адрес справа,
+	// который не принадлежит доверенному прокси. Левее него адреса задает сам клиент, как и
+	// X-Real-IP, который многие прокси пропускают без изменений, поэтому без такого адреса
+	// используется адрес соединения.
+	ips := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(ips) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(ips[i]))
+		if ip == nil {
+			break
+		}
+		if !isTrustedProxy(ip, trustedProxies) {
+			return ip.String()
+		}
+	}
+	return remoteIP
+}
+
+// isTrustedProxy проверяет, что адрес принадлежит доверенному прокси
+func isTrustedProxy(ip net.IP, trustedProxies []*net.IPNet) bool {
+	if ip == nil {
+		return false
+	}
+	for _, network := range trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTrustedProxies разбирает адреса и подсети (CIDR) доверенных прокси
+func ParseTrustedProxies(values []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", value)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", value, err)
+		}
+		networks = append(networks, network)
 	}
-	return ip
+	return networks, nil
 }
 
 // detectDeviceType определяет тип устройства по User-Agent
@@ -0,0 +1,273 @@
+package http
+
+import (
+	"GURLS-Backend/internal/auth"
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/repository"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RedirectRuleRequest правило редиректа в запросе замены правил.
+// Правило подходит посетителю, если совпадают все заданные условия; нужно хотя бы одно условие.
+// Дни недели и время суток проверяются в часовом поясе ссылки.
+type RedirectRuleRequest struct {
+	ID             int64    `json:"id,omitempty"` // ID существующего правила, чтобы сохранить его статистику
+	Name           string   `json:"name,omitempty"`
+	Countries      []string `json:"countries,omitempty"`
+	DeviceTypes    []string `json:"device_types,omitempty"` // desktop, mobile, tablet, bot, unknown
+	OSFamilies     []string `json:"os_families,omitempty"`  // iOS, Android, Windows, Mac OS X...
+	Browsers       []string `json:"browsers,omitempty"`     // Chrome, Mobile Safari, Firefox...
+	ActiveFrom     string   `json:"active_from,omitempty"`  // RFC3339, начало периода действия правила
+	ActiveUntil    string   `json:"active_until,omitempty"` // RFC3339, конец периода действия правила
+	Weekdays       []string `json:"weekdays,omitempty"`     // mon, tue, wed, thu, fri, sat, sun
+	TimeFrom       string   `json:"time_from,omitempty"`    // HH:MM, вместе с time_to
+	TimeTo         string   `json:"time_to,omitempty"`      // HH:MM, раньше time_from - окно через полночь
+	DestinationURL string   `json:"destination_url"`
+}
+
+// ReplaceRedirectRulesRequest структура запроса замены правил редиректа ссылки.
+// Правила проверяются в порядке списка; пустой список удаляет все правила.
+type ReplaceRedirectRulesRequest struct {
+	Rules []RedirectRuleRequest `json:"rules"`
+}
+
+// RedirectRuleInfo информация о правиле редиректа
+type RedirectRuleInfo struct {
+	ID             int64    `json:"id"`
+	Position       int      `json:"position"`
+	Name           string   `json:"name,omitempty"`
+	Countries      []string `json:"countries,omitempty"`
+	DeviceTypes    []string `json:"device_types,omitempty"`
+	OSFamilies     []string `json:"os_families,omitempty"`
+	Browsers       []string `json:"browsers,omitempty"`
+	ActiveFrom     string   `json:"active_from,omitempty"`
+	ActiveUntil    string   `json:"active_until,omitempty"`
+	Weekdays       []string `json:"weekdays,omitempty"`
+	TimeFrom       string   `json:"time_from,omitempty"`
+	TimeTo         string   `json:"time_to,omitempty"`
+	DestinationURL string   `json:"destination_url"`
+}
+
+// ListRedirectRulesResponse структура ответа со списком правил редиректа ссылки
+type ListRedirectRulesResponse struct {
+	Alias string             `json:"alias"`
+	Rules []RedirectRuleInfo `json:"rules"`
+}
+
+// ListRedirectRules возвращает правила редиректа ссылки
+//
+//	@Summary		List redirect rules
+//	@Description	Get the link's redirect rules in evaluation order
+//	@Tags			Links
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			alias	path		string						true	"Link alias"
+//	@Param			domain	query		string						false	"Custom domain of the link, omitted for the service domain"
+//	@Success		200		{object}	ListRedirectRulesResponse	"Redirect rules"
+//	@Failure		401		{object}	map[string]string			"Authentication required"
+//	@Failure		403		{object}	map[string]string			"Access denied"
+//	@Failure		404		{object}	map[string]string			"Link not found"
+//	@Router			/api/links/{alias}/rules [get]
+func (h *LinksHandler) ListRedirectRules(w http.ResponseWriter, r *http.Request) {
+	alias := extractAlias(r)
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	link, ok := h.getOwnedLink(w, r, alias, userID)
+	if !ok {
+		return
+	}
+
+	rules, err := h.storage.ListRedirectRules(r.Context(), link.ID)
+	if err != nil {
+		h.log.Error("failed to list redirect rules", zap.String("alias", alias), zap.Error(err))
+		h.writeError(w, "Failed to retrieve redirect rules", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, newListRedirectRulesResponse(link.Alias, rules), http.StatusOK)
+}
+
+// ReplaceRedirectRules заменяет правила редиректа ссылки
+//
+//	@Summary		Replace redirect rules
+//	@Description	Replace the link's redirect rules. Rules are evaluated in list order and the first rule whose conditions (countries, device types, OS families, browsers, active period, weekdays, time of day in the link's timezone) all match the visitor wins; visitors matching no rule go to the link destination. Pass the id of an existing rule to keep its click statistics.
+//	@Tags			Links
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			alias	path		string						true	"Link alias"
+//	@Param			domain	query		string						false	"Custom domain of the link, omitted for the service domain"
+//	@Param			request	body		ReplaceRedirectRulesRequest	true	"Redirect rules"
+//	@Success		200		{object}	ListRedirectRulesResponse	"Updated redirect rules"
+//	@Failure		400		{object}	map[string]string			"Invalid request data"
+//	@Failure		401		{object}	map[string]string			"Authentication required"
+//	@Failure		403		{object}	map[string]string			"Access denied"
+//	@Failure		404		{object}	map[string]string			"Link not found"
+//	@Router			/api/links/{alias}/rules [put]
+func (h *LinksHandler) ReplaceRedirectRules(w http.ResponseWriter, r *http.Request) {
+	alias := extractAlias(r)
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	var req ReplaceRedirectRulesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "Invalid request format", http.StatusBadRequest)
+		return
+	}
+
+	link, ok := h.getOwnedLink(w, r, alias, userID)
+	if !ok {
+		return
+	}
+
+	rules, err := newRedirectRules(req.Rules)
+	if err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	for i := range rules {
+		destinationURL, ok := h.checkDestination(w, r, fmt.Sprintf("destination_url in rule %d", i+1), rules[i].DestinationURL)
+		if !ok {
+			return
+		}
+		rules[i].DestinationURL = destinationURL
+	}
+
+	if err := h.storage.ReplaceRedirectRules(r.Context(), link.ID, rules); err != nil {
+		if err == repository.ErrRedirectRuleNotFound {
+			h.writeError(w, "Redirect rule not found", http.StatusBadRequest)
+			return
+		}
+		h.log.Error("failed to replace redirect rules", zap.String("alias", alias), zap.Error(err))
+		h.writeError(w, "Failed to update redirect rules", http.StatusInternalServerError)
+		return
+	}
+
+	h.log.Info("updated redirect rules", zap.String("alias", alias), zap.Int("count", len(rules)))
+	h.writeJSON(w, newListRedirectRulesResponse(link.Alias, rules), http.StatusOK)
+}
+
+// newRedirectRules проверяет правила из запроса и преобразует их в доменные
+func newRedirectRules(requests []RedirectRuleRequest) ([]domain.RedirectRule, error) {
+	if len(requests) > domain.MaxRedirectRulesPerLink {
+		return nil, fmt.Errorf("too many redirect rules (max %d)", domain.MaxRedirectRulesPerLink)
+	}
+
+	rules := make([]domain.RedirectRule, 0, len(requests))
+	for i, req := range requests {
+		rule, err := newRedirectRule(req)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i+1, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// newRedirectRule проверяет и нормализует условия одного правила
+func newRedirectRule(req RedirectRuleRequest) (domain.RedirectRule, error) {
+	rule := domain.RedirectRule{
+		ID:             req.ID,
+		Name:           strings.TrimSpace(req.Name),
+		DestinationURL: req.DestinationURL,
+	}
+	if len(rule.Name) > 100 {
+		return rule, fmt.Errorf("name is too long (max 100 characters)")
+	}
+
+	var err error
+	if rule.Countries, err = domain.NormalizeCountries(req.Countries); err != nil {
+		return rule, err
+	}
+	if rule.DeviceTypes, err = domain.NormalizeDeviceTypes(req.DeviceTypes); err != nil {
+		return rule, err
+	}
+	if rule.OSFamilies, err = domain.NormalizeRuleNames(req.OSFamilies); err != nil {
+		return rule, err
+	}
+	if rule.Browsers, err = domain.NormalizeRuleNames(req.Browsers); err != nil {
+		return rule, err
+	}
+	if rule.Weekdays, err = domain.NormalizeWeekdays(req.Weekdays); err != nil {
+		return rule, err
+	}
+
+	if req.ActiveFrom != "" {
+		activeFrom, err := time.Parse(time.RFC3339, req.ActiveFrom)
+		if err != nil {
+			return rule, fmt.Errorf("invalid active_from format, use RFC3339")
+		}
+		rule.ActiveFrom = &activeFrom
+	}
+	if req.ActiveUntil != "" {
+		activeUntil, err := time.Parse(time.RFC3339, req.ActiveUntil)
+		if err != nil {
+			return rule, fmt.Errorf("invalid active_until format, use RFC3339")
+		}
+		rule.ActiveUntil = &activeUntil
+	}
+	if rule.ActiveFrom != nil && rule.ActiveUntil != nil && !rule.ActiveUntil.After(*rule.ActiveFrom) {
+		return rule, domain.ErrInvalidActivePeriod
+	}
+
+	if req.TimeFrom != "" || req.TimeTo != "" {
+		from, errFrom := domain.ParseTimeOfDay(req.TimeFrom)
+		to, errTo := domain.ParseTimeOfDay(req.TimeTo)
+		if errFrom != nil || errTo != nil || from == to {
+			return rule, domain.ErrInvalidTimeOfDay
+		}
+		rule.TimeFrom, rule.TimeTo = req.TimeFrom, req.TimeTo
+	}
+
+	if !rule.HasConditions() {
+		return rule, domain.ErrEmptyRuleConditions
+	}
+
+	if !isValidRedirectURL(req.DestinationURL) {
+		return rule, fmt.Errorf("invalid destination_url, use an absolute http(s) URL")
+	}
+	return rule, nil
+}
+
+// newListRedirectRulesResponse преобразует правила редиректа в формат ответа API
+func newListRedirectRulesResponse(alias string, rules []domain.RedirectRule) ListRedirectRulesResponse {
+	response := ListRedirectRulesResponse{
+		Alias: alias,
+		Rules: make([]RedirectRuleInfo, len(rules)),
+	}
+	for i, rule := range rules {
+		response.Rules[i] = RedirectRuleInfo{
+			ID:             rule.ID,
+			Position:       rule.Position,
+			Name:           rule.Name,
+			Countries:      rule.CountryList(),
+			DeviceTypes:    rule.DeviceTypeList(),
+			OSFamilies:     rule.OSFamilyList(),
+			Browsers:       rule.BrowserList(),
+			Weekdays:       rule.WeekdayList(),
+			TimeFrom:       rule.TimeFrom,
+			TimeTo:         rule.TimeTo,
+			DestinationURL: rule.DestinationURL,
+		}
+		if rule.ActiveFrom != nil {
+			response.Rules[i].ActiveFrom = rule.ActiveFrom.Format(time.RFC3339)
+		}
+		if rule.ActiveUntil != nil {
+			response.Rules[i].ActiveUntil = rule.ActiveUntil.Format(time.RFC3339)
+		}
+	}
+	return response
+}
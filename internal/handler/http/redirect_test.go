@@ -0,0 +1,467 @@
+package http
+
+import (
+	"GURLS-Backend/internal/auth"
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/repository"
+	"GURLS-Backend/pkg/geoip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// redirectStorage ссылки, правила и варианты в памяти; остальные методы Storage не используются
+type redirectStorage struct {
+	repository.Storage
+	mu       sync.Mutex
+	links    map[string]*domain.Link
+	rules    map[int64][]domain.RedirectRule
+	variants map[int64][]domain.LinkVariant
+	clicks   []*domain.Click
+}
+
+func newRedirectStorage(links ...*domain.Link) *redirectStorage {
+	storage := &redirectStorage{
+		links:    make(map[string]*domain.Link),
+		rules:    make(map[int64][]domain.RedirectRule),
+		variants: make(map[int64][]domain.LinkVariant),
+	}
+	for _, link := range links {
+		storage.links[link.Alias] = link
+	}
+	return storage
+}
+
+func (s *redirectStorage) GetLink(ctx context.Context, domainID *int64, alias string) (*domain.Link, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	link, ok := s.links[alias]
+	if !ok || !link.IsActive || link.DeletedAt != nil {
+		return nil, repository.ErrAliasNotFound
+	}
+	copied := *link
+	return &copied, nil
+}
+
+func (s *redirectStorage) FindLink(ctx context.Context, domainID *int64, alias string) (*domain.Link, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	link, ok := s.links[alias]
+	if !ok || link.DeletedAt != nil {
+		return nil, repository.ErrAliasNotFound
+	}
+	copied := *link
+	return &copied, nil
+}
+
+func (s *redirectStorage) GetLinkAndRecordClick(ctx context.Context, domainID *int64, alias string, click *domain.Click) (*domain.Link, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	link, ok := s.links[alias]
+	switch {
+	case !ok || !link.IsActive || link.DeletedAt != nil:
+		return nil, repository.ErrAliasNotFound
+	case link.IsNotStarted():
+		return nil, repository.ErrLinkNotStarted
+	case link.IsExpired():
+		return nil, repository.ErrLinkExpired
+	case link.IsExhausted():
+		return nil, repository.ErrLinkExhausted
+	}
+	link.ClickCount++
+	click.LinkID = link.ID
+	s.clicks = append(s.clicks, click)
+	copied := *link
+	return &copied, nil
+}
+
+func (s *redirectStorage) ListRedirectRules(ctx context.Context, linkID int64) ([]domain.RedirectRule, error) {
+	return s.rules[linkID], nil
+}
+
+func (s *redirectStorage) ListLinkVariants(ctx context.Context, linkID int64) ([]domain.LinkVariant, error) {
+	return s.variants[linkID], nil
+}
+
+func (s *redirectStorage) clickCount(alias string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.links[alias].ClickCount
+}
+
+// newTestRedirectHandler обработчик редиректов для домена сервиса sho.rt
+func newTestRedirectHandler(t *testing.T, storage repository.Storage) *RedirectHandler {
+	t.Helper()
+	unlock := auth.NewLinkUnlockService(&auth.LinkUnlockConfig{
+		SecretKey:      []byte("test-unlock-secret"),
+		TokenDuration:  time.Minute,
+		MaxAttempts:    5,
+		AttemptsWindow: time.Minute,
+	}, auth.NewPasswordServiceWithCost(4))
+	return NewRedirectHandler(storage, unlock, geoip.Open("", zap.NewNop()), nil, zap.NewNop(), "https://sho.rt")
+}
+
+// serveRedirect выполняет запрос к обработчику редиректов
+func serveRedirect(h *RedirectHandler, r *http.Request) *httptest.ResponseRecorder {
+	r.Host = "sho.rt"
+	w := httptest.NewRecorder()
+	h.HandleRedirect(w, r)
+	return w
+}
+
+func TestHandleRedirect_ExpiredAndExhausted(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	limit := 2
+	fallback := "https://example.com/sold-out"
+	storage := newRedirectStorage(
+		&domain.Link{ID: 1, Alias: "expired", OriginalURL: "https://example.com/a", IsActive: true, ExpiresAt: &past},
+		&domain.Link{ID: 2, Alias: "exhausted", OriginalURL: "https://example.com/b", IsActive: true, MaxClicks: &limit, ClickCount: 2},
+		&domain.Link{ID: 3, Alias: "fallback", OriginalURL: "https://example.com/c", IsActive: true, MaxClicks: &limit, ClickCount: 2, FallbackURL: &fallback},
+		&domain.Link{ID: 4, Alias: "last", OriginalURL: "https://example.com/d", IsActive: true, MaxClicks: &limit, ClickCount: 1},
+	)
+	h := newTestRedirectHandler(t, storage)
+
+	w := serveRedirect(h, httptest.NewRequest(http.MethodGet, "/expired", nil))
+	assert.Equal(t, http.StatusGone, w.Code)
+	assert.Contains(t, w.Body.String(), "expired")
+
+	r := httptest.NewRequest(http.MethodGet, "/exhausted", nil)
+	r.Header.Set("Accept", "application/json")
+	w = serveRedirect(h, r)
+	assert.Equal(t, http.StatusGone, w.Code)
+	assert.Contains(t, w.Body.String(), "click limit")
+
+	w = serveRedirect(h, httptest.NewRequest(http.MethodGet, "/fallback", nil))
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, fallback, w.Header().Get("Location"))
+
+	// последний разрешенный переход проходит, следующий получает 410
+	w = serveRedirect(h, httptest.NewRequest(http.MethodGet, "/last", nil))
+	assert.Equal(t, http.StatusFound, w.Code)
+	w = serveRedirect(h, httptest.NewRequest(http.MethodGet, "/last", nil))
+	assert.Equal(t, http.StatusGone, w.Code)
+	assert.Equal(t, int64(2), storage.clickCount("last"))
+}
+
+func TestExtractIPAddress(t *testing.T) {
+	trusted, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.0.2.1"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		want       string
+	}{
+		{"direct client", "198.51.100.7:5000", nil, "198.51.100.7"},
+		{"spoofed header from untrusted client", "198.51.100.7:5000", map[string]string{"X-Forwarded-For": "203.0.113.9", "X-Real-IP": "203.0.113.9"}, "198.51.100.7"},
+		{"trusted proxy", "10.1.2.3:80", map[string]string{"X-Forwarded-For": "203.0.113.9"}, "203.0.113.9"},
+		{"client prepends a fake address", "10.1.2.3:80", map[string]string{"X-Forwarded-For": "1.1.1.1, 203.0.113.9"}, "203.0.113.9"},
+		{"proxy chain", "192.0.2.1:80", map[string]string{"X-Forwarded-For": "203.0.113.9, 10.0.0.5"}, "203.0.113.9"},
+		{"real ip header is ignored", "10.1.2.3:80", map[string]string{"X-Real-IP": "203.0.113.9", "X-Client-IP": "203.0.113.9"}, "10.1.2.3"},
+		{"only trusted hops", "10.1.2.3:80", map[string]string{"X-Forwarded-For": "10.0.0.7, 192.0.2.1", "X-Real-IP": "203.0.113.9"}, "10.1.2.3"},
+		{"garbage header", "10.1.2.3:80", map[string]string{"X-Forwarded-For": "not-an-ip"}, "10.1.2.3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/promo", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for name, value := range tt.headers {
+				r.Header.Set(name, value)
+			}
+			assert.Equal(t, tt.want, extractIPAddress(r, trusted))
+		})
+	}
+
+	_, err = ParseTrustedProxies([]string{"proxy.local"})
+	assert.Error(t, err)
+}
+
+func TestHandleRedirect_VariantStickyCookie(t *testing.T) {
+	storage := newRedirectStorage(&domain.Link{ID: 1, Alias: "ab", OriginalURL: "https://example.com/original", IsActive: true})
+	storage.variants[1] = []domain.LinkVariant{
+		{ID: 10, LinkID: 1, DestinationURL: "https://example.com/a", Weight: 1},
+		{ID: 11, LinkID: 1, DestinationURL: "https://example.com/b", Weight: 1},
+	}
+	h := newTestRedirectHandler(t, storage)
+
+	w := serveRedirect(h, httptest.NewRequest(http.MethodGet, "/ab", nil))
+	require.Equal(t, http.StatusFound, w.Code)
+	first := w.Header().Get("Location")
+	assert.Contains(t, []string{"https://example.com/a", "https://example.com/b"}, first)
+
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, variantCookieName, cookies[0].Name)
+	assert.Equal(t, "/ab", cookies[0].Path)
+
+	// С cookie посетитель каждый раз попадает на тот же вариант
+	for i := 0; i < 20; i++ {
+		r := httptest.NewRequest(http.MethodGet, "/ab", nil)
+		r.AddCookie(cookies[0])
+		w := serveRedirect(h, r)
+		require.Equal(t, http.StatusFound, w.Code)
+		assert.Equal(t, first, w.Header().Get("Location"))
+	}
+
+	// Cookie удаленного варианта игнорируется: вариант выбирается заново
+	r := httptest.NewRequest(http.MethodGet, "/ab", nil)
+	r.AddCookie(&http.Cookie{Name: variantCookieName, Value: "999"})
+	w = serveRedirect(h, r)
+	require.Equal(t, http.StatusFound, w.Code)
+	assert.NotEqual(t, "https://example.com/original", w.Header().Get("Location"))
+
+	// Каждый переход записан с вариантом, на который попал посетитель
+	require.Len(t, storage.clicks, 22)
+	for _, click := range storage.clicks {
+		assert.NotNil(t, click.VariantID)
+	}
+}
+
+func TestHandleRedirect_VariantWeights(t *testing.T) {
+	storage := newRedirectStorage(&domain.Link{ID: 1, Alias: "ab", OriginalURL: "https://example.com/original", IsActive: true})
+	storage.variants[1] = []domain.LinkVariant{
+		{ID: 10, LinkID: 1, DestinationURL: "https://example.com/a", Weight: 1},
+		{ID: 11, LinkID: 1, DestinationURL: "https://example.com/b", Weight: 1000},
+	}
+	h := newTestRedirectHandler(t, storage)
+
+	// Без cookie вариант выбирается по весам: вариант с весом 1 из 1001 почти не выпадает
+	counts := make(map[string]int)
+	for i := 0; i < 200; i++ {
+		w := serveRedirect(h, httptest.NewRequest(http.MethodGet, "/ab", nil))
+		require.Equal(t, http.StatusFound, w.Code)
+		counts[w.Header().Get("Location")]++
+	}
+	assert.Greater(t, counts["https://example.com/b"], 190)
+	assert.Zero(t, counts["https://example.com/original"])
+}
+
+func TestHandleRedirect_RedirectModes(t *testing.T) {
+	target := "https://example.com/target"
+	tests := []struct {
+		mode     domain.RedirectMode
+		status   int
+		location string
+		body     []string
+	}{
+		{"", http.StatusFound, target, nil},
+		{domain.RedirectMovedPermanently, http.StatusMovedPermanently, target, nil},
+		{domain.RedirectFound, http.StatusFound, target, nil},
+		{domain.RedirectTemporary, http.StatusTemporaryRedirect, target, nil},
+		{domain.RedirectPermanent, http.StatusPermanentRedirect, target, nil},
+		{domain.RedirectMetaRefresh, http.StatusOK, "", []string{`content="0;url=https://example.com/target"`}},
+		{domain.RedirectInterstitial, http.StatusOK, "", []string{
+			`content="1;url=https://example.com/target"`,
+			"Переходим по ссылке",
+			`<img src="https://pixel.example.com/p.gif"`,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run("mode "+string(tt.mode), func(t *testing.T) {
+			storage := newRedirectStorage(&domain.Link{
+				ID:             1,
+				Alias:          "promo",
+				OriginalURL:    target,
+				IsActive:       true,
+				RedirectMode:   string(tt.mode),
+				TrackingPixels: "https://pixel.example.com/p.gif",
+			})
+			h := newTestRedirectHandler(t, storage)
+
+			w := serveRedirect(h, httptest.NewRequest(http.MethodGet, "/promo", nil))
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.location, w.Header().Get("Location"))
+			for _, fragment := range tt.body {
+				assert.Contains(t, w.Body.String(), fragment)
+			}
+			if tt.status == http.StatusOK {
+				assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+				assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+			}
+			if tt.mode != domain.RedirectInterstitial {
+				assert.NotContains(t, w.Body.String(), "pixel.example.com")
+			}
+			assert.Equal(t, int64(1), storage.clickCount("promo"))
+		})
+	}
+}
+
+func TestHandleRedirect_UnfurlBotIsNotAClick(t *testing.T) {
+	cardTitle := "Весенняя распродажа"
+	pageTitle := "Example shop"
+	storage := newRedirectStorage(
+		&domain.Link{ID: 1, Alias: "card", OriginalURL: "https://example.com/card", IsActive: true, SocialPreview: domain.SocialPreview{Title: &cardTitle}},
+		&domain.Link{ID: 2, Alias: "plain", OriginalURL: "https://example.com/plain", IsActive: true, Metadata: domain.LinkMetadata{Title: pageTitle}},
+	)
+	h := newTestRedirectHandler(t, storage)
+
+	tests := []struct {
+		alias string
+		title string
+	}{
+		{"card", cardTitle},
+		// без карточки владельца бот тоже получает карточку, а не редирект
+		{"plain", pageTitle},
+	}
+	for _, tt := range tests {
+		t.Run(tt.alias, func(t *testing.T) {
+			for _, userAgent := range []string{"TelegramBot (like TwitterBot)", "facebookexternalhit/1.1", "Slackbot-LinkExpanding 1.0"} {
+				r := httptest.NewRequest(http.MethodGet, "/"+tt.alias, nil)
+				r.Header.Set("User-Agent", userAgent)
+				w := serveRedirect(h, r)
+
+				assert.Equal(t, http.StatusOK, w.Code, userAgent)
+				assert.Empty(t, w.Header().Get("Location"))
+				assert.Contains(t, w.Body.String(), `<meta property="og:title" content="`+tt.title+`">`)
+				assert.NotContains(t, w.Body.String(), "example.com/"+tt.alias)
+			}
+			assert.Equal(t, int64(0), storage.clickCount(tt.alias))
+
+			// обычный браузер по-прежнему считается переходом
+			r := httptest.NewRequest(http.MethodGet, "/"+tt.alias, nil)
+			r.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 Chrome/120.0 Safari/537.36")
+			w := serveRedirect(h, r)
+			assert.Equal(t, http.StatusFound, w.Code)
+			assert.Equal(t, int64(1), storage.clickCount(tt.alias))
+		})
+	}
+}
+
+func TestHandleRedirect_UnfurlBotRespectsAvailability(t *testing.T) {
+	cardTitle := "Весенняя распродажа"
+	card := domain.SocialPreview{Title: &cardTitle}
+	hash := "$2a$04$hash"
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	limit := 2
+	fallback := "https://example.com/sold-out"
+	storage := newRedirectStorage(
+		&domain.Link{ID: 1, Alias: "soon", OriginalURL: "https://example.com/soon", IsActive: true, StartsAt: &future, SocialPreview: card},
+		&domain.Link{ID: 2, Alias: "expired", OriginalURL: "https://example.com/expired", IsActive: true, ExpiresAt: &past, SocialPreview: card},
+		&domain.Link{ID: 3, Alias: "exhausted", OriginalURL: "https://example.com/exhausted", IsActive: true, MaxClicks: &limit, ClickCount: 2, SocialPreview: card},
+		&domain.Link{ID: 4, Alias: "fallback", OriginalURL: "https://example.com/fallback", IsActive: true, ExpiresAt: &past, FallbackURL: &fallback, SocialPreview: card},
+		&domain.Link{ID: 5, Alias: "secret", OriginalURL: "https://example.com/secret", IsActive: true, PasswordHash: &hash, SocialPreview: card},
+	)
+	h := newTestRedirectHandler(t, storage)
+
+	tests := []struct {
+		alias    string
+		code     int
+		location string
+	}{
+		{"soon", http.StatusNotFound, ""},
+		{"expired", http.StatusGone, ""},
+		{"exhausted", http.StatusGone, ""},
+		// бот, как и посетитель, уходит на запасной адрес
+		{"fallback", http.StatusFound, fallback},
+		// вместо карточки - страница ввода пароля
+		{"secret", http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.alias, func(t *testing.T) {
+			clicks := storage.clickCount(tt.alias)
+			r := httptest.NewRequest(http.MethodGet, "/"+tt.alias, nil)
+			r.Header.Set("User-Agent", "TelegramBot (like TwitterBot)")
+			w := serveRedirect(h, r)
+
+			assert.Equal(t, tt.code, w.Code)
+			assert.Equal(t, tt.location, w.Header().Get("Location"))
+			assert.NotContains(t, w.Body.String(), `property="og:title"`)
+			assert.NotContains(t, w.Body.String(), cardTitle)
+			assert.NotContains(t, w.Body.String(), "example.com/"+tt.alias)
+			assert.Equal(t, clicks, storage.clickCount(tt.alias))
+		})
+	}
+}
+
+func TestHandleRedirect_PermanentModeFallbackIsTemporary(t *testing.T) {
+	limit := 1
+	fallback := "https://example.com/sold-out"
+	storage := newRedirectStorage(&domain.Link{
+		ID:           1,
+		Alias:        "promo",
+		OriginalURL:  "https://example.com/target",
+		IsActive:     true,
+		RedirectMode: string(domain.RedirectPermanent),
+		MaxClicks:    &limit,
+		ClickCount:   1,
+		FallbackURL:  &fallback,
+	})
+	h := newTestRedirectHandler(t, storage)
+
+	// постоянный редирект закэшировался бы браузером и пережил бы снятие лимита
+	w := serveRedirect(h, httptest.NewRequest(http.MethodGet, "/promo", nil))
+	assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+	assert.Equal(t, fallback, w.Header().Get("Location"))
+}
+
+func TestHandlePreview_HidesDestination(t *testing.T) {
+	hash := "$2a$04$hash"
+	past := time.Now().Add(-time.Hour)
+	flaggedAt := time.Now()
+	threat := "phishing"
+	storage := newRedirectStorage(
+		&domain.Link{ID: 1, Alias: "open", OriginalURL: "https://example.com/open", IsActive: true},
+		&domain.Link{ID: 2, Alias: "secret", OriginalURL: "https://example.com/secret", IsActive: true, PasswordHash: &hash},
+		&domain.Link{ID: 3, Alias: "paused", OriginalURL: "https://example.com/paused", IsActive: false},
+		&domain.Link{ID: 4, Alias: "expired", OriginalURL: "https://example.com/expired", IsActive: true, ExpiresAt: &past},
+		&domain.Link{ID: 5, Alias: "flagged", OriginalURL: "https://example.com/flagged", IsActive: false, FlaggedAt: &flaggedAt, FlagReason: &threat},
+	)
+	h := newTestRedirectHandler(t, storage)
+
+	preview := func(alias string, jsonClient bool) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/"+alias+"+", nil)
+		if jsonClient {
+			r.Header.Set("Accept", "application/json")
+		}
+		return serveRedirect(h, r)
+	}
+
+	w := preview("open", true)
+	require.Equal(t, http.StatusOK, w.Code)
+	var open LinkPreviewResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &open))
+	assert.Equal(t, "https://example.com/open", open.DestinationURL)
+	assert.Equal(t, "example.com", open.DestinationHost)
+
+	w = preview("secret", true)
+	require.Equal(t, http.StatusOK, w.Code)
+	var secret LinkPreviewResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &secret))
+	assert.True(t, secret.HasPassword)
+	assert.Empty(t, secret.DestinationURL)
+	assert.Empty(t, secret.DestinationHost)
+
+	w = preview("expired", true)
+	require.Equal(t, http.StatusOK, w.Code)
+	var expired LinkPreviewResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &expired))
+	assert.Equal(t, "expired", expired.Status)
+	assert.Empty(t, expired.DestinationURL)
+
+	for _, alias := range []string{"secret", "paused", "expired", "flagged"} {
+		for _, jsonClient := range []bool{true, false} {
+			w := preview(alias, jsonClient)
+			assert.NotContains(t, w.Body.String(), "example.com/"+alias, "alias %s, json %v", alias, jsonClient)
+		}
+	}
+	assert.Equal(t, http.StatusNotFound, preview("paused", true).Code)
+	assert.Equal(t, http.StatusForbidden, preview("flagged", false).Code)
+
+	// предпросмотр не считается переходом
+	for _, link := range storage.links {
+		assert.Zero(t, link.ClickCount, link.Alias)
+	}
+	assert.Empty(t, storage.clicks)
+}
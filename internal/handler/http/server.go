@@ -4,6 +4,9 @@ import (
 	"GURLS-Backend/internal/auth"
 	"GURLS-Backend/internal/repository"
 	"GURLS-Backend/internal/service"
+	"GURLS-Backend/pkg/geoip"
+	"GURLS-Backend/pkg/urlpolicy"
+	"net"
 	"net/http"
 	"strings"
 
@@ -15,11 +18,16 @@ import (
 type Server struct {
 	authHandlers         *auth.AuthHandlers
 	linksHandler         *LinksHandler
+	bulkLinksHandler     *BulkLinksHandler
+	tagsHandler          *TagsHandler
+	domainsHandler       *DomainsHandler
+	accountHandler       *AccountHandler
 	redirectHandler      *RedirectHandler
 	healthHandler        *HealthHandler
 	paymentHandler       *PaymentHandler
 	subscriptionHandler  *SubscriptionHandler
 	authMiddleware       *auth.Middleware
+	aliasPolicy          *service.AliasPolicy
 	log                  *zap.Logger
 }
 
@@ -28,15 +36,27 @@ func NewServer(
 	storage repository.Storage,
 	urlShortener *service.URLShortenerService,
 	paymentService *service.PaymentService,
+	domainService *service.DomainService,
+	aliasPolicy *service.AliasPolicy,
+	urlPolicy *urlpolicy.Policy,
+	reputationService *service.ReputationService,
+	metadataService *service.MetadataService,
 	jwtService *auth.JWTService,
 	passwordService *auth.PasswordService,
+	linkUnlockService *auth.LinkUnlockService,
+	geoDB *geoip.Database,
+	trustedProxies []*net.IPNet,
 	log *zap.Logger,
 	baseURL string,
 ) *Server {
 	// Создаем handlers
 	authHandlers := auth.NewAuthHandlers(storage, jwtService, passwordService, log)
-	linksHandler := NewLinksHandler(storage, urlShortener, log, baseURL)
-	redirectHandler := NewRedirectHandler(storage, log)
+	linksHandler := NewLinksHandler(storage, urlShortener, urlPolicy, reputationService, metadataService, passwordService, log, baseURL)
+	bulkLinksHandler := NewBulkLinksHandler(service.NewBulkLinkService(storage, urlShortener, urlPolicy, reputationService, metadataService, log), log)
+	tagsHandler := NewTagsHandler(storage, log)
+	domainsHandler := NewDomainsHandler(storage, domainService, log, baseURL)
+	accountHandler := NewAccountHandler(storage, log)
+	redirectHandler := NewRedirectHandler(storage, linkUnlockService, geoDB, trustedProxies, log, baseURL)
 	healthHandler := NewHealthHandler(storage, log)
 	paymentHandler := NewPaymentHandler(storage, paymentService, log)
 	subscriptionHandler := NewSubscriptionHandler(storage, log)
@@ -47,18 +67,23 @@ func NewServer(
 	return &Server{
 		authHandlers:        authHandlers,
 		linksHandler:        linksHandler,
+		bulkLinksHandler:    bulkLinksHandler,
+		tagsHandler:         tagsHandler,
+		domainsHandler:      domainsHandler,
+		accountHandler:      accountHandler,
 		redirectHandler:     redirectHandler,
 		healthHandler:       healthHandler,
 		paymentHandler:      paymentHandler,
 		subscriptionHandler: subscriptionHandler,
 		authMiddleware:      authMiddleware,
+		aliasPolicy:         aliasPolicy,
 		log:                 log,
 	}
 }
 
-// SetupRoutes настраивает маршруты
+// SetupRoutes настраивает маршруты; первые сегменты путей маршрутов резервируются и не выдаются как алиасы
 func (s *Server) SetupRoutes() http.Handler {
-	mux := http.NewServeMux()
+	mux := &routeMux{ServeMux: http.NewServeMux()}
 
 	// Health checks (без аутентификации)
 	mux.HandleFunc("/health", s.healthHandler.Health)
@@ -79,9 +104,20 @@ func (s *Server) SetupRoutes() http.Handler {
 	// Stats endpoint - обрабатываем через custom router
 	mux.HandleFunc("/api/stats/", s.withCORS(s.authMiddleware.RequireAuth(s.linksHandler.GetStats)))
 	
-	// Delete endpoint - обрабатываем через custom router с авторизацией
+	// Update/delete/revisions endpoints - обрабатываем через custom router с авторизацией
 	mux.HandleFunc("/api/links/", s.withCORS(s.authMiddleware.RequireAuth(s.handleLinksAPI)))
 
+	// Tag endpoints (с аутентификацией)
+	mux.HandleFunc("/api/tags", s.withCORS(s.authMiddleware.RequireAuth(s.handleTagsAPI)))
+	mux.HandleFunc("/api/tags/", s.withCORS(s.authMiddleware.RequireAuth(s.handleTagsAPI)))
+
+	// Custom domain endpoints (с аутентификацией)
+	mux.HandleFunc("/api/domains", s.withCORS(s.authMiddleware.RequireAuth(s.handleDomainsAPI)))
+	mux.HandleFunc("/api/domains/", s.withCORS(s.authMiddleware.RequireAuth(s.handleDomainsAPI)))
+
+	// Account settings endpoints (с аутентификацией)
+	mux.HandleFunc("/api/account/utm-defaults", s.withCORS(s.authMiddleware.RequireAuth(s.handleUTMDefaults)))
+
 	// Payment endpoints (с аутентификацией)
 	mux.HandleFunc("/api/payments/create", s.withCORS(s.authMiddleware.RequireAuth(s.paymentHandler.CreatePayment)))
 	mux.HandleFunc("/api/payments/webhook", s.withCORS(s.paymentHandler.WebhookHandler)) // без аутентификации для webhook
@@ -96,14 +132,94 @@ func (s *Server) SetupRoutes() http.Handler {
 	// Redirect endpoint (без аутентификации) - должен быть последним
 	mux.HandleFunc("/", s.redirectHandler.HandleRedirect)
 
+	s.aliasPolicy.Reserve(mux.segments...)
+	s.aliasPolicy.Reserve(systemPathSegments...)
+
 	return mux
 }
 
+// routeMux регистрирует маршруты и запоминает первые сегменты их путей
+type routeMux struct {
+	*http.ServeMux
+	segments []string
+}
+
+func (m *routeMux) Handle(pattern string, handler http.Handler) {
+	m.reserve(pattern)
+	m.ServeMux.Handle(pattern, handler)
+}
+
+func (m *routeMux) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	m.reserve(pattern)
+	m.ServeMux.HandleFunc(pattern, handler)
+}
+
+func (m *routeMux) reserve(pattern string) {
+	segment, _, _ := strings.Cut(strings.TrimPrefix(pattern, "/"), "/")
+	if segment != "" {
+		m.segments = append(m.segments, segment)
+	}
+}
+
 // handleLinksAPI обрабатывает /api/links/* endpoints с разными HTTP методами
 func (s *Server) handleLinksAPI(w http.ResponseWriter, r *http.Request) {
+	// /api/links/bulk[/{job_id}], /api/links/export, /api/links/trash, /api/links/broken, /api/links/{alias}/restore,
+	// /api/links/{alias}/rules, /api/links/{alias}/variants, /api/links/{alias}/qr, /api/links/{alias}/appeal,
+	// /api/links/{alias}/metadata/refresh, /api/links/{alias}/revisions[/{id}/rollback]
+	pathParts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(pathParts) == 3 && pathParts[2] == "bulk" && r.Method == http.MethodPost {
+		s.bulkLinksHandler.CreateLinks(w, r)
+		return
+	}
+	if len(pathParts) == 4 && pathParts[2] == "bulk" && r.Method == http.MethodGet {
+		s.bulkLinksHandler.GetJob(w, r)
+		return
+	}
+	if len(pathParts) == 3 && pathParts[2] == "export" && r.Method == http.MethodGet {
+		s.linksHandler.ExportLinks(w, r)
+		return
+	}
+	if len(pathParts) == 3 && pathParts[2] == "trash" && r.Method == http.MethodGet {
+		s.linksHandler.ListTrash(w, r)
+		return
+	}
+	if len(pathParts) == 3 && pathParts[2] == "broken" && r.Method == http.MethodGet {
+		s.linksHandler.ListBrokenLinks(w, r)
+		return
+	}
+	if len(pathParts) > 3 {
+		switch {
+		case len(pathParts) == 4 && pathParts[3] == "restore" && r.Method == http.MethodPost:
+			s.linksHandler.RestoreLink(w, r)
+		case len(pathParts) == 4 && pathParts[3] == "rules" && r.Method == http.MethodGet:
+			s.linksHandler.ListRedirectRules(w, r)
+		case len(pathParts) == 4 && pathParts[3] == "rules" && r.Method == http.MethodPut:
+			s.linksHandler.ReplaceRedirectRules(w, r)
+		case len(pathParts) == 4 && pathParts[3] == "variants" && r.Method == http.MethodGet:
+			s.linksHandler.ListLinkVariants(w, r)
+		case len(pathParts) == 4 && pathParts[3] == "variants" && r.Method == http.MethodPut:
+			s.linksHandler.ReplaceLinkVariants(w, r)
+		case len(pathParts) == 4 && pathParts[3] == "qr" && r.Method == http.MethodGet:
+			s.linksHandler.GetLinkQRCode(w, r)
+		case len(pathParts) == 4 && pathParts[3] == "appeal" && r.Method == http.MethodPost:
+			s.linksHandler.AppealLink(w, r)
+		case len(pathParts) == 5 && pathParts[3] == "metadata" && pathParts[4] == "refresh" && r.Method == http.MethodPost:
+			s.linksHandler.RefreshLinkMetadata(w, r)
+		case len(pathParts) == 4 && pathParts[3] == "revisions" && r.Method == http.MethodGet:
+			s.linksHandler.ListRevisions(w, r)
+		case len(pathParts) == 6 && pathParts[3] == "revisions" && pathParts[5] == "rollback" && r.Method == http.MethodPost:
+			s.linksHandler.RollbackLink(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+		return
+	}
+
 	switch r.Method {
 	case http.MethodGet:
 		s.linksHandler.ListLinks(w, r)
+	case http.MethodPatch:
+		s.linksHandler.UpdateLink(w, r)
 	case http.MethodDelete:
 		s.linksHandler.DeleteLink(w, r)
 	default:
@@ -111,27 +227,77 @@ func (s *Server) handleLinksAPI(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleTagsAPI обрабатывает /api/tags, /api/tags/{id} и /api/tags/{id}/stats
+func (s *Server) handleTagsAPI(w http.ResponseWriter, r *http.Request) {
+	pathParts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	switch {
+	case len(pathParts) == 2 && r.Method == http.MethodGet:
+		s.tagsHandler.ListTags(w, r)
+	case len(pathParts) == 2 && r.Method == http.MethodPost:
+		s.tagsHandler.CreateTag(w, r)
+	case len(pathParts) == 3 && r.Method == http.MethodPatch:
+		s.tagsHandler.UpdateTag(w, r)
+	case len(pathParts) == 3 && r.Method == http.MethodDelete:
+		s.tagsHandler.DeleteTag(w, r)
+	case len(pathParts) == 4 && pathParts[3] == "stats" && r.Method == http.MethodGet:
+		s.tagsHandler.GetTagStats(w, r)
+	case len(pathParts) > 4 || (len(pathParts) == 4 && pathParts[3] != "stats"):
+		http.NotFound(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleDomainsAPI обрабатывает /api/domains, /api/domains/{id} и /api/domains/{id}/verify
+func (s *Server) handleDomainsAPI(w http.ResponseWriter, r *http.Request) {
+	pathParts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	switch {
+	case len(pathParts) == 2 && r.Method == http.MethodGet:
+		s.domainsHandler.ListDomains(w, r)
+	case len(pathParts) == 2 && r.Method == http.MethodPost:
+		s.domainsHandler.CreateDomain(w, r)
+	case len(pathParts) == 3 && r.Method == http.MethodGet:
+		s.domainsHandler.GetDomain(w, r)
+	case len(pathParts) == 3 && r.Method == http.MethodDelete:
+		s.domainsHandler.DeleteDomain(w, r)
+	case len(pathParts) == 4 && pathParts[3] == "verify" && r.Method == http.MethodPost:
+		s.domainsHandler.VerifyDomain(w, r)
+	case len(pathParts) > 4 || (len(pathParts) == 4 && pathParts[3] != "verify"):
+		http.NotFound(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleUTMDefaults обрабатывает GET и PUT /api/account/utm-defaults
+func (s *Server) handleUTMDefaults(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.accountHandler.GetUTMDefaults(w, r)
+	case http.MethodPut:
+		s.accountHandler.UpdateUTMDefaults(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
 // withCORS добавляет CORS headers к обработчику
 func (s *Server) withCORS(handler http.HandlerFunc) http.HandlerFunc {
 	return s.authMiddleware.CORS(handler)
 }
 
-// Utility method для проверки системных путей
+// systemPathSegments первые сегменты системных путей, которые не обрабатываются как алиасы
+var systemPathSegments = []string{"api", "health", "ready", "metrics", "swagger", "docs"}
+
+// isSystemPath проверяет, что первый сегмент пути относится к системным путям, а не к алиасу
 func isSystemPath(path string) bool {
-	systemPaths := []string{
-		"/api/",
-		"/health",
-		"/ready", 
-		"/metrics",
-		"/swagger/",
-		"/docs/",
-	}
-	
-	for _, systemPath := range systemPaths {
-		if strings.HasPrefix(path, systemPath) {
+	segment, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	for _, systemSegment := range systemPathSegments {
+		if segment == systemSegment {
 			return true
 		}
 	}
-	
 	return false
 }
\ No newline at end of file
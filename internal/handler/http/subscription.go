@@ -42,6 +42,7 @@ type SubscriptionPlanResponse struct {
 	APIAccess              bool    `json:"api_access"`
 	CustomDomains          bool    `json:"custom_domains"`
 	PrioritySupport        bool    `json:"priority_support"`
+	InterstitialRedirects  bool    `json:"interstitial_redirects"`
 	IsActive               bool    `json:"is_active"`
 }
 
@@ -97,6 +98,7 @@ func (h *SubscriptionHandler) ListSubscriptionPlans(w http.ResponseWriter, r *ht
 			APIAccess:              sub.APIAccess,
 			CustomDomains:          sub.CustomDomains,
 			PrioritySupport:        sub.PrioritySupport,
+			InterstitialRedirects:  sub.InterstitialRedirects,
 			IsActive:               sub.IsActive,
 		}
 		plans = append(plans, plan)
@@ -161,6 +163,7 @@ func (h *SubscriptionHandler) GetCurrentSubscription(w http.ResponseWriter, r *h
 		APIAccess:              subscriptionType.APIAccess,
 		CustomDomains:          subscriptionType.CustomDomains,
 		PrioritySupport:        subscriptionType.PrioritySupport,
+		InterstitialRedirects:  subscriptionType.InterstitialRedirects,
 		IsActive:               subscriptionType.IsActive,
 	}
 
@@ -184,6 +187,7 @@ func (h *SubscriptionHandler) GetCurrentSubscription(w http.ResponseWriter, r *h
 				APIAccess:              plan.APIAccess,
 				CustomDomains:          plan.CustomDomains,
 				PrioritySupport:        plan.PrioritySupport,
+				InterstitialRedirects:  plan.InterstitialRedirects,
 				IsActive:               plan.IsActive,
 			}
 			availablePlans = append(availablePlans, planResp)
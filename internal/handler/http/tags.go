@@ -0,0 +1,327 @@
+package http
+
+import (
+	"GURLS-Backend/internal/auth"
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/repository"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// TagsHandler обработчик тегов для группировки ссылок
+type TagsHandler struct {
+	storage repository.Storage
+	log     *zap.Logger
+}
+
+// NewTagsHandler создает новый обработчик тегов
+func NewTagsHandler(storage repository.Storage, log *zap.Logger) *TagsHandler {
+	return &TagsHandler{
+		storage: storage,
+		log:     log,
+	}
+}
+
+// TagRequest структура запроса создания или переименования тега
+type TagRequest struct {
+	Name string `json:"name"`
+}
+
+// TagInfo информация о теге
+type TagInfo struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	LinkCount int64  `json:"link_count"`
+	CreatedAt string `json:"created_at"`
+}
+
+// ListTagsResponse структура ответа списка тегов
+type ListTagsResponse struct {
+	Tags []TagInfo `json:"tags"`
+}
+
+// TagStatsResponse структура ответа агрегированной статистики по тегу
+type TagStatsResponse struct {
+	ID             int64            `json:"id"`
+	Name           string           `json:"name"`
+	LinkCount      int64            `json:"link_count"`
+	TotalClicks    int64            `json:"total_clicks"`
+	ClicksByDevice map[string]int64 `json:"clicks_by_device"`
+}
+
+// ListTags возвращает теги пользователя
+//
+//	@Summary		List tags
+//	@Description	Get the user's tags with the number of links in each
+//	@Tags			Tags
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	ListTagsResponse	"User tags"
+//	@Failure		401	{object}	map[string]string	"Authentication required"
+//	@Router			/api/tags [get]
+func (h *TagsHandler) ListTags(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	tags, err := h.storage.ListTags(r.Context(), userID)
+	if err != nil {
+		h.log.Error("failed to list tags", zap.Int64("user_id", userID), zap.Error(err))
+		h.writeError(w, "Failed to retrieve tags", http.StatusInternalServerError)
+		return
+	}
+
+	tagInfos := make([]TagInfo, len(tags))
+	for i, tag := range tags {
+		tagInfos[i] = newTagInfo(tag)
+	}
+
+	h.writeJSON(w, ListTagsResponse{Tags: tagInfos}, http.StatusOK)
+}
+
+// CreateTag создает тег
+//
+//	@Summary		Create a tag
+//	@Description	Create a tag for grouping links. Names are case-insensitive and unique per user.
+//	@Tags			Tags
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		TagRequest			true	"Tag name"
+//	@Success		201		{object}	TagInfo				"Tag created"
+//	@Failure		400		{object}	map[string]string	"Invalid tag name"
+//	@Failure		401		{object}	map[string]string	"Authentication required"
+//	@Failure		409		{object}	map[string]string	"Tag already exists"
+//	@Router			/api/tags [post]
+func (h *TagsHandler) CreateTag(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	name, ok := h.readTagName(w, r)
+	if !ok {
+		return
+	}
+
+	tag := &domain.Tag{UserID: userID, Name: name}
+	if err := h.storage.CreateTag(r.Context(), tag); err != nil {
+		if err == repository.ErrTagExists {
+			h.writeError(w, "Tag already exists", http.StatusConflict)
+			return
+		}
+		h.log.Error("failed to create tag", zap.Int64("user_id", userID), zap.Error(err))
+		h.writeError(w, "Failed to create tag", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, newTagInfo(tag), http.StatusCreated)
+}
+
+// UpdateTag переименовывает тег
+//
+//	@Summary		Rename a tag
+//	@Description	Rename a tag. Links keep the tag under the new name.
+//	@Tags			Tags
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int					true	"Tag ID"
+//	@Param			request	body		TagRequest			true	"New tag name"
+//	@Success		200		{object}	TagInfo				"Tag renamed"
+//	@Failure		400		{object}	map[string]string	"Invalid tag name"
+//	@Failure		401		{object}	map[string]string	"Authentication required"
+//	@Failure		403		{object}	map[string]string	"Access denied"
+//	@Failure		404		{object}	map[string]string	"Tag not found"
+//	@Failure		409		{object}	map[string]string	"Tag already exists"
+//	@Router			/api/tags/{id} [patch]
+func (h *TagsHandler) UpdateTag(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	tag, ok := h.getOwnedTag(w, r, userID)
+	if !ok {
+		return
+	}
+
+	name, ok := h.readTagName(w, r)
+	if !ok {
+		return
+	}
+
+	tag.Name = name
+	if err := h.storage.UpdateTag(r.Context(), tag); err != nil {
+		switch err {
+		case repository.ErrTagExists:
+			h.writeError(w, "Tag already exists", http.StatusConflict)
+		case repository.ErrTagNotFound:
+			h.writeError(w, "Tag not found", http.StatusNotFound)
+		default:
+			h.log.Error("failed to update tag", zap.Int64("tag_id", tag.ID), zap.Error(err))
+			h.writeError(w, "Failed to update tag", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.writeJSON(w, newTagInfo(tag), http.StatusOK)
+}
+
+// DeleteTag удаляет тег
+//
+//	@Summary		Delete a tag
+//	@Description	Delete a tag and remove it from all links. The links themselves are not affected.
+//	@Tags			Tags
+//	@Security		BearerAuth
+//	@Param			id	path	int	true	"Tag ID"
+//	@Success		204	"Tag deleted"
+//	@Failure		401	{object}	map[string]string	"Authentication required"
+//	@Failure		403	{object}	map[string]string	"Access denied"
+//	@Failure		404	{object}	map[string]string	"Tag not found"
+//	@Router			/api/tags/{id} [delete]
+func (h *TagsHandler) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	tag, ok := h.getOwnedTag(w, r, userID)
+	if !ok {
+		return
+	}
+
+	if err := h.storage.DeleteTag(r.Context(), tag.ID); err != nil {
+		if err == repository.ErrTagNotFound {
+			h.writeError(w, "Tag not found", http.StatusNotFound)
+			return
+		}
+		h.log.Error("failed to delete tag", zap.Int64("tag_id", tag.ID), zap.Error(err))
+		h.writeError(w, "Failed to delete tag", http.StatusInternalServerError)
+		return
+	}
+
+	h.log.Info("deleted tag", zap.Int64("tag_id", tag.ID), zap.Int64("user_id", userID))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetTagStats возвращает суммарную статистику по всем ссылкам с тегом
+//
+//	@Summary		Get tag statistics
+//	@Description	Get aggregated statistics for all links with the tag: link count, total clicks and clicks by device
+//	@Tags			Tags
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int					true	"Tag ID"
+//	@Success		200	{object}	TagStatsResponse	"Tag statistics"
+//	@Failure		401	{object}	map[string]string	"Authentication required"
+//	@Failure		403	{object}	map[string]string	"Access denied"
+//	@Failure		404	{object}	map[string]string	"Tag not found"
+//	@Router			/api/tags/{id}/stats [get]
+func (h *TagsHandler) GetTagStats(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeError(w, "User ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	tag, ok := h.getOwnedTag(w, r, userID)
+	if !ok {
+		return
+	}
+
+	stats, err := h.storage.GetTagStats(r.Context(), tag.ID)
+	if err != nil {
+		h.log.Error("failed to get tag stats", zap.Int64("tag_id", tag.ID), zap.Error(err))
+		h.writeError(w, "Failed to retrieve statistics", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, TagStatsResponse{
+		ID:             tag.ID,
+		Name:           tag.Name,
+		LinkCount:      stats.LinkCount,
+		TotalClicks:    stats.TotalClicks,
+		ClicksByDevice: stats.ClicksByDevice,
+	}, http.StatusOK)
+}
+
+// getOwnedTag получает тег из пути /api/tags/{id}/... и проверяет, что он принадлежит пользователю
+func (h *TagsHandler) getOwnedTag(w http.ResponseWriter, r *http.Request, userID int64) (*domain.Tag, bool) {
+	pathParts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(pathParts) < 3 {
+		h.writeError(w, "Tag ID is required", http.StatusBadRequest)
+		return nil, false
+	}
+	tagID, err := strconv.ParseInt(pathParts[2], 10, 64)
+	if err != nil {
+		h.writeError(w, "Invalid tag ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	tag, err := h.storage.GetTag(r.Context(), tagID)
+	if err != nil {
+		if err == repository.ErrTagNotFound {
+			h.writeError(w, "Tag not found", http.StatusNotFound)
+			return nil, false
+		}
+		h.log.Error("failed to get tag", zap.Int64("tag_id", tagID), zap.Error(err))
+		h.writeError(w, "Failed to retrieve tag", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	if tag.UserID != userID {
+		h.writeError(w, "Access denied", http.StatusForbidden)
+		return nil, false
+	}
+
+	return tag, true
+}
+
+// readTagName читает и нормализует название тега из тела запроса
+func (h *TagsHandler) readTagName(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var req TagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "Invalid request format", http.StatusBadRequest)
+		return "", false
+	}
+
+	name, err := domain.NormalizeTagName(req.Name)
+	if err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return "", false
+	}
+	return name, true
+}
+
+// newTagInfo преобразует доменный тег в ответ API
+func newTagInfo(tag *domain.Tag) TagInfo {
+	return TagInfo{
+		ID:        tag.ID,
+		Name:      tag.Name,
+		LinkCount: tag.LinkCount,
+		CreatedAt: tag.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func (h *TagsHandler) writeJSON(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *TagsHandler) writeError(w http.ResponseWriter, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,45 @@
+package http
+
+import (
+	"embed"
+	"html/template"
+)
+
+//go:embed templates/*.html
+var templatesFS embed.FS
+
+// pageTemplates HTML страницы, которые отдаются посетителям коротких ссылок
+var pageTemplates = template.Must(template.ParseFS(templatesFS, "templates/*.html"))
+
+// unlockPageData данные страницы ввода пароля для защищенной ссылки
+type unlockPageData struct {
+	Action string // адрес отправки формы (исходный путь запроса)
+	Error  string
+}
+
+// notStartedPageData данные страницы ссылки, время запуска которой еще не наступило
+type notStartedPageData struct {
+	StartsAt string // время запуска в часовом поясе ссылки
+	Timezone string
+}
+
+// flaggedPageData данные страницы предупреждения для ссылки, отключенной проверкой репутации
+type flaggedPageData struct {
+	Threat string // категория угрозы
+}
+
+// redirectPageData данные HTML страницы редиректа (meta refresh или промежуточная страница)
+type redirectPageData struct {
+	URL          string
+	Delay        int      // задержка перехода в секундах
+	Interstitial bool     // показывать промежуточную страницу
+	Pixels       []string // адреса пикселей аналитики
+}
+
+// socialPreviewPageData данные страницы с карточкой ссылки для ботов мессенджеров и соцсетей
+type socialPreviewPageData struct {
+	Title       string
+	Description string
+	ImageURL    string
+	Card        string // twitter:card: summary_large_image с картинкой, иначе summary
+}
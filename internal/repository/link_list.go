@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"GURLS-Backend/internal/domain"
+	"errors"
+	"time"
+)
+
+const (
+	// DefaultLinkListLimit размер страницы списка ссылок по умолчанию
+	DefaultLinkListLimit = 20
+	// MaxLinkListLimit максимальный размер страницы списка ссылок
+	MaxLinkListLimit = 100
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// LinkSortField поле сортировки списка ссылок
+type LinkSortField string
+
+const (
+	LinkSortCreatedAt  LinkSortField = "created_at"
+	LinkSortClickCount LinkSortField = "click_count"
+	LinkSortTitle      LinkSortField = "title"
+)
+
+// IsValid проверяет, поддерживается ли поле сортировки
+func (f LinkSortField) IsValid() bool {
+	switch f {
+	case LinkSortCreatedAt, LinkSortClickCount, LinkSortTitle:
+		return true
+	default:
+		return false
+	}
+}
+
+// LinkStatus фильтр по состоянию ссылки
+type LinkStatus string
+
+const (
+	LinkStatusAny       LinkStatus = ""
+	LinkStatusActive    LinkStatus = "active"    // включена, запущена, не истекла и не исчерпала лимит
+	LinkStatusScheduled LinkStatus = "scheduled" // время запуска еще не наступило
+	LinkStatusPaused    LinkStatus = "paused"    // приостановлена владельцем
+	LinkStatusExpired   LinkStatus = "expired"   // истек срок действия
+	LinkStatusExhausted LinkStatus = "exhausted" // исчерпан лимит переходов
+)
+
+// IsValid проверяет, поддерживается ли фильтр по состоянию
+func (s LinkStatus) IsValid() bool {
+	switch s {
+	case LinkStatusAny, LinkStatusActive, LinkStatusScheduled, LinkStatusPaused, LinkStatusExpired, LinkStatusExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
+// LinkListOptions параметры выборки ссылок пользователя
+type LinkListOptions struct {
+	// Фильтры
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Status      LinkStatus
+	HasPassword *bool
+	Search      string // подстрока в алиасе, адресе назначения, заголовке или описании
+	Tag         string // название тега (в нормализованном виде)
+
+	// Сортировка
+	SortBy   LinkSortField // по умолчанию created_at
+	SortDesc bool
+
+	// Пагинация
+	Cursor string // непрозрачный курсор из LinkListPage.NextCursor
+	Limit  int    // 0 = DefaultLinkListLimit
+}
+
+// Normalize подставляет значения по умолчанию и ограничивает размер страницы
+func (o *LinkListOptions) Normalize() {
+	if o.SortBy == "" {
+		o.SortBy = LinkSortCreatedAt
+		o.SortDesc = true
+	}
+	if o.Limit <= 0 {
+		o.Limit = DefaultLinkListLimit
+	}
+	if o.Limit > MaxLinkListLimit {
+		o.Limit = MaxLinkListLimit
+	}
+}
+
+// LinkListPage страница списка ссылок
+type LinkListPage struct {
+	Links      []*domain.Link
+	Total      int64  // количество ссылок, подходящих под фильтры (без учета пагинации)
+	NextCursor string // пустой, если страница последняя
+}
@@ -0,0 +1,10 @@
+package repository
+
+// VariantClicks количество кликов по ссылке, при которых посетитель попал на вариант A/B теста.
+// VariantID nil - переходы без варианта: до начала теста или по правилу редиректа.
+// Name пуст, если вариант без подписи или уже удален.
+type VariantClicks struct {
+	VariantID *int64 `gorm:"column:variant_id" json:"variant_id"`
+	Name      string `gorm:"column:name" json:"name,omitempty"`
+	Clicks    int64  `gorm:"column:clicks" json:"clicks"`
+}
@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/repository"
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// inDomain ограничивает запрос ссылок алиасом в домене; domainID nil - основной домен сервиса
+func inDomain(domainID *int64, alias string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if domainID == nil {
+			return db.Where("links.alias = ? AND links.domain_id IS NULL", alias)
+		}
+		return db.Where("links.alias = ? AND links.domain_id = ?", alias, *domainID)
+	}
+}
+
+// CreateCustomDomain добавляет домен пользователя.
+// Домен нельзя добавить повторно тому же пользователю или если его владение уже подтвердил кто-то другой.
+func (s *PostgresStorage) CreateCustomDomain(ctx context.Context, customDomain *domain.CustomDomain) error {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&domain.CustomDomain{}).
+		Where("hostname = ? AND (user_id = ? OR verified_at IS NOT NULL)", customDomain.Hostname, customDomain.UserID).
+		Count(&count).Error
+	if err != nil {
+		s.log.Error("failed to check custom domain existence", zap.String("hostname", customDomain.Hostname), zap.Error(err))
+		return fmt.Errorf("failed to check custom domain: %w", err)
+	}
+	if count > 0 {
+		return repository.ErrDomainExists
+	}
+
+	if err := s.db.WithContext(ctx).Create(customDomain).Error; err != nil {
+		s.log.Error("failed to create custom domain", zap.Int64("user_id", customDomain.UserID), zap.String("hostname", customDomain.Hostname), zap.Error(err))
+		return fmt.Errorf("failed to create custom domain: %w", err)
+	}
+
+	s.log.Info("created custom domain", zap.Int64("domain_id", customDomain.ID), zap.Int64("user_id", customDomain.UserID))
+	return nil
+}
+
+// GetCustomDomain получает домен по ID
+func (s *PostgresStorage) GetCustomDomain(ctx context.Context, domainID int64) (*domain.CustomDomain, error) {
+	var customDomain domain.CustomDomain
+
+	err := s.db.WithContext(ctx).Where("id = ?", domainID).First(&customDomain).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, repository.ErrDomainNotFound
+	}
+	if err != nil {
+		s.log.Error("failed to get custom domain", zap.Int64("domain_id", domainID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get custom domain: %w", err)
+	}
+
+	return &customDomain, nil
+}
+
+// GetUserDomainByHostname получает домен пользователя по имени (в том числе неподтвержденный)
+func (s *PostgresStorage) GetUserDomainByHostname(ctx context.Context, userID int64, hostname string) (*domain.CustomDomain, error) {
+	var customDomain domain.CustomDomain
+
+	err := s.db.WithContext(ctx).Where("user_id = ? AND hostname = ?", userID, hostname).First(&customDomain).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, repository.ErrDomainNotFound
+	}
+	if err != nil {
+		s.log.Error("failed to get user custom domain", zap.Int64("user_id", userID), zap.String("hostname", hostname), zap.Error(err))
+		return nil, fmt.Errorf("failed to get custom domain: %w", err)
+	}
+
+	return &customDomain, nil
+}
+
+// GetVerifiedDomain получает подтвержденный домен по имени; используется при редиректе по заголовку Host
+func (s *PostgresStorage) GetVerifiedDomain(ctx context.Context, hostname string) (*domain.CustomDomain, error) {
+	var customDomain domain.CustomDomain
+
+	err := s.db.WithContext(ctx).Where("hostname = ? AND verified_at IS NOT NULL", hostname).First(&customDomain).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, repository.ErrDomainNotFound
+	}
+	if err != nil {
+		s.log.Error("failed to get verified domain", zap.String("hostname", hostname), zap.Error(err))
+		return nil, fmt.Errorf("failed to get verified domain: %w", err)
+	}
+
+	return &customDomain, nil
+}
+
+// ListCustomDomains возвращает домены пользователя, отсортированные по имени
+func (s *PostgresStorage) ListCustomDomains(ctx context.Context, userID int64) ([]*domain.CustomDomain, error) {
+	var domains []*domain.CustomDomain
+
+	err := s.db.WithContext(ctx).Where("user_id = ?", userID).Order("hostname ASC").Find(&domains).Error
+	if err != nil {
+		s.log.Error("failed to list custom domains", zap.Int64("user_id", userID), zap.Error(err))
+		return nil, fmt.Errorf("failed to list custom domains: %w", err)
+	}
+
+	return domains, nil
+}
+
+// VerifyCustomDomain отмечает владение доменом как подтвержденное.
+// Возвращает ErrDomainExists, если тот же домен уже подтвердил другой пользователь.
+func (s *PostgresStorage) VerifyCustomDomain(ctx context.Context, domainID int64, verifiedAt time.Time) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var customDomain domain.CustomDomain
+		if err := tx.Where("id = ?", domainID).First(&customDomain).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return repository.ErrDomainNotFound
+			}
+			return err
+		}
+
+		var count int64
+		err := tx.Model(&domain.CustomDomain{}).
+			Where("hostname = ? AND id <> ? AND verified_at IS NOT NULL", customDomain.Hostname, domainID).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return repository.ErrDomainExists
+		}
+
+		return tx.Model(&domain.CustomDomain{}).Where("id = ?", domainID).Update("verified_at", verifiedAt).Error
+	})
+	if err == repository.ErrDomainNotFound || err == repository.ErrDomainExists {
+		return err
+	}
+	if err != nil {
+		s.log.Error("failed to verify custom domain", zap.Int64("domain_id", domainID), zap.Error(err))
+		return fmt.Errorf("failed to verify custom domain: %w", err)
+	}
+
+	s.log.Info("verified custom domain", zap.Int64("domain_id", domainID))
+	return nil
+}
+
+// DeleteCustomDomain удаляет домен пользователя.
+// Домен, к которому привязаны ссылки (включая ссылки в корзине), удалить нельзя.
+func (s *PostgresStorage) DeleteCustomDomain(ctx context.Context, domainID int64) error {
+	var deleted int64
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&domain.Link{}).Where("domain_id = ?", domainID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return repository.ErrDomainInUse
+		}
+
+		result := tx.Where("id = ?", domainID).Delete(&domain.CustomDomain{})
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected
+		return nil
+	})
+	if err == repository.ErrDomainInUse {
+		return err
+	}
+	if err != nil {
+		s.log.Error("failed to delete custom domain", zap.Int64("domain_id", domainID), zap.Error(err))
+		return fmt.Errorf("failed to delete custom domain: %w", err)
+	}
+	if deleted == 0 {
+		return repository.ErrDomainNotFound
+	}
+
+	s.log.Info("deleted custom domain", zap.Int64("domain_id", domainID))
+	return nil
+}
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/repository"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// clicksByDeviceSubquery агрегирует переходы ссылки по типам устройств в JSON объект;
+// группировка совпадает с GetClicksByDevice
+const clicksByDeviceSubquery = `LEFT JOIN LATERAL (
+	SELECT json_object_agg(device_type, clicks) AS clicks_by_device
+	FROM (
+		SELECT COALESCE(device_type, 'unknown') AS device_type, count(*) AS clicks
+		FROM clicks
+		WHERE clicks.link_id = links.id
+		GROUP BY 1
+	) AS device_clicks
+) AS device_stats ON TRUE`
+
+// linkExportRow строка выгрузки: ссылка, имя ее собственного домена и статистика по устройствам
+type linkExportRow struct {
+	domain.Link
+	DomainHostname     *string `gorm:"column:domain_hostname"`
+	ClicksByDeviceJSON *string `gorm:"column:clicks_by_device"`
+}
+
+// ExportUserLinks построчно читает ссылки пользователя с учетом фильтров и сортировки
+// и передает каждую в fn вместе с количеством переходов по устройствам (Link.ClicksByDevice)
+// и именем собственного домена (Link.Domain).
+// Строки читаются курсором, без загрузки всей выборки в память; курсор и лимит из opts игнорируются.
+// Ошибка, возвращенная fn, прерывает чтение и возвращается вызывающему.
+func (s *PostgresStorage) ExportUserLinks(ctx context.Context, userID int64, opts repository.LinkListOptions, fn func(link *domain.Link) error) error {
+	opts.Normalize()
+	if !opts.SortBy.IsValid() || !opts.Status.IsValid() {
+		return fmt.Errorf("invalid link list options")
+	}
+
+	direction := "ASC"
+	if opts.SortDesc {
+		direction = "DESC"
+	}
+
+	rows, err := s.filteredLinksQuery(ctx, userID, opts).
+		Select("links.*, device_stats.clicks_by_device, (SELECT hostname FROM custom_domains WHERE custom_domains.id = links.domain_id) AS domain_hostname").
+		Joins(clicksByDeviceSubquery).
+		Order(fmt.Sprintf("%s %s, id %s", linkSortExpression(opts.SortBy), direction, direction)).
+		Rows()
+	if err != nil {
+		s.log.Error("failed to export user links", zap.Int64("user_id", userID), zap.Error(err))
+		return fmt.Errorf("failed to export user links: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row linkExportRow
+		if err := s.db.ScanRows(rows, &row); err != nil {
+			return fmt.Errorf("failed to scan exported link: %w", err)
+		}
+
+		row.Link.ClicksByDevice = make(map[string]int64)
+		if row.ClicksByDeviceJSON != nil {
+			if err := json.Unmarshal([]byte(*row.ClicksByDeviceJSON), &row.Link.ClicksByDevice); err != nil {
+				return fmt.Errorf("failed to decode clicks by device: %w", err)
+			}
+		}
+
+		if row.DomainHostname != nil && row.Link.DomainID != nil {
+			row.Link.Domain = &domain.CustomDomain{ID: *row.Link.DomainID, Hostname: *row.DomainHostname}
+		}
+
+		if err := fn(&row.Link); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		s.log.Error("failed to read exported links", zap.Int64("user_id", userID), zap.Error(err))
+		return fmt.Errorf("failed to read exported links: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"GURLS-Backend/internal/domain"
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm/clause"
+)
+
+// ListLinksForHealthCheck возвращает страницу активных ссылок не из корзины в порядке ID, начиная после afterID
+func (s *PostgresStorage) ListLinksForHealthCheck(ctx context.Context, afterID int64, limit int) ([]*domain.Link, error) {
+	var links []*domain.Link
+
+	err := s.db.WithContext(ctx).
+		Where("id > ? AND is_active = ? AND deleted_at IS NULL", afterID, true).
+		Order("id ASC").
+		Limit(limit).
+		Find(&links).Error
+	if err != nil {
+		s.log.Error("failed to list links for health check", zap.Int64("after_id", afterID), zap.Error(err))
+		return nil, fmt.Errorf("failed to list links for health check: %w", err)
+	}
+
+	return links, nil
+}
+
+// ListLinkHealth возвращает результаты последних проверок ссылок по их ID; непроверенных ссылок в ответе нет
+func (s *PostgresStorage) ListLinkHealth(ctx context.Context, linkIDs []int64) (map[int64]*domain.LinkHealth, error) {
+	result := make(map[int64]*domain.LinkHealth, len(linkIDs))
+	if len(linkIDs) == 0 {
+		return result, nil
+	}
+
+	var records []*domain.LinkHealth
+	if err := s.db.WithContext(ctx).Where("link_id IN ?", linkIDs).Find(&records).Error; err != nil {
+		s.log.Error("failed to list link health", zap.Int("links", len(linkIDs)), zap.Error(err))
+		return nil, fmt.Errorf("failed to list link health: %w", err)
+	}
+
+	for _, record := range records {
+		result[record.LinkID] = record
+	}
+	return result, nil
+}
+
+// SaveLinkHealth сохраняет результат проверки ссылки, заменяя предыдущий
+func (s *PostgresStorage) SaveLinkHealth(ctx context.Context, health *domain.LinkHealth) error {
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "link_id"}},
+		UpdateAll: true,
+	}).Create(health).Error
+	if err != nil {
+		s.log.Error("failed to save link health", zap.Int64("link_id", health.LinkID), zap.Error(err))
+		return fmt.Errorf("failed to save link health: %w", err)
+	}
+	return nil
+}
+
+// ListBrokenLinks возвращает нерабочие ссылки пользователя (без корзины и отключенных), сначала недавно сломавшиеся
+func (s *PostgresStorage) ListBrokenLinks(ctx context.Context, userID int64) ([]*domain.LinkHealth, error) {
+	var records []*domain.LinkHealth
+
+	err := s.db.WithContext(ctx).
+		Joins("JOIN links ON links.id = link_health.link_id").
+		Where("links.user_id = ? AND links.deleted_at IS NULL AND links.is_active = ? AND link_health.broken_since IS NOT NULL", userID, true).
+		Preload("Link").
+		Preload("Link.Domain").
+		Order("link_health.broken_since DESC").
+		Find(&records).Error
+	if err != nil {
+		s.log.Error("failed to list broken links", zap.Int64("user_id", userID), zap.Error(err))
+		return nil, fmt.Errorf("failed to list broken links: %w", err)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,209 @@
+package postgres
+
+import (
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/repository"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// linkCursor содержимое курсора пагинации: значение поля сортировки и ID последней ссылки страницы
+type linkCursor struct {
+	SortBy repository.LinkSortField `json:"s"`
+	Value  string                   `json:"v"`
+	ID     int64                    `json:"id"`
+}
+
+// ListUserLinks возвращает страницу ссылок пользователя с учетом фильтров, сортировки и курсора.
+// Используется keyset-пагинация по паре (поле сортировки, id), поэтому страницы стабильны
+// при добавлении новых ссылок.
+func (s *PostgresStorage) ListUserLinks(ctx context.Context, userID int64, opts repository.LinkListOptions) (*repository.LinkListPage, error) {
+	opts.Normalize()
+	if !opts.SortBy.IsValid() || !opts.Status.IsValid() {
+		return nil, fmt.Errorf("invalid link list options")
+	}
+
+	query := s.filteredLinksQuery(ctx, userID, opts)
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		s.log.Error("failed to count user links", zap.Int64("user_id", userID), zap.Error(err))
+		return nil, fmt.Errorf("failed to count user links: %w", err)
+	}
+
+	sortExpr := linkSortExpression(opts.SortBy)
+	direction, comparison := "ASC", ">"
+	if opts.SortDesc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if opts.Cursor != "" {
+		cursor, err := decodeLinkCursor(opts.Cursor, opts.SortBy)
+		if err != nil {
+			return nil, err
+		}
+		value, err := cursor.sortValue()
+		if err != nil {
+			return nil, repository.ErrInvalidCursor
+		}
+		query = query.Where(fmt.Sprintf("(%s, id) %s (?, ?)", sortExpr, comparison), value, cursor.ID)
+	}
+
+	var links []*domain.Link
+	err := query.
+		Preload("Tags", func(db *gorm.DB) *gorm.DB { return db.Order("tags.name ASC") }).
+		Preload("Domain").
+		Order(fmt.Sprintf("%s %s, id %s", sortExpr, direction, direction)).
+		Limit(opts.Limit + 1).
+		Find(&links).Error
+	if err != nil {
+		s.log.Error("failed to list user links", zap.Int64("user_id", userID), zap.Error(err))
+		return nil, fmt.Errorf("failed to list user links: %w", err)
+	}
+
+	page := &repository.LinkListPage{Links: links, Total: total}
+	if len(links) > opts.Limit {
+		page.Links = links[:opts.Limit]
+		page.NextCursor = encodeLinkCursor(opts.SortBy, page.Links[opts.Limit-1])
+	}
+
+	return page, nil
+}
+
+// CountLinksCreatedSince считает ссылки пользователя, созданные начиная с since, включая ссылки в корзине
+func (s *PostgresStorage) CountLinksCreatedSince(ctx context.Context, userID int64, since time.Time) (int64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&domain.Link{}).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Count(&count).Error
+	if err != nil {
+		s.log.Error("failed to count created links", zap.Int64("user_id", userID), zap.Error(err))
+		return 0, fmt.Errorf("failed to count created links: %w", err)
+	}
+	return count, nil
+}
+
+// filteredLinksQuery строит запрос ссылок пользователя (без корзины) с примененными фильтрами
+func (s *PostgresStorage) filteredLinksQuery(ctx context.Context, userID int64, opts repository.LinkListOptions) *gorm.DB {
+	query := s.db.WithContext(ctx).Model(&domain.Link{}).
+		Where("user_id = ? AND deleted_at IS NULL", userID)
+
+	if opts.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *opts.CreatedFrom)
+	}
+	if opts.CreatedTo != nil {
+		query = query.Where("created_at < ?", *opts.CreatedTo)
+	}
+
+	now := time.Now()
+	switch opts.Status {
+	case repository.LinkStatusActive:
+		query = query.Where("is_active = ? AND (starts_at IS NULL OR starts_at <= ?) AND (expires_at IS NULL OR expires_at > ?) AND (max_clicks IS NULL OR click_count < max_clicks)", true, now, now)
+	case repository.LinkStatusScheduled:
+		query = query.Where("starts_at IS NOT NULL AND starts_at > ?", now)
+	case repository.LinkStatusPaused:
+		query = query.Where("is_active = ?", false)
+	case repository.LinkStatusExpired:
+		query = query.Where("expires_at IS NOT NULL AND expires_at <= ?", now)
+	case repository.LinkStatusExhausted:
+		query = query.Where("max_clicks IS NOT NULL AND click_count >= max_clicks")
+	}
+
+	if opts.HasPassword != nil {
+		if *opts.HasPassword {
+			query = query.Where("password_hash IS NOT NULL")
+		} else {
+			query = query.Where("password_hash IS NULL")
+		}
+	}
+
+	if search := strings.TrimSpace(opts.Search); search != "" {
+		pattern := "%" + escapeLike(search) + "%"
+		query = query.Where(
+			"(alias ILIKE ? OR original_url ILIKE ? OR title ILIKE ? OR description ILIKE ?)",
+			pattern, pattern, pattern, pattern,
+		)
+	}
+
+	if opts.Tag != "" {
+		query = query.Where(
+			"EXISTS (SELECT 1 FROM link_tags JOIN tags ON tags.id = link_tags.tag_id WHERE link_tags.link_id = links.id AND tags.name = ?)",
+			opts.Tag,
+		)
+	}
+
+	return query
+}
+
+// linkSortExpression возвращает SQL выражение для поля сортировки
+func linkSortExpression(field repository.LinkSortField) string {
+	switch field {
+	case repository.LinkSortClickCount:
+		return "click_count"
+	case repository.LinkSortTitle:
+		return "COALESCE(title, '')"
+	default:
+		return "created_at"
+	}
+}
+
+// encodeLinkCursor кодирует позицию последней ссылки страницы
+func encodeLinkCursor(sortBy repository.LinkSortField, link *domain.Link) string {
+	cursor := linkCursor{SortBy: sortBy, ID: link.ID}
+	switch sortBy {
+	case repository.LinkSortClickCount:
+		cursor.Value = strconv.FormatInt(link.ClickCount, 10)
+	case repository.LinkSortTitle:
+		if link.Title != nil {
+			cursor.Value = *link.Title
+		}
+	default:
+		cursor.Value = link.CreatedAt.Format(time.RFC3339Nano)
+	}
+
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeLinkCursor разбирает курсор и проверяет, что он выдан для той же сортировки
+func decodeLinkCursor(raw string, sortBy repository.LinkSortField) (*linkCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, repository.ErrInvalidCursor
+	}
+
+	var cursor linkCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, repository.ErrInvalidCursor
+	}
+	if cursor.SortBy != sortBy {
+		return nil, repository.ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+// sortValue преобразует значение курсора к типу поля сортировки
+func (c *linkCursor) sortValue() (interface{}, error) {
+	switch c.SortBy {
+	case repository.LinkSortClickCount:
+		return strconv.ParseInt(c.Value, 10, 64)
+	case repository.LinkSortTitle:
+		return c.Value, nil
+	default:
+		return time.Parse(time.RFC3339Nano, c.Value)
+	}
+}
+
+// escapeLike экранирует спецсимволы шаблона LIKE
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
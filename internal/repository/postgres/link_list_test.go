@@ -0,0 +1,217 @@
+package postgres
+
+import (
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/repository"
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	postgresDriver "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// newDryRunStorage создает storage, который только строит SQL и не ходит в базу
+func newDryRunStorage(t *testing.T) *PostgresStorage {
+	t.Helper()
+	db, err := gorm.Open(postgresDriver.New(postgresDriver.Config{DSN: "host=127.0.0.1 port=1 dbname=dryrun"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true, // обновления иначе открывают транзакцию и подключаются к базе
+	})
+	require.NoError(t, err)
+	return &PostgresStorage{db: db, log: zap.NewNop()}
+}
+
+// buildListSQL возвращает SQL и параметры запроса списка ссылок
+func buildListSQL(t *testing.T, opts repository.LinkListOptions) (string, []interface{}) {
+	t.Helper()
+	var links []*domain.Link
+	stmt := newDryRunStorage(t).filteredLinksQuery(context.Background(), 7, opts).Find(&links).Statement
+	return stmt.SQL.String(), stmt.Vars
+}
+
+func TestLinkCursor_RoundTrip(t *testing.T) {
+	title := "Отчет"
+	createdAt := time.Date(2024, 3, 15, 10, 30, 0, 123456789, time.UTC)
+	link := &domain.Link{ID: 42, ClickCount: 17, Title: &title, CreatedAt: createdAt}
+
+	tests := []struct {
+		sortBy repository.LinkSortField
+		want   interface{}
+	}{
+		{repository.LinkSortCreatedAt, createdAt},
+		{repository.LinkSortClickCount, int64(17)},
+		{repository.LinkSortTitle, "Отчет"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.sortBy), func(t *testing.T) {
+			raw := encodeLinkCursor(tt.sortBy, link)
+
+			cursor, err := decodeLinkCursor(raw, tt.sortBy)
+			require.NoError(t, err)
+			assert.Equal(t, int64(42), cursor.ID)
+
+			value, err := cursor.sortValue()
+			require.NoError(t, err)
+			if want, ok := tt.want.(time.Time); ok {
+				assert.True(t, want.Equal(value.(time.Time)))
+			} else {
+				assert.Equal(t, tt.want, value)
+			}
+		})
+	}
+}
+
+func TestLinkCursor_NilTitle(t *testing.T) {
+	raw := encodeLinkCursor(repository.LinkSortTitle, &domain.Link{ID: 3})
+
+	cursor, err := decodeLinkCursor(raw, repository.LinkSortTitle)
+	require.NoError(t, err)
+	value, err := cursor.sortValue()
+	require.NoError(t, err)
+	assert.Equal(t, "", value)
+}
+
+func TestDecodeLinkCursor_Invalid(t *testing.T) {
+	valid := encodeLinkCursor(repository.LinkSortClickCount, &domain.Link{ID: 1, ClickCount: 5})
+
+	tests := []struct {
+		name   string
+		raw    string
+		sortBy repository.LinkSortField
+	}{
+		{"not base64", "!!!", repository.LinkSortClickCount},
+		{"not json", base64.RawURLEncoding.EncodeToString([]byte("garbage")), repository.LinkSortClickCount},
+		{"other sort field", valid, repository.LinkSortCreatedAt},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeLinkCursor(tt.raw, tt.sortBy)
+			assert.ErrorIs(t, err, repository.ErrInvalidCursor)
+		})
+	}
+}
+
+func TestLinkCursor_SortValueRejectsForgedValue(t *testing.T) {
+	cursor := &linkCursor{SortBy: repository.LinkSortClickCount, Value: "DROP TABLE", ID: 1}
+	_, err := cursor.sortValue()
+	assert.Error(t, err)
+
+	cursor = &linkCursor{SortBy: repository.LinkSortCreatedAt, Value: "yesterday", ID: 1}
+	_, err = cursor.sortValue()
+	assert.Error(t, err)
+}
+
+func TestLinkSortExpression(t *testing.T) {
+	assert.Equal(t, "created_at", linkSortExpression(repository.LinkSortCreatedAt))
+	assert.Equal(t, "click_count", linkSortExpression(repository.LinkSortClickCount))
+	assert.Equal(t, "COALESCE(title, '')", linkSortExpression(repository.LinkSortTitle))
+	assert.Equal(t, "created_at", linkSortExpression("unknown"))
+}
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, `50\% off\_now \\ ok`, escapeLike(`50% off_now \ ok`))
+	assert.Equal(t, "plain", escapeLike("plain"))
+}
+
+func TestFilteredLinksQuery(t *testing.T) {
+	yes, no := true, false
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		opts     repository.LinkListOptions
+		contains []string
+		excludes []string
+		vars     []interface{}
+	}{
+		{
+			name:     "no filters excludes trash",
+			contains: []string{"user_id = $1 AND deleted_at IS NULL"},
+			excludes: []string{"ILIKE", "link_tags", "password_hash"},
+			vars:     []interface{}{int64(7)},
+		},
+		{
+			name:     "search is escaped",
+			opts:     repository.LinkListOptions{Search: "  100%_ "},
+			contains: []string{"alias ILIKE $2 OR original_url ILIKE $3 OR title ILIKE $4 OR description ILIKE $5"},
+			vars:     []interface{}{int64(7), `%100\%\_%`, `%100\%\_%`, `%100\%\_%`, `%100\%\_%`},
+		},
+		{
+			name:     "tag",
+			opts:     repository.LinkListOptions{Tag: "promo"},
+			contains: []string{"EXISTS (SELECT 1 FROM link_tags JOIN tags ON tags.id = link_tags.tag_id WHERE link_tags.link_id = links.id AND tags.name = $2)"},
+			vars:     []interface{}{int64(7), "promo"},
+		},
+		{
+			name:     "with password",
+			opts:     repository.LinkListOptions{HasPassword: &yes},
+			contains: []string{"password_hash IS NOT NULL"},
+		},
+		{
+			name:     "without password",
+			opts:     repository.LinkListOptions{HasPassword: &no},
+			contains: []string{"password_hash IS NULL"},
+			excludes: []string{"password_hash IS NOT NULL"},
+		},
+		{
+			name:     "created from",
+			opts:     repository.LinkListOptions{CreatedFrom: &from},
+			contains: []string{"created_at >= $2"},
+			vars:     []interface{}{int64(7), from},
+		},
+		{
+			name:     "paused",
+			opts:     repository.LinkListOptions{Status: repository.LinkStatusPaused},
+			contains: []string{"is_active = $2"},
+			vars:     []interface{}{int64(7), false},
+		},
+		{
+			name:     "exhausted",
+			opts:     repository.LinkListOptions{Status: repository.LinkStatusExhausted},
+			contains: []string{"max_clicks IS NOT NULL AND click_count >= max_clicks"},
+		},
+		{
+			name:     "active",
+			opts:     repository.LinkListOptions{Status: repository.LinkStatusActive},
+			contains: []string{"is_active = $2 AND (starts_at IS NULL OR starts_at <= $3) AND (expires_at IS NULL OR expires_at > $4) AND (max_clicks IS NULL OR click_count < max_clicks)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, vars := buildListSQL(t, tt.opts)
+			for _, fragment := range tt.contains {
+				assert.Contains(t, sql, fragment)
+			}
+			for _, fragment := range tt.excludes {
+				assert.NotContains(t, sql, fragment)
+			}
+			if tt.vars != nil {
+				assert.Equal(t, tt.vars, vars)
+			}
+		})
+	}
+}
+
+func TestCountLinksCreatedSince_IncludesTrash(t *testing.T) {
+	storage := newDryRunStorage(t)
+	var sql string
+	err := storage.db.Callback().Query().After("gorm:query").Register("test:capture_sql", func(db *gorm.DB) {
+		sql = db.Statement.SQL.String()
+	})
+	require.NoError(t, err)
+
+	_, err = storage.CountLinksCreatedSince(context.Background(), 7, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	assert.Contains(t, sql, "user_id = $1 AND created_at >= $2")
+	assert.NotContains(t, sql, "deleted_at")
+}
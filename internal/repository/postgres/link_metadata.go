@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"GURLS-Backend/internal/domain"
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ListLinksWithoutMetadata возвращает активные ссылки без заголовка или описания, страница которых
+// еще не загружалась; сначала новые
+func (s *PostgresStorage) ListLinksWithoutMetadata(ctx context.Context, limit int) ([]*domain.Link, error) {
+	var links []*domain.Link
+
+	err := s.db.WithContext(ctx).
+		Where("meta_fetched_at IS NULL AND deleted_at IS NULL AND is_active = ? AND password_hash IS NULL", true).
+		Where("title IS NULL OR title = '' OR description IS NULL OR description = ''").
+		Order("id DESC").
+		Limit(limit).
+		Find(&links).Error
+	if err != nil {
+		s.log.Error("failed to list links without metadata", zap.Error(err))
+		return nil, fmt.Errorf("failed to list links without metadata: %w", err)
+	}
+
+	return links, nil
+}
+
+// SaveLinkMetadata сохраняет метаданные страницы назначения. Заголовок и описание ссылки заменяются,
+// только если они пусты или совпадают с загруженными ранее, то есть не были заданы пользователем.
+// У защищенных паролем ссылок они не заменяются никогда: заголовок и описание видны в карточке
+// и предпросмотре без пароля и не должны раскрывать страницу назначения.
+// При ошибке загрузки сохраняются только время и ошибка, прежние метаданные остаются.
+func (s *PostgresStorage) SaveLinkMetadata(ctx context.Context, linkID int64, metadata domain.LinkMetadata) error {
+	updates := map[string]interface{}{
+		"meta_fetched_at": metadata.FetchedAt,
+		"meta_error":      metadata.Error,
+	}
+	if metadata.Error == "" {
+		// значения справа вычисляются по строке до обновления, meta_title - прежний загруженный заголовок
+		updates["title"] = gorm.Expr("CASE WHEN password_hash IS NULL AND (title IS NULL OR title = '' OR title = meta_title) THEN NULLIF(?, '') ELSE title END", metadata.Title)
+		updates["description"] = gorm.Expr("CASE WHEN password_hash IS NULL AND (description IS NULL OR description = '' OR description = meta_description) THEN NULLIF(?, '') ELSE description END", metadata.Description)
+		updates["meta_title"] = metadata.Title
+		updates["meta_description"] = metadata.Description
+		updates["meta_site_name"] = metadata.SiteName
+		updates["meta_image_url"] = metadata.ImageURL
+		updates["meta_favicon_url"] = metadata.FaviconURL
+		updates["meta_twitter_card"] = metadata.TwitterCard
+	}
+
+	result := s.db.WithContext(ctx).Model(&domain.Link{}).Where("id = ?", linkID).UpdateColumns(updates)
+	if result.Error != nil {
+		s.log.Error("failed to save link metadata", zap.Int64("link_id", linkID), zap.Error(result.Error))
+		return fmt.Errorf("failed to save link metadata: %w", result.Error)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"GURLS-Backend/internal/domain"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestListLinksWithoutMetadata_SkipsProtectedLinks(t *testing.T) {
+	storage := newDryRunStorage(t)
+	var sql string
+	err := storage.db.Callback().Query().After("gorm:query").Register("test:capture_sql", func(db *gorm.DB) {
+		sql = db.Statement.SQL.String()
+	})
+	require.NoError(t, err)
+
+	_, err = storage.ListLinksWithoutMetadata(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Contains(t, sql, "password_hash IS NULL")
+}
+
+func TestSaveLinkMetadata_NeverCopiesIntoProtectedLinks(t *testing.T) {
+	storage := newDryRunStorage(t)
+	var sql string
+	err := storage.db.Callback().Update().After("gorm:update").Register("test:capture_sql", func(db *gorm.DB) {
+		sql = db.Statement.SQL.String()
+	})
+	require.NoError(t, err)
+
+	err = storage.SaveLinkMetadata(context.Background(), 1, domain.LinkMetadata{Title: "Destination", Description: "About the destination"})
+	require.NoError(t, err)
+	assert.Contains(t, sql, `"title"=CASE WHEN password_hash IS NULL AND (title IS NULL`)
+	assert.Contains(t, sql, `"description"=CASE WHEN password_hash IS NULL AND (description IS NULL`)
+}
@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/repository"
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ListLinksForReputationScan возвращает страницу активных ссылок не из корзины в порядке ID, начиная после afterID
+func (s *PostgresStorage) ListLinksForReputationScan(ctx context.Context, afterID int64, limit int) ([]*domain.Link, error) {
+	var links []*domain.Link
+
+	err := s.db.WithContext(ctx).
+		Where("id > ? AND is_active = ? AND deleted_at IS NULL AND flagged_at IS NULL", afterID, true).
+		Order("id ASC").
+		Limit(limit).
+		Find(&links).Error
+	if err != nil {
+		s.log.Error("failed to list links for reputation scan", zap.Int64("after_id", afterID), zap.Error(err))
+		return nil, fmt.Errorf("failed to list links for reputation scan: %w", err)
+	}
+
+	return links, nil
+}
+
+// FlagLink отключает ссылку, адрес назначения которой найден в списках угроз.
+// Уже отмеченная ссылка не изменяется.
+func (s *PostgresStorage) FlagLink(ctx context.Context, linkID int64, threat string) error {
+	result := s.db.WithContext(ctx).Model(&domain.Link{}).
+		Where("id = ? AND flagged_at IS NULL", linkID).
+		Updates(map[string]interface{}{
+			"is_active":   false,
+			"flagged_at":  time.Now(),
+			"flag_reason": threat,
+			"updated_at":  time.Now(),
+		})
+	if result.Error != nil {
+		s.log.Error("failed to flag link", zap.Int64("link_id", linkID), zap.Error(result.Error))
+		return fmt.Errorf("failed to flag link: %w", result.Error)
+	}
+
+	if result.RowsAffected > 0 {
+		s.log.Warn("flagged link", zap.Int64("link_id", linkID), zap.String("threat", threat))
+	}
+	return nil
+}
+
+// UnflagLink снимает отметку угрозы и снова включает ссылку; ожидающие апелляции ссылки одобряются
+func (s *PostgresStorage) UnflagLink(ctx context.Context, linkID int64) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Link{}).
+			Where("id = ? AND flagged_at IS NOT NULL", linkID).
+			Updates(map[string]interface{}{
+				"is_active":   true,
+				"flagged_at":  nil,
+				"flag_reason": nil,
+				"updated_at":  time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repository.ErrAliasNotFound
+		}
+
+		return tx.Model(&domain.LinkAppeal{}).
+			Where("link_id = ? AND status = ?", linkID, domain.LinkAppealPending).
+			Updates(map[string]interface{}{
+				"status":      domain.LinkAppealApproved,
+				"resolved_at": time.Now(),
+			}).Error
+	})
+	if err == repository.ErrAliasNotFound {
+		return err
+	}
+	if err != nil {
+		s.log.Error("failed to unflag link", zap.Int64("link_id", linkID), zap.Error(err))
+		return fmt.Errorf("failed to unflag link: %w", err)
+	}
+
+	s.log.Info("unflagged link", zap.Int64("link_id", linkID))
+	return nil
+}
+
+// CreateLinkAppeal сохраняет апелляцию владельца отключенной ссылки
+func (s *PostgresStorage) CreateLinkAppeal(ctx context.Context, appeal *domain.LinkAppeal) error {
+	if err := s.db.WithContext(ctx).Create(appeal).Error; err != nil {
+		s.log.Error("failed to create link appeal", zap.Int64("link_id", appeal.LinkID), zap.Error(err))
+		return fmt.Errorf("failed to create link appeal: %w", err)
+	}
+	return nil
+}
+
+// HasPendingLinkAppeal проверяет, есть ли у ссылки апелляция, ожидающая проверки
+func (s *PostgresStorage) HasPendingLinkAppeal(ctx context.Context, linkID int64) (bool, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&domain.LinkAppeal{}).
+		Where("link_id = ? AND status = ?", linkID, domain.LinkAppealPending).
+		Count(&count).Error
+	if err != nil {
+		s.log.Error("failed to check pending link appeals", zap.Int64("link_id", linkID), zap.Error(err))
+		return false, fmt.Errorf("failed to check pending link appeals: %w", err)
+	}
+	return count > 0, nil
+}
+
+// ListLinksWithPendingAppeals возвращает страницу отключенных ссылок не из корзины, у которых есть
+// апелляция, ожидающая решения, в порядке ID, начиная после afterID
+func (s *PostgresStorage) ListLinksWithPendingAppeals(ctx context.Context, afterID int64, limit int) ([]*domain.Link, error) {
+	var links []*domain.Link
+
+	err := s.db.WithContext(ctx).
+		Where("id > ? AND flagged_at IS NOT NULL AND deleted_at IS NULL", afterID).
+		Where("EXISTS (SELECT 1 FROM link_appeals WHERE link_appeals.link_id = links.id AND link_appeals.status = ?)", domain.LinkAppealPending).
+		Order("id ASC").
+		Limit(limit).
+		Find(&links).Error
+	if err != nil {
+		s.log.Error("failed to list links with pending appeals", zap.Int64("after_id", afterID), zap.Error(err))
+		return nil, fmt.Errorf("failed to list links with pending appeals: %w", err)
+	}
+
+	return links, nil
+}
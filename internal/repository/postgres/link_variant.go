@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/repository"
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ListLinkVariants возвращает варианты A/B теста ссылки в порядке добавления
+func (s *PostgresStorage) ListLinkVariants(ctx context.Context, linkID int64) ([]domain.LinkVariant, error) {
+	var variants []domain.LinkVariant
+
+	err := s.db.WithContext(ctx).
+		Where("link_id = ?", linkID).
+		Order("position ASC, id ASC").
+		Find(&variants).Error
+	if err != nil {
+		s.log.Error("failed to list link variants", zap.Int64("link_id", linkID), zap.Error(err))
+		return nil, fmt.Errorf("failed to list link variants: %w", err)
+	}
+
+	return variants, nil
+}
+
+// ReplaceLinkVariants заменяет варианты A/B теста ссылки.
+// Варианты с ID существующих вариантов ссылки обновляются на месте, чтобы посетители с cookie
+// оставались на своем варианте, а статистика сохранялась; варианты без ID создаются,
+// отсутствующие в списке удаляются.
+// Если ID не принадлежит варианту этой ссылки, возвращается ErrLinkVariantNotFound.
+func (s *PostgresStorage) ReplaceLinkVariants(ctx context.Context, linkID int64, variants []domain.LinkVariant) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existingIDs []int64
+		if err := tx.Model(&domain.LinkVariant{}).Where("link_id = ?", linkID).Pluck("id", &existingIDs).Error; err != nil {
+			return err
+		}
+		existing := make(map[int64]bool, len(existingIDs))
+		for _, id := range existingIDs {
+			existing[id] = true
+		}
+
+		kept := make([]int64, 0, len(variants))
+		for i := range variants {
+			if variants[i].ID != 0 {
+				if !existing[variants[i].ID] {
+					return repository.ErrLinkVariantNotFound
+				}
+				kept = append(kept, variants[i].ID)
+			}
+		}
+
+		deleteQuery := tx.Where("link_id = ?", linkID)
+		if len(kept) > 0 {
+			deleteQuery = deleteQuery.Where("id NOT IN ?", kept)
+		}
+		if err := deleteQuery.Delete(&domain.LinkVariant{}).Error; err != nil {
+			return err
+		}
+
+		for i := range variants {
+			variants[i].LinkID = linkID
+			variants[i].Position = i + 1
+			if variants[i].ID == 0 {
+				if err := tx.Create(&variants[i]).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			err := tx.Model(&variants[i]).
+				Select("position", "name", "destination_url", "weight", "updated_at").
+				Updates(&variants[i]).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == repository.ErrLinkVariantNotFound {
+		return err
+	}
+	if err != nil {
+		s.log.Error("failed to replace link variants", zap.Int64("link_id", linkID), zap.Error(err))
+		return fmt.Errorf("failed to replace link variants: %w", err)
+	}
+
+	s.log.Info("replaced link variants", zap.Int64("link_id", linkID), zap.Int("count", len(variants)))
+	return nil
+}
+
+// GetClicksByVariant возвращает клики по ссылке, сгруппированные по варианту A/B теста.
+// Результат отсортирован по убыванию количества кликов.
+func (s *PostgresStorage) GetClicksByVariant(ctx context.Context, linkID int64) ([]repository.VariantClicks, error) {
+	variants := make([]repository.VariantClicks, 0)
+
+	err := s.db.WithContext(ctx).
+		Table("clicks").
+		Select("clicks.variant_id AS variant_id, COALESCE(link_variants.name, '') AS name, count(*) AS clicks").
+		Joins("LEFT JOIN link_variants ON link_variants.id = clicks.variant_id").
+		Where("clicks.link_id = ?", linkID).
+		Group("clicks.variant_id, link_variants.name").
+		Order("clicks DESC, variant_id").
+		Scan(&variants).Error
+	if err != nil {
+		s.log.Error("failed to get clicks by variant", zap.Int64("link_id", linkID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get clicks by variant: %w", err)
+	}
+
+	return variants, nil
+}
@@ -4,12 +4,16 @@ import (
 	"GURLS-Backend/internal/domain"
 	"GURLS-Backend/internal/repository"
 	"context"
+	"errors"
 	"fmt"
 	"net"
+	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // PostgresStorage реализует интерфейс Storage для PostgreSQL
@@ -118,7 +122,7 @@ func (s *PostgresStorage) FindUserByEmailAndPassword(ctx context.Context, email
 func (s *PostgresStorage) SaveLink(ctx context.Context, link *domain.Link) error {
 	// Проверяем, существует ли уже такой алиас
 	var existingLink domain.Link
-	err := s.db.WithContext(ctx).Where("alias = ?", link.Alias).First(&existingLink).Error
+	err := s.db.WithContext(ctx).Scopes(inDomain(link.DomainID, link.Alias)).First(&existingLink).Error
 	if err == nil {
 		return repository.ErrAliasExists
 	}
@@ -127,8 +131,44 @@ func (s *PostgresStorage) SaveLink(ctx context.Context, link *domain.Link) error
 		return fmt.Errorf("failed to check alias: %w", err)
 	}
 
-	// Сохраняем ссылку
-	if err := s.db.WithContext(ctx).Create(link).Error; err != nil {
+	// Сохраняем ссылку вместе с начальной ревизией адреса назначения.
+	// Лимит подписки проверяется повторно в той же транзакции под блокировкой строки пользователя:
+	// проверка в обработчике не защищает от параллельных запросов одного пользователя.
+	// Теги находятся или создаются по названиям в той же транзакции, чтобы при ошибке
+	// не оставалось новых тегов без ссылки.
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkMonthlyLinkLimit(tx, link.UserID); err != nil {
+			return err
+		}
+		if len(link.Tags) > 0 {
+			names := make([]string, len(link.Tags))
+			for i, tag := range link.Tags {
+				names[i] = tag.Name
+			}
+			tags, err := resolveTags(tx, link.UserID, names)
+			if err != nil {
+				return err
+			}
+			link.Tags = tags
+		}
+		if err := tx.Create(link).Error; err != nil {
+			return err
+		}
+		revision := domain.LinkRevision{
+			LinkID:      link.ID,
+			OriginalURL: link.OriginalURL,
+			ChangedBy:   link.UserID,
+		}
+		return tx.Create(&revision).Error
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrLinkLimitReached) {
+			return err
+		}
+		// Алиас мог быть занят параллельным запросом между проверкой и вставкой
+		if isAliasUniqueViolation(err) {
+			return repository.ErrAliasExists
+		}
 		s.log.Error("failed to save link", zap.String("alias", link.Alias), zap.Error(err))
 		return fmt.Errorf("failed to save link: %w", err)
 	}
@@ -142,11 +182,47 @@ func (s *PostgresStorage) SaveLink(ctx context.Context, link *domain.Link) error
 	return nil
 }
 
-// GetLink получает ссылку по алиасу
-func (s *PostgresStorage) GetLink(ctx context.Context, alias string) (*domain.Link, error) {
+// checkMonthlyLinkLimit блокирует строку пользователя до конца транзакции и проверяет, что он не исчерпал
+// месячный лимит ссылок подписки. Ссылки в корзине учитываются, как и в CountLinksCreatedSince.
+func checkMonthlyLinkLimit(tx *gorm.DB, userID int64) error {
+	var user domain.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "subscription_type_id").
+		First(&user, userID).Error; err != nil {
+		return fmt.Errorf("failed to lock user: %w", err)
+	}
+
+	var subscription domain.SubscriptionType
+	if err := tx.First(&subscription, user.SubscriptionTypeID).Error; err != nil {
+		return fmt.Errorf("failed to get subscription: %w", err)
+	}
+	if subscription.MaxLinksPerMonth == nil {
+		return nil
+	}
+
+	now := time.Now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	var created int64
+	if err := tx.Model(&domain.Link{}).
+		Where("user_id = ? AND created_at >= ?", userID, monthStart).
+		Count(&created).Error; err != nil {
+		return fmt.Errorf("failed to count created links: %w", err)
+	}
+	if created >= int64(*subscription.MaxLinksPerMonth) {
+		return repository.ErrLinkLimitReached
+	}
+	return nil
+}
+
+// GetLink получает ссылку по алиасу.
+// Истекшие и исчерпанные ссылки тоже возвращаются, чтобы владелец мог видеть их статистику;
+// проверка доступности для редиректа выполняется в GetLinkAndRecordClick.
+func (s *PostgresStorage) GetLink(ctx context.Context, domainID *int64, alias string) (*domain.Link, error) {
 	var link domain.Link
 
-	err := s.db.WithContext(ctx).Where("alias = ? AND is_active = ?", alias, true).First(&link).Error
+	err := s.db.WithContext(ctx).Scopes(inDomain(domainID, alias)).
+		Where("is_active = ? AND deleted_at IS NULL", true).First(&link).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, repository.ErrAliasNotFound
 	}
@@ -155,17 +231,128 @@ func (s *PostgresStorage) GetLink(ctx context.Context, alias string) (*domain.Li
 		return nil, fmt.Errorf("failed to get link: %w", err)
 	}
 
-	// Проверяем срок действия ссылки
-	if link.ExpiresAt != nil && time.Now().After(*link.ExpiresAt) {
+	return &link, nil
+}
+
+// FindLink получает ссылку по алиасу независимо от того, активна ли она (кроме ссылок в корзине).
+// Используется для управления ссылкой ее владельцем (например, чтобы снять паузу).
+func (s *PostgresStorage) FindLink(ctx context.Context, domainID *int64, alias string) (*domain.Link, error) {
+	var link domain.Link
+
+	err := s.db.WithContext(ctx).
+		Preload("Tags", func(db *gorm.DB) *gorm.DB { return db.Order("tags.name ASC") }).
+		Preload("Domain").
+		Scopes(inDomain(domainID, alias)).
+		Where("deleted_at IS NULL").
+		First(&link).Error
+	if err == gorm.ErrRecordNotFound {
 		return nil, repository.ErrAliasNotFound
 	}
+	if err != nil {
+		s.log.Error("failed to find link", zap.String("alias", alias), zap.Error(err))
+		return nil, fmt.Errorf("failed to find link: %w", err)
+	}
 
 	return &link, nil
 }
 
-// DeleteLink удаляет ссылку (мягкое удаление)
-func (s *PostgresStorage) DeleteLink(ctx context.Context, alias string) error {
-	result := s.db.WithContext(ctx).Model(&domain.Link{}).Where("alias = ?", alias).Update("is_active", false)
+// UpdateLink обновляет редактируемые поля ссылки.
+// Если изменился адрес назначения, в той же транзакции записывается ревизия, а метаданные прежней
+// страницы сбрасываются (см. domain.Link.ResetMetadata).
+func (s *PostgresStorage) UpdateLink(ctx context.Context, link *domain.Link, changedBy int64) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Блокируем строку, чтобы конкурентные изменения не потеряли ревизии
+		var current domain.Link
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "original_url", "meta_title", "meta_description").
+			Where("id = ?", link.ID).
+			First(&current).Error
+		if err == gorm.ErrRecordNotFound {
+			return repository.ErrAliasNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		urlChanged := current.OriginalURL != link.OriginalURL
+		if urlChanged {
+			// сравниваем с метаданными под блокировкой: фоновая загрузка могла обновить их после чтения ссылки
+			link.Metadata.Title = current.Metadata.Title
+			link.Metadata.Description = current.Metadata.Description
+			link.ResetMetadata()
+		}
+
+		updates := map[string]interface{}{
+			"original_url":    link.OriginalURL,
+			"title":           link.Title,
+			"description":     link.Description,
+			"starts_at":       link.StartsAt,
+			"expires_at":      link.ExpiresAt,
+			"fallback_url":    link.FallbackURL,
+			"timezone":        link.Timezone,
+			"is_active":       link.IsActive,
+			"forward_query":   link.ForwardQuery,
+			"query_conflict":  link.QueryConflict,
+			"forward_path":    link.ForwardPath,
+			"redirect_mode":   link.RedirectMode,
+			"tracking_pixels": link.TrackingPixels,
+			"utm_source":      link.UTM.Source,
+			"utm_medium":      link.UTM.Medium,
+			"utm_campaign":    link.UTM.Campaign,
+			"utm_term":        link.UTM.Term,
+			"utm_content":     link.UTM.Content,
+			"og_title":        link.SocialPreview.Title,
+			"og_description":  link.SocialPreview.Description,
+			"og_image_url":    link.SocialPreview.ImageURL,
+			"updated_at":      time.Now(),
+		}
+		if urlChanged {
+			updates["meta_title"] = link.Metadata.Title
+			updates["meta_description"] = link.Metadata.Description
+			updates["meta_site_name"] = link.Metadata.SiteName
+			updates["meta_image_url"] = link.Metadata.ImageURL
+			updates["meta_favicon_url"] = link.Metadata.FaviconURL
+			updates["meta_twitter_card"] = link.Metadata.TwitterCard
+			updates["meta_fetched_at"] = link.Metadata.FetchedAt
+			updates["meta_error"] = link.Metadata.Error
+		}
+		err = tx.Model(&domain.Link{}).Where("id = ?", link.ID).Updates(updates).Error
+		if err != nil {
+			return err
+		}
+
+		if !urlChanged {
+			return nil
+		}
+
+		previousURL := current.OriginalURL
+		revision := domain.LinkRevision{
+			LinkID:      link.ID,
+			OriginalURL: link.OriginalURL,
+			PreviousURL: &previousURL,
+			ChangedBy:   changedBy,
+		}
+		return tx.Create(&revision).Error
+	})
+	if err == repository.ErrAliasNotFound {
+		return err
+	}
+	if err != nil {
+		s.log.Error("failed to update link", zap.String("alias", link.Alias), zap.Error(err))
+		return fmt.Errorf("failed to update link: %w", err)
+	}
+
+	s.log.Info("updated link", zap.String("alias", link.Alias), zap.Int64("changed_by", changedBy))
+	return nil
+}
+
+// DeleteLink перемещает ссылку в корзину (мягкое удаление).
+// Алиас остается зарезервированным до окончательной очистки корзины.
+func (s *PostgresStorage) DeleteLink(ctx context.Context, domainID *int64, alias string) error {
+	result := s.db.WithContext(ctx).Model(&domain.Link{}).
+		Scopes(inDomain(domainID, alias)).
+		Where("deleted_at IS NULL").
+		Update("deleted_at", time.Now())
 	if result.Error != nil {
 		s.log.Error("failed to delete link", zap.String("alias", alias), zap.Error(result.Error))
 		return fmt.Errorf("failed to delete link: %w", result.Error)
@@ -179,10 +366,109 @@ func (s *PostgresStorage) DeleteLink(ctx context.Context, alias string) error {
 	return nil
 }
 
-// AliasExists проверяет, существует ли алиас
-func (s *PostgresStorage) AliasExists(ctx context.Context, alias string) (bool, error) {
+// GetDeletedLink получает ссылку из корзины по алиасу
+func (s *PostgresStorage) GetDeletedLink(ctx context.Context, domainID *int64, alias string) (*domain.Link, error) {
+	var link domain.Link
+
+	err := s.db.WithContext(ctx).Preload("Domain").Scopes(inDomain(domainID, alias)).
+		Where("deleted_at IS NOT NULL").First(&link).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, repository.ErrAliasNotFound
+	}
+	if err != nil {
+		s.log.Error("failed to get deleted link", zap.String("alias", alias), zap.Error(err))
+		return nil, fmt.Errorf("failed to get deleted link: %w", err)
+	}
+
+	return &link, nil
+}
+
+// ListDeletedLinks возвращает ссылки пользователя, находящиеся в корзине
+func (s *PostgresStorage) ListDeletedLinks(ctx context.Context, userID int64) ([]*domain.Link, error) {
+	var links []*domain.Link
+
+	err := s.db.WithContext(ctx).Preload("Domain").Where("user_id = ? AND deleted_at IS NOT NULL", userID).
+		Order("deleted_at DESC").Find(&links).Error
+	if err != nil {
+		s.log.Error("failed to list deleted links", zap.Int64("user_id", userID), zap.Error(err))
+		return nil, fmt.Errorf("failed to list deleted links: %w", err)
+	}
+
+	return links, nil
+}
+
+// RestoreLink восстанавливает ссылку из корзины
+func (s *PostgresStorage) RestoreLink(ctx context.Context, domainID *int64, alias string) error {
+	result := s.db.WithContext(ctx).Model(&domain.Link{}).
+		Scopes(inDomain(domainID, alias)).
+		Where("deleted_at IS NOT NULL").
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		s.log.Error("failed to restore link", zap.String("alias", alias), zap.Error(result.Error))
+		return fmt.Errorf("failed to restore link: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return repository.ErrAliasNotFound
+	}
+
+	s.log.Info("restored link", zap.String("alias", alias))
+	return nil
+}
+
+// PurgeDeletedLinks окончательно удаляет ссылки, находящиеся в корзине дольше retention,
+// вместе с их кликами, историей изменений и привязками тегов. Возвращает количество удаленных ссылок.
+func (s *PostgresStorage) PurgeDeletedLinks(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	var purged int64
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		expired := tx.Model(&domain.Link{}).Select("id").
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore)
+
+		if err := tx.Where("link_id IN (?)", expired).Delete(&domain.Click{}).Error; err != nil {
+			return fmt.Errorf("failed to purge clicks: %w", err)
+		}
+		if err := tx.Where("link_id IN (?)", expired).Delete(&domain.LinkRevision{}).Error; err != nil {
+			return fmt.Errorf("failed to purge link revisions: %w", err)
+		}
+		if err := tx.Exec("DELETE FROM link_tags WHERE link_id IN (?)", expired).Error; err != nil {
+			return fmt.Errorf("failed to purge link tags: %w", err)
+		}
+		if err := tx.Where("link_id IN (?)", expired).Delete(&domain.RedirectRule{}).Error; err != nil {
+			return fmt.Errorf("failed to purge redirect rules: %w", err)
+		}
+		if err := tx.Where("link_id IN (?)", expired).Delete(&domain.LinkVariant{}).Error; err != nil {
+			return fmt.Errorf("failed to purge link variants: %w", err)
+		}
+		if err := tx.Where("link_id IN (?)", expired).Delete(&domain.LinkAppeal{}).Error; err != nil {
+			return fmt.Errorf("failed to purge link appeals: %w", err)
+		}
+		if err := tx.Where("link_id IN (?)", expired).Delete(&domain.LinkHealth{}).Error; err != nil {
+			return fmt.Errorf("failed to purge link health: %w", err)
+		}
+
+		result := tx.Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).Delete(&domain.Link{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to purge links: %w", result.Error)
+		}
+		purged = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		s.log.Error("failed to purge deleted links", zap.Time("deleted_before", deletedBefore), zap.Error(err))
+		return 0, err
+	}
+
+	if purged > 0 {
+		s.log.Info("purged deleted links", zap.Int64("count", purged), zap.Time("deleted_before", deletedBefore))
+	}
+	return purged, nil
+}
+
+// AliasExists проверяет, занят ли алиас в домене (включая ссылки в корзине)
+func (s *PostgresStorage) AliasExists(ctx context.Context, domainID *int64, alias string) (bool, error) {
 	var count int64
-	err := s.db.WithContext(ctx).Model(&domain.Link{}).Where("alias = ?", alias).Count(&count).Error
+	err := s.db.WithContext(ctx).Model(&domain.Link{}).Scopes(inDomain(domainID, alias)).Count(&count).Error
 	if err != nil {
 		s.log.Error("failed to check alias existence", zap.String("alias", alias), zap.Error(err))
 		return false, fmt.Errorf("failed to check alias: %w", err)
@@ -191,12 +477,54 @@ func (s *PostgresStorage) AliasExists(ctx context.Context, alias string) (bool,
 	return count > 0, nil
 }
 
+// uniqueViolationCode код ошибки Postgres unique_violation
+const uniqueViolationCode = "23505"
+
+// aliasUniqueIndexes ограничения уникальности алиаса: текущие индексы по доменам и глобальные из ранних версий схемы
+var aliasUniqueIndexes = map[string]bool{
+	"idx_links_default_alias": true,
+	"idx_links_domain_alias":  true,
+	"idx_links_alias":         true,
+	"links_alias_key":         true,
+}
+
+// isAliasUniqueViolation проверяет, что ошибка вызвана нарушением уникальности алиаса
+func isAliasUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode && aliasUniqueIndexes[pgErr.ConstraintName]
+}
+
+// NextAliasSequence возвращает следующее значение последовательности link_alias_seq
+func (s *PostgresStorage) NextAliasSequence(ctx context.Context) (int64, error) {
+	var value int64
+	if err := s.db.WithContext(ctx).Raw("SELECT nextval('link_alias_seq')").Scan(&value).Error; err != nil {
+		s.log.Error("failed to get next alias sequence value", zap.Error(err))
+		return 0, fmt.Errorf("failed to get next alias sequence value: %w", err)
+	}
+	return value, nil
+}
+
+// IsAliasBlocked проверяет алиас по черному списку blocked_aliases без учета регистра
+func (s *PostgresStorage) IsAliasBlocked(ctx context.Context, alias string) (bool, error) {
+	var count int64
+	lowered := strings.ToLower(alias)
+	err := s.db.WithContext(ctx).Model(&domain.BlockedAlias{}).
+		Where("(match_type = ? AND lower(pattern) = ?) OR (match_type = ? AND strpos(?, lower(pattern)) > 0)",
+			domain.BlockedAliasMatchExact, lowered, domain.BlockedAliasMatchContains, lowered).
+		Count(&count).Error
+	if err != nil {
+		s.log.Error("failed to check alias blocklist", zap.String("alias", alias), zap.Error(err))
+		return false, fmt.Errorf("failed to check alias blocklist: %w", err)
+	}
+	return count > 0, nil
+}
+
 // RecordClick записывает клик и обновляет статистику
 func (s *PostgresStorage) RecordClick(ctx context.Context, alias string, deviceType string) error {
 	return s.RecordClickAdvanced(ctx, alias, deviceType, nil, nil, nil, nil)
 }
 
-// RecordClickAdvanced записывает клик с расширенной информацией
+// RecordClickAdvanced записывает клик с расширенной информацией по ссылке основного домена
 func (s *PostgresStorage) RecordClickAdvanced(ctx context.Context, alias string, deviceType string, ipAddress *string, userAgent *string, referer *string, clickedAt *time.Time) error {
 	// Начинаем транзакцию
 	tx := s.db.WithContext(ctx).Begin()
@@ -208,7 +536,7 @@ func (s *PostgresStorage) RecordClickAdvanced(ctx context.Context, alias string,
 
 	// Получаем ссылку
 	var link domain.Link
-	err := tx.Where("alias = ? AND is_active = ?", alias, true).First(&link).Error
+	err := tx.Scopes(inDomain(nil, alias)).Where("is_active = ? AND deleted_at IS NULL", true).First(&link).Error
 	if err == gorm.ErrRecordNotFound {
 		tx.Rollback()
 		return repository.ErrAliasNotFound
@@ -274,20 +602,6 @@ func (s *PostgresStorage) RecordClickAdvanced(ctx context.Context, alias string,
 	return nil
 }
 
-// ListUserLinks возвращает список ссылок пользователя
-func (s *PostgresStorage) ListUserLinks(ctx context.Context, userID int64) ([]*domain.Link, error) {
-	var links []*domain.Link
-
-	err := s.db.WithContext(ctx).Where("user_id = ? AND is_active = ?", userID, true).
-		Order("created_at DESC").Find(&links).Error
-	if err != nil {
-		s.log.Error("failed to list user links", zap.Int64("user_id", userID), zap.Error(err))
-		return nil, fmt.Errorf("failed to list user links: %w", err)
-	}
-
-	return links, nil
-}
-
 // GetClicksByDevice возвращает статистику кликов по типам устройств для ссылки
 func (s *PostgresStorage) GetClicksByDevice(ctx context.Context, linkID int64) (map[string]int64, error) {
 	var results []struct {
@@ -315,8 +629,35 @@ func (s *PostgresStorage) GetClicksByDevice(ctx context.Context, linkID int64) (
 	return clicksByDevice, nil
 }
 
+// GetClicksBySource получает статистику кликов по источнику: qr или direct
+func (s *PostgresStorage) GetClicksBySource(ctx context.Context, linkID int64) (map[string]int64, error) {
+	var results []struct {
+		Source string `gorm:"column:source"`
+		Count  int64  `gorm:"column:count"`
+	}
+
+	err := s.db.WithContext(ctx).
+		Model(&domain.Click{}).
+		Select("COALESCE(source, ?) as source, count(*) as count", domain.ClickSourceDirect).
+		Where("link_id = ?", linkID).
+		Group("1").
+		Find(&results).Error
+
+	if err != nil {
+		s.log.Error("failed to get clicks by source", zap.Int64("link_id", linkID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get clicks by source: %w", err)
+	}
+
+	clicksBySource := make(map[string]int64)
+	for _, result := range results {
+		clicksBySource[result.Source] = result.Count
+	}
+
+	return clicksBySource, nil
+}
+
 // GetLinkAndRecordClick получает ссылку и записывает клик атомарно (для unified service)
-func (s *PostgresStorage) GetLinkAndRecordClick(ctx context.Context, alias string, ipAddress *string, userAgent *string, referer *string) (*domain.Link, error) {
+func (s *PostgresStorage) GetLinkAndRecordClick(ctx context.Context, domainID *int64, alias string, click *domain.Click) (*domain.Link, error) {
 	// Начинаем транзакцию
 	tx := s.db.WithContext(ctx).Begin()
 	defer func() {
@@ -327,7 +668,7 @@ func (s *PostgresStorage) GetLinkAndRecordClick(ctx context.Context, alias strin
 
 	// Получаем ссылку
 	var link domain.Link
-	err := tx.Where("alias = ? AND is_active = ?", alias, true).First(&link).Error
+	err := tx.Scopes(inDomain(domainID, alias)).Where("is_active = ? AND deleted_at IS NULL", true).First(&link).Error
 	if err == gorm.ErrRecordNotFound {
 		tx.Rollback()
 		return nil, repository.ErrAliasNotFound
@@ -338,42 +679,39 @@ func (s *PostgresStorage) GetLinkAndRecordClick(ctx context.Context, alias strin
 		return nil, fmt.Errorf("failed to get link: %w", err)
 	}
 
-	// Проверяем срок действия ссылки
-	if link.ExpiresAt != nil && time.Now().After(*link.ExpiresAt) {
+	// Проверяем время запуска и срок действия ссылки
+	if link.IsNotStarted() {
 		tx.Rollback()
-		return nil, repository.ErrAliasNotFound
+		return nil, repository.ErrLinkNotStarted
 	}
-
-	// Обновляем счетчик кликов
-	err = tx.Model(&link).Update("click_count", gorm.Expr("click_count + 1")).Error
-	if err != nil {
+	if link.IsExpired() {
 		tx.Rollback()
-		s.log.Error("failed to update click count", zap.String("alias", alias), zap.Error(err))
-		return nil, fmt.Errorf("failed to update click count: %w", err)
+		return nil, repository.ErrLinkExpired
 	}
 
-	// Создаем запись клика
-	clickedAt := time.Now()
-	click := domain.Click{
-		LinkID:     link.ID,
-		ClickedAt:  clickedAt,
-		IsUnique:   true, // Simplified logic for now
-	}
-
-	// Добавляем дополнительную информацию если есть
-	if ipAddress != nil {
-		if ip := net.ParseIP(*ipAddress); ip != nil {
-			click.IPAddress = &ip
-		}
-	}
-	if userAgent != nil {
-		click.UserAgent = userAgent
+	// Обновляем счетчик кликов только если лимит переходов не исчерпан.
+	// Условие проверяется в самом UPDATE, поэтому конкурентные редиректы
+	// блокируются на строке и не могут превысить max_clicks.
+	result := tx.Model(&link).
+		Where("max_clicks IS NULL OR click_count < max_clicks").
+		Update("click_count", gorm.Expr("click_count + 1"))
+	if result.Error != nil {
+		tx.Rollback()
+		s.log.Error("failed to update click count", zap.String("alias", alias), zap.Error(result.Error))
+		return nil, fmt.Errorf("failed to update click count: %w", result.Error)
 	}
-	if referer != nil {
-		click.Referer = referer
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return nil, repository.ErrLinkExhausted
 	}
+	link.ClickCount++
 
-	err = tx.Create(&click).Error
+	// Создаем запись клика
+	click.LinkID = link.ID
+	click.ClickedAt = time.Now()
+	click.IsUnique = true // Simplified logic for now
+
+	err = tx.Create(click).Error
 	if err != nil {
 		tx.Rollback()
 		s.log.Error("failed to record click", zap.String("alias", alias), zap.Error(err))
@@ -395,6 +733,38 @@ func (s *PostgresStorage) GetLinkAndRecordClick(ctx context.Context, alias strin
 	return &link, nil
 }
 
+// --- Link Revision Methods ---
+
+// ListLinkRevisions возвращает историю изменений адреса назначения ссылки (новые первыми)
+func (s *PostgresStorage) ListLinkRevisions(ctx context.Context, linkID int64) ([]*domain.LinkRevision, error) {
+	var revisions []*domain.LinkRevision
+
+	err := s.db.WithContext(ctx).Where("link_id = ?", linkID).
+		Order("created_at DESC, id DESC").Find(&revisions).Error
+	if err != nil {
+		s.log.Error("failed to list link revisions", zap.Int64("link_id", linkID), zap.Error(err))
+		return nil, fmt.Errorf("failed to list link revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// GetLinkRevision получает ревизию ссылки по ID
+func (s *PostgresStorage) GetLinkRevision(ctx context.Context, linkID, revisionID int64) (*domain.LinkRevision, error) {
+	var revision domain.LinkRevision
+
+	err := s.db.WithContext(ctx).Where("id = ? AND link_id = ?", revisionID, linkID).First(&revision).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, repository.ErrRevisionNotFound
+	}
+	if err != nil {
+		s.log.Error("failed to get link revision", zap.Int64("link_id", linkID), zap.Int64("revision_id", revisionID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get link revision: %w", err)
+	}
+
+	return &revision, nil
+}
+
 // --- Helper Methods ---
 
 // createUserStats создает начальную статистику для пользователя
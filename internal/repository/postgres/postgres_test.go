@@ -4,7 +4,10 @@ package postgres
 
 import (
 	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/repository"
 	"context"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -45,6 +48,8 @@ func setupTestDB(t *testing.T) (*PostgresStorage, func()) {
 	err = db.AutoMigrate(
 		&domain.SubscriptionType{},
 		&domain.User{},
+		&domain.Tag{},
+		&domain.CustomDomain{},
 		&domain.Link{},
 		&domain.Click{},
 		&domain.UserStats{},
@@ -142,7 +147,7 @@ func TestPostgresStorage_SaveAndGetLink(t *testing.T) {
 	require.NoError(t, err)
 
 	// Test getting link
-	retrievedLink, err := storage.GetLink(ctx, "test123")
+	retrievedLink, err := storage.GetLink(ctx, nil, "test123")
 	require.NoError(t, err)
 	assert.Equal(t, link.OriginalURL, retrievedLink.OriginalURL)
 	assert.Equal(t, link.Alias, retrievedLink.Alias)
@@ -179,7 +184,7 @@ func TestPostgresStorage_RecordClickAdvanced(t *testing.T) {
 	require.NoError(t, err)
 
 	// Verify click was recorded
-	retrievedLink, err := storage.GetLink(ctx, "test123")
+	retrievedLink, err := storage.GetLink(ctx, nil, "test123")
 	require.NoError(t, err)
 	assert.Equal(t, int64(1), retrievedLink.ClickCount)
 
@@ -283,11 +288,11 @@ func TestPostgresStorage_DeleteLink(t *testing.T) {
 	require.NoError(t, err)
 
 	// Delete link (soft delete)
-	err = storage.DeleteLink(ctx, "test123")
+	err = storage.DeleteLink(ctx, nil, "test123")
 	require.NoError(t, err)
 
 	// Verify link is not accessible
-	_, err = storage.GetLink(ctx, "test123")
+	_, err = storage.GetLink(ctx, nil, "test123")
 	assert.Error(t, err)
 }
 
@@ -311,12 +316,221 @@ func TestPostgresStorage_AliasExists(t *testing.T) {
 	require.NoError(t, err)
 
 	// Test alias exists
-	exists, err := storage.AliasExists(ctx, "test123")
+	exists, err := storage.AliasExists(ctx, nil, "test123")
 	require.NoError(t, err)
 	assert.True(t, exists)
 
 	// Test alias doesn't exist
-	exists, err = storage.AliasExists(ctx, "nonexistent")
+	exists, err = storage.AliasExists(ctx, nil, "nonexistent")
 	require.NoError(t, err)
 	assert.False(t, exists)
-}
\ No newline at end of file
+}
+
+func TestPostgresStorage_GetLinkAndRecordClick_MaxClicksUnderConcurrency(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	user, err := storage.CreateUser(ctx, "limits@example.com", "hash")
+	require.NoError(t, err)
+
+	const maxClicks, redirects = 5, 40
+	limit := maxClicks
+	link := &domain.Link{
+		UserID:      user.ID,
+		OriginalURL: "https://example.com",
+		Alias:       "limited",
+		MaxClicks:   &limit,
+	}
+	require.NoError(t, storage.SaveLink(ctx, link))
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		recorded  int
+		exhausted int
+	)
+	for i := 0; i < redirects; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := storage.GetLinkAndRecordClick(ctx, nil, "limited", &domain.Click{})
+			mu.Lock()
+			defer mu.Unlock()
+			switch err {
+			case nil:
+				recorded++
+			case repository.ErrLinkExhausted:
+				exhausted++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, maxClicks, recorded)
+	assert.Equal(t, redirects-maxClicks, exhausted)
+
+	found, err := storage.FindLink(ctx, nil, "limited")
+	require.NoError(t, err)
+	assert.Equal(t, int64(maxClicks), found.ClickCount)
+}
+
+func TestPostgresStorage_GetLinkAndRecordClick_Expired(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	user, err := storage.CreateUser(ctx, "expired@example.com", "hash")
+	require.NoError(t, err)
+
+	expiresAt := time.Now().Add(-time.Hour)
+	link := &domain.Link{
+		UserID:      user.ID,
+		OriginalURL: "https://example.com",
+		Alias:       "expired",
+		ExpiresAt:   &expiresAt,
+	}
+	require.NoError(t, storage.SaveLink(ctx, link))
+
+	_, err = storage.GetLinkAndRecordClick(ctx, nil, "expired", &domain.Click{})
+	assert.ErrorIs(t, err, repository.ErrLinkExpired)
+
+	found, err := storage.FindLink(ctx, nil, "expired")
+	require.NoError(t, err)
+	assert.Zero(t, found.ClickCount)
+}
+
+func TestPostgresStorage_SaveLink_MonthlyLimitUnderConcurrency(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	const maxLinks, attempts = 3, 20
+	limit := maxLinks
+	plan := domain.SubscriptionType{ID: 3, Name: "limited", DisplayName: "Limited Plan", IsActive: true, MaxLinksPerMonth: &limit}
+	require.NoError(t, storage.db.Create(&plan).Error)
+
+	user, err := storage.CreateUser(ctx, "quota@example.com", "hash")
+	require.NoError(t, err)
+	require.NoError(t, storage.db.Model(user).Update("subscription_type_id", plan.ID).Error)
+
+	// Ссылка в корзине занимает место в лимите
+	trashed := &domain.Link{UserID: user.ID, OriginalURL: "https://example.com/trashed", Alias: "trashed"}
+	require.NoError(t, storage.SaveLink(ctx, trashed))
+	require.NoError(t, storage.DeleteLink(ctx, nil, "trashed"))
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		saved    int
+		rejected int
+	)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			link := &domain.Link{
+				UserID:      user.ID,
+				OriginalURL: "https://example.com",
+				Alias:       fmt.Sprintf("quota-%d", i),
+			}
+			err := storage.SaveLink(ctx, link)
+			mu.Lock()
+			defer mu.Unlock()
+			switch err {
+			case nil:
+				saved++
+			case repository.ErrLinkLimitReached:
+				rejected++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, maxLinks-1, saved)
+	assert.Equal(t, attempts-maxLinks+1, rejected)
+
+	created, err := storage.CountLinksCreatedSince(ctx, user.ID, time.Now().AddDate(0, -1, 0))
+	require.NoError(t, err)
+	assert.Equal(t, int64(maxLinks), created)
+}
+
+func TestPostgresStorage_SaveLink_CreatesTagsWithLink(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	limit := 1
+	plan := domain.SubscriptionType{ID: 4, Name: "single", DisplayName: "Single Link", IsActive: true, MaxLinksPerMonth: &limit}
+	require.NoError(t, storage.db.Create(&plan).Error)
+
+	user, err := storage.CreateUser(ctx, "tags@example.com", "hash")
+	require.NoError(t, err)
+	require.NoError(t, storage.db.Model(user).Update("subscription_type_id", plan.ID).Error)
+	_, err = storage.ResolveTags(ctx, user.ID, []string{"promo"})
+	require.NoError(t, err)
+
+	link := &domain.Link{
+		UserID:      user.ID,
+		OriginalURL: "https://example.com",
+		Alias:       "tagged",
+		Tags:        []domain.Tag{{UserID: user.ID, Name: "promo"}, {UserID: user.ID, Name: "spring"}},
+	}
+	require.NoError(t, storage.SaveLink(ctx, link))
+	require.Len(t, link.Tags, 2)
+	for _, tag := range link.Tags {
+		assert.NotZero(t, tag.ID, tag.Name)
+	}
+
+	// ссылка не сохранилась - новый тег не остается в списке пользователя
+	rejected := &domain.Link{
+		UserID:      user.ID,
+		OriginalURL: "https://example.com",
+		Alias:       "over-limit",
+		Tags:        []domain.Tag{{UserID: user.ID, Name: "orphan"}},
+	}
+	assert.ErrorIs(t, storage.SaveLink(ctx, rejected), repository.ErrLinkLimitReached)
+
+	tags, err := storage.ListTags(ctx, user.ID)
+	require.NoError(t, err)
+	var names []string
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	assert.Equal(t, []string{"promo", "spring"}, names)
+}
+
+func TestPostgresStorage_SaveLinkMetadata_ProtectedLinkCardHidesDestination(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	user, err := storage.CreateUser(ctx, "secret@example.com", "hash")
+	require.NoError(t, err)
+
+	hash := "password-hash"
+	link := &domain.Link{UserID: user.ID, OriginalURL: "https://example.com/secret", Alias: "secret", PasswordHash: &hash}
+	require.NoError(t, storage.SaveLink(ctx, link))
+
+	fetchedAt := time.Now()
+	require.NoError(t, storage.SaveLinkMetadata(ctx, link.ID, domain.LinkMetadata{
+		Title:       "Secret page",
+		Description: "About the secret page",
+		ImageURL:    "https://example.com/secret.png",
+		FetchedAt:   &fetchedAt,
+	}))
+
+	saved, err := storage.FindLink(ctx, nil, "secret")
+	require.NoError(t, err)
+	assert.Nil(t, saved.Title, "destination title is not copied into a protected link")
+	assert.Nil(t, saved.Description)
+
+	title, description, imageURL := saved.PreviewCard()
+	for _, value := range []string{title, description, imageURL} {
+		assert.NotContains(t, value, "secret", "card must not reveal the destination")
+	}
+}
@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/repository"
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ListRedirectRules возвращает правила редиректа ссылки в порядке проверки
+func (s *PostgresStorage) ListRedirectRules(ctx context.Context, linkID int64) ([]domain.RedirectRule, error) {
+	var rules []domain.RedirectRule
+
+	err := s.db.WithContext(ctx).
+		Where("link_id = ?", linkID).
+		Order("position ASC, id ASC").
+		Find(&rules).Error
+	if err != nil {
+		s.log.Error("failed to list redirect rules", zap.Int64("link_id", linkID), zap.Error(err))
+		return nil, fmt.Errorf("failed to list redirect rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// ReplaceRedirectRules заменяет правила редиректа ссылки; порядок правил задается порядком в списке.
+// Правила с ID существующих правил ссылки обновляются на месте, чтобы статистика по ним сохранялась,
+// правила без ID создаются, а отсутствующие в списке удаляются.
+// Если ID не принадлежит правилу этой ссылки, возвращается ErrRedirectRuleNotFound.
+func (s *PostgresStorage) ReplaceRedirectRules(ctx context.Context, linkID int64, rules []domain.RedirectRule) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existingIDs []int64
+		if err := tx.Model(&domain.RedirectRule{}).Where("link_id = ?", linkID).Pluck("id", &existingIDs).Error; err != nil {
+			return err
+		}
+		existing := make(map[int64]bool, len(existingIDs))
+		for _, id := range existingIDs {
+			existing[id] = true
+		}
+
+		kept := make([]int64, 0, len(rules))
+		for i := range rules {
+			if rules[i].ID != 0 {
+				if !existing[rules[i].ID] {
+					return repository.ErrRedirectRuleNotFound
+				}
+				kept = append(kept, rules[i].ID)
+			}
+		}
+
+		deleteQuery := tx.Where("link_id = ?", linkID)
+		if len(kept) > 0 {
+			deleteQuery = deleteQuery.Where("id NOT IN ?", kept)
+		}
+		if err := deleteQuery.Delete(&domain.RedirectRule{}).Error; err != nil {
+			return err
+		}
+
+		for i := range rules {
+			rules[i].LinkID = linkID
+			rules[i].Position = i + 1
+			if rules[i].ID == 0 {
+				if err := tx.Create(&rules[i]).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			err := tx.Model(&rules[i]).Select("position", "name", "countries", "device_types", "os_families",
+				"browsers", "active_from", "active_until", "weekdays", "time_from", "time_to",
+				"destination_url", "updated_at").Updates(&rules[i]).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == repository.ErrRedirectRuleNotFound {
+		return err
+	}
+	if err != nil {
+		s.log.Error("failed to replace redirect rules", zap.Int64("link_id", linkID), zap.Error(err))
+		return fmt.Errorf("failed to replace redirect rules: %w", err)
+	}
+
+	s.log.Info("replaced redirect rules", zap.Int64("link_id", linkID), zap.Int("count", len(rules)))
+	return nil
+}
+
+// GetClicksByRule возвращает клики по ссылке, сгруппированные по сработавшему правилу редиректа.
+// Результат отсортирован по убыванию количества кликов.
+func (s *PostgresStorage) GetClicksByRule(ctx context.Context, linkID int64) ([]repository.RuleClicks, error) {
+	rules := make([]repository.RuleClicks, 0)
+
+	err := s.db.WithContext(ctx).
+		Table("clicks").
+		Select("clicks.rule_id AS rule_id, COALESCE(redirect_rules.name, '') AS name, count(*) AS clicks").
+		Joins("LEFT JOIN redirect_rules ON redirect_rules.id = clicks.rule_id").
+		Where("clicks.link_id = ?", linkID).
+		Group("clicks.rule_id, redirect_rules.name").
+		Order("clicks DESC, rule_id").
+		Scan(&rules).Error
+	if err != nil {
+		s.log.Error("failed to get clicks by rule", zap.Int64("link_id", linkID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get clicks by rule: %w", err)
+	}
+
+	return rules, nil
+}
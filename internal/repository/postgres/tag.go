@@ -0,0 +1,241 @@
+package postgres
+
+import (
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/repository"
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateTag создает тег пользователя; название должно быть уникальным в пределах пользователя
+func (s *PostgresStorage) CreateTag(ctx context.Context, tag *domain.Tag) error {
+	exists, err := s.tagNameTaken(ctx, tag.UserID, tag.Name, 0)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return repository.ErrTagExists
+	}
+
+	if err := s.db.WithContext(ctx).Create(tag).Error; err != nil {
+		s.log.Error("failed to create tag", zap.Int64("user_id", tag.UserID), zap.String("name", tag.Name), zap.Error(err))
+		return fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	s.log.Info("created tag", zap.Int64("tag_id", tag.ID), zap.Int64("user_id", tag.UserID))
+	return nil
+}
+
+// GetTag получает тег по ID
+func (s *PostgresStorage) GetTag(ctx context.Context, tagID int64) (*domain.Tag, error) {
+	var tag domain.Tag
+
+	err := s.db.WithContext(ctx).Where("id = ?", tagID).First(&tag).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, repository.ErrTagNotFound
+	}
+	if err != nil {
+		s.log.Error("failed to get tag", zap.Int64("tag_id", tagID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+
+	return &tag, nil
+}
+
+// ListTags возвращает теги пользователя с количеством ссылок (без корзины), отсортированные по названию
+func (s *PostgresStorage) ListTags(ctx context.Context, userID int64) ([]*domain.Tag, error) {
+	var tags []*domain.Tag
+
+	err := s.db.WithContext(ctx).
+		Select(`tags.*, (
+			SELECT count(*) FROM link_tags
+			JOIN links ON links.id = link_tags.link_id
+			WHERE link_tags.tag_id = tags.id AND links.deleted_at IS NULL
+		) AS link_count`).
+		Where("user_id = ?", userID).
+		Order("name ASC").
+		Find(&tags).Error
+	if err != nil {
+		s.log.Error("failed to list tags", zap.Int64("user_id", userID), zap.Error(err))
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// UpdateTag переименовывает тег
+func (s *PostgresStorage) UpdateTag(ctx context.Context, tag *domain.Tag) error {
+	exists, err := s.tagNameTaken(ctx, tag.UserID, tag.Name, tag.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return repository.ErrTagExists
+	}
+
+	result := s.db.WithContext(ctx).Model(&domain.Tag{}).Where("id = ?", tag.ID).Update("name", tag.Name)
+	if result.Error != nil {
+		s.log.Error("failed to update tag", zap.Int64("tag_id", tag.ID), zap.Error(result.Error))
+		return fmt.Errorf("failed to update tag: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrTagNotFound
+	}
+
+	s.log.Info("updated tag", zap.Int64("tag_id", tag.ID))
+	return nil
+}
+
+// DeleteTag удаляет тег и снимает его со всех ссылок; сами ссылки не затрагиваются
+func (s *PostgresStorage) DeleteTag(ctx context.Context, tagID int64) error {
+	var deleted int64
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM link_tags WHERE tag_id = ?", tagID).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id = ?", tagID).Delete(&domain.Tag{})
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		s.log.Error("failed to delete tag", zap.Int64("tag_id", tagID), zap.Error(err))
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+	if deleted == 0 {
+		return repository.ErrTagNotFound
+	}
+
+	s.log.Info("deleted tag", zap.Int64("tag_id", tagID))
+	return nil
+}
+
+// ResolveTags возвращает теги пользователя с указанными названиями, создавая недостающие.
+// Названия должны быть уже нормализованы.
+func (s *PostgresStorage) ResolveTags(ctx context.Context, userID int64, names []string) ([]domain.Tag, error) {
+	if len(names) == 0 {
+		return []domain.Tag{}, nil
+	}
+
+	var tags []domain.Tag
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		tags, err = resolveTags(tx, userID, names)
+		return err
+	})
+	if err != nil {
+		s.log.Error("failed to resolve tags", zap.Int64("user_id", userID), zap.Strings("names", names), zap.Error(err))
+		return nil, fmt.Errorf("failed to resolve tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// resolveTags находит или создает теги пользователя в транзакции tx
+func resolveTags(tx *gorm.DB, userID int64, names []string) ([]domain.Tag, error) {
+	newTags := make([]domain.Tag, len(names))
+	for i, name := range names {
+		newTags[i] = domain.Tag{UserID: userID, Name: name}
+	}
+
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "name"}},
+		DoNothing: true,
+	}).Create(&newTags).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []domain.Tag
+	if err := tx.Where("user_id = ? AND name IN ?", userID, names).Order("name ASC").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// ReplaceLinkTags заменяет набор тегов ссылки; пустой список снимает все теги
+func (s *PostgresStorage) ReplaceLinkTags(ctx context.Context, linkID int64, tags []domain.Tag) error {
+	association := s.db.WithContext(ctx).Model(&domain.Link{ID: linkID}).Association("Tags")
+
+	var err error
+	if len(tags) == 0 {
+		err = association.Clear()
+	} else {
+		err = association.Replace(tags)
+	}
+	if err != nil {
+		s.log.Error("failed to replace link tags", zap.Int64("link_id", linkID), zap.Error(err))
+		return fmt.Errorf("failed to replace link tags: %w", err)
+	}
+
+	return nil
+}
+
+// GetTagStats возвращает суммарную статистику ссылок с тегом
+func (s *PostgresStorage) GetTagStats(ctx context.Context, tagID int64) (*repository.TagStats, error) {
+	var totals struct {
+		LinkCount   int64 `gorm:"column:link_count"`
+		TotalClicks int64 `gorm:"column:total_clicks"`
+	}
+
+	err := s.db.WithContext(ctx).
+		Table("links").
+		Select("count(*) AS link_count, COALESCE(sum(links.click_count), 0) AS total_clicks").
+		Joins("JOIN link_tags ON link_tags.link_id = links.id").
+		Where("link_tags.tag_id = ? AND links.deleted_at IS NULL", tagID).
+		Scan(&totals).Error
+	if err != nil {
+		s.log.Error("failed to get tag totals", zap.Int64("tag_id", tagID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get tag stats: %w", err)
+	}
+
+	var devices []struct {
+		DeviceType string `gorm:"column:device_type"`
+		Count      int64  `gorm:"column:count"`
+	}
+	err = s.db.WithContext(ctx).
+		Model(&domain.Click{}).
+		Select("COALESCE(clicks.device_type, 'unknown') AS device_type, count(*) AS count").
+		Joins("JOIN link_tags ON link_tags.link_id = clicks.link_id").
+		Joins("JOIN links ON links.id = clicks.link_id").
+		Where("link_tags.tag_id = ? AND links.deleted_at IS NULL", tagID).
+		Group("1").
+		Scan(&devices).Error
+	if err != nil {
+		s.log.Error("failed to get tag clicks by device", zap.Int64("tag_id", tagID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get tag stats: %w", err)
+	}
+
+	stats := &repository.TagStats{
+		LinkCount:      totals.LinkCount,
+		TotalClicks:    totals.TotalClicks,
+		ClicksByDevice: make(map[string]int64, len(devices)),
+	}
+	for _, device := range devices {
+		stats.ClicksByDevice[device.DeviceType] = device.Count
+	}
+
+	return stats, nil
+}
+
+// tagNameTaken проверяет, есть ли у пользователя другой тег с таким названием
+func (s *PostgresStorage) tagNameTaken(ctx context.Context, userID int64, name string, exceptID int64) (bool, error) {
+	var count int64
+
+	err := s.db.WithContext(ctx).Model(&domain.Tag{}).
+		Where("user_id = ? AND name = ? AND id <> ?", userID, name, exceptID).
+		Count(&count).Error
+	if err != nil {
+		s.log.Error("failed to check tag name", zap.Int64("user_id", userID), zap.String("name", name), zap.Error(err))
+		return false, fmt.Errorf("failed to check tag name: %w", err)
+	}
+
+	return count > 0, nil
+}
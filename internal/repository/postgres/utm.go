@@ -0,0 +1,32 @@
+package postgres
+
+import (
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/repository"
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// GetClicksByCampaign возвращает клики по ссылке, сгруппированные по UTM меткам входящих переходов.
+// Клики без меток не учитываются; результат отсортирован по убыванию количества кликов.
+func (s *PostgresStorage) GetClicksByCampaign(ctx context.Context, linkID int64) ([]repository.CampaignClicks, error) {
+	campaigns := make([]repository.CampaignClicks, 0)
+
+	err := s.db.WithContext(ctx).
+		Model(&domain.Click{}).
+		Select(`COALESCE(utm_source, '') AS source, COALESCE(utm_medium, '') AS medium,
+			COALESCE(utm_campaign, '') AS campaign, count(*) AS clicks`).
+		Where("link_id = ?", linkID).
+		Where("utm_source IS NOT NULL OR utm_medium IS NOT NULL OR utm_campaign IS NOT NULL").
+		Group("1, 2, 3").
+		Order("clicks DESC, source, medium, campaign").
+		Scan(&campaigns).Error
+	if err != nil {
+		s.log.Error("failed to get clicks by campaign", zap.Int64("link_id", linkID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get clicks by campaign: %w", err)
+	}
+
+	return campaigns, nil
+}
@@ -0,0 +1,10 @@
+package repository
+
+// RuleClicks количество кликов по ссылке, при которых сработало правило редиректа.
+// RuleID nil - переходы на основной адрес ссылки, когда ни одно правило не подошло.
+// Name пуст, если правило без подписи или уже удалено.
+type RuleClicks struct {
+	RuleID *int64 `gorm:"column:rule_id" json:"rule_id"`
+	Name   string `gorm:"column:name" json:"name,omitempty"`
+	Clicks int64  `gorm:"column:clicks" json:"clicks"`
+}
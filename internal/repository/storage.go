@@ -10,8 +10,20 @@ import (
 var (
 	ErrAliasNotFound              = errors.New("alias not found")
 	ErrAliasExists                = errors.New("alias already exists")
+	ErrLinkLimitReached           = errors.New("monthly link limit reached")
+	ErrLinkNotStarted             = errors.New("link not started yet")
+	ErrLinkExpired                = errors.New("link expired")
+	ErrLinkExhausted              = errors.New("link click limit reached")
+	ErrRevisionNotFound           = errors.New("link revision not found")
+	ErrTagNotFound                = errors.New("tag not found")
+	ErrTagExists                  = errors.New("tag already exists")
+	ErrRedirectRuleNotFound       = errors.New("redirect rule not found")
+	ErrLinkVariantNotFound        = errors.New("link variant not found")
 	ErrPaymentNotFound            = errors.New("payment not found")
 	ErrSubscriptionTypeNotFound   = errors.New("subscription type not found")
+	ErrDomainNotFound             = errors.New("custom domain not found")
+	ErrDomainExists               = errors.New("custom domain already exists")
+	ErrDomainInUse                = errors.New("custom domain has links")
 )
 
 type Storage interface {
@@ -25,19 +37,91 @@ type Storage interface {
 	FindUserByEmailAndPassword(ctx context.Context, email string) (*domain.User, error)
 
 	// Link methods
+	// Алиас уникален в пределах домена: domainID nil - основной домен сервиса, иначе ID собственного домена
+	// SaveLink возвращает ErrLinkLimitReached, если ссылка не укладывается в месячный лимит подписки владельца.
+	// Теги ссылки задаются нормализованными названиями; недостающие создаются вместе со ссылкой.
 	SaveLink(ctx context.Context, link *domain.Link) error
-	GetLink(ctx context.Context, alias string) (*domain.Link, error)
-	DeleteLink(ctx context.Context, alias string) error
-	AliasExists(ctx context.Context, alias string) (bool, error)
+	GetLink(ctx context.Context, domainID *int64, alias string) (*domain.Link, error)
+	FindLink(ctx context.Context, domainID *int64, alias string) (*domain.Link, error)
+	UpdateLink(ctx context.Context, link *domain.Link, changedBy int64) error
+	DeleteLink(ctx context.Context, domainID *int64, alias string) error
+	GetDeletedLink(ctx context.Context, domainID *int64, alias string) (*domain.Link, error)
+	ListDeletedLinks(ctx context.Context, userID int64) ([]*domain.Link, error)
+	RestoreLink(ctx context.Context, domainID *int64, alias string) error
+	PurgeDeletedLinks(ctx context.Context, deletedBefore time.Time) (int64, error)
+	AliasExists(ctx context.Context, domainID *int64, alias string) (bool, error)
+	NextAliasSequence(ctx context.Context) (int64, error)
+	IsAliasBlocked(ctx context.Context, alias string) (bool, error)
 	RecordClick(ctx context.Context, alias string, deviceType string) error
-	ListUserLinks(ctx context.Context, userID int64) ([]*domain.Link, error)
+	ListUserLinks(ctx context.Context, userID int64, opts LinkListOptions) (*LinkListPage, error)
+	ExportUserLinks(ctx context.Context, userID int64, opts LinkListOptions, fn func(link *domain.Link) error) error
+	// Считаются и ссылки в корзине: удаление не возвращает место в месячном лимите подписки
+	CountLinksCreatedSince(ctx context.Context, userID int64, since time.Time) (int64, error)
+
+	// Reputation methods
+	// Отмеченная ссылка отключается (is_active = false) и показывает посетителям предупреждение
+	ListLinksForReputationScan(ctx context.Context, afterID int64, limit int) ([]*domain.Link, error)
+	FlagLink(ctx context.Context, linkID int64, threat string) error
+	UnflagLink(ctx context.Context, linkID int64) error
+	CreateLinkAppeal(ctx context.Context, appeal *domain.LinkAppeal) error
+	HasPendingLinkAppeal(ctx context.Context, linkID int64) (bool, error)
+	ListLinksWithPendingAppeals(ctx context.Context, afterID int64, limit int) ([]*domain.Link, error)
+
+	// Link health methods
+	// Ссылка считается нерабочей, пока у ее записи проверки заполнено broken_since
+	ListLinksForHealthCheck(ctx context.Context, afterID int64, limit int) ([]*domain.Link, error)
+	ListLinkHealth(ctx context.Context, linkIDs []int64) (map[int64]*domain.LinkHealth, error)
+	SaveLinkHealth(ctx context.Context, health *domain.LinkHealth) error
+	ListBrokenLinks(ctx context.Context, userID int64) ([]*domain.LinkHealth, error)
+
+	// Link metadata methods
+	// Заголовок и описание ссылки заполняются из метаданных, только если пусты или были заполнены загрузкой,
+	// и никогда у защищенных паролем ссылок; такие ссылки не попадают в ListLinksWithoutMetadata
+	ListLinksWithoutMetadata(ctx context.Context, limit int) ([]*domain.Link, error)
+	SaveLinkMetadata(ctx context.Context, linkID int64, metadata domain.LinkMetadata) error
+
+	// Custom domain methods
+	CreateCustomDomain(ctx context.Context, customDomain *domain.CustomDomain) error
+	GetCustomDomain(ctx context.Context, domainID int64) (*domain.CustomDomain, error)
+	GetUserDomainByHostname(ctx context.Context, userID int64, hostname string) (*domain.CustomDomain, error)
+	GetVerifiedDomain(ctx context.Context, hostname string) (*domain.CustomDomain, error)
+	ListCustomDomains(ctx context.Context, userID int64) ([]*domain.CustomDomain, error)
+	VerifyCustomDomain(ctx context.Context, domainID int64, verifiedAt time.Time) error
+	DeleteCustomDomain(ctx context.Context, domainID int64) error
+
+	// Link revision methods
+	ListLinkRevisions(ctx context.Context, linkID int64) ([]*domain.LinkRevision, error)
+	GetLinkRevision(ctx context.Context, linkID, revisionID int64) (*domain.LinkRevision, error)
+
+	// Tag methods
+	CreateTag(ctx context.Context, tag *domain.Tag) error
+	GetTag(ctx context.Context, tagID int64) (*domain.Tag, error)
+	ListTags(ctx context.Context, userID int64) ([]*domain.Tag, error)
+	UpdateTag(ctx context.Context, tag *domain.Tag) error
+	DeleteTag(ctx context.Context, tagID int64) error
+	ResolveTags(ctx context.Context, userID int64, names []string) ([]domain.Tag, error)
+	ReplaceLinkTags(ctx context.Context, linkID int64, tags []domain.Tag) error
+	GetTagStats(ctx context.Context, tagID int64) (*TagStats, error)
+
+	// Redirect rule methods
+	ListRedirectRules(ctx context.Context, linkID int64) ([]domain.RedirectRule, error)
+	ReplaceRedirectRules(ctx context.Context, linkID int64, rules []domain.RedirectRule) error
+	GetClicksByRule(ctx context.Context, linkID int64) ([]RuleClicks, error)
+
+	// A/B variant methods
+	ListLinkVariants(ctx context.Context, linkID int64) ([]domain.LinkVariant, error)
+	ReplaceLinkVariants(ctx context.Context, linkID int64, variants []domain.LinkVariant) error
+	GetClicksByVariant(ctx context.Context, linkID int64) ([]VariantClicks, error)
 
 	// Extended analytics methods
 	RecordClickAdvanced(ctx context.Context, alias string, deviceType string, ipAddress *string, userAgent *string, referer *string, clickedAt *time.Time) error
 	GetClicksByDevice(ctx context.Context, linkID int64) (map[string]int64, error)
+	GetClicksByCampaign(ctx context.Context, linkID int64) ([]CampaignClicks, error)
+	GetClicksBySource(ctx context.Context, linkID int64) (map[string]int64, error)
 	
 	// Redirect with analytics recording (for unified service)
-	GetLinkAndRecordClick(ctx context.Context, alias string, ipAddress *string, userAgent *string, referer *string) (*domain.Link, error)
+	// click заполняется обработчиком (IP, User-Agent, referer, метки); LinkID и время клика выставляет хранилище
+	GetLinkAndRecordClick(ctx context.Context, domainID *int64, alias string, click *domain.Click) (*domain.Link, error)
 
 	// Payment methods
 	CreatePayment(ctx context.Context, payment *domain.Payment) error
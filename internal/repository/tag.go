@@ -0,0 +1,8 @@
+package repository
+
+// TagStats агрегированная статистика по всем ссылкам с тегом (без ссылок в корзине)
+type TagStats struct {
+	LinkCount      int64
+	TotalClicks    int64
+	ClicksByDevice map[string]int64
+}
@@ -0,0 +1,10 @@
+package repository
+
+// CampaignClicks количество кликов по ссылке с одинаковыми UTM метками source/medium/campaign.
+// Пустая строка означает, что метка во входящем переходе не была задана.
+type CampaignClicks struct {
+	Source   string `gorm:"column:source" json:"source,omitempty"`
+	Medium   string `gorm:"column:medium" json:"medium,omitempty"`
+	Campaign string `gorm:"column:campaign" json:"campaign,omitempty"`
+	Clicks   int64  `gorm:"column:clicks" json:"clicks"`
+}
@@ -0,0 +1,279 @@
+package service
+
+import (
+	"GURLS-Backend/internal/config"
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/repository"
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// Коды отказа политики алиасов; возвращаются клиенту в поле code
+const (
+	AliasErrTooShort    = "alias_too_short"
+	AliasErrTooLong     = "alias_too_long"
+	AliasErrInvalidChar = "alias_invalid_characters"
+	AliasErrReserved    = "alias_reserved"
+	AliasErrBlocked     = "alias_blocked"
+	AliasErrProfane     = "alias_profane"
+)
+
+// defaultReservedAliases слова, которые не выдаются как алиасы помимо путей зарегистрированных маршрутов:
+// служебные страницы, которые могут появиться у сервиса, и подресурсы /api/links/
+var defaultReservedAliases = []string{
+	"about", "account", "admin", "app", "assets", "auth", "billing", "blog", "broken", "bulk", "dashboard",
+	"docs", "domains", "export", "favicon", "help", "home", "index", "links", "login", "logout",
+	"privacy", "profile", "register", "robots", "root", "settings", "signin", "signup", "static",
+	"stats", "status", "support", "swagger", "terms", "trash", "www",
+}
+
+// profanityExceptions безобидные слова, внутри которых встречаются нецензурные слова из списка
+// (therapeutic, peacock, debate). Нецензурное слово, целиком лежащее внутри такого слова алиаса,
+// не считается нарушением.
+var profanityExceptions = []string{
+	"cockatoo", "cockpit", "cockroach", "cocktail", "hancock", "hitchcock", "peacock", "shuttlecock", "woodcock",
+	"debate", "rebate",
+	"dickens",
+	"drape", "grape", "grapefruit", "parapet", "scrape", "skyscraper", "therapeutic", "trapeze",
+	"scunthorpe",
+	"swank",
+}
+
+// leetReplacer приводит цифры, которыми маскируют буквы, к буквам для поиска нецензурных слов
+var leetReplacer = strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t")
+
+// AliasPolicyError отказ политики алиасов с машиночитаемым кодом
+type AliasPolicyError struct {
+	Code    string
+	Message string
+}
+
+func (e *AliasPolicyError) Error() string {
+	return e.Message
+}
+
+// AliasPolicy единая политика допустимых алиасов: набор символов, длина, зарезервированные слова
+// (пути маршрутов сервиса и список по умолчанию), черный список администраторов и список нецензурных слов.
+// Зарезервированные слова, черный список и нецензурные слова сравниваются без учета регистра.
+type AliasPolicy struct {
+	storage   repository.Storage
+	minLength int
+	maxLength int
+	profanity []string
+	log       *zap.Logger
+
+	mu       sync.RWMutex
+	reserved map[string]bool
+}
+
+// NewAliasPolicy создает политику алиасов. Список нецензурных слов читается из файла по строке на слово;
+// отсутствующий файл не считается ошибкой - проверка на нецензурные слова тогда отключается.
+func NewAliasPolicy(storage repository.Storage, cfg *config.AliasPolicy, log *zap.Logger) (*AliasPolicy, error) {
+	maxLength := cfg.MaxLength
+	if maxLength <= 0 || maxLength > domain.MaxAliasLength {
+		maxLength = domain.MaxAliasLength
+	}
+	minLength := max(cfg.MinLength, 1)
+	if minLength > maxLength {
+		return nil, fmt.Errorf("alias_policy min_length must not exceed %d", maxLength)
+	}
+
+	policy := &AliasPolicy{
+		storage:   storage,
+		minLength: minLength,
+		maxLength: maxLength,
+		log:       log,
+		reserved:  make(map[string]bool),
+	}
+	policy.Reserve(defaultReservedAliases...)
+	policy.Reserve(cfg.Reserved...)
+
+	if cfg.ProfanityFile != "" {
+		profanity, err := loadWordList(cfg.ProfanityFile)
+		if errors.Is(err, os.ErrNotExist) {
+			log.Warn("profanity list not found, profane alias check disabled", zap.String("path", cfg.ProfanityFile))
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to load profanity list: %w", err)
+		}
+		policy.profanity = profanity
+	}
+
+	return policy, nil
+}
+
+// Reserve добавляет зарезервированные слова; сервер резервирует первые сегменты путей своих маршрутов
+func (p *AliasPolicy) Reserve(words ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, word := range words {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			p.reserved[word] = true
+		}
+	}
+}
+
+// IsReserved проверяет, что алиас совпадает с зарезервированным словом
+func (p *AliasPolicy) IsReserved(alias string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.reserved[strings.ToLower(alias)]
+}
+
+// Check проверяет кастомный алиас. Возвращает *AliasPolicyError при отказе
+// и обычную ошибку, если не удалось проверить черный список.
+func (p *AliasPolicy) Check(ctx context.Context, alias string) error {
+	if len(alias) < p.minLength {
+		return &AliasPolicyError{Code: AliasErrTooShort, Message: fmt.Sprintf("alias must be at least %d characters long", p.minLength)}
+	}
+	if len(alias) > p.maxLength {
+		return &AliasPolicyError{Code: AliasErrTooLong, Message: fmt.Sprintf("alias must be at most %d characters long", p.maxLength)}
+	}
+	if !isValidAliasCharset(alias) {
+		return &AliasPolicyError{
+			Code:    AliasErrInvalidChar,
+			Message: "alias may contain only latin letters, digits, '-' and '_' and must start and end with a letter or digit",
+		}
+	}
+	return p.checkWords(ctx, alias)
+}
+
+// Acceptable проверяет сгенерированный алиас: длина и символы определяются стратегией генерации,
+// поэтому проверяются только зарезервированные слова, черный список и нецензурные слова.
+// Неподходящий алиас считается коллизией, и генерация повторяется.
+func (p *AliasPolicy) Acceptable(ctx context.Context, alias string) (bool, error) {
+	err := p.checkWords(ctx, alias)
+	var policyErr *AliasPolicyError
+	if errors.As(err, &policyErr) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// checkWords проверяет алиас по зарезервированным словам, нецензурным словам и черному списку
+func (p *AliasPolicy) checkWords(ctx context.Context, alias string) error {
+	if p.IsReserved(alias) {
+		return &AliasPolicyError{Code: AliasErrReserved, Message: "alias is reserved by the service"}
+	}
+	if p.isProfane(alias) {
+		return &AliasPolicyError{Code: AliasErrProfane, Message: "alias contains inappropriate language"}
+	}
+
+	blocked, err := p.storage.IsAliasBlocked(ctx, alias)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return &AliasPolicyError{Code: AliasErrBlocked, Message: "alias is not allowed"}
+	}
+	return nil
+}
+
+// isProfane ищет нецензурные слова с учетом замены букв цифрами. Слова от 4 букв ищутся как подстрока
+// алиаса без разделителей, кроме вхождений внутри слов из profanityExceptions; более короткие - только
+// как отдельное слово алиаса, чтобы не отклонять безобидные алиасы, случайно содержащие короткое сочетание букв.
+func (p *AliasPolicy) isProfane(alias string) bool {
+	if len(p.profanity) == 0 {
+		return false
+	}
+
+	normalized := leetReplacer.Replace(strings.ToLower(alias))
+	parts := strings.FieldsFunc(normalized, func(r rune) bool { return r == '-' || r == '_' })
+	compact := strings.Join(parts, "")
+
+	for _, word := range p.profanity {
+		if len(word) >= 4 {
+			if containsProfaneWord(compact, word) {
+				return true
+			}
+			continue
+		}
+		if compact == word {
+			return true
+		}
+		for _, part := range parts {
+			if part == word {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// containsProfaneWord проверяет, что слово встречается в алиасе вне слов-исключений
+func containsProfaneWord(alias, word string) bool {
+	for start := 0; ; {
+		i := strings.Index(alias[start:], word)
+		if i < 0 {
+			return false
+		}
+		i += start
+		if !isInsideException(alias, i, i+len(word)) {
+			return true
+		}
+		start = i + 1
+	}
+}
+
+// isInsideException проверяет, что фрагмент alias[from:to] целиком лежит внутри слова из profanityExceptions
+func isInsideException(alias string, from, to int) bool {
+	for _, exception := range profanityExceptions {
+		for start := 0; start <= from; {
+			i := strings.Index(alias[start:], exception)
+			if i < 0 {
+				break
+			}
+			i += start
+			if i <= from && to <= i+len(exception) {
+				return true
+			}
+			start = i + 1
+		}
+	}
+	return false
+}
+
+// isValidAliasCharset проверяет, что алиас состоит из латинских букв, цифр, '-' и '_'
+// и начинается и заканчивается буквой или цифрой. '+' зарезервирован для предпросмотра /{alias}+.
+func isValidAliasCharset(alias string) bool {
+	for i := 0; i < len(alias); i++ {
+		c := alias[i]
+		isAlnum := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+		if isAlnum {
+			continue
+		}
+		if (c != '-' && c != '_') || i == 0 || i == len(alias)-1 {
+			return false
+		}
+	}
+	return alias != ""
+}
+
+// loadWordList читает список слов: по слову на строку, пустые строки и строки с # пропускаются
+func loadWordList(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		word := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		words = append(words, word)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return words, nil
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"GURLS-Backend/internal/config"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// blocklistStorage черный список алиасов в памяти; остальные методы Storage не используются
+type blocklistStorage struct {
+	aliasStorage
+	exact    []string
+	contains []string
+}
+
+func (s *blocklistStorage) IsAliasBlocked(ctx context.Context, alias string) (bool, error) {
+	alias = strings.ToLower(alias)
+	for _, pattern := range s.exact {
+		if alias == pattern {
+			return true, nil
+		}
+	}
+	for _, pattern := range s.contains {
+		if strings.Contains(alias, pattern) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestAliasPolicy_Check(t *testing.T) {
+	profanityFile := filepath.Join(t.TempDir(), "profanity.txt")
+	require.NoError(t, os.WriteFile(profanityFile, []byte("# test list\nbadword\n\nass\ncock\nrape\nebat\n"), 0o644))
+
+	storage := &blocklistStorage{exact: []string{"promo"}, contains: []string{"acmebank"}}
+	policy, err := NewAliasPolicy(storage, &config.AliasPolicy{
+		MinLength:     3,
+		MaxLength:     20,
+		ProfanityFile: profanityFile,
+		Reserved:      []string{"Pricing"},
+	}, zap.NewNop())
+	require.NoError(t, err)
+	policy.Reserve("api", "health")
+
+	tests := []struct {
+		alias string
+		code  string
+	}{
+		{alias: "my-link_2024", code: ""},
+		{alias: "healthy", code: ""},
+		{alias: "class-notes", code: ""},
+		{alias: "ab", code: AliasErrTooShort},
+		{alias: "abcdefghijklmnopqrstu", code: AliasErrTooLong},
+		{alias: "link+", code: AliasErrInvalidChar},
+		{alias: "-link", code: AliasErrInvalidChar},
+		{alias: "ссылка", code: AliasErrInvalidChar},
+		{alias: "a.b.c", code: AliasErrInvalidChar},
+		{alias: "API", code: AliasErrReserved},
+		{alias: "health", code: AliasErrReserved},
+		{alias: "pricing", code: AliasErrReserved},
+		{alias: "trash", code: AliasErrReserved},
+		{alias: "b4dw0rd", code: AliasErrProfane},
+		{alias: "my-BAD-word", code: AliasErrProfane},
+		{alias: "kick-ass", code: AliasErrProfane},
+		{alias: "debate-club", code: ""},
+		{alias: "grapefruit", code: ""},
+		{alias: "scrape", code: ""},
+		{alias: "therapeutic", code: ""},
+		{alias: "peacock", code: ""},
+		{alias: "cockpit-view", code: ""},
+		{alias: "d3bate", code: ""},
+		{alias: "peacock-cock", code: AliasErrProfane},
+		{alias: "cockpitcock", code: AliasErrProfane},
+		{alias: "grape-rape", code: AliasErrProfane},
+		{alias: "Promo", code: AliasErrBlocked},
+		{alias: "acmebank-login", code: AliasErrBlocked},
+	}
+	for _, tt := range tests {
+		err := policy.Check(context.Background(), tt.alias)
+		if tt.code == "" {
+			assert.NoError(t, err, tt.alias)
+			continue
+		}
+		var policyErr *AliasPolicyError
+		if assert.True(t, errors.As(err, &policyErr), tt.alias) {
+			assert.Equal(t, tt.code, policyErr.Code, tt.alias)
+		}
+	}
+}
+
+func TestAliasPolicy_MissingProfanityFile(t *testing.T) {
+	policy, err := NewAliasPolicy(&blocklistStorage{}, &config.AliasPolicy{
+		MinLength:     3,
+		ProfanityFile: filepath.Join(t.TempDir(), "missing.txt"),
+	}, zap.NewNop())
+	require.NoError(t, err)
+	assert.NoError(t, policy.Check(context.Background(), "badword"))
+
+	acceptable, err := policy.Acceptable(context.Background(), "docs")
+	require.NoError(t, err)
+	assert.False(t, acceptable, "generated aliases must not take reserved words")
+}
+
+func TestAliasPolicy_BundledProfanityListFalsePositives(t *testing.T) {
+	policy, err := NewAliasPolicy(&blocklistStorage{}, &config.AliasPolicy{
+		MinLength:     3,
+		ProfanityFile: filepath.Join("..", "..", "assets", "profanity.txt"),
+	}, zap.NewNop())
+	require.NoError(t, err)
+	require.NotEmpty(t, policy.profanity)
+
+	for _, alias := range []string{"debate", "grapefruit", "scrape", "therapeutic", "peacock", "cockpit", "cocktail-bar", "rebate-2024", "skyscraper"} {
+		assert.NoError(t, policy.Check(context.Background(), alias), alias)
+	}
+	for _, alias := range []string{"fuck", "sh1t-happens", "cock", "rapist-rape"} {
+		assert.Error(t, policy.Check(context.Background(), alias), alias)
+	}
+}
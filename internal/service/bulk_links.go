@@ -0,0 +1,390 @@
+package service
+
+import (
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/repository"
+	"GURLS-Backend/pkg/random"
+	"GURLS-Backend/pkg/urlpolicy"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// MaxBulkLinks максимальное количество строк в одном пакете
+	MaxBulkLinks = 10000
+	// MaxSyncBulkLinks пакеты большего размера всегда обрабатываются асинхронно
+	MaxSyncBulkLinks = 500
+
+	bulkJobIDLength  = 16
+	bulkJobRetention = 24 * time.Hour
+	bulkJobTimeout   = 30 * time.Minute
+)
+
+var (
+	ErrBulkEmpty                 = errors.New("no links to create")
+	ErrBulkTooLarge              = fmt.Errorf("too many links in one batch (max %d)", MaxBulkLinks)
+	ErrBulkQuotaExceeded         = errors.New("batch exceeds monthly link limit")
+	ErrBulkCustomAliasNotAllowed = errors.New("custom aliases are not available in the current plan")
+)
+
+// BulkLinkInput одна строка пакетного создания ссылок
+type BulkLinkInput struct {
+	Row         int    `json:"-"`
+	OriginalURL string `json:"url"`
+	Title       string `json:"title,omitempty"`
+	CustomAlias string `json:"custom_alias,omitempty"`
+	ExpiresAt   string `json:"expires_at,omitempty"`
+}
+
+// BulkLinkResult результат обработки одной строки пакета
+type BulkLinkResult struct {
+	Row      int    `json:"row"`
+	URL      string `json:"url"`
+	Alias    string `json:"alias,omitempty"`
+	ShortURL string `json:"short_url,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Code     string `json:"code,omitempty"` // код отказа политики алиасов
+}
+
+// BulkJobStatus состояние асинхронного задания
+type BulkJobStatus string
+
+const (
+	BulkJobPending   BulkJobStatus = "pending"
+	BulkJobRunning   BulkJobStatus = "running"
+	BulkJobCompleted BulkJobStatus = "completed"
+	BulkJobFailed    BulkJobStatus = "failed"
+)
+
+// BulkJob асинхронное задание пакетного создания ссылок
+type BulkJob struct {
+	ID         string           `json:"id"`
+	UserID     int64            `json:"-"`
+	Status     BulkJobStatus    `json:"status"`
+	Total      int              `json:"total"`
+	Processed  int              `json:"processed"`
+	Succeeded  int              `json:"succeeded"`
+	Failed     int              `json:"failed"`
+	Error      string           `json:"error,omitempty"`
+	Results    []BulkLinkResult `json:"results,omitempty"`
+	CreatedAt  time.Time        `json:"created_at"`
+	FinishedAt *time.Time       `json:"finished_at,omitempty"`
+}
+
+// BulkLinkService создает ссылки пакетами с проверкой тарифа на весь пакет сразу
+type BulkLinkService struct {
+	storage    repository.Storage
+	shortener  *URLShortenerService
+	urlPolicy  *urlpolicy.Policy
+	reputation *ReputationService
+	metadata   *MetadataService
+	log        *zap.Logger
+
+	mu   sync.RWMutex
+	jobs map[string]*BulkJob
+}
+
+// NewBulkLinkService создает новый сервис пакетного создания ссылок
+func NewBulkLinkService(storage repository.Storage, shortener *URLShortenerService, urlPolicy *urlpolicy.Policy, reputation *ReputationService, metadata *MetadataService, log *zap.Logger) *BulkLinkService {
+	return &BulkLinkService{
+		storage:    storage,
+		shortener:  shortener,
+		urlPolicy:  urlPolicy,
+		reputation: reputation,
+		metadata:   metadata,
+		log:        log,
+		jobs:       make(map[string]*BulkJob),
+	}
+}
+
+// CheckEntitlements проверяет, что пакет целиком укладывается в тариф пользователя:
+// в месячный лимит ссылок и в доступность кастомных алиасов
+func (s *BulkLinkService) CheckEntitlements(ctx context.Context, userID int64, inputs []BulkLinkInput) error {
+	if len(inputs) == 0 {
+		return ErrBulkEmpty
+	}
+	if len(inputs) > MaxBulkLinks {
+		return ErrBulkTooLarge
+	}
+
+	user, err := s.storage.GetUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	subscription, err := s.storage.GetSubscriptionType(ctx, user.SubscriptionTypeID)
+	if err != nil {
+		return fmt.Errorf("failed to get subscription: %w", err)
+	}
+
+	if !subscription.CustomAliases {
+		for _, input := range inputs {
+			if input.CustomAlias != "" {
+				return ErrBulkCustomAliasNotAllowed
+			}
+		}
+	}
+
+	if subscription.MaxLinksPerMonth == nil {
+		return nil
+	}
+
+	now := time.Now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	created, err := s.storage.CountLinksCreatedSince(ctx, userID, monthStart)
+	if err != nil {
+		return fmt.Errorf("failed to count user links: %w", err)
+	}
+
+	if created+int64(len(inputs)) > int64(*subscription.MaxLinksPerMonth) {
+		return ErrBulkQuotaExceeded
+	}
+	return nil
+}
+
+// CreateLinks создает ссылки по строкам пакета и возвращает результат для каждой строки.
+// Ошибка в одной строке не прерывает обработку остальных.
+func (s *BulkLinkService) CreateLinks(ctx context.Context, userID int64, inputs []BulkLinkInput) []BulkLinkResult {
+	results := make([]BulkLinkResult, 0, len(inputs))
+	seenAliases := make(map[string]int, len(inputs))
+	utmDefaults := s.utmDefaults(ctx, userID)
+
+	for _, input := range inputs {
+		result := s.createLink(ctx, userID, input, utmDefaults, seenAliases)
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// StartJob запускает асинхронную обработку пакета и возвращает задание для отслеживания статуса
+func (s *BulkLinkService) StartJob(userID int64, inputs []BulkLinkInput) (*BulkJob, error) {
+	id, err := random.NewRandomString(bulkJobIDLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate job id: %w", err)
+	}
+
+	job := &BulkJob{
+		ID:        id,
+		UserID:    userID,
+		Status:    BulkJobPending,
+		Total:     len(inputs),
+		CreatedAt: time.Now(),
+	}
+
+	s.mu.Lock()
+	s.pruneJobsLocked()
+	s.jobs[id] = job
+	s.mu.Unlock()
+
+	go s.runJob(job, inputs)
+
+	s.log.Info("started bulk link job", zap.String("job_id", id), zap.Int64("user_id", userID), zap.Int("total", len(inputs)))
+	return s.snapshot(job, false), nil
+}
+
+// GetJob возвращает снимок состояния задания пользователя
+func (s *BulkLinkService) GetJob(userID int64, id string) (*BulkJob, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	job, ok := s.jobs[id]
+	if !ok || job.UserID != userID {
+		return nil, false
+	}
+	return s.snapshot(job, true), true
+}
+
+// runJob обрабатывает строки пакета в фоне, обновляя прогресс задания
+func (s *BulkLinkService) runJob(job *BulkJob, inputs []BulkLinkInput) {
+	ctx, cancel := context.WithTimeout(context.Background(), bulkJobTimeout)
+	defer cancel()
+
+	s.mu.Lock()
+	job.Status = BulkJobRunning
+	job.Results = make([]BulkLinkResult, 0, len(inputs))
+	s.mu.Unlock()
+
+	seenAliases := make(map[string]int, len(inputs))
+	utmDefaults := s.utmDefaults(ctx, job.UserID)
+	for _, input := range inputs {
+		if ctx.Err() != nil {
+			s.finishJob(job, BulkJobFailed, "job timed out")
+			return
+		}
+
+		result := s.createLink(ctx, job.UserID, input, utmDefaults, seenAliases)
+
+		s.mu.Lock()
+		job.Results = append(job.Results, result)
+		job.Processed++
+		if result.Error == "" {
+			job.Succeeded++
+		} else {
+			job.Failed++
+		}
+		s.mu.Unlock()
+	}
+
+	s.finishJob(job, BulkJobCompleted, "")
+}
+
+// finishJob фиксирует завершение задания
+func (s *BulkLinkService) finishJob(job *BulkJob, status BulkJobStatus, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	job.Status = status
+	job.Error = message
+	job.FinishedAt = &now
+
+	s.log.Info("finished bulk link job",
+		zap.String("job_id", job.ID),
+		zap.String("status", string(status)),
+		zap.Int("succeeded", job.Succeeded),
+		zap.Int("failed", job.Failed))
+}
+
+// utmDefaults возвращает UTM метки пользователя по умолчанию для ссылок пакета.
+// Ошибка чтения пользователя не прерывает пакет: ссылки создаются без меток.
+func (s *BulkLinkService) utmDefaults(ctx context.Context, userID int64) domain.UTMParams {
+	user, err := s.storage.GetUserByID(ctx, userID)
+	if err != nil {
+		s.log.Warn("failed to get user UTM defaults", zap.Int64("user_id", userID), zap.Error(err))
+		return domain.UTMParams{}
+	}
+	return user.UTMDefaults
+}
+
+// createLink проверяет и создает одну ссылку пакета
+func (s *BulkLinkService) createLink(ctx context.Context, userID int64, input BulkLinkInput, utmDefaults domain.UTMParams, seenAliases map[string]int) BulkLinkResult {
+	result := BulkLinkResult{Row: input.Row, URL: input.OriginalURL}
+
+	link, err := validateBulkInput(userID, input)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	link.UTM = utmDefaults
+
+	link.OriginalURL, err = s.urlPolicy.Check(ctx, link.OriginalURL)
+	if err != nil {
+		var urlErr *urlpolicy.Error
+		if errors.As(err, &urlErr) {
+			result.Error = urlErr.Message
+			result.Code = urlErr.Code
+			return result
+		}
+		s.log.Error("failed to check bulk link url", zap.Int64("user_id", userID), zap.Int("row", input.Row), zap.Error(err))
+		result.Error = "failed to create link"
+		return result
+	}
+	if verdict := s.reputation.CheckURL(ctx, link.OriginalURL); verdict != nil {
+		result.Error = fmt.Sprintf("the destination is listed as unsafe (%s)", verdict.Threat)
+		result.Code = CodeMaliciousURL
+		return result
+	}
+
+	var customAlias *string
+	if input.CustomAlias != "" {
+		if row, ok := seenAliases[input.CustomAlias]; ok {
+			result.Error = fmt.Sprintf("custom_alias duplicates row %d", row)
+			return result
+		}
+		seenAliases[input.CustomAlias] = input.Row
+		customAlias = &input.CustomAlias
+	}
+
+	alias, err := s.shortener.Shorten(ctx, link, customAlias)
+	if err != nil {
+		if errors.Is(err, repository.ErrAliasExists) {
+			result.Error = "alias already exists"
+			return result
+		}
+		if errors.Is(err, repository.ErrLinkLimitReached) {
+			result.Error = "monthly link limit reached"
+			return result
+		}
+		var policyErr *AliasPolicyError
+		if errors.As(err, &policyErr) {
+			result.Error = policyErr.Message
+			result.Code = policyErr.Code
+			return result
+		}
+		if errors.Is(err, ErrAliasGenerationFailed) {
+			result.Error = "failed to generate a unique alias"
+			return result
+		}
+		s.log.Error("failed to create bulk link", zap.Int64("user_id", userID), zap.Int("row", input.Row), zap.Error(err))
+		result.Error = "failed to create link"
+		return result
+	}
+
+	// в пакете заголовок задается редко; если очередь заполнится, остальные ссылки подберет обход
+	if link.NeedsMetadata() {
+		s.metadata.Enqueue(link)
+	}
+
+	result.Alias = alias
+	result.ShortURL = s.shortener.ShortURL(alias)
+	return result
+}
+
+// validateBulkInput проверяет строку пакета и строит из нее ссылку
+func validateBulkInput(userID int64, input BulkLinkInput) (*domain.Link, error) {
+	originalURL := strings.TrimSpace(input.OriginalURL)
+	if originalURL == "" {
+		return nil, errors.New("url is required")
+	}
+	if parsed, err := url.Parse(originalURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, errors.New("invalid url, use an absolute http(s) URL")
+	}
+
+	link := &domain.Link{
+		UserID:      userID,
+		OriginalURL: originalURL,
+		IsActive:    true,
+	}
+
+	if title := strings.TrimSpace(input.Title); title != "" {
+		link.Title = &title
+	}
+
+	if input.ExpiresAt != "" {
+		expiresAt, err := time.Parse(time.RFC3339, strings.TrimSpace(input.ExpiresAt))
+		if err != nil {
+			return nil, errors.New("invalid expires_at format, use RFC3339")
+		}
+		link.ExpiresAt = &expiresAt
+	}
+
+	return link, nil
+}
+
+// snapshot возвращает копию задания, безопасную для сериализации вне блокировки
+func (s *BulkLinkService) snapshot(job *BulkJob, withResults bool) *BulkJob {
+	copied := *job
+	copied.Results = nil
+	if withResults && job.Results != nil {
+		copied.Results = make([]BulkLinkResult, len(job.Results))
+		copy(copied.Results, job.Results)
+	}
+	return &copied
+}
+
+// pruneJobsLocked удаляет давно завершенные задания; вызывается под s.mu
+func (s *BulkLinkService) pruneJobsLocked() {
+	cutoff := time.Now().Add(-bulkJobRetention)
+	for id, job := range s.jobs {
+		if job.FinishedAt != nil && job.FinishedAt.Before(cutoff) {
+			delete(s.jobs, id)
+		}
+	}
+}
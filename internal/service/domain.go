@@ -0,0 +1,110 @@
+package service
+
+import (
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/repository"
+	"GURLS-Backend/pkg/random"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const domainVerificationTokenLength = 32
+
+var (
+	ErrDomainLimitReached = fmt.Errorf("too many custom domains (max %d)", domain.MaxCustomDomainsPerUser)
+	ErrDomainNotVerified  = errors.New("verification TXT record not found")
+	ErrDomainDNSLookup    = errors.New("DNS lookup failed")
+)
+
+// TXTResolver получает TXT записи домена; *net.Resolver удовлетворяет интерфейсу,
+// в тестах подставляется заглушка
+type TXTResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// DomainService управляет собственными доменами пользователей и подтверждением владения ими
+type DomainService struct {
+	storage  repository.Storage
+	resolver TXTResolver
+	log      *zap.Logger
+	now      func() time.Time
+}
+
+// NewDomainService создает новый сервис собственных доменов
+func NewDomainService(storage repository.Storage, resolver TXTResolver, log *zap.Logger) *DomainService {
+	return &DomainService{
+		storage:  storage,
+		resolver: resolver,
+		log:      log,
+		now:      time.Now,
+	}
+}
+
+// AddDomain добавляет домен пользователю и выдает токен для TXT записи.
+// Имя домена должно быть уже нормализовано (domain.NormalizeHostname).
+func (s *DomainService) AddDomain(ctx context.Context, userID int64, hostname string) (*domain.CustomDomain, error) {
+	domains, err := s.storage.ListCustomDomains(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list custom domains: %w", err)
+	}
+	if len(domains) >= domain.MaxCustomDomainsPerUser {
+		return nil, ErrDomainLimitReached
+	}
+
+	token, err := random.NewRandomString(domainVerificationTokenLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate verification token: %w", err)
+	}
+
+	customDomain := &domain.CustomDomain{
+		UserID:            userID,
+		Hostname:          hostname,
+		VerificationToken: token,
+	}
+	if err := s.storage.CreateCustomDomain(ctx, customDomain); err != nil {
+		return nil, err
+	}
+	return customDomain, nil
+}
+
+// VerifyDomain проверяет TXT запись домена и при совпадении токена отмечает домен подтвержденным.
+// Возвращает ErrDomainNotVerified, если запись не найдена, и ErrDomainDNSLookup при сбое DNS.
+func (s *DomainService) VerifyDomain(ctx context.Context, customDomain *domain.CustomDomain) error {
+	if customDomain.IsVerified() {
+		return nil
+	}
+
+	records, err := s.resolver.LookupTXT(ctx, customDomain.VerificationRecordName())
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return ErrDomainNotVerified
+		}
+		s.log.Warn("failed to look up domain verification record",
+			zap.String("hostname", customDomain.Hostname), zap.Error(err))
+		return fmt.Errorf("%w: %v", ErrDomainDNSLookup, err)
+	}
+
+	expected := customDomain.VerificationRecordValue()
+	for _, record := range records {
+		if strings.TrimSpace(record) != expected {
+			continue
+		}
+
+		verifiedAt := s.now()
+		if err := s.storage.VerifyCustomDomain(ctx, customDomain.ID, verifiedAt); err != nil {
+			return err
+		}
+		customDomain.VerifiedAt = &verifiedAt
+		s.log.Info("custom domain verified", zap.Int64("domain_id", customDomain.ID), zap.String("hostname", customDomain.Hostname))
+		return nil
+	}
+
+	return ErrDomainNotVerified
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/repository"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// stubResolver возвращает заранее заданные TXT записи
+type stubResolver struct {
+	records map[string][]string
+	err     error
+}
+
+func (r *stubResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	records, ok := r.records[name]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return records, nil
+}
+
+// domainStorage хранилище доменов в памяти; остальные методы Storage не используются
+type domainStorage struct {
+	repository.Storage
+	domains  []*domain.CustomDomain
+	verified map[int64]time.Time
+}
+
+func (s *domainStorage) ListCustomDomains(ctx context.Context, userID int64) ([]*domain.CustomDomain, error) {
+	return s.domains, nil
+}
+
+func (s *domainStorage) CreateCustomDomain(ctx context.Context, customDomain *domain.CustomDomain) error {
+	customDomain.ID = int64(len(s.domains) + 1)
+	s.domains = append(s.domains, customDomain)
+	return nil
+}
+
+func (s *domainStorage) VerifyCustomDomain(ctx context.Context, domainID int64, verifiedAt time.Time) error {
+	s.verified[domainID] = verifiedAt
+	return nil
+}
+
+func TestDomainService_VerifyDomain(t *testing.T) {
+	ctx := context.Background()
+	storage := &domainStorage{verified: map[int64]time.Time{}}
+	resolver := &stubResolver{records: map[string][]string{}}
+	service := NewDomainService(storage, resolver, zap.NewNop())
+
+	customDomain, err := service.AddDomain(ctx, 1, "go.example.com")
+	require.NoError(t, err)
+	assert.Len(t, customDomain.VerificationToken, domainVerificationTokenLength)
+	assert.Equal(t, "_gurls-verification.go.example.com", customDomain.VerificationRecordName())
+
+	// записи еще нет
+	assert.ErrorIs(t, service.VerifyDomain(ctx, customDomain), ErrDomainNotVerified)
+
+	// запись с чужим токеном
+	resolver.records[customDomain.VerificationRecordName()] = []string{"v=spf1 -all", "gurls-verification=other"}
+	assert.ErrorIs(t, service.VerifyDomain(ctx, customDomain), ErrDomainNotVerified)
+	assert.Empty(t, storage.verified)
+
+	// сбой DNS не считается отсутствием записи
+	resolver.err = &net.DNSError{Err: "server misbehaving", Name: customDomain.VerificationRecordName(), IsTemporary: true}
+	assert.ErrorIs(t, service.VerifyDomain(ctx, customDomain), ErrDomainDNSLookup)
+	resolver.err = nil
+
+	resolver.records[customDomain.VerificationRecordName()] = append(
+		resolver.records[customDomain.VerificationRecordName()], customDomain.VerificationRecordValue())
+	require.NoError(t, service.VerifyDomain(ctx, customDomain))
+	assert.True(t, customDomain.IsVerified())
+	assert.Contains(t, storage.verified, customDomain.ID)
+}
+
+func TestDomainService_AddDomainLimit(t *testing.T) {
+	storage := &domainStorage{verified: map[int64]time.Time{}}
+	service := NewDomainService(storage, &stubResolver{}, zap.NewNop())
+
+	for i := 0; i < domain.MaxCustomDomainsPerUser; i++ {
+		_, err := service.AddDomain(context.Background(), 1, "example.com")
+		require.NoError(t, err)
+	}
+	_, err := service.AddDomain(context.Background(), 1, "example.com")
+	assert.ErrorIs(t, err, ErrDomainLimitReached)
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/repository"
+	"GURLS-Backend/pkg/linkcheck"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// LinkHealthConfig конфигурация фоновой проверки доступности адресов назначения
+type LinkHealthConfig struct {
+	Interval         time.Duration // как часто ссылки проверяются заново
+	BatchSize        int           // сколько ссылок читается из БД за один запрос
+	Workers          int           // сколько ссылок проверяется одновременно
+	FailureThreshold int           // после скольких неудачных проверок подряд ссылка считается нерабочей
+}
+
+// LinkHealthService периодически проверяет, что адреса назначения ссылок отвечают, сохраняет код ответа,
+// задержку и адрес после редиректов. Ссылка, не отвечающая FailureThreshold проверок подряд, отмечается
+// нерабочей, о смене состояния сообщается через LinkHealthNotifier. Проверяется основной адрес назначения.
+type LinkHealthService struct {
+	storage  repository.Storage
+	checker  linkcheck.Checker
+	notifier LinkHealthNotifier
+	config   LinkHealthConfig
+	log      *zap.Logger
+
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	done    chan struct{}
+	started bool
+}
+
+// NewLinkHealthService создает новый сервис проверки доступности ссылок
+func NewLinkHealthService(storage repository.Storage, checker linkcheck.Checker, notifier LinkHealthNotifier, config LinkHealthConfig, log *zap.Logger) *LinkHealthService {
+	return &LinkHealthService{
+		storage:  storage,
+		checker:  checker,
+		notifier: notifier,
+		config:   config,
+		log:      log,
+	}
+}
+
+// Start запускает периодическую проверку ссылок
+func (s *LinkHealthService) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return fmt.Errorf("link health checker already started")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	s.started = true
+
+	s.log.Info("starting link health checker",
+		zap.Duration("interval", s.config.Interval),
+		zap.Int("workers", s.config.Workers),
+		zap.Int("failure_threshold", s.config.FailureThreshold))
+
+	go s.run(ctx)
+	return nil
+}
+
+// Stop останавливает периодическую проверку и ждет завершения текущего прохода
+func (s *LinkHealthService) Stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.started {
+		return fmt.Errorf("link health checker not started")
+	}
+
+	s.cancel()
+	<-s.done
+	s.started = false
+
+	s.log.Info("link health checker stopped")
+	return nil
+}
+
+// CheckOnce проверяет все активные ссылки. Возвращает количество ссылок, ставших нерабочими за этот проход.
+func (s *LinkHealthService) CheckOnce(ctx context.Context) (int, error) {
+	broken := 0
+	var afterID int64
+	for {
+		links, err := s.storage.ListLinksForHealthCheck(ctx, afterID, s.config.BatchSize)
+		if err != nil {
+			return broken, err
+		}
+		if len(links) == 0 {
+			return broken, nil
+		}
+
+		linkIDs := make([]int64, len(links))
+		for i, link := range links {
+			linkIDs[i] = link.ID
+		}
+		previous, err := s.storage.ListLinkHealth(ctx, linkIDs)
+		if err != nil {
+			return broken, err
+		}
+
+		broken += s.checkBatch(ctx, links, previous)
+		if ctx.Err() != nil {
+			return broken, ctx.Err()
+		}
+		if len(links) < s.config.BatchSize {
+			return broken, nil
+		}
+		afterID = links[len(links)-1].ID
+	}
+}
+
+// checkBatch проверяет страницу ссылок в Workers потоков
+func (s *LinkHealthService) checkBatch(ctx context.Context, links []*domain.Link, previous map[int64]*domain.LinkHealth) int {
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		broken int
+	)
+
+	queue := make(chan *domain.Link)
+	for i := 0; i < max(s.config.Workers, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for link := range queue {
+				if s.checkLink(ctx, link, previous[link.ID]) {
+					mu.Lock()
+					broken++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for _, link := range links {
+		select {
+		case queue <- link:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	return broken
+}
+
+// checkLink проверяет одну ссылку и сохраняет результат. Возвращает true, если ссылка только что стала нерабочей.
+func (s *LinkHealthService) checkLink(ctx context.Context, link *domain.Link, previous *domain.LinkHealth) bool {
+	result := s.checker.Check(ctx, link.OriginalURL)
+	if errors.Is(result.Err, linkcheck.ErrHostBackoff) || ctx.Err() != nil {
+		// хост попросил подождать или ответил 5xx на другой адрес: о ссылке это ничего не говорит,
+		// она будет проверена в следующий проход. Недоступный хост (linkcheck.ErrHostUnreachable)
+		// считается неудачной проверкой каждой его ссылки.
+		return false
+	}
+
+	health := &domain.LinkHealth{
+		LinkID:     link.ID,
+		StatusCode: result.StatusCode,
+		LatencyMs:  result.Latency.Milliseconds(),
+		FinalURL:   result.FinalURL,
+		CheckedAt:  time.Now(),
+	}
+	if result.Err != nil {
+		health.Error = truncate(result.Err.Error(), domain.MaxLinkHealthErrorLength)
+	}
+	if previous != nil {
+		health.ConsecutiveFailures = previous.ConsecutiveFailures
+		health.BrokenSince = previous.BrokenSince
+	}
+
+	becameBroken, recovered := false, false
+	if result.Broken() {
+		health.ConsecutiveFailures++
+		if health.BrokenSince == nil && health.ConsecutiveFailures >= s.config.FailureThreshold {
+			health.BrokenSince = &health.CheckedAt
+			becameBroken = true
+		}
+	} else {
+		recovered = health.BrokenSince != nil
+		health.ConsecutiveFailures = 0
+		health.BrokenSince = nil
+	}
+
+	if err := s.storage.SaveLinkHealth(ctx, health); err != nil {
+		s.log.Warn("failed to save link health", zap.String("alias", link.Alias), zap.Error(err))
+		return false
+	}
+
+	switch {
+	case becameBroken:
+		s.log.Info("link destination is broken",
+			zap.String("alias", link.Alias),
+			zap.Int("status_code", health.StatusCode),
+			zap.String("error", health.Error))
+		if err := s.notifier.LinkBroken(ctx, link, health); err != nil {
+			s.log.Warn("failed to notify about broken link", zap.String("alias", link.Alias), zap.Error(err))
+		}
+	case recovered:
+		s.log.Info("link destination recovered", zap.String("alias", link.Alias))
+		if err := s.notifier.LinkRecovered(ctx, link, health); err != nil {
+			s.log.Warn("failed to notify about recovered link", zap.String("alias", link.Alias), zap.Error(err))
+		}
+	}
+	return becameBroken
+}
+
+// run выполняет проверку сразу после старта и затем по таймеру
+func (s *LinkHealthService) run(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if broken, err := s.CheckOnce(ctx); err != nil && ctx.Err() == nil {
+			s.log.Error("failed to check links health", zap.Error(err))
+		} else if broken > 0 {
+			s.log.Info("link health check completed", zap.Int("broken", broken))
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// truncate обрезает строку до limit символов
+func truncate(value string, limit int) string {
+	runes := []rune(value)
+	if len(runes) <= limit {
+		return value
+	}
+	return string(runes[:limit])
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"GURLS-Backend/internal/domain"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// LinkHealthEventBroken событие webhook: ссылка стала нерабочей
+	LinkHealthEventBroken = "link.broken"
+	// LinkHealthEventRecovered событие webhook: адрес назначения снова отвечает
+	LinkHealthEventRecovered = "link.recovered"
+)
+
+// LinkHealthNotifier получает уведомления о смене состояния ссылки. Реализация может отправлять
+// письма владельцам, сообщения в мессенджеры и т.п.; ошибка уведомления только логируется.
+type LinkHealthNotifier interface {
+	LinkBroken(ctx context.Context, link *domain.Link, health *domain.LinkHealth) error
+	LinkRecovered(ctx context.Context, link *domain.Link, health *domain.LinkHealth) error
+}
+
+// LogHealthNotifier пишет уведомления в лог; используется, если webhook не настроен
+type LogHealthNotifier struct {
+	log *zap.Logger
+}
+
+// NewLogHealthNotifier создает уведомления через лог
+func NewLogHealthNotifier(log *zap.Logger) *LogHealthNotifier {
+	return &LogHealthNotifier{log: log}
+}
+
+// LinkBroken реализует LinkHealthNotifier
+func (n *LogHealthNotifier) LinkBroken(ctx context.Context, link *domain.Link, health *domain.LinkHealth) error {
+	n.log.Warn("broken link notification",
+		zap.String("alias", link.Alias),
+		zap.Int64("user_id", link.UserID),
+		zap.String("original_url", link.OriginalURL),
+		zap.Int("status_code", health.StatusCode),
+		zap.Int("consecutive_failures", health.ConsecutiveFailures))
+	return nil
+}
+
+// LinkRecovered реализует LinkHealthNotifier
+func (n *LogHealthNotifier) LinkRecovered(ctx context.Context, link *domain.Link, health *domain.LinkHealth) error {
+	n.log.Info("recovered link notification",
+		zap.String("alias", link.Alias),
+		zap.Int64("user_id", link.UserID),
+		zap.Int("status_code", health.StatusCode))
+	return nil
+}
+
+// linkHealthEvent тело запроса webhook
+type linkHealthEvent struct {
+	Event               string     `json:"event"`
+	UserID              int64      `json:"user_id"`
+	Alias               string     `json:"alias"`
+	DomainID            *int64     `json:"domain_id,omitempty"`
+	OriginalURL         string     `json:"original_url"`
+	StatusCode          int        `json:"status_code"`
+	FinalURL            string     `json:"final_url"`
+	Error               string     `json:"error,omitempty"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	CheckedAt           time.Time  `json:"checked_at"`
+	BrokenSince         *time.Time `json:"broken_since,omitempty"`
+}
+
+// WebhookHealthNotifier отправляет уведомления POST запросом с JSON телом на заданный адрес
+type WebhookHealthNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookHealthNotifier создает уведомления через webhook
+func NewWebhookHealthNotifier(url string, client *http.Client) *WebhookHealthNotifier {
+	return &WebhookHealthNotifier{url: url, client: client}
+}
+
+// LinkBroken реализует LinkHealthNotifier
+func (n *WebhookHealthNotifier) LinkBroken(ctx context.Context, link *domain.Link, health *domain.LinkHealth) error {
+	return n.send(ctx, LinkHealthEventBroken, link, health)
+}
+
+// LinkRecovered реализует LinkHealthNotifier
+func (n *WebhookHealthNotifier) LinkRecovered(ctx context.Context, link *domain.Link, health *domain.LinkHealth) error {
+	return n.send(ctx, LinkHealthEventRecovered, link, health)
+}
+
+// send отправляет событие; ответ со статусом не 2xx считается ошибкой
+func (n *WebhookHealthNotifier) send(ctx context.Context, event string, link *domain.Link, health *domain.LinkHealth) error {
+	body, err := json.Marshal(linkHealthEvent{
+		Event:               event,
+		UserID:              link.UserID,
+		Alias:               link.Alias,
+		DomainID:            link.DomainID,
+		OriginalURL:         link.OriginalURL,
+		StatusCode:          health.StatusCode,
+		FinalURL:            health.FinalURL,
+		Error:               health.Error,
+		ConsecutiveFailures: health.ConsecutiveFailures,
+		CheckedAt:           health.CheckedAt,
+		BrokenSince:         health.BrokenSince,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
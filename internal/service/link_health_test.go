@@ -0,0 +1,196 @@
+package service
+
+import (
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/repository"
+	"GURLS-Backend/pkg/linkcheck"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// stubProber отвечает заданным кодом для каждого адреса
+type stubProber struct {
+	mu       sync.Mutex
+	statuses map[string]int
+}
+
+func (p *stubProber) Check(ctx context.Context, rawURL string) linkcheck.Result {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	status, ok := p.statuses[rawURL]
+	if !ok {
+		return linkcheck.Result{Err: linkcheck.ErrHostBackoff}
+	}
+	return linkcheck.Result{StatusCode: status, FinalURL: rawURL}
+}
+
+// healthStorage ссылки и результаты проверок в памяти; остальные методы Storage не используются
+type healthStorage struct {
+	repository.Storage
+	mu     sync.Mutex
+	links  []*domain.Link
+	health map[int64]*domain.LinkHealth
+}
+
+func (s *healthStorage) ListLinksForHealthCheck(ctx context.Context, afterID int64, limit int) ([]*domain.Link, error) {
+	var page []*domain.Link
+	for _, link := range s.links {
+		if link.ID > afterID && len(page) < limit {
+			page = append(page, link)
+		}
+	}
+	return page, nil
+}
+
+func (s *healthStorage) ListLinkHealth(ctx context.Context, linkIDs []int64) (map[int64]*domain.LinkHealth, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make(map[int64]*domain.LinkHealth)
+	for _, id := range linkIDs {
+		if health, ok := s.health[id]; ok {
+			result[id] = health
+		}
+	}
+	return result, nil
+}
+
+func (s *healthStorage) SaveLinkHealth(ctx context.Context, health *domain.LinkHealth) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.health[health.LinkID] = health
+	return nil
+}
+
+// recordingNotifier запоминает алиасы из уведомлений
+type recordingNotifier struct {
+	mu        sync.Mutex
+	broken    []string
+	recovered []string
+}
+
+func (n *recordingNotifier) LinkBroken(ctx context.Context, link *domain.Link, health *domain.LinkHealth) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.broken = append(n.broken, link.Alias)
+	return nil
+}
+
+func (n *recordingNotifier) LinkRecovered(ctx context.Context, link *domain.Link, health *domain.LinkHealth) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.recovered = append(n.recovered, link.Alias)
+	return nil
+}
+
+func TestLinkHealthService_FlagsAfterConsecutiveFailures(t *testing.T) {
+	ctx := context.Background()
+	storage := &healthStorage{
+		links: []*domain.Link{
+			{ID: 1, Alias: "ok", OriginalURL: "https://example.com/ok"},
+			{ID: 2, Alias: "dead", OriginalURL: "https://example.com/dead"},
+			{ID: 3, Alias: "busy", OriginalURL: "https://busy.example/"},
+		},
+		health: map[int64]*domain.LinkHealth{},
+	}
+	prober := &stubProber{statuses: map[string]int{
+		"https://example.com/ok":   http.StatusOK,
+		"https://example.com/dead": http.StatusNotFound,
+	}}
+	notifier := &recordingNotifier{}
+	service := NewLinkHealthService(storage, prober, notifier, LinkHealthConfig{BatchSize: 2, Workers: 2, FailureThreshold: 2}, zap.NewNop())
+
+	broken, err := service.CheckOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, broken)
+	assert.Equal(t, 1, storage.health[2].ConsecutiveFailures)
+	assert.False(t, storage.health[2].IsBroken())
+	assert.NotContains(t, storage.health, int64(3), "hosts in backoff are not recorded")
+
+	broken, err = service.CheckOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, broken)
+	assert.True(t, storage.health[2].IsBroken())
+	assert.False(t, storage.health[1].IsBroken())
+	assert.Equal(t, []string{"dead"}, notifier.broken)
+
+	// повторная неудача не отправляет уведомление снова
+	_, err = service.CheckOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, storage.health[2].ConsecutiveFailures)
+	assert.Len(t, notifier.broken, 1)
+
+	prober.statuses["https://example.com/dead"] = http.StatusOK
+	_, err = service.CheckOnce(ctx)
+	require.NoError(t, err)
+	assert.False(t, storage.health[2].IsBroken())
+	assert.Equal(t, 0, storage.health[2].ConsecutiveFailures)
+	assert.Equal(t, []string{"dead"}, notifier.recovered)
+}
+
+func TestLinkHealthService_UnreachableHostFailsEveryLink(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	storage := &healthStorage{
+		links: []*domain.Link{
+			{ID: 1, Alias: "a", OriginalURL: server.URL + "/a"},
+			{ID: 2, Alias: "b", OriginalURL: server.URL + "/b"},
+			{ID: 3, Alias: "c", OriginalURL: server.URL + "/c"},
+		},
+		health: map[int64]*domain.LinkHealth{},
+	}
+	prober := linkcheck.New(server.Client(), linkcheck.Config{BackoffBase: time.Minute, BackoffMax: 30 * time.Minute})
+	notifier := &recordingNotifier{}
+	service := NewLinkHealthService(storage, prober, notifier, LinkHealthConfig{BatchSize: 10, Workers: 1, FailureThreshold: 2}, zap.NewNop())
+
+	_, err := service.CheckOnce(ctx)
+	require.NoError(t, err)
+	for _, link := range storage.links {
+		require.Contains(t, storage.health, link.ID, link.Alias)
+		assert.Equal(t, 1, storage.health[link.ID].ConsecutiveFailures, link.Alias)
+	}
+
+	// второй проход идет, пока хост на паузе: запросов нет, но каждая ссылка получает отказ
+	broken, err := service.CheckOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, broken)
+	for _, link := range storage.links {
+		assert.True(t, storage.health[link.ID].IsBroken(), link.Alias)
+	}
+	assert.ElementsMatch(t, []string{"a", "b", "c"}, notifier.broken)
+}
+
+func TestLinkHealthService_RateLimitedHostIsNotRecorded(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	storage := &healthStorage{
+		links: []*domain.Link{
+			{ID: 1, Alias: "a", OriginalURL: server.URL + "/a"},
+			{ID: 2, Alias: "b", OriginalURL: server.URL + "/b"},
+		},
+		health: map[int64]*domain.LinkHealth{},
+	}
+	prober := linkcheck.New(server.Client(), linkcheck.Config{BackoffBase: time.Minute, BackoffMax: 30 * time.Minute})
+	service := NewLinkHealthService(storage, prober, &recordingNotifier{}, LinkHealthConfig{BatchSize: 10, Workers: 1, FailureThreshold: 1}, zap.NewNop())
+
+	broken, err := service.CheckOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, broken)
+	require.Contains(t, storage.health, int64(1))
+	assert.Equal(t, 0, storage.health[1].ConsecutiveFailures, "429 means the site is up")
+	assert.NotContains(t, storage.health, int64(2), "links skipped during backoff are not recorded")
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/repository"
+	"GURLS-Backend/pkg/pagemeta"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// metadataRefreshCooldown как часто владелец может вручную обновлять метаданные одной ссылки
+const metadataRefreshCooldown = time.Minute
+
+var (
+	ErrMetadataRefreshTooSoon = errors.New("link metadata was refreshed recently, try again in a minute")
+	ErrMetadataFetchFailed    = errors.New("failed to fetch destination page")
+)
+
+// MetadataFetcher загружает страницу и разбирает ее метаданные
+type MetadataFetcher interface {
+	Fetch(ctx context.Context, rawURL string) (*pagemeta.Metadata, error)
+}
+
+// MetadataConfig конфигурация фоновой загрузки метаданных
+type MetadataConfig struct {
+	Workers        int           // сколько страниц загружается одновременно
+	QueueSize      int           // сколько ссылок может ждать загрузки
+	Timeout        time.Duration // на загрузку одной страницы
+	SweepInterval  time.Duration // как часто ищутся ссылки, пропущенные очередью
+	SweepBatchSize int           // сколько таких ссылок берется за раз
+}
+
+// metadataJob ссылка в очереди загрузки
+type metadataJob struct {
+	linkID int64
+	url    string
+}
+
+// MetadataService заполняет заголовок, описание, OpenGraph и иконку ссылок из страницы назначения.
+// Новые ссылки без заголовка или описания ставятся в очередь при создании; ссылки, не попавшие
+// в переполненную очередь или созданные до перезапуска, находит периодический обход.
+type MetadataService struct {
+	storage repository.Storage
+	fetcher MetadataFetcher
+	config  MetadataConfig
+	log     *zap.Logger
+
+	queue chan metadataJob
+
+	mu      sync.Mutex
+	pending map[int64]struct{} // ссылки в очереди или в работе, чтобы обход не добавлял их повторно
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	started bool
+}
+
+// NewMetadataService создает новый сервис загрузки метаданных
+func NewMetadataService(storage repository.Storage, fetcher MetadataFetcher, config MetadataConfig, log *zap.Logger) *MetadataService {
+	return &MetadataService{
+		storage: storage,
+		fetcher: fetcher,
+		config:  config,
+		log:     log,
+		queue:   make(chan metadataJob, max(config.QueueSize, 1)),
+		pending: make(map[int64]struct{}),
+	}
+}
+
+// Enqueue ставит ссылку в очередь загрузки, не блокируясь. Если сервис не запущен или очередь
+// заполнена, ссылка будет найдена периодическим обходом. Защищенные паролем ссылки не загружаются
+// в фоне: их страница назначения не должна попадать в заголовок и карточку ссылки.
+func (s *MetadataService) Enqueue(link *domain.Link) {
+	if link.PasswordHash != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.started {
+		return
+	}
+	if _, ok := s.pending[link.ID]; ok {
+		return
+	}
+	select {
+	case s.queue <- metadataJob{linkID: link.ID, url: link.OriginalURL}:
+		s.pending[link.ID] = struct{}{}
+	default:
+		s.log.Debug("metadata queue is full, link left for the sweep", zap.String("alias", link.Alias))
+	}
+}
+
+// Refresh сразу загружает метаданные ссылки по запросу владельца. Возвращает сохраненные метаданные;
+// если страницу загрузить не удалось, ошибка сохраняется и возвращается ErrMetadataFetchFailed.
+func (s *MetadataService) Refresh(ctx context.Context, link *domain.Link) (*domain.LinkMetadata, error) {
+	if fetchedAt := link.Metadata.FetchedAt; fetchedAt != nil && time.Since(*fetchedAt) < metadataRefreshCooldown {
+		return nil, ErrMetadataRefreshTooSoon
+	}
+
+	metadata, err := s.fetch(ctx, link.ID, link.OriginalURL)
+	if err != nil {
+		return nil, err
+	}
+	if metadata.Error != "" {
+		return metadata, fmt.Errorf("%w: %s", ErrMetadataFetchFailed, metadata.Error)
+	}
+	return metadata, nil
+}
+
+// Start запускает обработчики очереди и периодический обход
+func (s *MetadataService) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return fmt.Errorf("metadata fetcher already started")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.started = true
+
+	s.log.Info("starting metadata fetcher",
+		zap.Int("workers", s.config.Workers),
+		zap.Duration("sweep_interval", s.config.SweepInterval))
+
+	for i := 0; i < max(s.config.Workers, 1); i++ {
+		s.wg.Add(1)
+		go s.work(ctx)
+	}
+	s.wg.Add(1)
+	go s.sweep(ctx)
+	return nil
+}
+
+// Stop останавливает загрузку и ждет завершения начатых загрузок; ссылки из очереди подберет обход
+// после следующего запуска
+func (s *MetadataService) Stop() error {
+	s.mu.Lock()
+	if !s.started {
+		s.mu.Unlock()
+		return fmt.Errorf("metadata fetcher not started")
+	}
+	s.started = false
+	s.cancel()
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	s.log.Info("metadata fetcher stopped")
+	return nil
+}
+
+// SweepOnce ставит в очередь ссылки без метаданных. Возвращает количество добавленных ссылок.
+func (s *MetadataService) SweepOnce(ctx context.Context) (int, error) {
+	links, err := s.storage.ListLinksWithoutMetadata(ctx, s.config.SweepBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	queued := 0
+	for _, link := range links {
+		s.mu.Lock()
+		_, inQueue := s.pending[link.ID]
+		if !inQueue {
+			s.pending[link.ID] = struct{}{}
+		}
+		s.mu.Unlock()
+		if inQueue {
+			continue
+		}
+
+		select {
+		case s.queue <- metadataJob{linkID: link.ID, url: link.OriginalURL}:
+			queued++
+		case <-ctx.Done():
+			s.done(link.ID)
+			return queued, ctx.Err()
+		}
+	}
+	return queued, nil
+}
+
+// work обрабатывает очередь загрузки
+func (s *MetadataService) work(ctx context.Context) {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case job := <-s.queue:
+			if _, err := s.fetch(ctx, job.linkID, job.url); err != nil && ctx.Err() == nil {
+				s.log.Warn("failed to save link metadata", zap.Int64("link_id", job.linkID), zap.Error(err))
+			}
+			s.done(job.linkID)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sweep периодически ищет ссылки, пропущенные очередью
+func (s *MetadataService) sweep(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.config.SweepInterval)
+	defer ticker.Stop()
+
+	for {
+		if queued, err := s.SweepOnce(ctx); err != nil && ctx.Err() == nil {
+			s.log.Error("failed to sweep links without metadata", zap.Error(err))
+		} else if queued > 0 {
+			s.log.Debug("queued links without metadata", zap.Int("count", queued))
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// fetch загружает страницу и сохраняет результат. Ошибка загрузки сохраняется в метаданных,
+// возвращается только ошибка сохранения.
+func (s *MetadataService) fetch(ctx context.Context, linkID int64, rawURL string) (*domain.LinkMetadata, error) {
+	fetchCtx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	fetchedAt := time.Now()
+	metadata := &domain.LinkMetadata{FetchedAt: &fetchedAt}
+
+	page, err := s.fetcher.Fetch(fetchCtx, rawURL)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		metadata.Error = truncate(err.Error(), domain.MaxMetadataErrorLength)
+		s.log.Debug("failed to fetch link metadata", zap.Int64("link_id", linkID), zap.Error(err))
+	} else {
+		metadata.Title = truncate(page.BestTitle(), domain.MaxMetadataTitleLength)
+		metadata.Description = truncate(page.BestDescription(), domain.MaxMetadataDescriptionLength)
+		metadata.SiteName = truncate(page.OpenGraph.SiteName, domain.MaxMetadataSiteNameLength)
+		metadata.ImageURL = page.BestImage()
+		metadata.FaviconURL = page.FaviconURL
+		metadata.TwitterCard = truncate(page.Twitter.Card, domain.MaxMetadataTwitterCardLength)
+	}
+
+	if err := s.storage.SaveLinkMetadata(ctx, linkID, *metadata); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// done снимает отметку ссылки в очереди
+func (s *MetadataService) done(linkID int64) {
+	s.mu.Lock()
+	delete(s.pending, linkID)
+	s.mu.Unlock()
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/repository"
+	"GURLS-Backend/pkg/pagemeta"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// stubFetcher отдает заранее заданные метаданные; неизвестные адреса - ошибка
+type stubFetcher struct {
+	pages map[string]*pagemeta.Metadata
+}
+
+func (f *stubFetcher) Fetch(ctx context.Context, rawURL string) (*pagemeta.Metadata, error) {
+	if page, ok := f.pages[rawURL]; ok {
+		return page, nil
+	}
+	return nil, errors.New("unexpected status 404")
+}
+
+// metadataStorage сохраненные метаданные в памяти; остальные методы Storage не используются
+type metadataStorage struct {
+	repository.Storage
+	mu      sync.Mutex
+	pending []*domain.Link
+	saved   chan domain.LinkMetadata
+}
+
+func (s *metadataStorage) ListLinksWithoutMetadata(ctx context.Context, limit int) ([]*domain.Link, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	links := s.pending
+	s.pending = nil
+	return links, nil
+}
+
+func (s *metadataStorage) SaveLinkMetadata(ctx context.Context, linkID int64, metadata domain.LinkMetadata) error {
+	s.saved <- metadata
+	return nil
+}
+
+func TestMetadataService_QueueSweepAndRefresh(t *testing.T) {
+	fetcher := &stubFetcher{pages: map[string]*pagemeta.Metadata{
+		"https://example.com/new": {Title: "New page", OpenGraph: pagemeta.OpenGraph{Description: "About the new page", Image: "https://example.com/og.png"}},
+		"https://example.com/old": {Title: "Old page"},
+	}}
+	storage := &metadataStorage{
+		pending: []*domain.Link{{ID: 2, OriginalURL: "https://example.com/old"}},
+		saved:   make(chan domain.LinkMetadata, 4),
+	}
+	service := NewMetadataService(storage, fetcher, MetadataConfig{Workers: 1, QueueSize: 4, Timeout: time.Second, SweepInterval: time.Hour, SweepBatchSize: 10}, zap.NewNop())
+
+	require.NoError(t, service.Start())
+	defer service.Stop()
+
+	// ссылка, созданная до запуска, находится обходом
+	select {
+	case saved := <-storage.saved:
+		assert.Equal(t, "Old page", saved.Title)
+	case <-time.After(time.Second):
+		t.Fatal("sweep did not fetch pending link")
+	}
+
+	service.Enqueue(&domain.Link{ID: 1, OriginalURL: "https://example.com/new"})
+	select {
+	case saved := <-storage.saved:
+		assert.Equal(t, "New page", saved.Title)
+		assert.Equal(t, "About the new page", saved.Description)
+		assert.Equal(t, "https://example.com/og.png", saved.ImageURL)
+		assert.NotNil(t, saved.FetchedAt)
+	case <-time.After(time.Second):
+		t.Fatal("queued link was not fetched")
+	}
+
+	// ошибка загрузки сохраняется и возвращается владельцу
+	link := &domain.Link{ID: 3, OriginalURL: "https://example.com/missing"}
+	metadata, err := service.Refresh(context.Background(), link)
+	assert.ErrorIs(t, err, ErrMetadataFetchFailed)
+	assert.Contains(t, metadata.Error, "404")
+	assert.Contains(t, (<-storage.saved).Error, "404")
+
+	link.Metadata.FetchedAt = metadata.FetchedAt
+	_, err = service.Refresh(context.Background(), link)
+	assert.ErrorIs(t, err, ErrMetadataRefreshTooSoon)
+}
+
+func TestMetadataService_SkipsProtectedLinks(t *testing.T) {
+	fetcher := &stubFetcher{pages: map[string]*pagemeta.Metadata{
+		"https://example.com/secret": {Title: "Secret page"},
+		"https://example.com/open":   {Title: "Open page"},
+	}}
+	storage := &metadataStorage{saved: make(chan domain.LinkMetadata, 4)}
+	service := NewMetadataService(storage, fetcher, MetadataConfig{Workers: 1, QueueSize: 4, Timeout: time.Second, SweepInterval: time.Hour, SweepBatchSize: 10}, zap.NewNop())
+
+	require.NoError(t, service.Start())
+	defer service.Stop()
+
+	hash := "hash"
+	service.Enqueue(&domain.Link{ID: 1, OriginalURL: "https://example.com/secret", PasswordHash: &hash})
+	service.Enqueue(&domain.Link{ID: 2, OriginalURL: "https://example.com/open"})
+
+	select {
+	case saved := <-storage.saved:
+		assert.Equal(t, "Open page", saved.Title, "protected link must not be fetched")
+	case <-time.After(time.Second):
+		t.Fatal("queued link was not fetched")
+	}
+	select {
+	case saved := <-storage.saved:
+		t.Fatalf("unexpected fetch: %+v", saved)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/repository"
+	"GURLS-Backend/pkg/reputation"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// CodeMaliciousURL код отказа для адреса назначения из списков угроз
+	CodeMaliciousURL = "url_malicious"
+	// CodeLinkFlagged код отказа при попытке включить ссылку, отключенную проверкой репутации
+	CodeLinkFlagged = "link_flagged"
+)
+
+var (
+	ErrLinkNotFlagged    = errors.New("link is not flagged")
+	ErrLinkAppealPending = errors.New("link already has a pending appeal")
+	ErrLinkAppealTooLong = fmt.Errorf("appeal message is too long (max %d characters)", domain.MaxLinkAppealMessageLength)
+)
+
+// ReputationConfig конфигурация периодической проверки существующих ссылок
+type ReputationConfig struct {
+	Interval  time.Duration // как часто ссылки проверяются заново
+	BatchSize int           // сколько ссылок читается из БД за один запрос
+}
+
+// ReputationService проверяет адреса назначения по спискам угроз: при создании и изменении ссылок
+// и периодически для уже созданных. Ссылка с найденным адресом отключается, владелец может
+// подать апелляцию.
+type ReputationService struct {
+	storage repository.Storage
+	checker reputation.Checker
+	config  ReputationConfig
+	log     *zap.Logger
+
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	done    chan struct{}
+	started bool
+}
+
+// NewReputationService создает новый сервис проверки репутации
+func NewReputationService(storage repository.Storage, checker reputation.Checker, config ReputationConfig, log *zap.Logger) *ReputationService {
+	return &ReputationService{
+		storage: storage,
+		checker: checker,
+		config:  config,
+		log:     log,
+	}
+}
+
+// CheckURL проверяет один адрес назначения. Сбой проверки не мешает созданию ссылки:
+// он логируется, а адрес будет проверен повторно при следующем проходе.
+func (s *ReputationService) CheckURL(ctx context.Context, rawURL string) *reputation.Verdict {
+	verdict, err := s.checker.Check(ctx, rawURL)
+	if err != nil {
+		s.log.Warn("failed to check url reputation", zap.String("url", rawURL), zap.Error(err))
+		return nil
+	}
+	return verdict
+}
+
+// CheckLink проверяет все адреса назначения ссылки: основной, резервный, адреса правил редиректа
+// и вариантов A/B теста. Возвращает первое срабатывание или nil.
+func (s *ReputationService) CheckLink(ctx context.Context, link *domain.Link) (*reputation.Verdict, error) {
+	destinations := []string{link.OriginalURL}
+	if link.FallbackURL != nil && *link.FallbackURL != "" {
+		destinations = append(destinations, *link.FallbackURL)
+	}
+
+	rules, err := s.storage.ListRedirectRules(ctx, link.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list redirect rules: %w", err)
+	}
+	for _, rule := range rules {
+		destinations = append(destinations, rule.DestinationURL)
+	}
+	variants, err := s.storage.ListLinkVariants(ctx, link.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list link variants: %w", err)
+	}
+	for _, variant := range variants {
+		destinations = append(destinations, variant.DestinationURL)
+	}
+
+	for _, destination := range destinations {
+		verdict, err := s.checker.Check(ctx, destination)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check url reputation: %w", err)
+		}
+		if verdict != nil {
+			return verdict, nil
+		}
+	}
+	return nil, nil
+}
+
+// Appeal обрабатывает апелляцию владельца отключенной ссылки. Адреса назначения проверяются
+// заново по текущим спискам: если срабатываний больше нет, ссылка сразу включается и апелляция
+// сохраняется одобренной, иначе - остается ожидающей и проверяется заново при каждом проходе ScanOnce.
+func (s *ReputationService) Appeal(ctx context.Context, link *domain.Link, userID int64, message string) (*domain.LinkAppeal, error) {
+	if !link.IsFlagged() {
+		return nil, ErrLinkNotFlagged
+	}
+	if len([]rune(message)) > domain.MaxLinkAppealMessageLength {
+		return nil, ErrLinkAppealTooLong
+	}
+
+	pending, err := s.storage.HasPendingLinkAppeal(ctx, link.ID)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, ErrLinkAppealPending
+	}
+
+	appeal := &domain.LinkAppeal{
+		LinkID:  link.ID,
+		UserID:  userID,
+		Message: message,
+		Status:  domain.LinkAppealPending,
+	}
+	if link.FlagReason != nil {
+		appeal.Threat = *link.FlagReason
+	}
+
+	verdict, err := s.CheckLink(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+	if verdict == nil {
+		if err := s.storage.UnflagLink(ctx, link.ID); err != nil {
+			return nil, err
+		}
+		resolvedAt := time.Now()
+		appeal.Status = domain.LinkAppealApproved
+		appeal.ResolvedAt = &resolvedAt
+		link.IsActive, link.FlaggedAt, link.FlagReason = true, nil, nil
+	}
+
+	if err := s.storage.CreateLinkAppeal(ctx, appeal); err != nil {
+		return nil, err
+	}
+	return appeal, nil
+}
+
+// Start запускает периодическую проверку ссылок
+func (s *ReputationService) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return fmt.Errorf("reputation scanner already started")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	s.started = true
+
+	s.log.Info("starting reputation scanner",
+		zap.Duration("interval", s.config.Interval),
+		zap.Int("batch_size", s.config.BatchSize))
+
+	go s.run(ctx)
+	return nil
+}
+
+// Stop останавливает периодическую проверку и ждет завершения текущего прохода
+func (s *ReputationService) Stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.started {
+		return fmt.Errorf("reputation scanner not started")
+	}
+
+	s.cancel()
+	<-s.done
+	s.started = false
+
+	s.log.Info("reputation scanner stopped")
+	return nil
+}
+
+// ScanOnce перечитывает списки угроз (если проверка это поддерживает), проверяет все активные
+// ссылки и пересматривает ожидающие апелляции. Возвращает количество отключенных ссылок.
+func (s *ReputationService) ScanOnce(ctx context.Context) (int, error) {
+	if reloader, ok := s.checker.(reputation.Reloader); ok {
+		if err := reloader.Reload(); err != nil {
+			s.log.Warn("failed to reload threat lists", zap.Error(err))
+		}
+	}
+
+	flagged := 0
+	var afterID int64
+	for {
+		links, err := s.storage.ListLinksForReputationScan(ctx, afterID, s.config.BatchSize)
+		if err != nil {
+			return flagged, err
+		}
+		for _, link := range links {
+			verdict, err := s.CheckLink(ctx, link)
+			if err != nil {
+				if ctx.Err() != nil {
+					return flagged, ctx.Err()
+				}
+				s.log.Warn("failed to check link reputation", zap.String("alias", link.Alias), zap.Error(err))
+				continue
+			}
+			if verdict == nil {
+				continue
+			}
+			if err := s.storage.FlagLink(ctx, link.ID, string(verdict.Threat)); err != nil {
+				return flagged, err
+			}
+			s.log.Warn("disabled link with listed destination",
+				zap.String("alias", link.Alias),
+				zap.Int64("user_id", link.UserID),
+				zap.String("threat", string(verdict.Threat)),
+				zap.String("source", verdict.Source))
+			flagged++
+		}
+		if len(links) < s.config.BatchSize {
+			break
+		}
+		afterID = links[len(links)-1].ID
+	}
+
+	if _, err := s.reviewPendingAppeals(ctx); err != nil {
+		return flagged, err
+	}
+	return flagged, nil
+}
+
+// reviewPendingAppeals проверяет заново ссылки с ожидающими апелляциями: ссылка, адресов которой
+// больше нет в списках угроз (администратор убрал ложное срабатывание или владелец сменил адрес),
+// включается, а апелляция одобряется. Возвращает количество включенных ссылок.
+func (s *ReputationService) reviewPendingAppeals(ctx context.Context) (int, error) {
+	approved := 0
+	var afterID int64
+	for {
+		links, err := s.storage.ListLinksWithPendingAppeals(ctx, afterID, s.config.BatchSize)
+		if err != nil {
+			return approved, err
+		}
+		for _, link := range links {
+			verdict, err := s.CheckLink(ctx, link)
+			if err != nil {
+				if ctx.Err() != nil {
+					return approved, ctx.Err()
+				}
+				s.log.Warn("failed to re-check appealed link", zap.String("alias", link.Alias), zap.Error(err))
+				continue
+			}
+			if verdict != nil {
+				continue
+			}
+			if err := s.storage.UnflagLink(ctx, link.ID); err != nil && err != repository.ErrAliasNotFound {
+				return approved, err
+			}
+			s.log.Info("re-enabled appealed link", zap.String("alias", link.Alias), zap.Int64("user_id", link.UserID))
+			approved++
+		}
+		if len(links) < s.config.BatchSize {
+			return approved, nil
+		}
+		afterID = links[len(links)-1].ID
+	}
+}
+
+// run выполняет проверку сразу после старта и затем по таймеру
+func (s *ReputationService) run(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if flagged, err := s.ScanOnce(ctx); err != nil && ctx.Err() == nil {
+			s.log.Error("failed to scan links reputation", zap.Error(err))
+		} else if flagged > 0 {
+			s.log.Info("reputation scan completed", zap.Int("flagged", flagged))
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
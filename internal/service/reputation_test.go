@@ -0,0 +1,189 @@
+package service
+
+import (
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/repository"
+	"GURLS-Backend/pkg/reputation"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// stubChecker отмечает адреса, содержащие одну из подстрок
+type stubChecker struct {
+	listed []string
+}
+
+func (c *stubChecker) Check(ctx context.Context, rawURL string) (*reputation.Verdict, error) {
+	for _, listed := range c.listed {
+		if strings.Contains(rawURL, listed) {
+			return &reputation.Verdict{Threat: reputation.ThreatPhishing, Source: "stub", Match: listed}, nil
+		}
+	}
+	return nil, nil
+}
+
+// reputationStorage ссылки и апелляции в памяти; остальные методы Storage не используются
+type reputationStorage struct {
+	repository.Storage
+	links    []*domain.Link
+	variants map[int64][]domain.LinkVariant
+	appeals  []*domain.LinkAppeal
+}
+
+func (s *reputationStorage) ListLinksForReputationScan(ctx context.Context, afterID int64, limit int) ([]*domain.Link, error) {
+	var page []*domain.Link
+	for _, link := range s.links {
+		if link.ID > afterID && link.IsActive && !link.IsFlagged() && len(page) < limit {
+			page = append(page, link)
+		}
+	}
+	return page, nil
+}
+
+func (s *reputationStorage) FlagLink(ctx context.Context, linkID int64, threat string) error {
+	for _, link := range s.links {
+		if link.ID == linkID {
+			now := time.Now()
+			link.IsActive, link.FlaggedAt, link.FlagReason = false, &now, &threat
+		}
+	}
+	return nil
+}
+
+func (s *reputationStorage) UnflagLink(ctx context.Context, linkID int64) error {
+	for _, link := range s.links {
+		if link.ID == linkID {
+			link.IsActive, link.FlaggedAt, link.FlagReason = true, nil, nil
+		}
+	}
+	for _, appeal := range s.appeals {
+		if appeal.LinkID == linkID && appeal.Status == domain.LinkAppealPending {
+			now := time.Now()
+			appeal.Status, appeal.ResolvedAt = domain.LinkAppealApproved, &now
+		}
+	}
+	return nil
+}
+
+func (s *reputationStorage) ListRedirectRules(ctx context.Context, linkID int64) ([]domain.RedirectRule, error) {
+	return nil, nil
+}
+
+func (s *reputationStorage) ListLinkVariants(ctx context.Context, linkID int64) ([]domain.LinkVariant, error) {
+	return s.variants[linkID], nil
+}
+
+func (s *reputationStorage) CreateLinkAppeal(ctx context.Context, appeal *domain.LinkAppeal) error {
+	appeal.ID = int64(len(s.appeals) + 1)
+	s.appeals = append(s.appeals, appeal)
+	return nil
+}
+
+func (s *reputationStorage) ListLinksWithPendingAppeals(ctx context.Context, afterID int64, limit int) ([]*domain.Link, error) {
+	var page []*domain.Link
+	for _, link := range s.links {
+		if link.ID > afterID && link.IsFlagged() && len(page) < limit {
+			if pending, _ := s.HasPendingLinkAppeal(ctx, link.ID); pending {
+				page = append(page, link)
+			}
+		}
+	}
+	return page, nil
+}
+
+func (s *reputationStorage) HasPendingLinkAppeal(ctx context.Context, linkID int64) (bool, error) {
+	for _, appeal := range s.appeals {
+		if appeal.LinkID == linkID && appeal.Status == domain.LinkAppealPending {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestReputationService_ScanAndAppeal(t *testing.T) {
+	ctx := context.Background()
+	storage := &reputationStorage{variants: map[int64][]domain.LinkVariant{}}
+	for i := int64(1); i <= 5; i++ {
+		storage.links = append(storage.links, &domain.Link{ID: i, Alias: "link", OriginalURL: "https://example.com/page", IsActive: true})
+	}
+	storage.links[1].OriginalURL = "https://evil.example/login"
+	storage.variants[4] = []domain.LinkVariant{{DestinationURL: "https://evil.example/b"}}
+
+	checker := &stubChecker{listed: []string{"evil.example"}}
+	service := NewReputationService(storage, checker, ReputationConfig{BatchSize: 2}, zap.NewNop())
+
+	flagged, err := service.ScanOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, flagged)
+	assert.True(t, storage.links[1].IsFlagged())
+	assert.False(t, storage.links[1].IsActive)
+	assert.True(t, storage.links[3].IsFlagged(), "variant destinations are checked as well")
+	assert.False(t, storage.links[0].IsFlagged())
+
+	_, err = service.Appeal(ctx, storage.links[0], 1, "")
+	assert.ErrorIs(t, err, ErrLinkNotFlagged)
+
+	// адрес все еще в списке - апелляция остается ожидающей
+	appeal, err := service.Appeal(ctx, storage.links[1], 1, "This is my bank's real site")
+	require.NoError(t, err)
+	assert.Equal(t, domain.LinkAppealPending, appeal.Status)
+	assert.Equal(t, string(reputation.ThreatPhishing), appeal.Threat)
+	assert.False(t, storage.links[1].IsActive)
+
+	_, err = service.Appeal(ctx, storage.links[1], 1, "again")
+	assert.ErrorIs(t, err, ErrLinkAppealPending)
+
+	// адрес убран из списков - ссылка включается сразу
+	storage.variants[4] = nil
+	appeal, err = service.Appeal(ctx, storage.links[3], 1, "")
+	require.NoError(t, err)
+	assert.Equal(t, domain.LinkAppealApproved, appeal.Status)
+	assert.NotNil(t, appeal.ResolvedAt)
+	assert.True(t, storage.links[3].IsActive)
+	assert.False(t, storage.links[3].IsFlagged())
+}
+
+func TestReputationService_ScanReviewsPendingAppeals(t *testing.T) {
+	ctx := context.Background()
+	storage := &reputationStorage{variants: map[int64][]domain.LinkVariant{}}
+	for i := int64(1); i <= 3; i++ {
+		storage.links = append(storage.links, &domain.Link{ID: i, Alias: "link", OriginalURL: "https://evil.example/page", IsActive: true})
+	}
+
+	checker := &stubChecker{listed: []string{"evil.example"}}
+	service := NewReputationService(storage, checker, ReputationConfig{BatchSize: 2}, zap.NewNop())
+
+	flagged, err := service.ScanOnce(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, flagged)
+
+	for _, link := range storage.links[:2] {
+		appeal, err := service.Appeal(ctx, link, 1, "false positive")
+		require.NoError(t, err)
+		require.Equal(t, domain.LinkAppealPending, appeal.Status)
+	}
+
+	// пока адрес в списках, апелляции остаются ожидающими
+	_, err = service.ScanOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, domain.LinkAppealPending, storage.appeals[0].Status)
+	assert.False(t, storage.links[0].IsActive)
+
+	// администратор убрал ложное срабатывание из списков - следующий проход включает ссылки с апелляцией
+	checker.listed = nil
+	flagged, err = service.ScanOnce(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, flagged)
+	for i, appeal := range storage.appeals {
+		assert.Equal(t, domain.LinkAppealApproved, appeal.Status, i)
+		assert.NotNil(t, appeal.ResolvedAt, i)
+		assert.True(t, storage.links[i].IsActive, i)
+	}
+	assert.True(t, storage.links[2].IsFlagged(), "links without an appeal stay disabled")
+}
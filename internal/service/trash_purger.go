@@ -0,0 +1,102 @@
+package service
+
+import (
+	"GURLS-Backend/internal/repository"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// TrashPurgerConfig конфигурация фоновой очистки корзины
+type TrashPurgerConfig struct {
+	Retention time.Duration // сколько ссылка хранится в корзине до окончательного удаления
+	Interval  time.Duration // как часто запускается очистка
+}
+
+// TrashPurger периодически удаляет ссылки, пролежавшие в корзине дольше срока хранения
+type TrashPurger struct {
+	storage repository.Storage
+	config  TrashPurgerConfig
+	log     *zap.Logger
+
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	done    chan struct{}
+	started bool
+}
+
+// NewTrashPurger создает новый сервис очистки корзины
+func NewTrashPurger(storage repository.Storage, config TrashPurgerConfig, log *zap.Logger) *TrashPurger {
+	return &TrashPurger{
+		storage: storage,
+		config:  config,
+		log:     log,
+	}
+}
+
+// Start запускает фоновую очистку
+func (p *TrashPurger) Start() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.started {
+		return fmt.Errorf("trash purger already started")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	p.done = make(chan struct{})
+	p.started = true
+
+	p.log.Info("starting trash purger",
+		zap.Duration("retention", p.config.Retention),
+		zap.Duration("interval", p.config.Interval))
+
+	go p.run(ctx)
+	return nil
+}
+
+// Stop останавливает фоновую очистку и ждет завершения текущего прохода
+func (p *TrashPurger) Stop() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.started {
+		return fmt.Errorf("trash purger not started")
+	}
+
+	p.cancel()
+	<-p.done
+	p.started = false
+
+	p.log.Info("trash purger stopped")
+	return nil
+}
+
+// PurgeOnce удаляет ссылки, срок хранения которых в корзине истек
+func (p *TrashPurger) PurgeOnce(ctx context.Context) (int64, error) {
+	return p.storage.PurgeDeletedLinks(ctx, time.Now().Add(-p.config.Retention))
+}
+
+// run выполняет очистку сразу после старта и затем по таймеру
+func (p *TrashPurger) run(ctx context.Context) {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.PurgeOnce(ctx); err != nil && ctx.Err() == nil {
+			p.log.Error("failed to purge trash", zap.Error(err))
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"GURLS-Backend/internal/config"
+	"GURLS-Backend/internal/repository"
+	"GURLS-Backend/pkg/urlpolicy"
+	"context"
+	"net/url"
+
+	"go.uber.org/zap"
+)
+
+// NewURLPolicy создает политику адресов назначения. Адреса на домене сервиса (из baseURL)
+// и на подтвержденных собственных доменах пользователей отклоняются как петли редиректа.
+func NewURLPolicy(storage repository.Storage, cfg *config.URLPolicy, baseURL string, resolver urlpolicy.Resolver, log *zap.Logger) *urlpolicy.Policy {
+	var selfHosts []string
+	if u, err := url.Parse(baseURL); err == nil && u.Hostname() != "" {
+		selfHosts = append(selfHosts, u.Hostname())
+	}
+
+	var shortenerHosts []string
+	if cfg.BlockShorteners {
+		shortenerHosts = append(append(shortenerHosts, urlpolicy.DefaultShortenerHosts...), cfg.ShortenerHosts...)
+	}
+
+	return urlpolicy.New(urlpolicy.Options{
+		SelfHosts: selfHosts,
+		IsSelfHost: func(ctx context.Context, host string) (bool, error) {
+			_, err := storage.GetVerifiedDomain(ctx, host)
+			if err == repository.ErrDomainNotFound {
+				return false, nil
+			}
+			if err != nil {
+				log.Warn("failed to check destination against custom domains", zap.String("host", host), zap.Error(err))
+				return false, err
+			}
+			return true, nil
+		},
+		ShortenerHosts: shortenerHosts,
+		BlockPrivate:   cfg.BlockPrivateNetworks,
+		Resolver:       resolver,
+	})
+}
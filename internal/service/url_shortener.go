@@ -4,59 +4,196 @@ import (
 	"GURLS-Backend/internal/config"
 	"GURLS-Backend/internal/domain"
 	"GURLS-Backend/internal/repository"
-	"GURLS-Backend/pkg/random"
+	"GURLS-Backend/pkg/aliasgen"
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 )
 
-const maxRetries = 5
+const (
+	maxRetries = 10
+
+	// collisionsPerLengthStep коллизий в одном запросе, после которых следующая попытка длиннее на символ
+	collisionsPerLengthStep = 3
+
+	// aliasLengthWindow и aliasLengthGrowCollisions: если в окне из aliasLengthWindow попыток
+	// набралось aliasLengthGrowCollisions коллизий, длина новых алиасов растет на символ
+	aliasLengthWindow         = 100
+	aliasLengthGrowCollisions = 25
+)
+
+var ErrAliasGenerationFailed = errors.New("failed to generate a unique alias, try again later")
 
 type URLShortenerService struct {
-	storage repository.Storage
-	config  *config.URLShortener
+	storage   repository.Storage
+	config    *config.URLShortener
+	policy    *AliasPolicy
+	generator aliasgen.Generator
+	length    *aliasLength
 }
 
-func NewURLShortener(storage repository.Storage, cfg *config.URLShortener) *URLShortenerService {
+// NewURLShortener создает сервис сокращения ссылок со стратегией генерации алиасов из конфигурации;
+// кастомные и сгенерированные алиасы проверяются политикой алиасов
+func NewURLShortener(storage repository.Storage, cfg *config.URLShortener, policy *AliasPolicy) (*URLShortenerService, error) {
+	if cfg.AliasLength <= 0 || cfg.AliasLength > domain.MaxAliasLength {
+		return nil, fmt.Errorf("alias_length must be between 1 and %d", domain.MaxAliasLength)
+	}
+	maxLength := min(max(cfg.AliasMaxLength, cfg.AliasLength), domain.MaxAliasLength)
+
+	generator, err := newAliasGenerator(storage, cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	return &URLShortenerService{
-		storage: storage,
-		config:  cfg,
+		storage:   storage,
+		config:    cfg,
+		policy:    policy,
+		generator: generator,
+		length:    &aliasLength{current: cfg.AliasLength, max: maxLength},
+	}, nil
+}
+
+// newAliasGenerator выбирает стратегию генерации алиасов; sequential и hashids берут числа из последовательности БД
+func newAliasGenerator(storage repository.Storage, cfg *config.URLShortener) (aliasgen.Generator, error) {
+	switch aliasgen.Strategy(cfg.AliasStrategy) {
+	case aliasgen.StrategyRandom, "":
+		return aliasgen.NewRandom(cfg.AliasAlphabet)
+	case aliasgen.StrategySequential:
+		return aliasgen.NewSequential(storage.NextAliasSequence), nil
+	case aliasgen.StrategyHashids:
+		return aliasgen.NewHashids(storage.NextAliasSequence, cfg.AliasSalt, cfg.AliasAlphabet)
+	case aliasgen.StrategyWords:
+		return aliasgen.NewWords(), nil
 	}
+	return nil, aliasgen.ErrInvalidStrategy
 }
 
-// Shorten теперь также обрабатывает кастомный алиас
+// Shorten теперь также обрабатывает кастомный алиас; алиас проверяется в домене ссылки (link.DomainID).
+// Кастомный алиас, отклоненный политикой алиасов, возвращает *AliasPolicyError.
 func (s *URLShortenerService) Shorten(ctx context.Context, link *domain.Link, customAlias *string) (string, error) {
-	var alias string
-	if customAlias != nil && *customAlias != "" {
-		alias = *customAlias
-		exists, err := s.storage.AliasExists(ctx, alias)
-		if err != nil {
-			return "", fmt.Errorf("failed to check custom alias existence: %w", err)
-		}
-		if exists {
-			return "", repository.ErrAliasExists
-		}
-	} else {
-		var err error
-		for i := 0; i < maxRetries; i++ {
-			alias, err = random.NewRandomString(s.config.AliasLength)
-			if err != nil {
-				return "", fmt.Errorf("failed to generate alias: %w", err)
-			}
-			exists, err := s.storage.AliasExists(ctx, alias)
-			if err != nil {
-				return "", fmt.Errorf("failed to check alias existence: %w", err)
-			}
-			if !exists {
-				break
-			}
+	if customAlias == nil || *customAlias == "" {
+		return s.shortenGenerated(ctx, link)
+	}
+
+	alias := *customAlias
+	if err := s.policy.Check(ctx, alias); err != nil {
+		var policyErr *AliasPolicyError
+		if errors.As(err, &policyErr) {
+			return "", err
 		}
+		return "", fmt.Errorf("failed to check custom alias policy: %w", err)
+	}
+
+	exists, err := s.storage.AliasExists(ctx, link.DomainID, alias)
+	if err != nil {
+		return "", fmt.Errorf("failed to check custom alias existence: %w", err)
+	}
+	if exists {
+		return "", repository.ErrAliasExists
 	}
 
 	link.Alias = alias
 
+	// алиас мог занять параллельный запрос: SaveLink вернет ErrAliasExists
 	if err := s.storage.SaveLink(ctx, link); err != nil {
+		if err == repository.ErrAliasExists {
+			return "", err
+		}
 		return "", fmt.Errorf("failed to save link: %w", err)
 	}
 
 	return alias, nil
 }
+
+// shortenGenerated сохраняет ссылку со сгенерированным алиасом.
+// Занятый алиас и нарушение уникальности при вставке считаются коллизией: попытка повторяется,
+// а после каждых collisionsPerLengthStep коллизий алиас удлиняется на символ.
+// Алиас, отклоненный политикой алиасов, тоже заменяется новым, но в статистику коллизий не попадает.
+func (s *URLShortenerService) shortenGenerated(ctx context.Context, link *domain.Link) (string, error) {
+	baseLength := s.length.get()
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		length := min(baseLength+attempt/collisionsPerLengthStep, s.length.max)
+		alias, err := s.generator.Generate(ctx, length)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate alias: %w", err)
+		}
+
+		acceptable, err := s.policy.Acceptable(ctx, alias)
+		if err != nil {
+			return "", fmt.Errorf("failed to check alias policy: %w", err)
+		}
+		if !acceptable {
+			continue
+		}
+
+		exists, err := s.storage.AliasExists(ctx, link.DomainID, alias)
+		if err != nil {
+			return "", fmt.Errorf("failed to check alias existence: %w", err)
+		}
+		if exists {
+			s.length.record(true)
+			continue
+		}
+
+		link.Alias = alias
+		err = s.storage.SaveLink(ctx, link)
+		if err == repository.ErrAliasExists {
+			s.length.record(true)
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to save link: %w", err)
+		}
+
+		s.length.record(false)
+		return alias, nil
+	}
+
+	link.Alias = ""
+	return "", ErrAliasGenerationFailed
+}
+
+// ShortURL возвращает полный короткий URL для алиаса
+func (s *URLShortenerService) ShortURL(alias string) string {
+	return s.config.BaseURL + "/" + alias
+}
+
+// aliasLength адаптивная длина генерируемых алиасов. Частые коллизии означают, что пространство
+// алиасов текущей длины заполнено, и длина увеличивается до max. Состояние хранится в памяти процесса:
+// после перезапуска длина снова начинается с alias_length и при необходимости растет заново.
+type aliasLength struct {
+	mu         sync.Mutex
+	current    int
+	max        int
+	attempts   int
+	collisions int
+}
+
+// get возвращает текущую длину
+func (l *aliasLength) get() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.current
+}
+
+// record учитывает попытку генерации и при необходимости увеличивает длину
+func (l *aliasLength) record(collision bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.attempts++
+	if collision {
+		l.collisions++
+	}
+
+	if l.collisions >= aliasLengthGrowCollisions && l.current < l.max {
+		l.current++
+		l.attempts, l.collisions = 0, 0
+		return
+	}
+	if l.attempts >= aliasLengthWindow {
+		l.attempts, l.collisions = 0, 0
+	}
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"GURLS-Backend/internal/config"
+	"GURLS-Backend/internal/domain"
+	"GURLS-Backend/internal/repository"
+	"GURLS-Backend/pkg/aliasgen"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// aliasStorage хранилище алиасов в памяти; raced - алиасы, которые "занимает" параллельный запрос
+// между проверкой и вставкой; остальные методы Storage не используются
+type aliasStorage struct {
+	repository.Storage
+	taken    map[string]bool
+	raced    map[string]bool
+	sequence int64
+}
+
+func (s *aliasStorage) AliasExists(ctx context.Context, domainID *int64, alias string) (bool, error) {
+	return s.taken[alias], nil
+}
+
+func (s *aliasStorage) SaveLink(ctx context.Context, link *domain.Link) error {
+	if s.taken[link.Alias] || s.raced[link.Alias] {
+		s.taken[link.Alias] = true
+		return repository.ErrAliasExists
+	}
+	s.taken[link.Alias] = true
+	return nil
+}
+
+func (s *aliasStorage) NextAliasSequence(ctx context.Context) (int64, error) {
+	s.sequence++
+	return s.sequence, nil
+}
+
+func (s *aliasStorage) IsAliasBlocked(ctx context.Context, alias string) (bool, error) {
+	return false, nil
+}
+
+func newTestShortener(t *testing.T, storage *aliasStorage, strategy string) *URLShortenerService {
+	t.Helper()
+	policy, err := NewAliasPolicy(storage, &config.AliasPolicy{MinLength: 2}, zap.NewNop())
+	require.NoError(t, err)
+	shortener, err := NewURLShortener(storage, &config.URLShortener{
+		AliasLength:    2,
+		AliasMaxLength: 4,
+		AliasStrategy:  strategy,
+		BaseURL:        "http://localhost:8080",
+	}, policy)
+	require.NoError(t, err)
+	return shortener
+}
+
+func TestURLShortener_InsertRaceIsRetried(t *testing.T) {
+	storage := &aliasStorage{taken: map[string]bool{"01": true}, raced: map[string]bool{"02": true}}
+	shortener := newTestShortener(t, storage, "sequential")
+
+	alias, err := shortener.Shorten(context.Background(), &domain.Link{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "03", alias)
+
+	custom := "02"
+	_, err = shortener.Shorten(context.Background(), &domain.Link{}, &custom)
+	assert.Equal(t, repository.ErrAliasExists, err)
+}
+
+func TestURLShortener_LengthGrowsOnCollisions(t *testing.T) {
+	// все двухсимвольные алиасы заняты
+	storage := &aliasStorage{taken: map[string]bool{}}
+	for i := int64(0); i < 62*62; i++ {
+		storage.taken[encodeBase62(i, 2)] = true
+	}
+	shortener := newTestShortener(t, storage, "sequential")
+
+	alias, err := shortener.Shorten(context.Background(), &domain.Link{}, nil)
+	require.NoError(t, err)
+	assert.Len(t, alias, 3, "length must grow within a request after repeated collisions")
+
+	for i := 0; i < aliasLengthGrowCollisions; i++ {
+		shortener.length.record(true)
+	}
+	assert.Equal(t, 3, shortener.length.get())
+
+	for i := 0; i < aliasLengthGrowCollisions*10; i++ {
+		shortener.length.record(true)
+	}
+	assert.Equal(t, 4, shortener.length.get(), "length must not exceed alias_max_length")
+}
+
+func TestURLShortener_GenerationFails(t *testing.T) {
+	storage := &aliasStorage{taken: map[string]bool{}}
+	shortener := newTestShortener(t, storage, "words")
+	shortener.generator = constantGenerator("taken")
+	storage.taken["taken"] = true
+
+	_, err := shortener.Shorten(context.Background(), &domain.Link{}, nil)
+	assert.ErrorIs(t, err, ErrAliasGenerationFailed)
+}
+
+func TestNewURLShortener_InvalidConfig(t *testing.T) {
+	_, err := NewURLShortener(&aliasStorage{}, &config.URLShortener{AliasLength: 4, AliasStrategy: "uuid"}, nil)
+	assert.Error(t, err)
+
+	_, err = NewURLShortener(&aliasStorage{}, &config.URLShortener{AliasLength: 4, AliasAlphabet: "abc"}, nil)
+	assert.Error(t, err)
+
+	_, err = NewURLShortener(&aliasStorage{}, &config.URLShortener{AliasLength: domain.MaxAliasLength + 1}, nil)
+	assert.Error(t, err)
+}
+
+type constantGenerator string
+
+func (g constantGenerator) Generate(ctx context.Context, length int) (string, error) {
+	return string(g), nil
+}
+
+// encodeBase62 повторяет кодирование стратегии sequential
+func encodeBase62(n int64, length int) string {
+	b := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		b[i] = aliasgen.Base62Alphabet[n%62]
+		n /= 62
+	}
+	return string(b)
+}
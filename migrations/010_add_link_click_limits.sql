@@ -0,0 +1,16 @@
+-- 010_add_link_click_limits.sql
+-- Резервный URL для истекших ссылок и ссылок с исчерпанным лимитом переходов
+
+ALTER TABLE links ADD COLUMN IF NOT EXISTS fallback_url TEXT NULL;
+
+-- Лимит переходов должен быть положительным
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'links_max_clicks_check') THEN
+        ALTER TABLE links ADD CONSTRAINT links_max_clicks_check
+            CHECK (max_clicks IS NULL OR max_clicks > 0);
+    END IF;
+END $$;
+
+COMMENT ON COLUMN links.max_clicks IS 'Максимальное число переходов (NULL = без ограничений)';
+COMMENT ON COLUMN links.fallback_url IS 'URL для редиректа после истечения срока или исчерпания лимита переходов';
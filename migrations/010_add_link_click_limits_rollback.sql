@@ -0,0 +1,5 @@
+-- 010_add_link_click_limits_rollback.sql
+-- Rollback link click limits changes
+
+ALTER TABLE links DROP CONSTRAINT IF EXISTS links_max_clicks_check;
+ALTER TABLE links DROP COLUMN IF EXISTS fallback_url;
@@ -0,0 +1,19 @@
+-- 011_create_link_revisions.sql
+-- История изменений адреса назначения ссылок
+
+CREATE TABLE IF NOT EXISTS link_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    link_id BIGINT NOT NULL REFERENCES links(id) ON DELETE CASCADE,
+    original_url TEXT NOT NULL,
+    previous_url TEXT NULL,
+    changed_by BIGINT NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Индексы
+CREATE INDEX idx_link_revisions_link_id ON link_revisions(link_id);
+CREATE INDEX idx_link_revisions_created_at ON link_revisions(created_at);
+
+-- Начальная ревизия для уже существующих ссылок
+INSERT INTO link_revisions (link_id, original_url, previous_url, changed_by, created_at)
+SELECT id, original_url, NULL, user_id, created_at FROM links;
@@ -0,0 +1,7 @@
+-- 011_create_link_revisions_rollback.sql
+-- Rollback link_revisions table
+
+DROP INDEX IF EXISTS idx_link_revisions_created_at;
+DROP INDEX IF EXISTS idx_link_revisions_link_id;
+
+DROP TABLE IF EXISTS link_revisions;
@@ -0,0 +1,16 @@
+-- 012_add_links_soft_delete.sql
+-- Корзина для удаленных ссылок: алиас остается занятым до окончательной очистки
+
+ALTER TABLE links ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE NULL;
+
+-- Ранее удаление выполнялось через is_active = false - переносим такие ссылки в корзину
+UPDATE links SET deleted_at = updated_at WHERE is_active = false AND deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_links_deleted_at ON links(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Клики удаляются вместе со ссылкой при очистке корзины
+ALTER TABLE clicks DROP CONSTRAINT IF EXISTS clicks_link_id_fkey;
+ALTER TABLE clicks ADD CONSTRAINT clicks_link_id_fkey
+    FOREIGN KEY (link_id) REFERENCES links(id) ON DELETE CASCADE;
+
+COMMENT ON COLUMN links.deleted_at IS 'Время перемещения в корзину (NULL = ссылка не удалена)';
@@ -0,0 +1,11 @@
+-- 012_add_links_soft_delete_rollback.sql
+-- Rollback links soft delete
+
+ALTER TABLE clicks DROP CONSTRAINT IF EXISTS clicks_link_id_fkey;
+ALTER TABLE clicks ADD CONSTRAINT clicks_link_id_fkey
+    FOREIGN KEY (link_id) REFERENCES links(id);
+
+DROP INDEX IF EXISTS idx_links_deleted_at;
+
+UPDATE links SET is_active = false WHERE deleted_at IS NOT NULL;
+ALTER TABLE links DROP COLUMN IF EXISTS deleted_at;
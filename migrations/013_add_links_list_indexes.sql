@@ -0,0 +1,6 @@
+-- 013_add_links_list_indexes.sql
+-- Индексы для постраничного списка ссылок пользователя (keyset-пагинация по полю сортировки и id)
+
+CREATE INDEX IF NOT EXISTS idx_links_user_created ON links(user_id, created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_links_user_clicks ON links(user_id, click_count, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_links_user_title ON links(user_id, (COALESCE(title, '')), id) WHERE deleted_at IS NULL;
@@ -0,0 +1,6 @@
+-- 013_add_links_list_indexes_rollback.sql
+-- Rollback links list indexes
+
+DROP INDEX IF EXISTS idx_links_user_title;
+DROP INDEX IF EXISTS idx_links_user_clicks;
+DROP INDEX IF EXISTS idx_links_user_created;
@@ -0,0 +1,21 @@
+-- 014_create_tags.sql
+-- Теги пользователей и связь ссылок с тегами (многие ко многим)
+
+CREATE TABLE IF NOT EXISTS tags (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_tags_user_name ON tags(user_id, name);
+
+CREATE TABLE IF NOT EXISTS link_tags (
+    link_id BIGINT NOT NULL REFERENCES links(id) ON DELETE CASCADE,
+    tag_id BIGINT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (link_id, tag_id)
+);
+
+-- Индекс для выборки ссылок по тегу и агрегированной статистики
+CREATE INDEX idx_link_tags_tag_id ON link_tags(tag_id);
@@ -0,0 +1,8 @@
+-- 014_create_tags_rollback.sql
+-- Rollback tags and link_tags tables
+
+DROP INDEX IF EXISTS idx_link_tags_tag_id;
+DROP TABLE IF EXISTS link_tags;
+
+DROP INDEX IF EXISTS idx_tags_user_name;
+DROP TABLE IF EXISTS tags;
@@ -0,0 +1,13 @@
+-- 015_add_link_forwarding.sql
+-- Передача query string и хвоста пути короткой ссылки в адрес назначения
+
+ALTER TABLE links ADD COLUMN IF NOT EXISTS forward_query BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE links ADD COLUMN IF NOT EXISTS query_conflict VARCHAR(16) NOT NULL DEFAULT 'keep';
+ALTER TABLE links ADD COLUMN IF NOT EXISTS forward_path BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE links ADD CONSTRAINT links_query_conflict_check
+    CHECK (query_conflict IN ('keep', 'override', 'append'));
+
+COMMENT ON COLUMN links.forward_query IS 'Добавлять параметры запроса к адресу назначения';
+COMMENT ON COLUMN links.query_conflict IS 'Политика при совпадении параметров: keep (оставить параметр ссылки), override (заменить), append (оставить оба)';
+COMMENT ON COLUMN links.forward_path IS 'Переносить путь после алиаса в адрес назначения';
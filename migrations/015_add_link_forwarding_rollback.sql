@@ -0,0 +1,7 @@
+-- 015_add_link_forwarding_rollback.sql
+-- Rollback link query and path forwarding
+
+ALTER TABLE links DROP CONSTRAINT IF EXISTS links_query_conflict_check;
+ALTER TABLE links DROP COLUMN IF EXISTS forward_path;
+ALTER TABLE links DROP COLUMN IF EXISTS query_conflict;
+ALTER TABLE links DROP COLUMN IF EXISTS forward_query;
@@ -0,0 +1,28 @@
+-- 016_add_utm_fields.sql
+-- UTM метки ссылок, метки по умолчанию пользователя и метки входящих переходов
+
+ALTER TABLE links ADD COLUMN IF NOT EXISTS utm_source VARCHAR(100);
+ALTER TABLE links ADD COLUMN IF NOT EXISTS utm_medium VARCHAR(100);
+ALTER TABLE links ADD COLUMN IF NOT EXISTS utm_campaign VARCHAR(100);
+ALTER TABLE links ADD COLUMN IF NOT EXISTS utm_term VARCHAR(100);
+ALTER TABLE links ADD COLUMN IF NOT EXISTS utm_content VARCHAR(100);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS utm_default_source VARCHAR(100);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS utm_default_medium VARCHAR(100);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS utm_default_campaign VARCHAR(100);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS utm_default_term VARCHAR(100);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS utm_default_content VARCHAR(100);
+
+ALTER TABLE clicks ADD COLUMN IF NOT EXISTS utm_source VARCHAR(100);
+ALTER TABLE clicks ADD COLUMN IF NOT EXISTS utm_medium VARCHAR(100);
+ALTER TABLE clicks ADD COLUMN IF NOT EXISTS utm_campaign VARCHAR(100);
+ALTER TABLE clicks ADD COLUMN IF NOT EXISTS utm_term VARCHAR(100);
+ALTER TABLE clicks ADD COLUMN IF NOT EXISTS utm_content VARCHAR(100);
+
+-- Статистика по кампаниям группирует клики ссылки по source/medium/campaign
+CREATE INDEX IF NOT EXISTS idx_clicks_link_utm ON clicks(link_id, utm_source, utm_medium, utm_campaign)
+    WHERE utm_source IS NOT NULL OR utm_medium IS NOT NULL OR utm_campaign IS NOT NULL;
+
+COMMENT ON COLUMN links.utm_source IS 'UTM метка, добавляемая к адресу назначения, если ее там нет';
+COMMENT ON COLUMN users.utm_default_source IS 'UTM метка по умолчанию для новых ссылок пользователя';
+COMMENT ON COLUMN clicks.utm_source IS 'UTM метка из адреса перехода или referer';
@@ -0,0 +1,22 @@
+-- 016_add_utm_fields_rollback.sql
+-- Rollback UTM fields
+
+DROP INDEX IF EXISTS idx_clicks_link_utm;
+
+ALTER TABLE clicks DROP COLUMN IF EXISTS utm_content;
+ALTER TABLE clicks DROP COLUMN IF EXISTS utm_term;
+ALTER TABLE clicks DROP COLUMN IF EXISTS utm_campaign;
+ALTER TABLE clicks DROP COLUMN IF EXISTS utm_medium;
+ALTER TABLE clicks DROP COLUMN IF EXISTS utm_source;
+
+ALTER TABLE users DROP COLUMN IF EXISTS utm_default_content;
+ALTER TABLE users DROP COLUMN IF EXISTS utm_default_term;
+ALTER TABLE users DROP COLUMN IF EXISTS utm_default_campaign;
+ALTER TABLE users DROP COLUMN IF EXISTS utm_default_medium;
+ALTER TABLE users DROP COLUMN IF EXISTS utm_default_source;
+
+ALTER TABLE links DROP COLUMN IF EXISTS utm_content;
+ALTER TABLE links DROP COLUMN IF EXISTS utm_term;
+ALTER TABLE links DROP COLUMN IF EXISTS utm_campaign;
+ALTER TABLE links DROP COLUMN IF EXISTS utm_medium;
+ALTER TABLE links DROP COLUMN IF EXISTS utm_source;
@@ -0,0 +1,17 @@
+-- 017_create_redirect_rules.sql
+-- Правила выбора адреса назначения по стране посетителя
+
+CREATE TABLE IF NOT EXISTS redirect_rules (
+    id BIGSERIAL PRIMARY KEY,
+    link_id BIGINT NOT NULL REFERENCES links(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    countries VARCHAR(255) NOT NULL,
+    destination_url TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Правила ссылки выбираются при каждом редиректе в порядке проверки
+CREATE INDEX idx_redirect_rules_link_position ON redirect_rules(link_id, position);
+
+COMMENT ON COLUMN redirect_rules.countries IS 'ISO 3166-1 alpha-2 коды стран через запятую, например KZ,BY';
+COMMENT ON COLUMN redirect_rules.position IS 'Порядок проверки: срабатывает первое подходящее правило';
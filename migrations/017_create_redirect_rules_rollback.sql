@@ -0,0 +1,4 @@
+-- 017_create_redirect_rules_rollback.sql
+-- Rollback redirect rules
+
+DROP TABLE IF EXISTS redirect_rules;
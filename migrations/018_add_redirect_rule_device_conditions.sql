@@ -0,0 +1,17 @@
+-- 018_add_redirect_rule_device_conditions.sql
+-- Условия правил редиректа по устройству, ОС и браузеру; сработавшее правило в кликах
+
+ALTER TABLE redirect_rules ALTER COLUMN countries SET DEFAULT '';
+ALTER TABLE redirect_rules ADD COLUMN IF NOT EXISTS name VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE redirect_rules ADD COLUMN IF NOT EXISTS device_types VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE redirect_rules ADD COLUMN IF NOT EXISTS os_families VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE redirect_rules ADD COLUMN IF NOT EXISTS browsers VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE redirect_rules ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW();
+
+-- Правила могут удаляться, а клики по ним остаются в статистике, поэтому внешнего ключа нет
+ALTER TABLE clicks ADD COLUMN IF NOT EXISTS rule_id BIGINT;
+
+COMMENT ON COLUMN redirect_rules.device_types IS 'Типы устройств через запятую: desktop, mobile, tablet, bot, unknown';
+COMMENT ON COLUMN redirect_rules.os_families IS 'Семейства ОС парсера User-Agent через запятую, например iOS,Android';
+COMMENT ON COLUMN redirect_rules.browsers IS 'Семейства браузеров парсера User-Agent через запятую, например Chrome,Firefox';
+COMMENT ON COLUMN clicks.rule_id IS 'Сработавшее правило редиректа, NULL - основной адрес ссылки';
@@ -0,0 +1,11 @@
+-- 018_add_redirect_rule_device_conditions_rollback.sql
+-- Rollback redirect rule device conditions
+
+ALTER TABLE clicks DROP COLUMN IF EXISTS rule_id;
+
+ALTER TABLE redirect_rules DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE redirect_rules DROP COLUMN IF EXISTS browsers;
+ALTER TABLE redirect_rules DROP COLUMN IF EXISTS os_families;
+ALTER TABLE redirect_rules DROP COLUMN IF EXISTS device_types;
+ALTER TABLE redirect_rules DROP COLUMN IF EXISTS name;
+ALTER TABLE redirect_rules ALTER COLUMN countries DROP DEFAULT;
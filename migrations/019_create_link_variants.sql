@@ -0,0 +1,21 @@
+-- 019_create_link_variants.sql
+-- Варианты адреса назначения для A/B тестов ссылок
+
+CREATE TABLE IF NOT EXISTS link_variants (
+    id BIGSERIAL PRIMARY KEY,
+    link_id BIGINT NOT NULL REFERENCES links(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    name VARCHAR(100) NOT NULL DEFAULT '',
+    destination_url TEXT NOT NULL,
+    weight INTEGER NOT NULL CHECK (weight > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_link_variants_link_position ON link_variants(link_id, position);
+
+-- Варианты могут удаляться, а клики по ним остаются в статистике, поэтому внешнего ключа нет
+ALTER TABLE clicks ADD COLUMN IF NOT EXISTS variant_id BIGINT;
+
+COMMENT ON COLUMN link_variants.weight IS 'Относительный вес: вероятность варианта равна weight / сумма весов вариантов ссылки';
+COMMENT ON COLUMN clicks.variant_id IS 'Вариант A/B теста, на который попал посетитель';
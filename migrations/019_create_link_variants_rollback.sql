@@ -0,0 +1,6 @@
+-- 019_create_link_variants_rollback.sql
+-- Rollback link variants
+
+ALTER TABLE clicks DROP COLUMN IF EXISTS variant_id;
+
+DROP TABLE IF EXISTS link_variants;
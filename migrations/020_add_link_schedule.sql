@@ -0,0 +1,19 @@
+-- 020_add_link_schedule.sql
+-- Время запуска и часовой пояс ссылок, условия правил редиректа по времени
+
+ALTER TABLE links ADD COLUMN IF NOT EXISTS starts_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE links ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT '';
+
+ALTER TABLE redirect_rules ADD COLUMN IF NOT EXISTS active_from TIMESTAMP WITH TIME ZONE;
+ALTER TABLE redirect_rules ADD COLUMN IF NOT EXISTS active_until TIMESTAMP WITH TIME ZONE;
+ALTER TABLE redirect_rules ADD COLUMN IF NOT EXISTS weekdays VARCHAR(30) NOT NULL DEFAULT '';
+ALTER TABLE redirect_rules ADD COLUMN IF NOT EXISTS time_from VARCHAR(5) NOT NULL DEFAULT '';
+ALTER TABLE redirect_rules ADD COLUMN IF NOT EXISTS time_to VARCHAR(5) NOT NULL DEFAULT '';
+
+-- Запланированные ссылки выбираются фильтром status=scheduled
+CREATE INDEX IF NOT EXISTS idx_links_starts_at ON links(starts_at) WHERE starts_at IS NOT NULL;
+
+COMMENT ON COLUMN links.starts_at IS 'До этого момента ссылка показывает страницу ожидания вместо редиректа';
+COMMENT ON COLUMN links.timezone IS 'IANA часовой пояс для правил редиректа по времени, пустая строка - UTC';
+COMMENT ON COLUMN redirect_rules.weekdays IS 'Дни недели в часовом поясе ссылки через запятую: mon,tue,...';
+COMMENT ON COLUMN redirect_rules.time_from IS 'Начало окна времени суток HH:MM; окно, где time_from > time_to, переходит через полночь';
@@ -0,0 +1,13 @@
+-- 020_add_link_schedule_rollback.sql
+-- Rollback link schedule
+
+DROP INDEX IF EXISTS idx_links_starts_at;
+
+ALTER TABLE redirect_rules DROP COLUMN IF EXISTS time_to;
+ALTER TABLE redirect_rules DROP COLUMN IF EXISTS time_from;
+ALTER TABLE redirect_rules DROP COLUMN IF EXISTS weekdays;
+ALTER TABLE redirect_rules DROP COLUMN IF EXISTS active_until;
+ALTER TABLE redirect_rules DROP COLUMN IF EXISTS active_from;
+
+ALTER TABLE links DROP COLUMN IF EXISTS timezone;
+ALTER TABLE links DROP COLUMN IF EXISTS starts_at;
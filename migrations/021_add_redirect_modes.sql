@@ -0,0 +1,12 @@
+-- 021_add_redirect_modes.sql
+-- Способ редиректа ссылок и пиксели аналитики промежуточной страницы
+
+ALTER TABLE links ADD COLUMN IF NOT EXISTS redirect_mode VARCHAR(16) NOT NULL DEFAULT '302';
+ALTER TABLE links ADD COLUMN IF NOT EXISTS tracking_pixels TEXT NOT NULL DEFAULT '';
+
+-- HTML страницы редиректа доступны на платных тарифах
+ALTER TABLE subscription_types ADD COLUMN IF NOT EXISTS interstitial_redirects BOOLEAN NOT NULL DEFAULT false;
+UPDATE subscription_types SET interstitial_redirects = true WHERE name IN ('base', 'enterprise');
+
+COMMENT ON COLUMN links.redirect_mode IS 'Способ редиректа: 301, 302, 307, 308, meta_refresh или interstitial';
+COMMENT ON COLUMN links.tracking_pixels IS 'Адреса пикселей аналитики промежуточной страницы, по одному в строке';
@@ -0,0 +1,7 @@
+-- 021_add_redirect_modes_rollback.sql
+-- Rollback redirect modes
+
+ALTER TABLE subscription_types DROP COLUMN IF EXISTS interstitial_redirects;
+
+ALTER TABLE links DROP COLUMN IF EXISTS tracking_pixels;
+ALTER TABLE links DROP COLUMN IF EXISTS redirect_mode;
@@ -0,0 +1,6 @@
+-- 022_add_click_source.sql
+-- Источник клика: переходы по QR коду ссылки учитываются отдельно
+
+ALTER TABLE clicks ADD COLUMN IF NOT EXISTS source VARCHAR(10);
+
+COMMENT ON COLUMN clicks.source IS 'Источник перехода: qr - по QR коду с меткой ?qr=1, NULL - остальные';
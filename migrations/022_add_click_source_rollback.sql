@@ -0,0 +1,4 @@
+-- 022_add_click_source_rollback.sql
+-- Rollback click source
+
+ALTER TABLE clicks DROP COLUMN IF EXISTS source;
@@ -0,0 +1,28 @@
+-- 023_create_custom_domains.sql
+-- Собственные домены пользователей; алиас ссылки становится уникальным в пределах домена
+
+CREATE TABLE IF NOT EXISTS custom_domains (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    hostname VARCHAR(253) NOT NULL,
+    verification_token VARCHAR(64) NOT NULL,
+    verified_at TIMESTAMP WITH TIME ZONE NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Домен можно добавить нескольким пользователям, но подтвердить владение может только один
+CREATE UNIQUE INDEX idx_custom_domains_user_hostname ON custom_domains(user_id, hostname);
+CREATE UNIQUE INDEX idx_custom_domains_verified_hostname ON custom_domains(hostname) WHERE verified_at IS NOT NULL;
+
+ALTER TABLE links ADD COLUMN IF NOT EXISTS domain_id BIGINT REFERENCES custom_domains(id);
+
+-- Глобальная уникальность алиаса заменяется уникальностью в пределах домена
+ALTER TABLE links DROP CONSTRAINT IF EXISTS links_alias_key;
+DROP INDEX IF EXISTS idx_links_alias;
+CREATE UNIQUE INDEX idx_links_default_alias ON links(alias) WHERE domain_id IS NULL;
+CREATE UNIQUE INDEX idx_links_domain_alias ON links(domain_id, alias) WHERE domain_id IS NOT NULL;
+
+COMMENT ON COLUMN custom_domains.hostname IS 'Имя домена в ASCII виде (punycode), нижний регистр';
+COMMENT ON COLUMN custom_domains.verification_token IS 'Токен, который владелец публикует в TXT записи _gurls-verification.<hostname>';
+COMMENT ON COLUMN links.domain_id IS 'Собственный домен ссылки, NULL - основной домен сервиса';
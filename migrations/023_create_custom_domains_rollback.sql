@@ -0,0 +1,16 @@
+-- 023_create_custom_domains_rollback.sql
+-- Rollback custom domains
+-- Ссылки на собственных доменах удаляются: их алиасы могут совпадать с алиасами основного домена
+
+DELETE FROM clicks WHERE link_id IN (SELECT id FROM links WHERE domain_id IS NOT NULL);
+DELETE FROM link_revisions WHERE link_id IN (SELECT id FROM links WHERE domain_id IS NOT NULL);
+DELETE FROM link_tags WHERE link_id IN (SELECT id FROM links WHERE domain_id IS NOT NULL);
+DELETE FROM links WHERE domain_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_links_domain_alias;
+DROP INDEX IF EXISTS idx_links_default_alias;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_links_alias ON links(alias);
+
+ALTER TABLE links DROP COLUMN IF EXISTS domain_id;
+
+DROP TABLE IF EXISTS custom_domains;
@@ -0,0 +1,4 @@
+-- 024_create_link_alias_sequence.sql
+-- Последовательность для стратегий генерации алиасов sequential и hashids
+
+CREATE SEQUENCE IF NOT EXISTS link_alias_seq;
@@ -0,0 +1,4 @@
+-- 024_create_link_alias_sequence_rollback.sql
+-- Rollback link alias sequence
+
+DROP SEQUENCE IF EXISTS link_alias_seq;
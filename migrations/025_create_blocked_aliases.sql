@@ -0,0 +1,15 @@
+-- 025_create_blocked_aliases.sql
+-- Черный список алиасов, который ведут администраторы: точные совпадения и подстроки (названия брендов)
+
+CREATE TABLE IF NOT EXISTS blocked_aliases (
+    id BIGSERIAL PRIMARY KEY,
+    pattern VARCHAR(20) NOT NULL,
+    match_type VARCHAR(16) NOT NULL DEFAULT 'exact',
+    reason VARCHAR(200),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_blocked_aliases_pattern ON blocked_aliases(pattern);
+
+COMMENT ON COLUMN blocked_aliases.pattern IS 'Запрещенный алиас или его часть в нижнем регистре';
+COMMENT ON COLUMN blocked_aliases.match_type IS 'exact - алиас целиком, contains - любой алиас, содержащий шаблон';
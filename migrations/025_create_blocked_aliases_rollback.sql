@@ -0,0 +1,4 @@
+-- 025_create_blocked_aliases_rollback.sql
+-- Rollback blocked aliases
+
+DROP TABLE IF EXISTS blocked_aliases;
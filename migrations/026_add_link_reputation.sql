@@ -0,0 +1,23 @@
+-- 026_add_link_reputation.sql
+-- Отключение ссылок, адрес назначения которых найден в списках угроз, и апелляции владельцев
+
+ALTER TABLE links ADD COLUMN IF NOT EXISTS flagged_at TIMESTAMP WITH TIME ZONE NULL;
+ALTER TABLE links ADD COLUMN IF NOT EXISTS flag_reason VARCHAR(32) NULL;
+
+CREATE TABLE IF NOT EXISTS link_appeals (
+    id BIGSERIAL PRIMARY KEY,
+    link_id BIGINT NOT NULL REFERENCES links(id),
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    threat VARCHAR(32) NOT NULL,
+    message TEXT NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    resolved_at TIMESTAMP WITH TIME ZONE NULL
+);
+
+CREATE INDEX idx_link_appeals_link_id ON link_appeals(link_id);
+CREATE INDEX idx_link_appeals_status ON link_appeals(status);
+
+COMMENT ON COLUMN links.flagged_at IS 'Когда адрес назначения найден в списках угроз; ссылка отключена до апелляции';
+COMMENT ON COLUMN links.flag_reason IS 'Категория угрозы: phishing, malware, unwanted или malicious';
+COMMENT ON COLUMN link_appeals.status IS 'pending - ждет проверки, approved - ссылка включена, rejected - угроза подтверждена';
@@ -0,0 +1,7 @@
+-- 026_add_link_reputation_rollback.sql
+-- Rollback link reputation
+
+DROP TABLE IF EXISTS link_appeals;
+
+ALTER TABLE links DROP COLUMN IF EXISTS flag_reason;
+ALTER TABLE links DROP COLUMN IF EXISTS flagged_at;
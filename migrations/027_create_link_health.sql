@@ -0,0 +1,19 @@
+-- 027_create_link_health.sql
+-- Результаты фоновой проверки доступности адресов назначения
+
+CREATE TABLE IF NOT EXISTS link_health (
+    link_id BIGINT PRIMARY KEY REFERENCES links(id),
+    status_code INTEGER NOT NULL DEFAULT 0,
+    latency_ms BIGINT NOT NULL DEFAULT 0,
+    final_url TEXT NOT NULL DEFAULT '',
+    error VARCHAR(500) NOT NULL DEFAULT '',
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    checked_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    broken_since TIMESTAMP WITH TIME ZONE NULL
+);
+
+CREATE INDEX idx_link_health_broken_since ON link_health(broken_since);
+
+COMMENT ON COLUMN link_health.status_code IS 'Код последнего ответа, 0 - ответ не получен';
+COMMENT ON COLUMN link_health.final_url IS 'Адрес назначения после всех редиректов';
+COMMENT ON COLUMN link_health.broken_since IS 'С какого момента ссылка считается нерабочей, NULL - работает';
@@ -0,0 +1,4 @@
+-- 027_create_link_health_rollback.sql
+-- Rollback link health checks
+
+DROP TABLE IF EXISTS link_health;
@@ -0,0 +1,18 @@
+-- 028_add_link_metadata.sql
+-- Метаданные страницы назначения: заголовок, описание, OpenGraph, Twitter card и иконка
+
+ALTER TABLE links ADD COLUMN IF NOT EXISTS meta_title VARCHAR(200) NOT NULL DEFAULT '';
+ALTER TABLE links ADD COLUMN IF NOT EXISTS meta_description VARCHAR(500) NOT NULL DEFAULT '';
+ALTER TABLE links ADD COLUMN IF NOT EXISTS meta_site_name VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE links ADD COLUMN IF NOT EXISTS meta_image_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE links ADD COLUMN IF NOT EXISTS meta_favicon_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE links ADD COLUMN IF NOT EXISTS meta_twitter_card VARCHAR(32) NOT NULL DEFAULT '';
+ALTER TABLE links ADD COLUMN IF NOT EXISTS meta_fetched_at TIMESTAMP WITH TIME ZONE NULL;
+ALTER TABLE links ADD COLUMN IF NOT EXISTS meta_error VARCHAR(500) NOT NULL DEFAULT '';
+
+-- Фоновая загрузка ищет ссылки, страница которых еще не загружалась
+CREATE INDEX IF NOT EXISTS idx_links_meta_pending ON links(id) WHERE meta_fetched_at IS NULL AND deleted_at IS NULL;
+
+COMMENT ON COLUMN links.meta_title IS 'Заголовок страницы назначения: og:title, twitter:title или <title>';
+COMMENT ON COLUMN links.meta_fetched_at IS 'Когда страница назначения загружалась последний раз, NULL - еще не загружалась';
+COMMENT ON COLUMN links.meta_error IS 'Ошибка последней загрузки страницы назначения';
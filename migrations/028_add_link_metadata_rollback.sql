@@ -0,0 +1,13 @@
+-- 028_add_link_metadata_rollback.sql
+-- Rollback link metadata
+
+DROP INDEX IF EXISTS idx_links_meta_pending;
+
+ALTER TABLE links DROP COLUMN IF EXISTS meta_error;
+ALTER TABLE links DROP COLUMN IF EXISTS meta_fetched_at;
+ALTER TABLE links DROP COLUMN IF EXISTS meta_twitter_card;
+ALTER TABLE links DROP COLUMN IF EXISTS meta_favicon_url;
+ALTER TABLE links DROP COLUMN IF EXISTS meta_image_url;
+ALTER TABLE links DROP COLUMN IF EXISTS meta_site_name;
+ALTER TABLE links DROP COLUMN IF EXISTS meta_description;
+ALTER TABLE links DROP COLUMN IF EXISTS meta_title;
@@ -0,0 +1,10 @@
+-- 029_add_link_social_preview.sql
+-- Превью ссылки для мессенджеров и соцсетей, заданное владельцем: заголовок, описание и картинка OpenGraph
+
+ALTER TABLE links ADD COLUMN IF NOT EXISTS og_title VARCHAR(200) NULL;
+ALTER TABLE links ADD COLUMN IF NOT EXISTS og_description VARCHAR(500) NULL;
+ALTER TABLE links ADD COLUMN IF NOT EXISTS og_image_url TEXT NULL;
+
+COMMENT ON COLUMN links.og_title IS 'Заголовок карточки ссылки для ботов мессенджеров, NULL - заголовок ссылки или страницы назначения';
+COMMENT ON COLUMN links.og_description IS 'Описание карточки ссылки для ботов мессенджеров';
+COMMENT ON COLUMN links.og_image_url IS 'Картинка карточки ссылки для ботов мессенджеров';
@@ -0,0 +1,6 @@
+-- 029_add_link_social_preview_rollback.sql
+-- Rollback link social preview
+
+ALTER TABLE links DROP COLUMN IF EXISTS og_image_url;
+ALTER TABLE links DROP COLUMN IF EXISTS og_description;
+ALTER TABLE links DROP COLUMN IF EXISTS og_title;
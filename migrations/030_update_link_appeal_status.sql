@@ -0,0 +1,6 @@
+-- 030_update_link_appeal_status.sql
+-- Апелляции решаются повторной проверкой по спискам угроз, статуса rejected больше нет
+
+UPDATE link_appeals SET status = 'pending', resolved_at = NULL WHERE status = 'rejected';
+
+COMMENT ON COLUMN link_appeals.status IS 'pending - адрес еще в списках угроз, проверяется при каждом проходе; approved - ссылка включена';
@@ -0,0 +1,4 @@
+-- 030_update_link_appeal_status_rollback.sql
+-- Rollback link appeal status comment
+
+COMMENT ON COLUMN link_appeals.status IS 'pending - ждет проверки, approved - ссылка включена, rejected - угроза подтверждена';
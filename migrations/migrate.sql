@@ -11,6 +11,27 @@
 \i 007_create_refresh_tokens.sql
 \i 008_remove_telegram_integration.sql
 \i 009_create_payments.sql
+\i 010_add_link_click_limits.sql
+\i 011_create_link_revisions.sql
+\i 012_add_links_soft_delete.sql
+\i 013_add_links_list_indexes.sql
+\i 014_create_tags.sql
+\i 015_add_link_forwarding.sql
+\i 016_add_utm_fields.sql
+\i 017_create_redirect_rules.sql
+\i 018_add_redirect_rule_device_conditions.sql
+\i 019_create_link_variants.sql
+\i 020_add_link_schedule.sql
+\i 021_add_redirect_modes.sql
+\i 022_add_click_source.sql
+\i 023_create_custom_domains.sql
+\i 024_create_link_alias_sequence.sql
+\i 025_create_blocked_aliases.sql
+\i 026_add_link_reputation.sql
+\i 027_create_link_health.sql
+\i 028_add_link_metadata.sql
+\i 029_add_link_social_preview.sql
+\i 030_update_link_appeal_status.sql
 
 -- Информация о выполненных миграциях
 SELECT 'Database migration completed successfully!' as status;
\ No newline at end of file
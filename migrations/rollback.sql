@@ -2,6 +2,14 @@
 -- Откат всех изменений (для тестирования)
 
 -- Удаляем таблицы в обратном порядке (из-за внешних ключей)
+DROP TABLE IF EXISTS blocked_aliases CASCADE;
+DROP TABLE IF EXISTS link_health CASCADE;
+DROP TABLE IF EXISTS link_appeals CASCADE;
+DROP TABLE IF EXISTS link_variants CASCADE;
+DROP TABLE IF EXISTS redirect_rules CASCADE;
+DROP TABLE IF EXISTS link_tags CASCADE;
+DROP TABLE IF EXISTS tags CASCADE;
+DROP TABLE IF EXISTS link_revisions CASCADE;
 DROP TABLE IF EXISTS subscription_changes CASCADE;
 DROP TABLE IF EXISTS payments CASCADE;
 DROP TABLE IF EXISTS refresh_tokens CASCADE;
@@ -9,7 +17,9 @@ DROP TABLE IF EXISTS sessions CASCADE;
 DROP TABLE IF EXISTS user_stats CASCADE;
 DROP TABLE IF EXISTS clicks CASCADE;
 DROP TABLE IF EXISTS links CASCADE;
+DROP TABLE IF EXISTS custom_domains CASCADE;
 DROP TABLE IF EXISTS users CASCADE;
 DROP TABLE IF EXISTS subscription_types CASCADE;
+DROP SEQUENCE IF EXISTS link_alias_seq;
 
 SELECT 'Database rollback completed!' as status;
\ No newline at end of file
@@ -0,0 +1,125 @@
+// Package aliasgen generates short link aliases using interchangeable strategies:
+// random strings, sequential base62 numbers, hashids-style obfuscated numbers
+// and human-readable word combinations.
+package aliasgen
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Strategy names a generation strategy.
+type Strategy string
+
+const (
+	StrategyRandom     Strategy = "random"
+	StrategySequential Strategy = "sequential"
+	StrategyHashids    Strategy = "hashids"
+	StrategyWords      Strategy = "words"
+)
+
+// DefaultAlphabet is the random strategy alphabet. Characters that are easy to
+// confuse when an alias is read aloud or retyped (0/O/o, 1/l/I) are left out.
+const DefaultAlphabet = "abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+var (
+	ErrInvalidStrategy = errors.New("alias strategy must be random, sequential, hashids or words")
+	ErrInvalidAlphabet = errors.New("alias alphabet must contain at least 16 unique ASCII letters and digits")
+	ErrInvalidLength   = errors.New("alias length must be positive")
+)
+
+// Generator produces alias candidates. Candidates are not guaranteed to be free:
+// the caller checks them against storage and retries on collision.
+//
+// length is the target alias length in characters. Random aliases have exactly
+// that length, sequential and hashids aliases have at least that length and word
+// aliases use it to pick how many words to combine.
+type Generator interface {
+	Generate(ctx context.Context, length int) (string, error)
+}
+
+// SequenceFunc returns the next value of a monotonically increasing sequence,
+// such as a Postgres sequence. Values must be non-negative and never repeat.
+type SequenceFunc func(ctx context.Context) (int64, error)
+
+// IsValid reports whether the strategy is supported.
+func (s Strategy) IsValid() bool {
+	switch s {
+	case StrategyRandom, StrategySequential, StrategyHashids, StrategyWords:
+		return true
+	}
+	return false
+}
+
+// Random generates aliases of random characters from an alphabet.
+type Random struct {
+	alphabet string
+}
+
+// NewRandom returns a random generator. An empty alphabet selects DefaultAlphabet.
+func NewRandom(alphabet string) (*Random, error) {
+	if alphabet == "" {
+		alphabet = DefaultAlphabet
+	}
+	if err := validateAlphabet(alphabet); err != nil {
+		return nil, err
+	}
+	return &Random{alphabet: alphabet}, nil
+}
+
+// Generate returns length characters drawn uniformly from the alphabet.
+func (g *Random) Generate(ctx context.Context, length int) (string, error) {
+	if length <= 0 {
+		return "", ErrInvalidLength
+	}
+	b := make([]byte, length)
+	for i := range b {
+		n, err := randomInt(len(g.alphabet))
+		if err != nil {
+			return "", err
+		}
+		b[i] = g.alphabet[n]
+	}
+	return string(b), nil
+}
+
+// validateAlphabet checks that the alphabet consists of unique ASCII letters and digits.
+func validateAlphabet(alphabet string) error {
+	if len(alphabet) < 16 {
+		return ErrInvalidAlphabet
+	}
+	seen := make(map[rune]bool, len(alphabet))
+	for _, c := range alphabet {
+		isAlnum := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+		if !isAlnum || seen[c] {
+			return ErrInvalidAlphabet
+		}
+		seen[c] = true
+	}
+	return nil
+}
+
+// randomInt returns a uniform random number in [0, n).
+func randomInt(n int) (int, error) {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read random number: %w", err)
+	}
+	return int(v.Int64()), nil
+}
+
+// encodeNumber writes a non-negative n in the positional system given by the alphabet.
+func encodeNumber(n int64, alphabet string) string {
+	base := int64(len(alphabet))
+	var digits []byte
+	for {
+		digits = append([]byte{alphabet[n%base]}, digits...)
+		n /= base
+		if n == 0 {
+			return string(digits)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package aliasgen
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hashidsAlphabet is the alphabet of the reference hashids implementations.
+const hashidsAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890"
+
+func counter(start int64) SequenceFunc {
+	n := start - 1
+	return func(ctx context.Context) (int64, error) {
+		n++
+		return n, nil
+	}
+}
+
+func TestRandom(t *testing.T) {
+	g, err := NewRandom("")
+	require.NoError(t, err)
+
+	for i := 0; i < 100; i++ {
+		alias, err := g.Generate(context.Background(), 7)
+		require.NoError(t, err)
+		require.Len(t, alias, 7)
+		for _, c := range alias {
+			assert.True(t, strings.ContainsRune(DefaultAlphabet, c), "unexpected character %q", c)
+		}
+	}
+
+	_, err = g.Generate(context.Background(), 0)
+	assert.ErrorIs(t, err, ErrInvalidLength)
+}
+
+func TestNewRandom_InvalidAlphabet(t *testing.T) {
+	for _, alphabet := range []string{"abc", "abcdefghijklmnopa", "abcdefghijklmno-", "абвгдеёжзийклмноп"} {
+		_, err := NewRandom(alphabet)
+		assert.ErrorIs(t, err, ErrInvalidAlphabet, alphabet)
+	}
+}
+
+func TestSequential(t *testing.T) {
+	g := NewSequential(counter(61))
+	ctx := context.Background()
+
+	tests := []struct {
+		length int
+		want   string
+	}{
+		{length: 1, want: "Z"},
+		{length: 1, want: "10"},
+		{length: 4, want: "0011"},
+	}
+	for _, tt := range tests {
+		alias, err := g.Generate(ctx, tt.length)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, alias)
+	}
+
+	failing := NewSequential(func(ctx context.Context) (int64, error) { return 0, errors.New("boom") })
+	_, err := failing.Generate(ctx, 4)
+	assert.Error(t, err)
+}
+
+func TestHashids_ReferenceVectors(t *testing.T) {
+	g, err := NewHashids(nil, "this is my salt", hashidsAlphabet)
+	require.NoError(t, err)
+
+	assert.Equal(t, "NkK9", g.Encode(12345, 0))
+	assert.Equal(t, "gB0NV05e", g.Encode(1, 8))
+}
+
+func TestHashids_Unique(t *testing.T) {
+	g, err := NewHashids(counter(0), "salt", "")
+	require.NoError(t, err)
+
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		alias, err := g.Generate(context.Background(), 6)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, len(alias), 6)
+		require.False(t, seen[alias], "duplicate alias %q", alias)
+		seen[alias] = true
+	}
+}
+
+func TestWords(t *testing.T) {
+	g := NewWords()
+	ctx := context.Background()
+
+	tests := []struct {
+		length int
+		parts  int
+	}{
+		{length: 6, parts: 2},
+		{length: 8, parts: 3},
+		{length: 12, parts: 4},
+	}
+	for _, tt := range tests {
+		alias, err := g.Generate(ctx, tt.length)
+		require.NoError(t, err)
+		assert.Len(t, strings.Split(alias, WordSeparator), tt.parts, alias)
+		assert.LessOrEqual(t, len(alias), 20, alias)
+	}
+}
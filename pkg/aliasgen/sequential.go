@@ -0,0 +1,184 @@
+package aliasgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Base62Alphabet is the digit order used by the sequential strategy.
+const Base62Alphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+var ErrNegativeSequence = errors.New("sequence returned a negative value")
+
+// Sequential encodes consecutive sequence values in base62. Aliases are padded
+// with leading zeros to the requested length, so they are short and dense
+// but reveal how many links were created.
+type Sequential struct {
+	next SequenceFunc
+}
+
+// NewSequential returns a sequential base62 generator.
+func NewSequential(next SequenceFunc) *Sequential {
+	return &Sequential{next: next}
+}
+
+// Generate returns the next sequence value in base62, at least length characters long.
+func (g *Sequential) Generate(ctx context.Context, length int) (string, error) {
+	if length <= 0 {
+		return "", ErrInvalidLength
+	}
+	n, err := nextValue(ctx, g.next)
+	if err != nil {
+		return "", err
+	}
+	alias := encodeNumber(n, Base62Alphabet)
+	if len(alias) < length {
+		alias = strings.Repeat(Base62Alphabet[:1], length-len(alias)) + alias
+	}
+	return alias, nil
+}
+
+// hashidsSeparators are the default hashids separator characters.
+const hashidsSeparators = "cfhistuCFHISTU"
+
+// Hashids encodes consecutive sequence values with the hashids algorithm: the
+// aliases stay unique and reversible with the salt but do not reveal the order
+// or number of links.
+type Hashids struct {
+	next     SequenceFunc
+	salt     string
+	alphabet string
+	guards   string
+}
+
+// NewHashids returns a hashids generator. An empty alphabet selects DefaultAlphabet.
+// The salt must stay the same for the lifetime of the data, otherwise new aliases
+// may repeat the ones created with the previous salt.
+func NewHashids(next SequenceFunc, salt, alphabet string) (*Hashids, error) {
+	if alphabet == "" {
+		alphabet = DefaultAlphabet
+	}
+	if err := validateAlphabet(alphabet); err != nil {
+		return nil, err
+	}
+
+	// separators are taken out of the alphabet; a single encoded number never
+	// contains them, but they must be removed to stay compatible with hashids
+	var seps, rest []byte
+	for i := 0; i < len(hashidsSeparators); i++ {
+		if strings.IndexByte(alphabet, hashidsSeparators[i]) >= 0 {
+			seps = append(seps, hashidsSeparators[i])
+		}
+	}
+	for i := 0; i < len(alphabet); i++ {
+		if strings.IndexByte(hashidsSeparators, alphabet[i]) < 0 {
+			rest = append(rest, alphabet[i])
+		}
+	}
+	consistentShuffle(seps, salt)
+
+	if len(seps) == 0 || float64(len(rest))/float64(len(seps)) > 3.5 {
+		sepsLength := int(math.Ceil(float64(len(rest)) / 3.5))
+		if sepsLength == 1 {
+			sepsLength = 2
+		}
+		if sepsLength > len(seps) {
+			diff := sepsLength - len(seps)
+			seps = append(seps, rest[:diff]...)
+			rest = rest[diff:]
+		} else {
+			seps = seps[:sepsLength]
+		}
+	}
+	consistentShuffle(rest, salt)
+
+	guardCount := int(math.Ceil(float64(len(rest)) / 12))
+	var guards []byte
+	if len(rest) < 3 {
+		guards = seps[:guardCount]
+	} else {
+		guards, rest = rest[:guardCount], rest[guardCount:]
+	}
+
+	return &Hashids{
+		next:     next,
+		salt:     salt,
+		alphabet: string(rest),
+		guards:   string(guards),
+	}, nil
+}
+
+// Generate returns the hashids encoding of the next sequence value, at least length characters long.
+func (g *Hashids) Generate(ctx context.Context, length int) (string, error) {
+	if length <= 0 {
+		return "", ErrInvalidLength
+	}
+	n, err := nextValue(ctx, g.next)
+	if err != nil {
+		return "", err
+	}
+	return g.Encode(n, length), nil
+}
+
+// Encode returns the hashids encoding of a single non-negative number padded to minLength.
+func (g *Hashids) Encode(n int64, minLength int) string {
+	alphabet := []byte(g.alphabet)
+	numberHash := n % 100
+
+	lottery := alphabet[numberHash%int64(len(alphabet))]
+	buffer := append([]byte{lottery}, g.salt...)
+	buffer = append(buffer, alphabet...)
+	consistentShuffle(alphabet, string(buffer[:len(alphabet)]))
+	result := []byte{lottery}
+	result = append(result, encodeNumber(n, string(alphabet))...)
+
+	if len(result) < minLength {
+		guardIndex := (numberHash + int64(result[0])) % int64(len(g.guards))
+		result = append([]byte{g.guards[guardIndex]}, result...)
+		if len(result) < minLength {
+			guardIndex = (numberHash + int64(result[2])) % int64(len(g.guards))
+			result = append(result, g.guards[guardIndex])
+		}
+	}
+
+	half := len(alphabet) / 2
+	for len(result) < minLength {
+		consistentShuffle(alphabet, string(alphabet))
+		padded := append([]byte{}, alphabet[half:]...)
+		padded = append(padded, result...)
+		padded = append(padded, alphabet[:half]...)
+		result = padded
+		if excess := len(result) - minLength; excess > 0 {
+			result = result[excess/2 : excess/2+minLength]
+		}
+	}
+	return string(result)
+}
+
+// consistentShuffle permutes the alphabet in place deterministically by the salt.
+func consistentShuffle(alphabet []byte, salt string) {
+	if salt == "" {
+		return
+	}
+	for i, v, p := len(alphabet)-1, 0, 0; i > 0; i, v = i-1, v+1 {
+		v %= len(salt)
+		p += int(salt[v])
+		j := (int(salt[v]) + v + p) % i
+		alphabet[i], alphabet[j] = alphabet[j], alphabet[i]
+	}
+}
+
+// nextValue reads the next sequence value.
+func nextValue(ctx context.Context, next SequenceFunc) (int64, error) {
+	n, err := next(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get next sequence value: %w", err)
+	}
+	if n < 0 {
+		return 0, ErrNegativeSequence
+	}
+	return n, nil
+}
@@ -0,0 +1,79 @@
+package aliasgen
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// WordSeparator joins the words of a word alias.
+const WordSeparator = "-"
+
+// adjectives and nouns are short, neutral English words of 3-5 lowercase letters,
+// so that the longest alias ("adjective-adjective-noun-NN") fits into 20 characters.
+var adjectives = []string{
+	"able", "acid", "aged", "airy", "amber", "ample", "azure", "bald", "basic", "bold",
+	"brave", "brief", "brisk", "broad", "calm", "chief", "civil", "clean", "clear", "close",
+	"cool", "cozy", "crisp", "curly", "cute", "daily", "dark", "deep", "dense", "eager",
+	"early", "easy", "equal", "exact", "extra", "fair", "fancy", "fast", "fine", "firm",
+	"first", "fluid", "fresh", "full", "giant", "glad", "gold", "grand", "great", "green",
+	"happy", "hardy", "huge", "ideal", "inner", "jolly", "juicy", "keen", "kind", "large",
+	"lazy", "light", "lush", "loud", "lucky", "major", "merry", "mild", "minor", "misty",
+	"neat", "new", "noble", "odd", "olive", "open", "outer", "pale", "plain", "polar",
+	"proud", "pure", "quick", "quiet", "rapid", "rare", "ready", "rich", "round", "royal",
+	"rural", "safe", "sharp", "shiny", "silky", "slim", "smart", "soft", "solid", "sunny",
+}
+
+var nouns = []string{
+	"acorn", "apple", "arch", "badge", "bay", "beach", "bear", "bell", "birch", "bird",
+	"boat", "brook", "cabin", "cake", "cedar", "chalk", "cliff", "cloud", "coast", "comet",
+	"coral", "crane", "creek", "crown", "daisy", "delta", "dove", "dune", "eagle", "elm",
+	"fern", "field", "finch", "fjord", "flame", "fox", "frog", "frost", "gate", "gem",
+	"glade", "grove", "gull", "heron", "hawk", "hill", "honey", "horse", "iris", "inlet",
+	"jade", "kite", "lake", "lark", "leaf", "lemon", "lily", "lion", "maple", "marsh",
+	"mango", "moon", "moss", "moth", "oak", "ocean", "otter", "owl", "panda", "path",
+	"peach", "pearl", "pine", "plum", "pond", "quail", "rain", "reef", "ridge", "river",
+	"robin", "rock", "rose", "sage", "sand", "seal", "shell", "sky", "snow", "star",
+	"stone", "storm", "swan", "tide", "tiger", "trail", "tulip", "wave", "whale", "wolf",
+}
+
+// Words generates human-readable aliases such as "calm-otter" or
+// "brave-misty-comet-42" from embedded word lists.
+type Words struct{}
+
+// NewWords returns a word generator.
+func NewWords() *Words {
+	return &Words{}
+}
+
+// Generate picks the alias shape by length: below 8 characters two words are
+// used, below 12 three words and otherwise three words with a two-digit suffix.
+// Longer targets give a larger alias space, so the adaptive length of the caller
+// works for word aliases as well.
+func (g *Words) Generate(ctx context.Context, length int) (string, error) {
+	if length <= 0 {
+		return "", ErrInvalidLength
+	}
+
+	lists := [][]string{adjectives, nouns}
+	if length >= 8 {
+		lists = [][]string{adjectives, adjectives, nouns}
+	}
+
+	parts := make([]string, 0, len(lists)+1)
+	for _, list := range lists {
+		n, err := randomInt(len(list))
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, list[n])
+	}
+	if length >= 12 {
+		n, err := randomInt(100)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, fmt.Sprintf("%02d", n))
+	}
+	return strings.Join(parts, WordSeparator), nil
+}
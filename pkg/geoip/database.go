@@ -0,0 +1,178 @@
+package geoip
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Location is the result of an IP lookup. Empty fields mean the value is unknown.
+type Location struct {
+	Country string // ISO 3166-1 alpha-2 code, upper case
+	City    string // English city name
+}
+
+// Database is a MaxMind DB file that can be replaced on disk and reloaded without a restart.
+// Lookups keep working with the previous version until the new file is loaded successfully.
+// A missing file is not an error: lookups return an empty Location until the file appears.
+type Database struct {
+	path string
+	log  *zap.Logger
+
+	reader atomic.Pointer[Reader]
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// Open loads the database at path. When the file is missing or broken the database starts
+// empty and picks the file up on the next successful reload.
+func Open(path string, log *zap.Logger) *Database {
+	db := &Database{path: path, log: log}
+	if _, err := db.reloadIfChanged(); err != nil {
+		if os.IsNotExist(err) {
+			log.Warn("GeoIP database not found, geo lookups are disabled until it appears", zap.String("path", path))
+		} else {
+			log.Error("failed to load GeoIP database, geo lookups are disabled", zap.String("path", path), zap.Error(err))
+		}
+	}
+	return db
+}
+
+// Lookup returns the location of ip. Unknown addresses and lookup errors yield an empty Location.
+func (d *Database) Lookup(ip net.IP) Location {
+	reader := d.reader.Load()
+	if reader == nil || ip == nil {
+		return Location{}
+	}
+
+	record, err := reader.Lookup(ip)
+	if err != nil {
+		d.log.Debug("GeoIP lookup failed", zap.String("ip", ip.String()), zap.Error(err))
+		return Location{}
+	}
+	return locationFromRecord(record)
+}
+
+// Loaded reports whether a database file is currently loaded.
+func (d *Database) Loaded() bool {
+	return d.reader.Load() != nil
+}
+
+// Reload reads the file again if its size or modification time has changed.
+func (d *Database) Reload() error {
+	_, err := d.reloadIfChanged()
+	return err
+}
+
+// StartWatching checks the file for changes every interval and reloads it when it changes.
+func (d *Database) StartWatching(interval time.Duration) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.cancel != nil {
+		return fmt.Errorf("GeoIP watcher already started")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	d.done = make(chan struct{})
+
+	go d.watch(ctx, interval, d.done)
+	return nil
+}
+
+// StopWatching stops the file watcher.
+func (d *Database) StopWatching() {
+	d.mu.Lock()
+	cancel, done := d.cancel, d.done
+	d.cancel, d.done = nil, nil
+	d.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+func (d *Database) watch(ctx context.Context, interval time.Duration, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := d.reloadIfChanged(); err != nil && !os.IsNotExist(err) {
+				d.log.Error("failed to reload GeoIP database", zap.String("path", d.path), zap.Error(err))
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// reloadIfChanged loads the file when it differs from the loaded version and reports whether it did.
+func (d *Database) reloadIfChanged() (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	info, err := os.Stat(d.path)
+	if err != nil {
+		return false, err
+	}
+	if !d.modTime.IsZero() && info.ModTime().Equal(d.modTime) && info.Size() == d.size {
+		return false, nil
+	}
+
+	buffer, err := os.ReadFile(d.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read GeoIP database: %w", err)
+	}
+	reader, err := NewReader(buffer)
+	if err != nil {
+		// remember the broken version so it is not re-parsed on every tick
+		d.modTime, d.size = info.ModTime(), info.Size()
+		return false, err
+	}
+
+	d.reader.Store(reader)
+	d.modTime, d.size = info.ModTime(), info.Size()
+	d.log.Info("GeoIP database loaded",
+		zap.String("path", d.path),
+		zap.String("type", reader.DatabaseType()),
+		zap.Time("modified_at", info.ModTime()))
+	return true, nil
+}
+
+// locationFromRecord extracts the country code and the English city name from a GeoIP2 record.
+func locationFromRecord(record map[string]any) Location {
+	var location Location
+
+	country, _ := record["country"].(map[string]any)
+	if country == nil {
+		country, _ = record["registered_country"].(map[string]any)
+	}
+	if code, ok := country["iso_code"].(string); ok {
+		location.Country = strings.ToUpper(code)
+	}
+
+	if city, ok := record["city"].(map[string]any); ok {
+		if names, ok := city["names"].(map[string]any); ok {
+			location.City, _ = names["en"].(string)
+		}
+	}
+
+	return location
+}
@@ -0,0 +1,386 @@
+// Package geoip resolves IP addresses to a country and city using a local
+// MaxMind DB (.mmdb) file such as GeoLite2-City or GeoLite2-Country.
+package geoip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+)
+
+var (
+	ErrInvalidDatabase = errors.New("invalid MaxMind DB file")
+	ErrInvalidIP       = errors.New("invalid IP address")
+)
+
+// metadataStart marks the beginning of the metadata section at the end of the file.
+var metadataStart = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// dataSectionSeparator is the size of the zero gap between the search tree and the data section.
+const dataSectionSeparator = 16
+
+// maxDecodeDepth protects the decoder from pointer loops in a corrupted file.
+const maxDecodeDepth = 32
+
+// data types of the MaxMind DB data section
+const (
+	typeExtended  = 0
+	typePointer   = 1
+	typeString    = 2
+	typeDouble    = 3
+	typeBytes     = 4
+	typeUint16    = 5
+	typeUint32    = 6
+	typeMap       = 7
+	typeInt32     = 8
+	typeUint64    = 9
+	typeUint128   = 10
+	typeArray     = 11
+	typeContainer = 12
+	typeEnd       = 13
+	typeBool      = 14
+	typeFloat     = 15
+)
+
+// Reader is a read-only in-memory MaxMind DB. It is safe for concurrent use.
+type Reader struct {
+	buffer     []byte
+	data       []byte
+	nodeCount  uint
+	recordSize uint
+	ipVersion  uint
+	ipv4Start  uint
+	dbType     string
+}
+
+// NewReader parses a MaxMind DB from its raw bytes.
+func NewReader(buffer []byte) (*Reader, error) {
+	metaIndex := bytes.LastIndex(buffer, metadataStart)
+	if metaIndex == -1 {
+		return nil, fmt.Errorf("%w: metadata not found", ErrInvalidDatabase)
+	}
+	metaStart := metaIndex + len(metadataStart)
+
+	meta, _, err := (&decoder{buffer: buffer[metaStart:]}).decode(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDatabase, err)
+	}
+	metadata, ok := meta.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: metadata is not a map", ErrInvalidDatabase)
+	}
+
+	r := &Reader{
+		buffer:     buffer,
+		nodeCount:  uint(toUint(metadata["node_count"])),
+		recordSize: uint(toUint(metadata["record_size"])),
+		ipVersion:  uint(toUint(metadata["ip_version"])),
+	}
+	r.dbType, _ = metadata["database_type"].(string)
+
+	if r.recordSize != 24 && r.recordSize != 28 && r.recordSize != 32 {
+		return nil, fmt.Errorf("%w: unsupported record size %d", ErrInvalidDatabase, r.recordSize)
+	}
+	if r.ipVersion != 4 && r.ipVersion != 6 {
+		return nil, fmt.Errorf("%w: unsupported IP version %d", ErrInvalidDatabase, r.ipVersion)
+	}
+
+	treeSize := r.nodeCount * r.recordSize / 4
+	dataStart := treeSize + dataSectionSeparator
+	if r.nodeCount == 0 || dataStart > uint(metaIndex) {
+		return nil, fmt.Errorf("%w: search tree is out of bounds", ErrInvalidDatabase)
+	}
+	r.data = buffer[dataStart:metaIndex]
+
+	// IPv4 addresses live under ::/96 in IPv6 databases
+	if r.ipVersion == 6 {
+		node := uint(0)
+		for i := 0; i < 96 && node < r.nodeCount; i++ {
+			node, err = r.readNode(node, 0)
+			if err != nil {
+				return nil, err
+			}
+		}
+		r.ipv4Start = node
+	}
+
+	return r, nil
+}
+
+// DatabaseType returns the database_type from the metadata, e.g. "GeoLite2-City".
+func (r *Reader) DatabaseType() string {
+	return r.dbType
+}
+
+// Lookup returns the decoded record for ip, or nil when the database has no data for it.
+func (r *Reader) Lookup(ip net.IP) (map[string]any, error) {
+	node, bitCount, err := r.startNode(ip)
+	if err != nil {
+		return nil, err
+	}
+
+	address := ip.To4()
+	if bitCount == 128 {
+		address = ip.To16()
+	}
+
+	for i := 0; i < bitCount && node < r.nodeCount; i++ {
+		bit := uint(address[i>>3]>>(7-uint(i&7))) & 1
+		if node, err = r.readNode(node, bit); err != nil {
+			return nil, err
+		}
+	}
+
+	if node == r.nodeCount {
+		return nil, nil
+	}
+	if node < r.nodeCount {
+		return nil, fmt.Errorf("%w: search tree is too deep", ErrInvalidDatabase)
+	}
+
+	offset := node - r.nodeCount - dataSectionSeparator
+	if offset >= uint(len(r.data)) {
+		return nil, fmt.Errorf("%w: data pointer is out of bounds", ErrInvalidDatabase)
+	}
+
+	value, _, err := (&decoder{buffer: r.data}).decode(offset, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDatabase, err)
+	}
+	record, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: record is not a map", ErrInvalidDatabase)
+	}
+	return record, nil
+}
+
+// startNode returns the node to start the search from and the number of address bits to walk.
+func (r *Reader) startNode(ip net.IP) (uint, int, error) {
+	if ip4 := ip.To4(); ip4 != nil {
+		if r.ipVersion == 4 {
+			return 0, 32, nil
+		}
+		return r.ipv4Start, 32, nil
+	}
+	if ip.To16() == nil {
+		return 0, 0, ErrInvalidIP
+	}
+	if r.ipVersion == 4 {
+		// IPv6 addresses cannot be found in an IPv4-only database
+		return r.nodeCount, 0, nil
+	}
+	return 0, 128, nil
+}
+
+// readNode returns the left (bit 0) or right (bit 1) record of a search tree node.
+func (r *Reader) readNode(node, bit uint) (uint, error) {
+	offset := node * r.recordSize / 4
+	if offset+r.recordSize/4 > uint(len(r.buffer)) {
+		return 0, fmt.Errorf("%w: node is out of bounds", ErrInvalidDatabase)
+	}
+	b := r.buffer[offset:]
+
+	switch r.recordSize {
+	case 24:
+		b = b[bit*3:]
+		return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2]), nil
+	case 28:
+		if bit == 0 {
+			return uint(b[3]&0xF0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2]), nil
+		}
+		return uint(b[3]&0x0F)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6]), nil
+	default:
+		return uint(binary.BigEndian.Uint32(b[bit*4:])), nil
+	}
+}
+
+// decoder decodes values of the MaxMind DB data section into Go values:
+// map[string]any, []any, string, []byte, float64, float32, uint64, int32, bool.
+// uint128 values are returned as []byte.
+type decoder struct {
+	buffer []byte
+}
+
+// decode decodes the value at offset and returns it with the offset of the next value.
+func (d *decoder) decode(offset uint, depth int) (any, uint, error) {
+	if depth > maxDecodeDepth {
+		return nil, 0, errors.New("data is nested too deeply")
+	}
+
+	typ, size, offset, err := d.decodeControl(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if typ == typePointer {
+		pointer, next, err := d.decodePointer(size, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		value, _, err := d.decode(pointer, depth+1)
+		return value, next, err
+	}
+
+	switch typ {
+	case typeMap:
+		result := make(map[string]any, size)
+		for i := uint(0); i < size; i++ {
+			key, next, err := d.decode(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			keyString, ok := key.(string)
+			if !ok {
+				return nil, 0, errors.New("map key is not a string")
+			}
+			value, next, err := d.decode(next, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			result[keyString] = value
+			offset = next
+		}
+		return result, offset, nil
+	case typeArray:
+		result := make([]any, 0, size)
+		for i := uint(0); i < size; i++ {
+			value, next, err := d.decode(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			result = append(result, value)
+			offset = next
+		}
+		return result, offset, nil
+	case typeBool:
+		return size != 0, offset, nil
+	}
+
+	end := offset + size
+	if end > uint(len(d.buffer)) || end < offset {
+		return nil, 0, errors.New("value is out of bounds")
+	}
+	raw := d.buffer[offset:end]
+
+	switch typ {
+	case typeString:
+		return string(raw), end, nil
+	case typeBytes, typeUint128:
+		return append([]byte(nil), raw...), end, nil
+	case typeDouble:
+		if size != 8 {
+			return nil, 0, errors.New("invalid double size")
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(raw)), end, nil
+	case typeFloat:
+		if size != 4 {
+			return nil, 0, errors.New("invalid float size")
+		}
+		return math.Float32frombits(binary.BigEndian.Uint32(raw)), end, nil
+	case typeUint16, typeUint32, typeUint64:
+		if size > 8 {
+			return nil, 0, errors.New("invalid unsigned integer size")
+		}
+		var value uint64
+		for _, b := range raw {
+			value = value<<8 | uint64(b)
+		}
+		return value, end, nil
+	case typeInt32:
+		if size > 4 {
+			return nil, 0, errors.New("invalid int32 size")
+		}
+		var value uint32
+		for _, b := range raw {
+			value = value<<8 | uint32(b)
+		}
+		return int32(value), end, nil
+	default:
+		return nil, 0, fmt.Errorf("unsupported data type %d", typ)
+	}
+}
+
+// decodeControl reads the control byte(s) and returns the type, the payload size and the payload offset.
+func (d *decoder) decodeControl(offset uint) (int, uint, uint, error) {
+	if offset >= uint(len(d.buffer)) {
+		return 0, 0, 0, errors.New("unexpected end of data")
+	}
+	ctrl := d.buffer[offset]
+	offset++
+
+	typ := int(ctrl >> 5)
+	if typ == typePointer {
+		return typ, uint(ctrl & 0x1F), offset, nil
+	}
+	if typ == typeExtended {
+		if offset >= uint(len(d.buffer)) {
+			return 0, 0, 0, errors.New("unexpected end of data")
+		}
+		typ = 7 + int(d.buffer[offset])
+		offset++
+		if typ < typeInt32 {
+			return 0, 0, 0, fmt.Errorf("invalid extended type %d", typ)
+		}
+	}
+
+	size := uint(ctrl & 0x1F)
+	if size < 29 {
+		return typ, size, offset, nil
+	}
+
+	extra := size - 28
+	if offset+extra > uint(len(d.buffer)) {
+		return 0, 0, 0, errors.New("unexpected end of data")
+	}
+	var value uint
+	for _, b := range d.buffer[offset : offset+extra] {
+		value = value<<8 | uint(b)
+	}
+	offset += extra
+
+	switch extra {
+	case 1:
+		size = 29 + value
+	case 2:
+		size = 285 + value
+	default:
+		size = 65821 + value
+	}
+	return typ, size, offset, nil
+}
+
+// decodePointer resolves a pointer whose control bits are ctrlBits and returns
+// the data offset it points to and the offset after the pointer.
+func (d *decoder) decodePointer(ctrlBits, offset uint) (uint, uint, error) {
+	pointerSize := (ctrlBits >> 3) + 1
+	if offset+pointerSize > uint(len(d.buffer)) {
+		return 0, 0, errors.New("unexpected end of data")
+	}
+	raw := d.buffer[offset : offset+pointerSize]
+
+	var prefix uint
+	if pointerSize != 4 {
+		prefix = ctrlBits & 0x7
+	}
+	value := prefix
+	for _, b := range raw {
+		value = value<<8 | uint(b)
+	}
+
+	switch pointerSize {
+	case 2:
+		value += 2048
+	case 3:
+		value += 526336
+	}
+	return value, offset + pointerSize, nil
+}
+
+// toUint converts a decoded unsigned integer metadata value.
+func toUint(value any) uint64 {
+	v, _ := value.(uint64)
+	return v
+}
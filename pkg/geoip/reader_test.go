@@ -0,0 +1,213 @@
+package geoip
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestReader_Lookup(t *testing.T) {
+	for _, recordSize := range []int{24, 28, 32} {
+		t.Run("record size "+strconv.Itoa(recordSize), func(t *testing.T) {
+			reader, err := NewReader(buildTestDatabase(t, recordSize))
+			require.NoError(t, err)
+			assert.Equal(t, "Test-City", reader.DatabaseType())
+
+			tests := []struct {
+				ip   string
+				want Location
+			}{
+				{ip: "81.2.69.142", want: Location{Country: "RU", City: "Moscow"}},
+				{ip: "81.2.255.1", want: Location{Country: "RU", City: "Moscow"}},
+				{ip: "2.16.0.1", want: Location{Country: "KZ"}},
+				{ip: "2.17.3.4", want: Location{Country: "KZ"}},
+				{ip: "175.16.199.1", want: Location{Country: "BY", City: "Minsk"}},
+				{ip: "2001:db8::1", want: Location{Country: "DE"}},
+				{ip: "8.8.8.8", want: Location{}},
+				{ip: "2001:db9::1", want: Location{}},
+			}
+			for _, tt := range tests {
+				record, err := reader.Lookup(net.ParseIP(tt.ip))
+				require.NoError(t, err, tt.ip)
+				assert.Equal(t, tt.want, locationFromRecord(record), tt.ip)
+			}
+		})
+	}
+}
+
+func TestReader_InvalidDatabase(t *testing.T) {
+	_, err := NewReader([]byte("not a database"))
+	assert.ErrorIs(t, err, ErrInvalidDatabase)
+
+	data := buildTestDatabase(t, 24)
+	_, err = NewReader(data[len(data)/2:])
+	assert.ErrorIs(t, err, ErrInvalidDatabase)
+}
+
+func TestDatabase_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "geo.mmdb")
+	db := Open(path, zap.NewNop())
+	assert.False(t, db.Loaded())
+	assert.Equal(t, Location{}, db.Lookup(net.ParseIP("81.2.69.142")))
+
+	require.NoError(t, os.WriteFile(path, buildTestDatabase(t, 24), 0o644))
+	require.NoError(t, db.Reload())
+	assert.True(t, db.Loaded())
+	assert.Equal(t, "RU", db.Lookup(net.ParseIP("81.2.69.142")).Country)
+
+	// a broken replacement keeps the previous version in use
+	require.NoError(t, os.WriteFile(path, []byte("broken"), 0o644))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Minute)))
+	assert.Error(t, db.Reload())
+	assert.Equal(t, "RU", db.Lookup(net.ParseIP("81.2.69.142")).Country)
+}
+
+// testNetwork is a network of the generated test database with its record.
+type testNetwork struct {
+	cidr   string
+	record map[string]any
+}
+
+var testNetworks = []testNetwork{
+	{cidr: "81.2.0.0/16", record: map[string]any{
+		"country": map[string]any{"iso_code": "RU"},
+		"city":    map[string]any{"names": map[string]any{"en": "Moscow", "ru": "Москва"}},
+	}},
+	{cidr: "2.16.0.0/15", record: map[string]any{
+		"country": map[string]any{"iso_code": "KZ"},
+	}},
+	{cidr: "175.16.199.0/24", record: map[string]any{
+		"registered_country": map[string]any{"iso_code": "by"},
+		"city":               map[string]any{"names": map[string]any{"en": "Minsk"}},
+	}},
+	{cidr: "2001:db8::/32", record: map[string]any{
+		"country": map[string]any{"iso_code": "DE"},
+	}},
+}
+
+// testNode is a node of the search tree being built; a leaf has a data offset.
+type testNode struct {
+	children [2]*testNode
+	leaf     bool
+	offset   int
+}
+
+// buildTestDatabase writes an IPv6 MaxMind DB with testNetworks; IPv4 networks are stored under ::/96.
+func buildTestDatabase(t *testing.T, recordSize int) []byte {
+	t.Helper()
+
+	var dataSection bytes.Buffer
+	root := &testNode{}
+	for _, network := range testNetworks {
+		_, ipNet, err := net.ParseCIDR(network.cidr)
+		require.NoError(t, err)
+		ones, bits := ipNet.Mask.Size()
+		address := ipNet.IP.To16()
+		if bits == 32 {
+			address = append(make([]byte, 12), ipNet.IP.To4()...)
+			ones += 96
+		}
+
+		offset := dataSection.Len()
+		encodeValue(&dataSection, network.record)
+
+		node := root
+		for i := 0; i < ones; i++ {
+			bit := address[i/8] >> (7 - uint(i%8)) & 1
+			if node.children[bit] == nil {
+				node.children[bit] = &testNode{}
+			}
+			node = node.children[bit]
+		}
+		node.leaf, node.offset = true, offset
+	}
+
+	// number the internal nodes in breadth-first order
+	var nodes []*testNode
+	ids := map[*testNode]int{}
+	queue := []*testNode{root}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		if node.leaf {
+			continue
+		}
+		ids[node] = len(nodes)
+		nodes = append(nodes, node)
+		for _, child := range node.children {
+			if child != nil {
+				queue = append(queue, child)
+			}
+		}
+	}
+
+	nodeCount := len(nodes)
+	recordValue := func(child *testNode) int {
+		switch {
+		case child == nil:
+			return nodeCount
+		case child.leaf:
+			return nodeCount + dataSectionSeparator + child.offset
+		default:
+			return ids[child]
+		}
+	}
+
+	var out bytes.Buffer
+	for _, node := range nodes {
+		left, right := recordValue(node.children[0]), recordValue(node.children[1])
+		switch recordSize {
+		case 24:
+			out.Write([]byte{byte(left >> 16), byte(left >> 8), byte(left), byte(right >> 16), byte(right >> 8), byte(right)})
+		case 28:
+			out.Write([]byte{byte(left >> 16), byte(left >> 8), byte(left), byte(left>>24)<<4 | byte(right>>24)&0x0F, byte(right >> 16), byte(right >> 8), byte(right)})
+		case 32:
+			out.Write([]byte{byte(left >> 24), byte(left >> 16), byte(left >> 8), byte(left), byte(right >> 24), byte(right >> 16), byte(right >> 8), byte(right)})
+		}
+	}
+	out.Write(make([]byte, dataSectionSeparator))
+	out.Write(dataSection.Bytes())
+	out.Write(metadataStart)
+	encodeValue(&out, map[string]any{
+		"node_count":    uint32(nodeCount),
+		"record_size":   uint16(recordSize),
+		"ip_version":    uint16(6),
+		"database_type": "Test-City",
+	})
+	return out.Bytes()
+}
+
+// encodeValue writes a value in the MaxMind DB data format; only the types used by the tests are supported.
+func encodeValue(buf *bytes.Buffer, value any) {
+	switch v := value.(type) {
+	case string:
+		buf.WriteByte(typeString<<5 | byte(len(v)))
+		buf.WriteString(v)
+	case uint16:
+		buf.Write([]byte{typeUint16<<5 | 2, byte(v >> 8), byte(v)})
+	case uint32:
+		buf.Write([]byte{typeUint32<<5 | 4, byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)})
+	case map[string]any:
+		buf.WriteByte(typeMap<<5 | byte(len(v)))
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			encodeValue(buf, key)
+			encodeValue(buf, v[key])
+		}
+	default:
+		panic("unsupported test value")
+	}
+}
@@ -0,0 +1,264 @@
+// Package linkcheck probes link destinations over HTTP. Prober limits how many
+// requests run against one host at a time and backs off hosts that fail or ask
+// to slow down, so a periodic check of many links does not hammer any site.
+package linkcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ErrHostBackoff is returned in Result.Err when the destination host is backing
+// off after it asked to slow down (429) or answered 5xx, and no request was
+// made. Such results say nothing about the link and should not be recorded.
+var ErrHostBackoff = errors.New("host is backing off after recent failures")
+
+// ErrHostUnreachable is returned in Result.Err, wrapped with the last transport
+// error, when the destination host is backing off because it could not be
+// reached at all (DNS, connection or timeout error) and no request was made.
+// Unlike ErrHostBackoff this is a host-level failure: it applies to every link
+// on the host, and Result.Broken reports true.
+var ErrHostUnreachable = errors.New("host could not be reached on a recent probe")
+
+// maxDrainBytes is how much of a GET response body is read so the connection
+// can be reused.
+const maxDrainBytes = 64 << 10
+
+// Result is the outcome of a single probe.
+type Result struct {
+	StatusCode int           // final response status, 0 when no response was received
+	Latency    time.Duration // time until the final response headers, redirects included
+	FinalURL   string        // URL after following redirects
+	Err        error         // transport error, nil when a response was received
+}
+
+// Broken reports whether the destination looks dead: unreachable, gone or
+// failing on the server side. Other client errors such as 401, 403 or 429
+// usually mean the site is up but refuses automated requests.
+func (r Result) Broken() bool {
+	if r.Err != nil {
+		return true
+	}
+	return r.StatusCode == http.StatusNotFound || r.StatusCode == http.StatusGone || r.StatusCode >= 500
+}
+
+// Checker probes a destination URL.
+type Checker interface {
+	Check(ctx context.Context, rawURL string) Result
+}
+
+// Config controls request limits of a Prober. Zero values fall back to defaults.
+type Config struct {
+	Timeout            time.Duration // per probe, redirects included; default 10s
+	PerHostConcurrency int           // simultaneous requests to one host; default 2
+	BackoffBase        time.Duration // pause after the first failure, doubled after each next one; default 1m
+	BackoffMax         time.Duration // longest pause; default 1h
+	UserAgent          string
+}
+
+// Prober is a Checker that sends HEAD requests and falls back to GET when HEAD
+// is rejected. It is safe for concurrent use.
+type Prober struct {
+	client *http.Client
+	config Config
+	now    func() time.Time
+
+	mu    sync.Mutex
+	hosts map[string]*hostState
+}
+
+// hostState tracks request slots and backoff of one host.
+type hostState struct {
+	slots     chan struct{}
+	users     int // probes holding a reference; the state is dropped when unused and healthy
+	failures  int
+	retryAt   time.Time
+	lastError error // transport error of the last failure, nil when the host answered
+}
+
+// New creates a Prober that sends requests with client.
+func New(client *http.Client, config Config) *Prober {
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.PerHostConcurrency <= 0 {
+		config.PerHostConcurrency = 2
+	}
+	if config.BackoffBase <= 0 {
+		config.BackoffBase = time.Minute
+	}
+	if config.BackoffMax < config.BackoffBase {
+		config.BackoffMax = max(config.BackoffBase, time.Hour)
+	}
+	return &Prober{
+		client: client,
+		config: config,
+		now:    time.Now,
+		hosts:  make(map[string]*hostState),
+	}
+}
+
+// NewClient returns an HTTP client for probing. control is used as
+// net.Dialer.Control, e.g. urlpolicy.DialControl to refuse internal addresses;
+// it may be nil.
+func NewClient(timeout time.Duration, control func(network, address string, c syscall.RawConn) error) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: control}
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   timeout,
+		ResponseHeaderTimeout: timeout,
+		MaxIdleConnsPerHost:   2,
+		IdleConnTimeout:       90 * time.Second,
+	}
+	return &http.Client{Transport: transport, Timeout: timeout}
+}
+
+// Check implements Checker.
+func (p *Prober) Check(ctx context.Context, rawURL string) Result {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return Result{Err: err}
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return Result{Err: errors.New("url has no host")}
+	}
+
+	state, err := p.acquire(host)
+	if err != nil {
+		return Result{Err: err}
+	}
+	defer p.release(host, state)
+
+	select {
+	case state.slots <- struct{}{}:
+		defer func() { <-state.slots }()
+	case <-ctx.Done():
+		return Result{Err: ctx.Err()}
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, p.config.Timeout)
+	defer cancel()
+
+	started := p.now()
+	resp, err := p.do(probeCtx, http.MethodHead, rawURL)
+	if err != nil || resp.StatusCode >= 400 {
+		// Some servers do not implement HEAD or answer it differently
+		if resp != nil {
+			resp.Body.Close()
+		}
+		resp, err = p.do(probeCtx, http.MethodGet, rawURL)
+	}
+	result := Result{Latency: p.now().Sub(started), FinalURL: rawURL}
+	if err != nil {
+		result.Err = err
+		if ctx.Err() == nil {
+			p.record(state, 0, nil, err)
+		}
+		return result
+	}
+	defer resp.Body.Close()
+	_, _ = io.CopyN(io.Discard, resp.Body, maxDrainBytes)
+
+	result.StatusCode = resp.StatusCode
+	result.FinalURL = resp.Request.URL.String()
+	p.record(state, resp.StatusCode, resp.Header, nil)
+	return result
+}
+
+// do sends a single request; redirects are followed by the client.
+func (p *Prober) do(ctx context.Context, method, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if p.config.UserAgent != "" {
+		req.Header.Set("User-Agent", p.config.UserAgent)
+	}
+	req.Header.Set("Accept", "*/*")
+	return p.client.Do(req)
+}
+
+// acquire returns the state of host, or ErrHostBackoff or ErrHostUnreachable
+// while the host is backing off.
+func (p *Prober) acquire(host string) (*hostState, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	state := p.hosts[host]
+	if state == nil {
+		state = &hostState{slots: make(chan struct{}, p.config.PerHostConcurrency)}
+		p.hosts[host] = state
+	}
+	if p.now().Before(state.retryAt) {
+		if state.lastError != nil {
+			return nil, fmt.Errorf("%w: %v", ErrHostUnreachable, state.lastError)
+		}
+		return nil, ErrHostBackoff
+	}
+	state.users++
+	return state, nil
+}
+
+// release drops the reference taken by acquire.
+func (p *Prober) release(host string, state *hostState) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	state.users--
+	if state.users == 0 && state.failures == 0 {
+		delete(p.hosts, host)
+	}
+}
+
+// record updates the backoff of a host. Transport errors (probeErr), 429 and
+// 5xx responses count as failures; Retry-After is honoured when it asks for a
+// longer pause.
+func (p *Prober) record(state *hostState, statusCode int, header http.Header, probeErr error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	state.lastError = probeErr
+	if statusCode != 0 && statusCode != http.StatusTooManyRequests && statusCode < 500 {
+		state.failures = 0
+		state.retryAt = time.Time{}
+		return
+	}
+
+	state.failures++
+	pause := p.config.BackoffBase
+	for i := 1; i < state.failures && pause < p.config.BackoffMax; i++ {
+		pause *= 2
+	}
+	if retryAfter := parseRetryAfter(header, p.now()); retryAfter > pause {
+		pause = retryAfter
+	}
+	pause = min(pause, p.config.BackoffMax)
+	state.retryAt = p.now().Add(pause)
+}
+
+// parseRetryAfter reads the Retry-After header in seconds or as an HTTP date.
+func parseRetryAfter(header http.Header, now time.Time) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return at.Sub(now)
+	}
+	return 0
+}
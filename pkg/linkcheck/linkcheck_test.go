@@ -0,0 +1,135 @@
+package linkcheck
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProber_FollowsRedirectsAndFallsBackToGet(t *testing.T) {
+	var heads atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+		case "/new":
+			if r.Method == http.MethodHead {
+				heads.Add(1)
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			assert.Equal(t, "test-agent", r.UserAgent())
+			w.Write([]byte("ok"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	prober := New(server.Client(), Config{UserAgent: "test-agent"})
+
+	result := prober.Check(context.Background(), server.URL+"/old")
+	require.NoError(t, result.Err)
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+	assert.Equal(t, server.URL+"/new", result.FinalURL)
+	assert.False(t, result.Broken())
+	assert.Equal(t, int32(1), heads.Load())
+
+	result = prober.Check(context.Background(), server.URL+"/missing")
+	assert.Equal(t, http.StatusNotFound, result.StatusCode)
+	assert.True(t, result.Broken())
+}
+
+func TestProber_BacksOffFailingHost(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	prober := New(server.Client(), Config{BackoffBase: time.Minute, BackoffMax: time.Hour})
+	prober.now = func() time.Time { return now }
+
+	result := prober.Check(context.Background(), server.URL+"/a")
+	assert.Equal(t, http.StatusServiceUnavailable, result.StatusCode)
+	assert.True(t, result.Broken())
+	sent := requests.Load()
+
+	// Retry-After is longer than the first pause
+	now = now.Add(90 * time.Second)
+	result = prober.Check(context.Background(), server.URL+"/b")
+	assert.True(t, errors.Is(result.Err, ErrHostBackoff))
+	assert.Equal(t, sent, requests.Load())
+
+	now = now.Add(time.Minute)
+	result = prober.Check(context.Background(), server.URL+"/b")
+	assert.NoError(t, result.Err)
+	assert.Greater(t, requests.Load(), sent)
+}
+
+func TestProber_UnreachableHost(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	now := time.Now()
+	prober := New(server.Client(), Config{BackoffBase: time.Minute, BackoffMax: 30 * time.Minute})
+	prober.now = func() time.Time { return now }
+
+	result := prober.Check(context.Background(), server.URL+"/a")
+	require.Error(t, result.Err)
+	assert.False(t, errors.Is(result.Err, ErrHostUnreachable))
+	assert.True(t, result.Broken())
+
+	// siblings on the same host fail without a request while the host backs off
+	result = prober.Check(context.Background(), server.URL+"/b")
+	assert.True(t, errors.Is(result.Err, ErrHostUnreachable))
+	assert.False(t, errors.Is(result.Err, ErrHostBackoff))
+	assert.True(t, result.Broken())
+
+	now = now.Add(2 * time.Minute)
+	result = prober.Check(context.Background(), server.URL+"/b")
+	require.Error(t, result.Err)
+	assert.False(t, errors.Is(result.Err, ErrHostUnreachable), "a new request is made after the pause")
+}
+
+func TestProber_LimitsRequestsPerHost(t *testing.T) {
+	var active, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := active.Add(1)
+		defer active.Add(-1)
+		for {
+			seen := peak.Load()
+			if current <= seen || peak.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	prober := New(server.Client(), Config{PerHostConcurrency: 2})
+
+	done := make(chan struct{})
+	for i := 0; i < 8; i++ {
+		go func() {
+			prober.Check(context.Background(), server.URL)
+			done <- struct{}{}
+		}()
+	}
+	for i := 0; i < 8; i++ {
+		<-done
+	}
+
+	assert.LessOrEqual(t, peak.Load(), int32(2))
+	assert.Empty(t, prober.hosts, "healthy hosts are not kept after the probes finish")
+}
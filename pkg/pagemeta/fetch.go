@@ -0,0 +1,70 @@
+package pagemeta
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+)
+
+// DefaultMaxBytes is how much of a page Fetcher reads by default. The head of
+// real pages fits comfortably; the rest is never needed.
+const DefaultMaxBytes = 512 << 10
+
+// Fetcher downloads pages and parses their metadata. The client is expected to
+// carry the timeout and, for user-supplied URLs, a dialer that refuses internal
+// addresses.
+type Fetcher struct {
+	client    *http.Client
+	maxBytes  int64
+	userAgent string
+}
+
+// NewFetcher creates a Fetcher that reads at most maxBytes of each page
+// (DefaultMaxBytes when maxBytes <= 0).
+func NewFetcher(client *http.Client, maxBytes int64, userAgent string) *Fetcher {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBytes
+	}
+	return &Fetcher{client: client, maxBytes: maxBytes, userAgent: userAgent}
+}
+
+// Fetch downloads rawURL and parses its metadata. Non-2xx responses and
+// non-HTML content are errors.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (*Metadata, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.1")
+	if f.userAgent != "" {
+		req.Header.Set("User-Agent", f.userAgent)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || !strings.Contains(mediaType, "html") {
+			return nil, ErrNotHTML
+		}
+	}
+
+	body, err := charset.NewReader(io.LimitReader(resp.Body, f.maxBytes), contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode page: %w", err)
+	}
+	return Parse(body, resp.Request.URL)
+}
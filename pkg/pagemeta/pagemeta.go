@@ -0,0 +1,220 @@
+// Package pagemeta extracts the title, description, OpenGraph and Twitter card
+// tags and the favicon of an HTML page. Only the document head is parsed.
+package pagemeta
+
+import (
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// ErrNotHTML is returned by Fetcher for responses that are not HTML pages.
+var ErrNotHTML = errors.New("response is not an HTML page")
+
+// OpenGraph holds og:* properties.
+type OpenGraph struct {
+	Title       string
+	Description string
+	Image       string // absolute URL
+	SiteName    string
+	Type        string
+	URL         string
+}
+
+// TwitterCard holds twitter:* properties.
+type TwitterCard struct {
+	Card        string // summary, summary_large_image, ...
+	Title       string
+	Description string
+	Image       string // absolute URL
+	Site        string
+}
+
+// Metadata is what a page says about itself.
+type Metadata struct {
+	URL         string // page URL after redirects, relative URLs are resolved against it
+	Title       string // <title>
+	Description string // <meta name="description">
+	OpenGraph   OpenGraph
+	Twitter     TwitterCard
+	FaviconURL  string // <link rel="icon">, /favicon.ico when the page declares none
+}
+
+// BestTitle returns the OpenGraph title, falling back to the Twitter card and <title>.
+func (m *Metadata) BestTitle() string {
+	return firstNonEmpty(m.OpenGraph.Title, m.Twitter.Title, m.Title)
+}
+
+// BestDescription returns the OpenGraph description, falling back to the Twitter
+// card and the description meta tag.
+func (m *Metadata) BestDescription() string {
+	return firstNonEmpty(m.OpenGraph.Description, m.Twitter.Description, m.Description)
+}
+
+// BestImage returns the OpenGraph image, falling back to the Twitter card image.
+func (m *Metadata) BestImage() string {
+	return firstNonEmpty(m.OpenGraph.Image, m.Twitter.Image)
+}
+
+// Parse reads an HTML document up to the end of its head. base is the page URL
+// used to resolve relative links; it may be nil.
+func Parse(r io.Reader, base *url.URL) (*Metadata, error) {
+	meta := &Metadata{}
+	if base != nil {
+		meta.URL = base.String()
+	}
+
+	var (
+		appleTouchIcon string
+		inTitle        bool
+		title          strings.Builder
+	)
+
+	tokenizer := html.NewTokenizer(r)
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			if err := tokenizer.Err(); err != io.EOF {
+				return nil, err
+			}
+			return meta.finish(base, title.String(), appleTouchIcon), nil
+
+		case html.TextToken:
+			if inTitle {
+				title.Write(tokenizer.Text())
+			}
+
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			switch atom.Lookup(name) {
+			case atom.Title:
+				inTitle = false
+			case atom.Head:
+				return meta.finish(base, title.String(), appleTouchIcon), nil
+			}
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			switch token.DataAtom {
+			case atom.Body:
+				return meta.finish(base, title.String(), appleTouchIcon), nil
+			case atom.Title:
+				// the first <title> wins, <svg><title> and the like come later
+				inTitle = title.Len() == 0
+			case atom.Meta:
+				meta.applyMeta(token, base)
+			case atom.Link:
+				rel := strings.Fields(strings.ToLower(attr(token, "rel")))
+				href := resolve(base, attr(token, "href"))
+				if href == "" {
+					continue
+				}
+				for _, value := range rel {
+					if value == "icon" && meta.FaviconURL == "" {
+						meta.FaviconURL = href
+					}
+					if value == "apple-touch-icon" && appleTouchIcon == "" {
+						appleTouchIcon = href
+					}
+				}
+			}
+		}
+	}
+}
+
+// applyMeta stores a <meta> tag; OpenGraph uses property=, Twitter and the
+// description use name=, but sites mix them up, so both are accepted.
+func (m *Metadata) applyMeta(token html.Token, base *url.URL) {
+	key := strings.ToLower(strings.TrimSpace(firstNonEmpty(attr(token, "property"), attr(token, "name"))))
+	content := clean(attr(token, "content"))
+	if key == "" || content == "" {
+		return
+	}
+
+	targets := map[string]*string{
+		"description":         &m.Description,
+		"og:title":            &m.OpenGraph.Title,
+		"og:description":      &m.OpenGraph.Description,
+		"og:site_name":        &m.OpenGraph.SiteName,
+		"og:type":             &m.OpenGraph.Type,
+		"twitter:card":        &m.Twitter.Card,
+		"twitter:title":       &m.Twitter.Title,
+		"twitter:description": &m.Twitter.Description,
+		"twitter:site":        &m.Twitter.Site,
+	}
+	switch key {
+	case "og:image", "og:image:url", "og:image:secure_url":
+		targets[key] = &m.OpenGraph.Image
+		content = resolve(base, content)
+	case "twitter:image", "twitter:image:src":
+		targets[key] = &m.Twitter.Image
+		content = resolve(base, content)
+	case "og:url":
+		targets[key] = &m.OpenGraph.URL
+		content = resolve(base, content)
+	}
+
+	// the first occurrence wins, later ones are usually alternatives
+	if target, ok := targets[key]; ok && *target == "" {
+		*target = content
+	}
+}
+
+// finish fills values that depend on the whole head.
+func (m *Metadata) finish(base *url.URL, title, appleTouchIcon string) *Metadata {
+	m.Title = clean(title)
+	if m.FaviconURL == "" {
+		m.FaviconURL = appleTouchIcon
+	}
+	if m.FaviconURL == "" && base != nil {
+		m.FaviconURL = resolve(base, "/favicon.ico")
+	}
+	return m
+}
+
+// attr returns the value of an attribute of token.
+func attr(token html.Token, name string) string {
+	for _, a := range token.Attr {
+		if strings.EqualFold(a.Key, name) {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// resolve turns ref into an absolute http(s) URL; anything else is dropped.
+func resolve(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return ""
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ""
+	}
+	return u.String()
+}
+
+// clean collapses whitespace.
+func clean(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,104 @@
+package pagemeta
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPage = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>
+    Spring sale &amp; more
+  </title>
+  <meta name="description" content="Everything  is 50% off">
+  <meta property="og:title" content="Spring sale">
+  <meta property="og:image" content="/img/sale.png">
+  <meta property="og:image" content="/img/other.png">
+  <meta property="og:site_name" content="Example Shop">
+  <meta name="twitter:card" content="summary_large_image">
+  <meta name="twitter:description" content="Half price on everything">
+  <link rel="apple-touch-icon" href="/apple.png">
+  <link rel="shortcut icon" href="//cdn.example.com/favicon.png">
+  <script>document.write("<title>not a title</title>")</script>
+</head>
+<body>
+  <meta property="og:description" content="ignored, outside of head">
+  <svg><title>icon</title></svg>
+</body>
+</html>`
+
+func TestParse(t *testing.T) {
+	base, _ := url.Parse("https://shop.example.com/sale/spring")
+
+	meta, err := Parse(strings.NewReader(testPage), base)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Spring sale & more", meta.Title)
+	assert.Equal(t, "Everything is 50% off", meta.Description)
+	assert.Equal(t, "Spring sale", meta.OpenGraph.Title)
+	assert.Equal(t, "https://shop.example.com/img/sale.png", meta.OpenGraph.Image)
+	assert.Equal(t, "Example Shop", meta.OpenGraph.SiteName)
+	assert.Empty(t, meta.OpenGraph.Description)
+	assert.Equal(t, "summary_large_image", meta.Twitter.Card)
+	assert.Equal(t, "https://cdn.example.com/favicon.png", meta.FaviconURL)
+
+	assert.Equal(t, "Spring sale", meta.BestTitle())
+	assert.Equal(t, "Half price on everything", meta.BestDescription())
+	assert.Equal(t, "https://shop.example.com/img/sale.png", meta.BestImage())
+}
+
+func TestParse_DefaultFavicon(t *testing.T) {
+	base, _ := url.Parse("https://example.com/a/b")
+
+	meta, err := Parse(strings.NewReader(`<title>Bare</title><p>text`), base)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Bare", meta.BestTitle())
+	assert.Equal(t, "https://example.com/favicon.ico", meta.FaviconURL)
+}
+
+func TestFetcher(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/cp1251":
+			w.Header().Set("Content-Type", "text/html; charset=windows-1251")
+			// "Привет" in windows-1251
+			w.Write([]byte("<title>\xcf\xf0\xe8\xe2\xe5\xf2</title>"))
+		case "/large":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<head><title>Large</title>" + strings.Repeat("<!-- padding -->", 1000) + `<meta name="description" content="too far">`))
+		case "/image":
+			w.Header().Set("Content-Type", "image/png")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher(server.Client(), 1024, "test-agent")
+
+	meta, err := fetcher.Fetch(context.Background(), server.URL+"/cp1251")
+	require.NoError(t, err)
+	assert.Equal(t, "Привет", meta.Title)
+
+	meta, err = fetcher.Fetch(context.Background(), server.URL+"/large")
+	require.NoError(t, err)
+	assert.Equal(t, "Large", meta.Title)
+	assert.Empty(t, meta.Description, "only maxBytes of the page are read")
+
+	_, err = fetcher.Fetch(context.Background(), server.URL+"/image")
+	assert.True(t, errors.Is(err, ErrNotHTML))
+
+	_, err = fetcher.Fetch(context.Background(), server.URL+"/missing")
+	assert.Error(t, err)
+}
@@ -0,0 +1,573 @@
+// Package qrcode encodes data into QR Code symbols (ISO/IEC 18004, model 2) using byte mode
+// and renders them as PNG or SVG images.
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Level is the error correction level of a symbol.
+type Level int
+
+const (
+	LevelL Level = iota // recovers ~7% of damaged codewords
+	LevelM              // recovers ~15% of damaged codewords
+	LevelQ              // recovers ~25% of damaged codewords
+	LevelH              // recovers ~30% of damaged codewords
+)
+
+const (
+	minVersion = 1
+	maxVersion = 40
+)
+
+var (
+	ErrDataTooLong  = errors.New("data too long for a QR code")
+	ErrInvalidLevel = errors.New("error correction level must be L, M, Q or H")
+)
+
+// ParseLevel parses a level name: L, M, Q or H in any case.
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToUpper(name) {
+	case "L":
+		return LevelL, nil
+	case "M":
+		return LevelM, nil
+	case "Q":
+		return LevelQ, nil
+	case "H":
+		return LevelH, nil
+	}
+	return 0, ErrInvalidLevel
+}
+
+// String returns the level name.
+func (l Level) String() string {
+	if l < LevelL || l > LevelH {
+		return fmt.Sprintf("Level(%d)", int(l))
+	}
+	return "LMQH"[l : l+1]
+}
+
+// formatBits are the level bits of the format information.
+func (l Level) formatBits() int {
+	return [...]int{1, 0, 3, 2}[l]
+}
+
+// eccCodewordsPerBlock is indexed by level and version; index 0 is unused.
+var eccCodewordsPerBlock = [4][maxVersion + 1]int{
+	{0, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{0, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{0, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{0, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+// errorCorrectionBlocks is indexed by level and version; index 0 is unused.
+var errorCorrectionBlocks = [4][maxVersion + 1]int{
+	{0, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{0, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{0, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{0, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// penalty weights of the mask evaluation rules
+const (
+	penaltyRun     = 3
+	penaltyBlock   = 3
+	penaltyFinder  = 40
+	penaltyBalance = 10
+)
+
+// Code is an encoded QR Code symbol.
+type Code struct {
+	version  int
+	level    Level
+	mask     int
+	size     int
+	modules  []bool // dark modules, row by row
+	function []bool // modules reserved for function patterns
+}
+
+// Encode encodes data in byte mode with the smallest version that fits at the given level.
+func Encode(data []byte, level Level) (*Code, error) {
+	if level < LevelL || level > LevelH {
+		return nil, ErrInvalidLevel
+	}
+
+	version := minVersion
+	for ; version <= maxVersion; version++ {
+		if 4+charCountBits(version)+8*len(data) <= dataCodewords(version, level)*8 {
+			break
+		}
+	}
+	if version > maxVersion {
+		return nil, ErrDataTooLong
+	}
+
+	// mode indicator, character count, data, terminator and padding
+	var bits bitBuffer
+	bits.append(0x4, 4)
+	bits.append(len(data), charCountBits(version))
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+	capacity := dataCodewords(version, level) * 8
+	bits.append(0, min(4, capacity-bits.len()))
+	bits.append(0, (8-bits.len()%8)%8)
+	for pad := 0xEC; bits.len() < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+
+	code := newCode(version, level)
+	code.drawFunctionPatterns()
+	code.drawCodewords(addErrorCorrection(bits.bytes(), version, level))
+	code.applyBestMask()
+	return code, nil
+}
+
+// Version returns the symbol version from 1 to 40.
+func (c *Code) Version() int {
+	return c.version
+}
+
+// Level returns the error correction level.
+func (c *Code) Level() Level {
+	return c.level
+}
+
+// Size returns the number of modules per side, without the quiet zone.
+func (c *Code) Size() int {
+	return c.size
+}
+
+// Dark reports whether the module at column x and row y is dark. Modules outside the symbol are light.
+func (c *Code) Dark(x, y int) bool {
+	if x < 0 || y < 0 || x >= c.size || y >= c.size {
+		return false
+	}
+	return c.modules[y*c.size+x]
+}
+
+func newCode(version int, level Level) *Code {
+	size := version*4 + 17
+	return &Code{
+		version:  version,
+		level:    level,
+		size:     size,
+		modules:  make([]bool, size*size),
+		function: make([]bool, size*size),
+	}
+}
+
+func (c *Code) setFunction(x, y int, dark bool) {
+	c.modules[y*c.size+x] = dark
+	c.function[y*c.size+x] = true
+}
+
+func (c *Code) isFunction(x, y int) bool {
+	return c.function[y*c.size+x]
+}
+
+// drawFunctionPatterns draws the timing, finder and alignment patterns and reserves
+// the format and version information areas.
+func (c *Code) drawFunctionPatterns() {
+	for i := 0; i < c.size; i++ {
+		c.setFunction(6, i, i%2 == 0)
+		c.setFunction(i, 6, i%2 == 0)
+	}
+
+	c.drawFinder(3, 3)
+	c.drawFinder(c.size-4, 3)
+	c.drawFinder(3, c.size-4)
+
+	positions := alignmentPositions(c.version)
+	last := len(positions) - 1
+	for i, x := range positions {
+		for j, y := range positions {
+			// alignment patterns never overlap the finder patterns
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			c.drawAlignment(x, y)
+		}
+	}
+
+	c.drawFormatBits(0)
+	c.drawVersionBits()
+}
+
+// drawFinder draws a finder pattern with its separator around the center x, y.
+func (c *Code) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || yy < 0 || xx >= c.size || yy >= c.size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			c.setFunction(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+// drawAlignment draws an alignment pattern around the center x, y.
+func (c *Code) drawAlignment(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			c.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// drawFormatBits draws both copies of the format information for the mask and the dark module.
+func (c *Code) drawFormatBits(mask int) {
+	data := c.level.formatBits()<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+
+	// first copy around the top left finder
+	for i := 0; i <= 5; i++ {
+		c.setFunction(8, i, bit(bits, i))
+	}
+	c.setFunction(8, 7, bit(bits, 6))
+	c.setFunction(8, 8, bit(bits, 7))
+	c.setFunction(7, 8, bit(bits, 8))
+	for i := 9; i < 15; i++ {
+		c.setFunction(14-i, 8, bit(bits, i))
+	}
+
+	// second copy split between the top right and bottom left finders
+	for i := 0; i < 8; i++ {
+		c.setFunction(c.size-1-i, 8, bit(bits, i))
+	}
+	for i := 8; i < 15; i++ {
+		c.setFunction(8, c.size-15+i, bit(bits, i))
+	}
+	c.setFunction(8, c.size-8, true)
+}
+
+// drawVersionBits draws both copies of the version information for versions 7 and up.
+func (c *Code) drawVersionBits() {
+	if c.version < 7 {
+		return
+	}
+	rem := c.version
+	for i := 0; i < 12; i++ {
+		rem = rem<<1 ^ (rem>>11)*0x1F25
+	}
+	bits := c.version<<12 | rem
+
+	for i := 0; i < 18; i++ {
+		a, b := c.size-11+i%3, i/3
+		c.setFunction(a, b, bit(bits, i))
+		c.setFunction(b, a, bit(bits, i))
+	}
+}
+
+// drawCodewords places the codewords in the two-module wide columns zigzagging from the bottom right corner.
+func (c *Code) drawCodewords(codewords []byte) {
+	i := 0
+	for right := c.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // skip the vertical timing pattern
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < c.size; vert++ {
+			y := vert
+			if upward {
+				y = c.size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if c.isFunction(x, y) || i >= len(codewords)*8 {
+					continue
+				}
+				c.modules[y*c.size+x] = bit(int(codewords[i>>3]), 7-i&7)
+				i++
+			}
+		}
+	}
+}
+
+// applyBestMask applies the mask with the lowest penalty score and draws its format information.
+func (c *Code) applyBestMask() {
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		c.applyMask(mask)
+		c.drawFormatBits(mask)
+		if penalty := c.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			best, bestPenalty = mask, penalty
+		}
+		c.applyMask(mask) // XOR undoes the mask
+	}
+	c.mask = best
+	c.applyMask(best)
+	c.drawFormatBits(best)
+}
+
+// applyMask inverts the data modules selected by the mask pattern.
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.size; y++ {
+		for x := 0; x < c.size; x++ {
+			if !c.isFunction(x, y) && maskSelects(mask, x, y) {
+				c.modules[y*c.size+x] = !c.modules[y*c.size+x]
+			}
+		}
+	}
+}
+
+func maskSelects(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+// penalty scores the symbol by the four mask evaluation rules; lower is better.
+func (c *Code) penalty() int {
+	result := 0
+
+	// runs of five or more same-colored modules and finder-like patterns in rows and columns
+	for i := 0; i < c.size; i++ {
+		result += c.linePenalty(func(j int) bool { return c.Dark(j, i) })
+		result += c.linePenalty(func(j int) bool { return c.Dark(i, j) })
+	}
+
+	// 2x2 blocks of the same color
+	for y := 0; y < c.size-1; y++ {
+		for x := 0; x < c.size-1; x++ {
+			color := c.Dark(x, y)
+			if color == c.Dark(x+1, y) && color == c.Dark(x, y+1) && color == c.Dark(x+1, y+1) {
+				result += penaltyBlock
+			}
+		}
+	}
+
+	// balance of dark and light modules
+	dark := 0
+	for _, module := range c.modules {
+		if module {
+			dark++
+		}
+	}
+	total := len(c.modules)
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	result += k * penaltyBalance
+	return result
+}
+
+// finderLike is the 1:1:3:1:1 pattern of a finder, checked with four light modules on either side.
+var finderLike = []bool{true, false, true, true, true, false, true}
+
+func (c *Code) linePenalty(dark func(int) bool) int {
+	result := 0
+	run := 1
+	for j := 1; j <= c.size; j++ {
+		if j < c.size && dark(j) == dark(j-1) {
+			run++
+			continue
+		}
+		if run >= 5 {
+			result += penaltyRun + run - 5
+		}
+		run = 1
+	}
+
+	// modules outside the symbol count as light, like the quiet zone
+	for j := -4; j < c.size; j++ {
+		matches := true
+		for k, want := range finderLike {
+			if c.darkInLine(dark, j+k) != want {
+				matches = false
+				break
+			}
+		}
+		if !matches {
+			continue
+		}
+		lightBefore, lightAfter := true, true
+		for k := 1; k <= 4; k++ {
+			lightBefore = lightBefore && !c.darkInLine(dark, j-k)
+			lightAfter = lightAfter && !c.darkInLine(dark, j+len(finderLike)-1+k)
+		}
+		if lightBefore || lightAfter {
+			result += penaltyFinder
+		}
+	}
+	return result
+}
+
+func (c *Code) darkInLine(dark func(int) bool, j int) bool {
+	return j >= 0 && j < c.size && dark(j)
+}
+
+// alignmentPositions returns the row and column centers of the alignment patterns.
+func alignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	count := version/7 + 2
+	step := (version*8 + count*3 + 5) / (count*4 - 4) * 2
+	positions := make([]int, count)
+	positions[0] = 6
+	for i, pos := count-1, version*4+17-7; i >= 1; i, pos = i-1, pos-step {
+		positions[i] = pos
+	}
+	return positions
+}
+
+// rawDataModules returns the number of modules available for codewords, including remainder bits.
+func rawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		count := version/7 + 2
+		result -= (25*count-10)*count - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+// dataCodewords returns the number of data codewords of a version and level.
+func dataCodewords(version int, level Level) int {
+	return rawDataModules(version)/8 - eccCodewordsPerBlock[level][version]*errorCorrectionBlocks[level][version]
+}
+
+// charCountBits returns the length of the byte mode character count field.
+func charCountBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+// addErrorCorrection splits the data into blocks, appends Reed-Solomon codewords to each
+// block and interleaves the blocks.
+func addErrorCorrection(data []byte, version int, level Level) []byte {
+	numBlocks := errorCorrectionBlocks[level][version]
+	eccLen := eccCodewordsPerBlock[level][version]
+	rawCodewords := rawDataModules(version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortBlockLen := rawCodewords / numBlocks
+
+	divisor := reedSolomonDivisor(eccLen)
+	blocks := make([][]byte, numBlocks)
+	for i, k := 0, 0; i < numBlocks; i++ {
+		dataLen := shortBlockLen - eccLen
+		if i >= numShortBlocks {
+			dataLen++
+		}
+		block := make([]byte, 0, shortBlockLen+1)
+		block = append(block, data[k:k+dataLen]...)
+		k += dataLen
+		if i < numShortBlocks {
+			block = append(block, 0) // placeholder skipped when interleaving
+		}
+		blocks[i] = append(block, reedSolomonRemainder(data[k-dataLen:k], divisor)...)
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := 0; i <= shortBlockLen; i++ {
+		for j, block := range blocks {
+			if i != shortBlockLen-eccLen || j >= numShortBlocks {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+// reedSolomonDivisor returns the generator polynomial of the given degree without its leading term.
+func reedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+// reedSolomonRemainder returns the error correction codewords of data.
+func reedSolomonRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i := range result {
+			result[i] ^= gfMultiply(divisor[i], factor)
+		}
+	}
+	return result
+}
+
+// gfMultiply multiplies two elements of GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1.
+func gfMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11D
+		z ^= int(y>>i&1) * int(x)
+	}
+	return byte(z)
+}
+
+// bitBuffer accumulates bits most significant first.
+type bitBuffer []bool
+
+func (b *bitBuffer) append(value, length int) {
+	for i := length - 1; i >= 0; i-- {
+		*b = append(*b, value>>i&1 != 0)
+	}
+}
+
+func (b *bitBuffer) len() int {
+	return len(*b)
+}
+
+func (b *bitBuffer) bytes() []byte {
+	result := make([]byte, (len(*b)+7)/8)
+	for i, set := range *b {
+		if set {
+			result[i>>3] |= 0x80 >> (i & 7)
+		}
+	}
+	return result
+}
+
+func bit(value, i int) bool {
+	return value>>i&1 != 0
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,220 @@
+package qrcode
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode_Capacity(t *testing.T) {
+	// byte mode capacities from the specification
+	tests := []struct {
+		version int
+		level   Level
+		bytes   int
+	}{
+		{1, LevelL, 17}, {1, LevelM, 14}, {1, LevelQ, 11}, {1, LevelH, 7},
+		{2, LevelM, 26}, {10, LevelM, 213}, {10, LevelH, 119},
+		{40, LevelL, 2953}, {40, LevelH, 1273},
+	}
+	for _, tt := range tests {
+		code, err := Encode(make([]byte, tt.bytes), tt.level)
+		require.NoError(t, err)
+		assert.Equal(t, tt.version, code.Version(), "%d bytes at %s", tt.bytes, tt.level)
+
+		code, err = Encode(make([]byte, tt.bytes+1), tt.level)
+		if tt.version == maxVersion {
+			assert.ErrorIs(t, err, ErrDataTooLong)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, tt.version+1, code.Version(), "%d bytes at %s", tt.bytes+1, tt.level)
+	}
+}
+
+func TestCode_FormatAndVersionBits(t *testing.T) {
+	// format information for masks 0-7 from the specification
+	formats := map[Level][8]int{
+		LevelL: {0b111011111000100, 0b111001011110011, 0b111110110101010, 0b111100010011101, 0b110011000101111, 0b110001100011000, 0b110110001000001, 0b110100101110110},
+		LevelM: {0b101010000010010, 0b101000100100101, 0b101111001111100, 0b101101101001011, 0b100010111111001, 0b100000011001110, 0b100111110010111, 0b100101010100000},
+	}
+	for level, masks := range formats {
+		for mask, want := range masks {
+			code := newCode(1, level)
+			code.drawFormatBits(mask)
+			assert.Equal(t, want, readFormatBits(code), "%s mask %d", level, mask)
+		}
+	}
+
+	code := newCode(7, LevelL)
+	code.drawVersionBits()
+	version := 0
+	for i := 17; i >= 0; i-- {
+		version <<= 1
+		if code.Dark(code.size-11+i%3, i/3) {
+			version |= 1
+		}
+	}
+	assert.Equal(t, 0b000111110010010100, version)
+}
+
+func TestEncode_RoundTrip(t *testing.T) {
+	inputs := []string{
+		"https://gurls.example/abc123",
+		"https://gurls.example/summer-sale-2025?qr=1",
+		strings.Repeat("Ж", 300),
+	}
+	for _, input := range inputs {
+		for level := LevelL; level <= LevelH; level++ {
+			code, err := Encode([]byte(input), level)
+			require.NoError(t, err)
+			assert.Equal(t, input, string(decode(t, code)), "%s version %d", level, code.Version())
+		}
+	}
+}
+
+func TestCode_Render(t *testing.T) {
+	code, err := Encode([]byte("https://gurls.example/abc123"), LevelM)
+	require.NoError(t, err)
+
+	opts := DefaultRenderOptions(300)
+	var buf bytes.Buffer
+	require.NoError(t, code.PNG(&buf, opts))
+	img, err := png.Decode(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, 300, img.Bounds().Dx())
+
+	// the top left module of the finder pattern is dark, the quiet zone is light
+	scale := 300 / (code.Size() + 2*DefaultMargin)
+	offset := (300-scale*(code.Size()+2*DefaultMargin))/2 + DefaultMargin*scale
+	r, _, _, _ := img.At(offset, offset).RGBA()
+	assert.Zero(t, r)
+	r, _, _, _ = img.At(offset-1, offset-1).RGBA()
+	assert.NotZero(t, r)
+
+	buf.Reset()
+	fg, err := ParseColor("#1A2b3c")
+	require.NoError(t, err)
+	opts.Foreground = fg
+	require.NoError(t, code.SVG(&buf, opts))
+	assert.Contains(t, buf.String(), `fill="#1a2b3c"`)
+	assert.Contains(t, buf.String(), `viewBox="0 0 37 37"`)
+
+	_, err = ParseColor("red")
+	assert.ErrorIs(t, err, ErrInvalidColor)
+}
+
+// readFormatBits reads the first copy of the format information.
+func readFormatBits(code *Code) int {
+	var positions [][2]int
+	for i := 0; i <= 5; i++ {
+		positions = append(positions, [2]int{8, i})
+	}
+	positions = append(positions, [2]int{8, 7}, [2]int{8, 8}, [2]int{7, 8})
+	for i := 9; i < 15; i++ {
+		positions = append(positions, [2]int{14 - i, 8})
+	}
+	bits := 0
+	for i, p := range positions {
+		if code.Dark(p[0], p[1]) {
+			bits |= 1 << i
+		}
+	}
+	return bits
+}
+
+// decode reads the codewords back from the symbol, checks the Reed-Solomon syndromes
+// of every block and returns the byte mode payload.
+func decode(t *testing.T, code *Code) []byte {
+	t.Helper()
+
+	format := readFormatBits(code) ^ 0x5412
+	mask := format >> 10 & 7
+	require.Equal(t, code.level.formatBits(), format>>13, "level in format information")
+
+	// read the raw codewords in placement order with the mask removed
+	var bits bitBuffer
+	for right := code.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < code.size; vert++ {
+			y := vert
+			if upward {
+				y = code.size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if !code.isFunction(x, y) {
+					bits = append(bits, code.Dark(x, y) != maskSelects(mask, x, y))
+				}
+			}
+		}
+	}
+	raw := bits.bytes()[:rawDataModules(code.version)/8]
+
+	// de-interleave the blocks
+	numBlocks := errorCorrectionBlocks[code.level][code.version]
+	eccLen := eccCodewordsPerBlock[code.level][code.version]
+	numShortBlocks := numBlocks - len(raw)%numBlocks
+	shortDataLen := len(raw)/numBlocks - eccLen
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := 0; i < shortDataLen+1; i++ {
+		for j := range blocks {
+			if i < shortDataLen || j >= numShortBlocks {
+				blocks[j] = append(blocks[j], raw[k])
+				k++
+			}
+		}
+	}
+	for i := 0; i < eccLen; i++ {
+		for j := range blocks {
+			blocks[j] = append(blocks[j], raw[k])
+			k++
+		}
+	}
+
+	var data []byte
+	for _, block := range blocks {
+		root := byte(1)
+		for i := 0; i < eccLen; i++ {
+			syndrome := byte(0)
+			for _, b := range block {
+				syndrome = gfMultiply(syndrome, root) ^ b
+			}
+			require.Zero(t, syndrome, "syndrome %d", i)
+			root = gfMultiply(root, 0x02)
+		}
+		data = append(data, block[:len(block)-eccLen]...)
+	}
+
+	// mode indicator and character count
+	var payload bitBuffer
+	for _, b := range data {
+		payload.append(int(b), 8)
+	}
+	read := func(offset, length int) int {
+		value := 0
+		for _, set := range payload[offset : offset+length] {
+			value <<= 1
+			if set {
+				value |= 1
+			}
+		}
+		return value
+	}
+	require.Equal(t, 0x4, read(0, 4))
+	count := read(4, charCountBits(code.version))
+	start := 4 + charCountBits(code.version)
+	result := make([]byte, count)
+	for i := range result {
+		result[i] = byte(read(start+i*8, 8))
+	}
+	return result
+}
@@ -0,0 +1,117 @@
+package qrcode
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DefaultMargin is the quiet zone width in modules required by the specification.
+const DefaultMargin = 4
+
+var ErrInvalidColor = errors.New("color must be a hex RGB value such as 000000 or #1a2b3c")
+
+// RenderOptions controls how a symbol is drawn.
+type RenderOptions struct {
+	Size       int // image width and height in pixels; grown to fit at least one pixel per module
+	Margin     int // quiet zone width in modules
+	Foreground color.RGBA
+	Background color.RGBA
+}
+
+// DefaultRenderOptions returns black modules on white with the standard quiet zone.
+func DefaultRenderOptions(size int) RenderOptions {
+	return RenderOptions{
+		Size:       size,
+		Margin:     DefaultMargin,
+		Foreground: color.RGBA{A: 0xFF},
+		Background: color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+	}
+}
+
+// ParseColor parses a hex color in the RRGGBB or RGB form with an optional leading #.
+func ParseColor(value string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(value, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return color.RGBA{}, ErrInvalidColor
+	}
+	rgb, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, ErrInvalidColor
+	}
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 0xFF}, nil
+}
+
+// Image draws the symbol as a paletted image of opts.Size pixels. Modules are scaled by a whole
+// number of pixels and the leftover space is filled with the background around the quiet zone.
+func (c *Code) Image(opts RenderOptions) image.Image {
+	modules := c.size + 2*opts.Margin
+	scale := max(opts.Size/modules, 1)
+	size := max(opts.Size, modules*scale)
+	offset := (size-modules*scale)/2 + opts.Margin*scale
+
+	img := image.NewPaletted(image.Rect(0, 0, size, size), color.Palette{opts.Background, opts.Foreground})
+	for y := 0; y < c.size; y++ {
+		for x := 0; x < c.size; x++ {
+			if !c.Dark(x, y) {
+				continue
+			}
+			for py := 0; py < scale; py++ {
+				row := img.Pix[(offset+y*scale+py)*img.Stride:]
+				for px := 0; px < scale; px++ {
+					row[offset+x*scale+px] = 1
+				}
+			}
+		}
+	}
+	return img
+}
+
+// PNG writes the symbol as a PNG image.
+func (c *Code) PNG(w io.Writer, opts RenderOptions) error {
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	return encoder.Encode(w, c.Image(opts))
+}
+
+// SVG writes the symbol as an SVG document scaled to opts.Size pixels. Horizontal runs
+// of dark modules are merged into a single path.
+func (c *Code) SVG(w io.Writer, opts RenderOptions) error {
+	modules := c.size + 2*opts.Margin
+	size := max(opts.Size, modules)
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, `<?xml version="1.0" encoding="UTF-8"?>`+"\n")
+	fmt.Fprintf(out, `<svg xmlns="http://www.w3.org/2000/svg" version="1.1" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+"\n",
+		size, size, modules, modules)
+	fmt.Fprintf(out, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", hexColor(opts.Background))
+	fmt.Fprintf(out, `<path fill="%s" d="`, hexColor(opts.Foreground))
+	for y := 0; y < c.size; y++ {
+		for x := 0; x < c.size; {
+			if !c.Dark(x, y) {
+				x++
+				continue
+			}
+			run := 1
+			for c.Dark(x+run, y) {
+				run++
+			}
+			fmt.Fprintf(out, "M%d %dh%dv1h-%dz", x+opts.Margin, y+opts.Margin, run, run)
+			x += run
+		}
+	}
+	fmt.Fprint(out, "\"/>\n</svg>\n")
+	return out.Flush()
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
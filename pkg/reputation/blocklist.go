@@ -0,0 +1,281 @@
+package reputation
+
+import (
+	"GURLS-Backend/pkg/urlpolicy"
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/idna"
+)
+
+// Hash prefix lengths accepted in hash-prefix lists, in bytes.
+const (
+	MinHashPrefixLength = 4
+	MaxHashPrefixLength = sha256.Size
+)
+
+// List sources reported in Verdict.Source.
+const (
+	SourceDomains      = "domains"
+	SourceURLs         = "urls"
+	SourceHashPrefixes = "hash_prefixes"
+)
+
+// BlocklistFiles are the list files loaded by Blocklist. Empty paths are skipped.
+//
+// Every file has one entry per line, optionally followed by whitespace and a
+// threat category; blank lines and lines starting with # are ignored:
+//   - Domains: host names; subdomains of a listed domain match as well.
+//   - URLs: absolute URLs, matched exactly after normalization (fragment ignored).
+//   - HashPrefixes: hex SHA-256 prefixes (4 to 32 bytes) of host/path expressions,
+//     see Expressions.
+type BlocklistFiles struct {
+	Domains      string
+	URLs         string
+	HashPrefixes string
+}
+
+// Blocklist is a Checker backed by local list files. It is safe for concurrent
+// use; Reload swaps lists atomically while checks are running.
+type Blocklist struct {
+	files BlocklistFiles
+
+	mu            sync.RWMutex
+	domains       map[string]Threat
+	urls          map[string]Threat
+	prefixes      map[int]map[string]Threat // prefix length -> prefix -> threat
+	prefixLengths []int
+}
+
+// NewBlocklist creates an empty blocklist; call Reload to load the files.
+func NewBlocklist(files BlocklistFiles) *Blocklist {
+	return &Blocklist{files: files}
+}
+
+// Reload re-reads the list files. A list that fails to load keeps its previous
+// entries; errors of all lists are joined.
+func (b *Blocklist) Reload() error {
+	var errs []error
+
+	domains, err := loadList(b.files.Domains, normalizeDomainEntry)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	urls, err := loadList(b.files.URLs, normalizeURLEntry)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	hashes, err := loadList(b.files.HashPrefixes, normalizeHashPrefixEntry)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if domains != nil {
+		b.domains = domains
+	}
+	if urls != nil {
+		b.urls = urls
+	}
+	if hashes != nil {
+		b.prefixes = make(map[int]map[string]Threat)
+		for prefix, threat := range hashes {
+			set, ok := b.prefixes[len(prefix)]
+			if !ok {
+				set = make(map[string]Threat)
+				b.prefixes[len(prefix)] = set
+			}
+			set[prefix] = threat
+		}
+		b.prefixLengths = b.prefixLengths[:0]
+		for length := range b.prefixes {
+			b.prefixLengths = append(b.prefixLengths, length)
+		}
+		sort.Ints(b.prefixLengths)
+	}
+	return errors.Join(errs...)
+}
+
+// Len returns the total number of loaded entries.
+func (b *Blocklist) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	n := len(b.domains) + len(b.urls)
+	for _, set := range b.prefixes {
+		n += len(set)
+	}
+	return n
+}
+
+// Check implements Checker. URLs that cannot be normalized are not listed.
+func (b *Blocklist) Check(_ context.Context, rawURL string) (*Verdict, error) {
+	u, ok := parseURL(rawURL)
+	if !ok {
+		return nil, nil
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if threat, ok := b.urls[u.String()]; ok {
+		return &Verdict{Threat: threat, Source: SourceURLs, Match: u.String()}, nil
+	}
+
+	for host := u.Hostname(); host != ""; {
+		if threat, ok := b.domains[host]; ok {
+			return &Verdict{Threat: threat, Source: SourceDomains, Match: host}, nil
+		}
+		_, parent, found := strings.Cut(host, ".")
+		if !found {
+			break
+		}
+		host = parent
+	}
+
+	if len(b.prefixLengths) == 0 {
+		return nil, nil
+	}
+	for _, expression := range Expressions(u) {
+		sum := sha256.Sum256([]byte(expression))
+		for _, length := range b.prefixLengths {
+			if threat, ok := b.prefixes[length][string(sum[:length])]; ok {
+				return &Verdict{Threat: threat, Source: SourceHashPrefixes, Match: hex.EncodeToString(sum[:length])}, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// Expressions returns the host/path combinations of u that hash-prefix lists are
+// matched against, in the spirit of Safe Browsing: the exact host and up to four
+// parent domains built from its last five labels, combined with the path with and
+// without the query, the root path "/" and up to three leading directories.
+// For https://a.b.example.com/1/2/page?x=y these include "a.b.example.com/1/2/page?x=y",
+// "b.example.com/1/" and "example.com/". Ports, schemes and fragments are not part
+// of an expression, so "example.com/" covers the whole domain.
+func Expressions(u *url.URL) []string {
+	host := strings.ToLower(u.Hostname())
+	hosts := []string{host}
+	if net.ParseIP(host) == nil {
+		labels := strings.Split(host, ".")
+		for i := max(1, len(labels)-5); i <= len(labels)-2; i++ {
+			hosts = append(hosts, strings.Join(labels[i:], "."))
+		}
+	}
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	var paths []string
+	if u.RawQuery != "" {
+		paths = append(paths, path+"?"+u.RawQuery)
+	}
+	paths = append(paths, path, "/")
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	prefix := "/"
+	for i := 0; i < len(segments)-1 && i < 3; i++ {
+		prefix += segments[i] + "/"
+		paths = append(paths, prefix)
+	}
+
+	seen := make(map[string]bool, len(hosts)*len(paths))
+	expressions := make([]string, 0, len(hosts)*len(paths))
+	for _, h := range hosts {
+		for _, p := range paths {
+			expression := h + p
+			if !seen[expression] {
+				seen[expression] = true
+				expressions = append(expressions, expression)
+			}
+		}
+	}
+	return expressions
+}
+
+// parseURL normalizes rawURL and drops its fragment.
+func parseURL(rawURL string) (*url.URL, bool) {
+	normalized, err := urlpolicy.Normalize(rawURL)
+	if err != nil {
+		return nil, false
+	}
+	u, err := url.Parse(normalized)
+	if err != nil {
+		return nil, false
+	}
+	u.Fragment, u.RawFragment = "", ""
+	return u, true
+}
+
+func normalizeDomainEntry(entry string) (string, error) {
+	domain := strings.TrimSuffix(strings.TrimPrefix(strings.ToLower(entry), "*."), ".")
+	if ascii, err := idna.Lookup.ToASCII(domain); err == nil {
+		domain = ascii
+	}
+	if domain == "" || strings.ContainsAny(domain, "/:@ ") {
+		return "", fmt.Errorf("invalid domain %q", entry)
+	}
+	return domain, nil
+}
+
+func normalizeURLEntry(entry string) (string, error) {
+	u, ok := parseURL(entry)
+	if !ok {
+		return "", fmt.Errorf("invalid url %q", entry)
+	}
+	return u.String(), nil
+}
+
+func normalizeHashPrefixEntry(entry string) (string, error) {
+	prefix, err := hex.DecodeString(entry)
+	if err != nil || len(prefix) < MinHashPrefixLength || len(prefix) > MaxHashPrefixLength {
+		return "", fmt.Errorf("invalid hash prefix %q: want %d to %d hex-encoded bytes", entry, MinHashPrefixLength, MaxHashPrefixLength)
+	}
+	return string(prefix), nil
+}
+
+// loadList reads a list file into a map of normalized entries. It returns a nil
+// map for an empty path.
+func loadList(path string, normalize func(entry string) (string, error)) (map[string]Threat, error) {
+	if path == "" {
+		return nil, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	list := make(map[string]Threat)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		entry, err := normalize(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		threat := ThreatMalicious
+		if len(fields) > 1 {
+			threat = Threat(strings.ToLower(fields[1]))
+		}
+		list[entry] = threat
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return list, nil
+}
@@ -0,0 +1,98 @@
+package reputation
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeList(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func hashPrefix(expression string, length int) string {
+	sum := sha256.Sum256([]byte(expression))
+	return hex.EncodeToString(sum[:length])
+}
+
+func TestBlocklist_Check(t *testing.T) {
+	blocklist := NewBlocklist(BlocklistFiles{
+		Domains: writeList(t, "domains.txt", "# test list\nevil.example phishing\n\nПример-фишинг.рф\n"),
+		URLs:    writeList(t, "urls.txt", "HTTPS://Files.Example.com:443/payload.exe malware\n"),
+		HashPrefixes: writeList(t, "hashes.txt",
+			hashPrefix("bad.example.org/", 4)+" unwanted\n"+
+				hashPrefix("cdn.example.net/kits/", 32)+"\n"),
+	})
+	require.NoError(t, blocklist.Reload())
+	assert.Equal(t, 5, blocklist.Len())
+
+	tests := []struct {
+		raw    string
+		threat Threat
+		source string
+	}{
+		{raw: "https://example.com/page", threat: "", source: ""},
+		{raw: "https://evil.example/login", threat: ThreatPhishing, source: SourceDomains},
+		{raw: "https://login.EVIL.example./", threat: ThreatPhishing, source: SourceDomains},
+		{raw: "https://notevil.example/", threat: "", source: ""},
+		{raw: "https://пример-фишинг.рф/", threat: ThreatMalicious, source: SourceDomains},
+		{raw: "https://files.example.com/payload.exe#download", threat: ThreatMalware, source: SourceURLs},
+		{raw: "https://files.example.com/payload.exe?v=2", threat: "", source: ""},
+		{raw: "http://www.bad.example.org/any/path?q=1", threat: ThreatUnwanted, source: SourceHashPrefixes},
+		{raw: "https://cdn.example.net/kits/bank/index.html", threat: ThreatMalicious, source: SourceHashPrefixes},
+		{raw: "https://cdn.example.net/assets/app.js", threat: "", source: ""},
+		{raw: "javascript:alert(1)", threat: "", source: ""},
+	}
+	for _, tt := range tests {
+		verdict, err := blocklist.Check(context.Background(), tt.raw)
+		require.NoError(t, err, tt.raw)
+		if tt.threat == "" {
+			assert.Nil(t, verdict, tt.raw)
+			continue
+		}
+		if assert.NotNil(t, verdict, tt.raw) {
+			assert.Equal(t, tt.threat, verdict.Threat, tt.raw)
+			assert.Equal(t, tt.source, verdict.Source, tt.raw)
+		}
+	}
+}
+
+func TestBlocklist_ReloadKeepsListOnError(t *testing.T) {
+	domains := writeList(t, "domains.txt", "evil.example\n")
+	blocklist := NewBlocklist(BlocklistFiles{Domains: domains, HashPrefixes: writeList(t, "hashes.txt", "abc\n")})
+	assert.Error(t, blocklist.Reload())
+
+	verdict, err := blocklist.Check(context.Background(), "https://evil.example/")
+	require.NoError(t, err)
+	assert.NotNil(t, verdict)
+
+	require.NoError(t, os.Remove(domains))
+	assert.Error(t, blocklist.Reload())
+	verdict, err = blocklist.Check(context.Background(), "https://evil.example/")
+	require.NoError(t, err)
+	assert.NotNil(t, verdict, "a missing file must not clear the loaded list")
+}
+
+func TestExpressions(t *testing.T) {
+	u, err := url.Parse("https://a.b.c.d.e.example.com:8443/1/2/3/4/page.html?x=y")
+	require.NoError(t, err)
+	expressions := Expressions(u)
+
+	assert.Contains(t, expressions, "a.b.c.d.e.example.com/1/2/3/4/page.html?x=y")
+	assert.Contains(t, expressions, "a.b.c.d.e.example.com/1/2/3/")
+	assert.Contains(t, expressions, "c.d.e.example.com/")
+	assert.Contains(t, expressions, "example.com/1/")
+	assert.NotContains(t, expressions, "b.c.d.e.example.com/", "only the last five labels form parent domains")
+	assert.NotContains(t, expressions, "com/")
+	assert.NotContains(t, expressions, "example.com/1/2/3/4/")
+}
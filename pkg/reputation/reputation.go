@@ -0,0 +1,43 @@
+// Package reputation checks link destinations against threat lists. Checker is the
+// extension point for external services; Blocklist is a built-in implementation
+// backed by locally loaded domain, URL and hash-prefix lists.
+package reputation
+
+import "context"
+
+// Threat is the category a listed URL belongs to.
+type Threat string
+
+// Threat categories. Lists may use any other single-word category as well.
+const (
+	ThreatMalicious Threat = "malicious" // default for entries without a category
+	ThreatPhishing  Threat = "phishing"
+	ThreatMalware   Threat = "malware"
+	ThreatUnwanted  Threat = "unwanted"
+)
+
+// Verdict describes why a URL was flagged.
+type Verdict struct {
+	Threat Threat
+	Source string // list that matched, e.g. "domains", "urls" or "hash_prefixes"
+	Match  string // matched entry: domain, URL or hash prefix in hex
+}
+
+// Checker looks up a URL in threat lists. It returns a nil Verdict for URLs that
+// are not listed; an error means the lookup itself failed.
+type Checker interface {
+	Check(ctx context.Context, rawURL string) (*Verdict, error)
+}
+
+// Reloader is implemented by checkers whose lists can be refreshed at runtime.
+type Reloader interface {
+	Reload() error
+}
+
+// Nop is a Checker that never flags anything.
+type Nop struct{}
+
+// Check implements Checker.
+func (Nop) Check(context.Context, string) (*Verdict, error) {
+	return nil, nil
+}
@@ -0,0 +1,228 @@
+// Package urlforward builds redirect targets by carrying the incoming query string
+// and path suffix of a short link request over to the link destination.
+package urlforward
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// ConflictPolicy decides what happens when the incoming query and the destination
+// both contain the same parameter.
+type ConflictPolicy string
+
+const (
+	// ConflictKeepDestination keeps the destination value and drops the incoming one.
+	ConflictKeepDestination ConflictPolicy = "keep"
+	// ConflictOverride replaces the destination value with the incoming one.
+	ConflictOverride ConflictPolicy = "override"
+	// ConflictAppend keeps both values, destination first.
+	ConflictAppend ConflictPolicy = "append"
+)
+
+var (
+	ErrInvalidPathSuffix      = errors.New("invalid path suffix")
+	ErrUnsupportedDestination = errors.New("destination URL cannot be extended")
+)
+
+// IsValid reports whether the policy is one of the supported values.
+func (p ConflictPolicy) IsValid() bool {
+	switch p {
+	case ConflictKeepDestination, ConflictOverride, ConflictAppend:
+		return true
+	default:
+		return false
+	}
+}
+
+// Options controls which parts of the incoming request are forwarded.
+type Options struct {
+	ForwardQuery bool
+	ForwardPath  bool
+	Policy       ConflictPolicy // empty means ConflictKeepDestination
+	// Params is a raw query the link itself adds to the destination, such as UTM tags.
+	// It never overrides destination parameters and is applied before the incoming query.
+	Params string
+}
+
+// Request describes the parts of the incoming short link request that can be forwarded.
+type Request struct {
+	// PathSuffix is the escaped path after "/{alias}/", without the leading slash.
+	PathSuffix string
+	// HasPathSuffix is true when the request path continued after the alias, even with an empty suffix.
+	HasPathSuffix bool
+	// RawQuery is the incoming query string without the leading "?".
+	RawQuery string
+}
+
+// Build returns the redirect target for destination. When nothing has to be forwarded
+// the destination is returned unchanged, byte for byte.
+func Build(destination string, req Request, opts Options) (string, error) {
+	forwardPath := opts.ForwardPath && req.HasPathSuffix
+	forwardQuery := opts.ForwardQuery && req.RawQuery != ""
+	addParams := opts.Params != ""
+	if !forwardPath && !forwardQuery && !addParams {
+		return destination, nil
+	}
+
+	target, err := url.Parse(destination)
+	if err != nil {
+		return "", err
+	}
+	if target.Opaque != "" {
+		return "", ErrUnsupportedDestination
+	}
+
+	if forwardPath {
+		if err := AppendPath(target, req.PathSuffix); err != nil {
+			return "", err
+		}
+	}
+	if addParams {
+		target.RawQuery = MergeQuery(target.RawQuery, opts.Params, ConflictKeepDestination)
+	}
+	if forwardQuery {
+		target.RawQuery = MergeQuery(target.RawQuery, req.RawQuery, opts.Policy)
+	}
+	if addParams || forwardQuery {
+		target.ForceQuery = false
+	}
+
+	return target.String(), nil
+}
+
+// AppendPath appends an escaped path suffix to the destination path. Dot segments and
+// control characters are rejected so a suffix cannot climb above the destination path.
+func AppendPath(target *url.URL, rawSuffix string) error {
+	for _, segment := range strings.Split(rawSuffix, "/") {
+		decoded, err := url.PathUnescape(segment)
+		if err != nil {
+			return ErrInvalidPathSuffix
+		}
+		if isDotPath(decoded) || hasControlChars(decoded) {
+			return ErrInvalidPathSuffix
+		}
+	}
+
+	base := target.EscapedPath()
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+	rawPath := base + rawSuffix
+
+	path, err := url.PathUnescape(rawPath)
+	if err != nil {
+		return ErrInvalidPathSuffix
+	}
+	target.Path = path
+	target.RawPath = rawPath
+	return nil
+}
+
+// MergeQuery merges the incoming query into the destination query according to policy.
+// Destination parameters keep their original order and encoding; incoming parameters are
+// re-encoded and appended in the order they were received. Malformed incoming pairs are dropped.
+func MergeQuery(destinationQuery, incomingQuery string, policy ConflictPolicy) string {
+	if policy == "" {
+		policy = ConflictKeepDestination
+	}
+
+	destination := splitQuery(destinationQuery)
+	incoming := make([]queryPair, 0)
+	for _, pair := range splitQuery(incomingQuery) {
+		if normalized, ok := pair.normalize(); ok {
+			incoming = append(incoming, normalized)
+		}
+	}
+	if len(incoming) == 0 {
+		return destinationQuery
+	}
+
+	destinationKeys := keySet(destination)
+	incomingKeys := keySet(incoming)
+
+	parts := make([]string, 0, len(destination)+len(incoming))
+	for _, pair := range destination {
+		if policy == ConflictOverride && incomingKeys[pair.key] {
+			continue
+		}
+		parts = append(parts, pair.raw)
+	}
+	for _, pair := range incoming {
+		if policy == ConflictKeepDestination && destinationKeys[pair.key] {
+			continue
+		}
+		parts = append(parts, pair.raw)
+	}
+
+	return strings.Join(parts, "&")
+}
+
+// queryPair is a single "key=value" element of a query string.
+type queryPair struct {
+	key string // decoded key, used for conflict detection
+	raw string // pair as it appears in the query string
+}
+
+// splitQuery splits a raw query into pairs, skipping empty elements.
+func splitQuery(rawQuery string) []queryPair {
+	var pairs []queryPair
+	for _, raw := range strings.Split(rawQuery, "&") {
+		if raw == "" {
+			continue
+		}
+		rawKey, _, _ := strings.Cut(raw, "=")
+		key, err := url.QueryUnescape(rawKey)
+		if err != nil {
+			key = rawKey
+		}
+		pairs = append(pairs, queryPair{key: key, raw: raw})
+	}
+	return pairs
+}
+
+// normalize decodes and re-encodes an incoming pair. A pair without "=" stays a bare key.
+func (p queryPair) normalize() (queryPair, bool) {
+	rawKey, rawValue, hasValue := strings.Cut(p.raw, "=")
+	key, err := url.QueryUnescape(rawKey)
+	if err != nil || key == "" {
+		return queryPair{}, false
+	}
+	if !hasValue {
+		return queryPair{key: key, raw: url.QueryEscape(key)}, true
+	}
+	value, err := url.QueryUnescape(rawValue)
+	if err != nil {
+		return queryPair{}, false
+	}
+	return queryPair{key: key, raw: url.QueryEscape(key) + "=" + url.QueryEscape(value)}, true
+}
+
+func keySet(pairs []queryPair) map[string]bool {
+	keys := make(map[string]bool, len(pairs))
+	for _, pair := range pairs {
+		keys[pair.key] = true
+	}
+	return keys
+}
+
+// isDotPath reports whether a decoded segment is or contains a dot component,
+// including ones hidden behind an encoded slash such as "..%2Fadmin".
+func isDotPath(decoded string) bool {
+	for _, part := range strings.Split(decoded, "/") {
+		if part == "." || part == ".." {
+			return true
+		}
+	}
+	return false
+}
+
+func hasControlChars(s string) bool {
+	for _, r := range s {
+		if r < 0x20 || r == 0x7f {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,288 @@
+package urlforward
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuild_NothingToForward(t *testing.T) {
+	destination := "https://example.com/a%20b?z=1&a=2#top"
+
+	target, err := Build(destination, Request{RawQuery: "utm_source=x"}, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, destination, target, "destination must be untouched when forwarding is off")
+
+	target, err = Build(destination, Request{}, Options{ForwardQuery: true, ForwardPath: true})
+	require.NoError(t, err)
+	assert.Equal(t, destination, target, "destination must be untouched when the request has nothing to forward")
+}
+
+func TestBuild_Query(t *testing.T) {
+	tests := []struct {
+		name        string
+		destination string
+		rawQuery    string
+		policy      ConflictPolicy
+		want        string
+	}{
+		{
+			name:        "destination without query",
+			destination: "https://example.com/page",
+			rawQuery:    "utm_source=x",
+			want:        "https://example.com/page?utm_source=x",
+		},
+		{
+			name:        "keep destination value on conflict",
+			destination: "https://example.com/page?utm_source=site&id=7",
+			rawQuery:    "utm_source=x&ref=y",
+			policy:      ConflictKeepDestination,
+			want:        "https://example.com/page?utm_source=site&id=7&ref=y",
+		},
+		{
+			name:        "empty policy behaves as keep",
+			destination: "https://example.com/?a=1",
+			rawQuery:    "a=2",
+			want:        "https://example.com/?a=1",
+		},
+		{
+			name:        "override destination value on conflict",
+			destination: "https://example.com/page?utm_source=site&id=7",
+			rawQuery:    "utm_source=x",
+			policy:      ConflictOverride,
+			want:        "https://example.com/page?id=7&utm_source=x",
+		},
+		{
+			name:        "override removes every destination value of the key",
+			destination: "https://example.com/?tag=a&tag=b&id=1",
+			rawQuery:    "tag=c",
+			policy:      ConflictOverride,
+			want:        "https://example.com/?id=1&tag=c",
+		},
+		{
+			name:        "append keeps both values",
+			destination: "https://example.com/?tag=a",
+			rawQuery:    "tag=b",
+			policy:      ConflictAppend,
+			want:        "https://example.com/?tag=a&tag=b",
+		},
+		{
+			name:        "fragment stays after the query",
+			destination: "https://example.com/page#section",
+			rawQuery:    "a=1",
+			want:        "https://example.com/page?a=1#section",
+		},
+		{
+			name:        "incoming values are re-encoded",
+			destination: "https://example.com/",
+			rawQuery:    "q=a+b&name=%D0%BF%D1%80%D0%B8%D0%B2%D0%B5%D1%82&x=<script>",
+			want:        "https://example.com/?q=a+b&name=%D0%BF%D1%80%D0%B8%D0%B2%D0%B5%D1%82&x=%3Cscript%3E",
+		},
+		{
+			name:        "encoded ampersand stays inside the value",
+			destination: "https://example.com/",
+			rawQuery:    "q=a%26b%3Dc",
+			want:        "https://example.com/?q=a%26b%3Dc",
+		},
+		{
+			name:        "conflicts are detected on decoded keys",
+			destination: "https://example.com/?utm%5Fsource=site",
+			rawQuery:    "utm_source=x",
+			policy:      ConflictKeepDestination,
+			want:        "https://example.com/?utm%5Fsource=site",
+		},
+		{
+			name:        "destination encoding is preserved",
+			destination: "https://example.com/?redirect=https%3A%2F%2Fa.b%2F&b=1",
+			rawQuery:    "c=2",
+			want:        "https://example.com/?redirect=https%3A%2F%2Fa.b%2F&b=1&c=2",
+		},
+		{
+			name:        "bare keys and empty values are kept",
+			destination: "https://example.com/",
+			rawQuery:    "debug&empty=",
+			want:        "https://example.com/?debug&empty=",
+		},
+		{
+			name:        "malformed and empty pairs are dropped",
+			destination: "https://example.com/?a=1",
+			rawQuery:    "&&bad=%zz&=novalue&ok=1",
+			want:        "https://example.com/?a=1&ok=1",
+		},
+		{
+			name:        "only malformed pairs leave destination as is",
+			destination: "https://example.com/?a=1",
+			rawQuery:    "bad=%zz",
+			want:        "https://example.com/?a=1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, err := Build(tt.destination, Request{RawQuery: tt.rawQuery}, Options{ForwardQuery: true, Policy: tt.policy})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, target)
+		})
+	}
+}
+
+func TestBuild_Path(t *testing.T) {
+	tests := []struct {
+		name        string
+		destination string
+		suffix      string
+		want        string
+	}{
+		{
+			name:        "destination without path",
+			destination: "https://example.com",
+			suffix:      "docs/intro",
+			want:        "https://example.com/docs/intro",
+		},
+		{
+			name:        "destination with trailing slash",
+			destination: "https://example.com/base/",
+			suffix:      "page",
+			want:        "https://example.com/base/page",
+		},
+		{
+			name:        "destination without trailing slash",
+			destination: "https://example.com/base",
+			suffix:      "page",
+			want:        "https://example.com/base/page",
+		},
+		{
+			name:        "empty suffix adds trailing slash",
+			destination: "https://example.com/base",
+			suffix:      "",
+			want:        "https://example.com/base/",
+		},
+		{
+			name:        "query and fragment of destination are kept",
+			destination: "https://example.com/base?x=1#top",
+			suffix:      "page",
+			want:        "https://example.com/base/page?x=1#top",
+		},
+		{
+			name:        "escaped characters in suffix are preserved",
+			destination: "https://example.com/files",
+			suffix:      "a%20b/c%2Fd/%D1%84%D0%B0%D0%B9%D0%BB",
+			want:        "https://example.com/files/a%20b/c%2Fd/%D1%84%D0%B0%D0%B9%D0%BB",
+		},
+		{
+			name:        "escaped characters in destination are preserved",
+			destination: "https://example.com/a%2Fb",
+			suffix:      "c",
+			want:        "https://example.com/a%2Fb/c",
+		},
+		{
+			name:        "dots inside segments are allowed",
+			destination: "https://example.com/",
+			suffix:      "v1.2/file..txt",
+			want:        "https://example.com/v1.2/file..txt",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, err := Build(tt.destination, Request{PathSuffix: tt.suffix, HasPathSuffix: true}, Options{ForwardPath: true})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, target)
+		})
+	}
+}
+
+func TestBuild_PathAndQuery(t *testing.T) {
+	target, err := Build(
+		"https://example.com/shop?ref=short",
+		Request{PathSuffix: "shoes/42", HasPathSuffix: true, RawQuery: "utm_source=mail&ref=mail"},
+		Options{ForwardPath: true, ForwardQuery: true, Policy: ConflictOverride},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/shop/shoes/42?utm_source=mail&ref=mail", target)
+}
+
+func TestBuild_InvalidPathSuffix(t *testing.T) {
+	suffixes := []string{
+		"..",
+		"a/../../admin",
+		"./a",
+		"%2e%2e/admin",
+		"%2E%2E",
+		"..%2Fadmin",
+		"a%2F..%2Fb",
+		"a%00b",
+		"a%0d%0aSet-Cookie:x",
+		"bad%zz",
+	}
+
+	for _, suffix := range suffixes {
+		t.Run(suffix, func(t *testing.T) {
+			_, err := Build("https://example.com/base", Request{PathSuffix: suffix, HasPathSuffix: true}, Options{ForwardPath: true})
+			assert.ErrorIs(t, err, ErrInvalidPathSuffix)
+		})
+	}
+}
+
+func TestBuild_UnsupportedDestination(t *testing.T) {
+	_, err := Build("mailto:user@example.com", Request{RawQuery: "a=1"}, Options{ForwardQuery: true})
+	assert.ErrorIs(t, err, ErrUnsupportedDestination)
+}
+
+func TestConflictPolicy_IsValid(t *testing.T) {
+	assert.True(t, ConflictKeepDestination.IsValid())
+	assert.True(t, ConflictOverride.IsValid())
+	assert.True(t, ConflictAppend.IsValid())
+	assert.False(t, ConflictPolicy("").IsValid())
+	assert.False(t, ConflictPolicy("merge").IsValid())
+}
+
+func TestBuild_Params(t *testing.T) {
+	tests := []struct {
+		name        string
+		destination string
+		params      string
+		req         Request
+		opts        Options
+		want        string
+	}{
+		{
+			name:        "params are added to destination",
+			destination: "https://example.com/page",
+			params:      "utm_source=news&utm_medium=email",
+			want:        "https://example.com/page?utm_source=news&utm_medium=email",
+		},
+		{
+			name:        "destination parameters are not clobbered",
+			destination: "https://example.com/page?utm_source=site&id=1",
+			params:      "utm_source=news&utm_campaign=spring",
+			want:        "https://example.com/page?utm_source=site&id=1&utm_campaign=spring",
+		},
+		{
+			name:        "params are added even when forwarding is off",
+			destination: "https://example.com/",
+			params:      "utm_source=news",
+			req:         Request{RawQuery: "utm_source=x"},
+			want:        "https://example.com/?utm_source=news",
+		},
+		{
+			name:        "incoming query is merged after params",
+			destination: "https://example.com/",
+			params:      "utm_source=news",
+			req:         Request{RawQuery: "utm_source=x&ref=y"},
+			opts:        Options{ForwardQuery: true, Policy: ConflictOverride},
+			want:        "https://example.com/?utm_source=x&ref=y",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.Params = tt.params
+			target, err := Build(tt.destination, tt.req, opts)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, target)
+		})
+	}
+}
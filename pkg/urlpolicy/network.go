@@ -0,0 +1,60 @@
+package urlpolicy
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+	"syscall"
+)
+
+// ErrNonPublicAddress is returned by DialControl for connections to non-public addresses.
+var ErrNonPublicAddress = errors.New("connection to a non-public address is not allowed")
+
+// nonPublicPrefixes are special-purpose ranges that net/netip does not classify
+// as private, loopback, link-local or multicast.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "this network"
+	netip.MustParsePrefix("100.64.0.0/10"),   // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // TEST-NET-1
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // TEST-NET-2
+	netip.MustParsePrefix("203.0.113.0/24"),  // TEST-NET-3
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved, including broadcast
+	netip.MustParsePrefix("2001:db8::/32"),   // documentation
+}
+
+// IsPublicAddr reports whether addr is a globally routable unicast address.
+// IPv4-mapped IPv6 addresses are checked as IPv4.
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsUnspecified() || addr.IsLoopback() || addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// DialControl can be used as net.Dialer.Control by HTTP clients that fetch
+// user-supplied URLs. It checks the address actually being connected to, so
+// DNS rebinding and redirects to internal hosts are refused as well.
+func DialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !IsPublicAddr(addr) {
+		return ErrNonPublicAddress
+	}
+	return nil
+}
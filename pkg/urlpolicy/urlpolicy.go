@@ -0,0 +1,393 @@
+// Package urlpolicy validates and normalizes link destinations: scheme allow-list,
+// IDN hosts, trailing dots and default ports, and rejects URLs that point back at
+// the service itself, at other URL shorteners or at private network addresses.
+package urlpolicy
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/idna"
+)
+
+// MaxURLLength is the longest accepted URL in bytes.
+const MaxURLLength = 4096
+
+// Error codes returned in Error.Code.
+const (
+	CodeInvalid        = "url_invalid"
+	CodeScheme         = "url_scheme_not_allowed"
+	CodeCredentials    = "url_credentials_not_allowed"
+	CodeSelfReference  = "url_self_reference"
+	CodeShortener      = "url_shortener_not_allowed"
+	CodePrivateNetwork = "url_private_network"
+)
+
+const (
+	dnsLookupTimeout     = 2 * time.Second
+	defaultInvalidReason = "URL must be an absolute http(s) URL such as https://example.com/page"
+)
+
+// DefaultSchemes are the schemes accepted when Options.Schemes is empty.
+var DefaultSchemes = []string{"http", "https"}
+
+// DefaultShortenerHosts are well-known URL shorteners. Chaining short links hides
+// the final destination from our checks and adds a redirect hop for visitors.
+var DefaultShortenerHosts = []string{
+	"bit.ly", "bit.do", "buff.ly", "clck.ru", "cutt.ly", "goo.gl", "is.gd", "lnkd.in",
+	"ow.ly", "rb.gy", "rebrand.ly", "s.id", "shorturl.at", "t.co", "t.ly", "tiny.cc",
+	"tinyurl.com", "v.gd", "vk.cc",
+}
+
+// privateSuffixes are host name suffixes that never resolve on the public internet.
+var privateSuffixes = []string{".localhost", ".local", ".internal", ".home.arpa"}
+
+// Error is a policy rejection with a machine-readable code.
+type Error struct {
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Resolver resolves host names; *net.Resolver satisfies it.
+type Resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// Options configures a Policy.
+type Options struct {
+	// Schemes is the scheme allow-list; empty means DefaultSchemes.
+	Schemes []string
+	// SelfHosts are the host names of the service. URLs pointing at them or at
+	// their subdomains would redirect back to the service.
+	SelfHosts []string
+	// IsSelfHost reports additional self hosts, such as verified custom domains.
+	// Errors are treated as "not a self host".
+	IsSelfHost func(ctx context.Context, host string) (bool, error)
+	// ShortenerHosts are rejected together with their subdomains.
+	ShortenerHosts []string
+	// BlockPrivate rejects loopback, private, link-local and other non-public
+	// addresses, both as IP literals and, when Resolver is set, after resolving the host.
+	BlockPrivate bool
+	// Resolver is used to resolve host names when BlockPrivate is set.
+	// Lookup failures do not reject the URL: the destination may simply be down.
+	Resolver Resolver
+}
+
+// Policy checks link destinations.
+type Policy struct {
+	schemes      map[string]bool
+	selfHosts    []string
+	isSelfHost   func(ctx context.Context, host string) (bool, error)
+	shorteners   []string
+	blockPrivate bool
+	resolver     Resolver
+}
+
+// New returns a policy with the given options.
+func New(opts Options) *Policy {
+	schemes := opts.Schemes
+	if len(schemes) == 0 {
+		schemes = DefaultSchemes
+	}
+	p := &Policy{
+		schemes:      make(map[string]bool, len(schemes)),
+		isSelfHost:   opts.IsSelfHost,
+		blockPrivate: opts.BlockPrivate,
+		resolver:     opts.Resolver,
+	}
+	for _, scheme := range schemes {
+		p.schemes[strings.ToLower(scheme)] = true
+	}
+	for _, host := range opts.SelfHosts {
+		if host = normalizeHostForMatch(host); host != "" {
+			p.selfHosts = append(p.selfHosts, host)
+		}
+	}
+	for _, host := range opts.ShortenerHosts {
+		if host = normalizeHostForMatch(host); host != "" {
+			p.shorteners = append(p.shorteners, host)
+		}
+	}
+	return p
+}
+
+// Check normalizes a redirect destination and applies all checks. The returned
+// error is always an *Error.
+func (p *Policy) Check(ctx context.Context, rawURL string) (string, error) {
+	u, err := p.normalize(rawURL)
+	if err != nil {
+		return "", err
+	}
+	host := strings.Trim(u.Hostname(), "[]")
+
+	if p.isSelf(ctx, host) {
+		return "", &Error{Code: CodeSelfReference, Message: "URL points to this link shortener and would create a redirect loop"}
+	}
+	if matchesHost(host, p.shorteners) {
+		return "", &Error{Code: CodeShortener, Message: "URL points to another link shortener, use the final destination instead"}
+	}
+	if err := p.checkNetwork(ctx, host); err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// CheckResource normalizes a URL that is loaded by the visitor's browser rather
+// than redirected to, such as a tracking pixel: only the scheme, credentials and
+// network checks apply.
+func (p *Policy) CheckResource(ctx context.Context, rawURL string) (string, error) {
+	u, err := p.normalize(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if err := p.checkNetwork(ctx, strings.Trim(u.Hostname(), "[]")); err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// Normalize parses an absolute URL and normalizes its host: lower case, IDN
+// converted to punycode, trailing dots removed, IPv4 literals in dotted-decimal
+// form and the default port of the scheme removed. Path, query and fragment are
+// kept as they are.
+func Normalize(rawURL string) (string, error) {
+	u, err := New(Options{}).normalize(rawURL)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+func (p *Policy) normalize(rawURL string) (*url.URL, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" || len(rawURL) > MaxURLLength || strings.IndexFunc(rawURL, isControlOrSpace) >= 0 {
+		return nil, invalid(defaultInvalidReason)
+	}
+
+	// "example.com:8080/page" parses with the scheme "example.com"
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme == "" || strings.Contains(u.Scheme, ".") {
+		return nil, invalid(defaultInvalidReason)
+	}
+	if !p.schemes[u.Scheme] {
+		return nil, &Error{Code: CodeScheme, Message: "URL scheme " + u.Scheme + " is not allowed, use http or https"}
+	}
+	if u.Opaque != "" || u.Host == "" {
+		return nil, invalid(defaultInvalidReason)
+	}
+	if u.User != nil {
+		return nil, &Error{Code: CodeCredentials, Message: "URL must not contain a user name or password"}
+	}
+
+	host, err := normalizeHost(u.Hostname())
+	if err != nil {
+		return nil, err
+	}
+
+	port := u.Port()
+	if port != "" {
+		n, err := strconv.Atoi(port)
+		if err != nil || n < 1 || n > 65535 {
+			return nil, invalid("URL port must be between 1 and 65535")
+		}
+		port = strconv.Itoa(n)
+		if (u.Scheme == "http" && n == 80) || (u.Scheme == "https" && n == 443) {
+			port = ""
+		}
+	}
+
+	u.Host = host
+	if port != "" {
+		u.Host = net.JoinHostPort(strings.Trim(host, "[]"), port)
+	}
+	return u, nil
+}
+
+// normalizeHost normalizes a host name or IP literal; IPv6 literals are returned in brackets.
+func normalizeHost(host string) (string, error) {
+	if strings.Contains(host, ":") {
+		addr, err := netip.ParseAddr(host)
+		if err != nil || addr.Zone() != "" {
+			return "", invalid("URL contains an invalid IPv6 address")
+		}
+		return "[" + addr.String() + "]", nil
+	}
+
+	host = strings.TrimRight(host, ".")
+	if host == "" {
+		return "", invalid(defaultInvalidReason)
+	}
+	if addr, ok := parseIPv4(host); ok {
+		return addr.String(), nil
+	}
+
+	ascii, err := idna.Lookup.ToASCII(host)
+	if err != nil {
+		// idna rejects '_', which some real host names use
+		ascii = strings.ToLower(host)
+		if !isASCIIHostname(ascii) {
+			return "", invalid("URL host name is not valid")
+		}
+	}
+	if !strings.Contains(ascii, ".") && ascii != "localhost" {
+		return "", invalid("URL host must be a fully qualified domain name such as example.com")
+	}
+	return ascii, nil
+}
+
+// isSelf reports whether the host belongs to the service.
+func (p *Policy) isSelf(ctx context.Context, host string) bool {
+	if matchesHost(host, p.selfHosts) {
+		return true
+	}
+	if p.isSelfHost == nil {
+		return false
+	}
+	self, err := p.isSelfHost(ctx, host)
+	return err == nil && self
+}
+
+// checkNetwork rejects non-public destinations when BlockPrivate is set.
+func (p *Policy) checkNetwork(ctx context.Context, host string) error {
+	if !p.blockPrivate {
+		return nil
+	}
+	privateErr := &Error{Code: CodePrivateNetwork, Message: "URL points to a private or local network address"}
+
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !IsPublicAddr(addr) {
+			return privateErr
+		}
+		return nil
+	}
+	if host == "localhost" || matchesSuffix(host, privateSuffixes) {
+		return privateErr
+	}
+	if p.resolver == nil {
+		return nil
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, dnsLookupTimeout)
+	defer cancel()
+	addrs, err := p.resolver.LookupIPAddr(lookupCtx, host)
+	if err != nil {
+		return nil
+	}
+	for _, ipAddr := range addrs {
+		addr, ok := netip.AddrFromSlice(ipAddr.IP)
+		if !ok || !IsPublicAddr(addr) {
+			return privateErr
+		}
+	}
+	return nil
+}
+
+// matchesHost reports whether host equals one of hosts or is their subdomain.
+func matchesHost(host string, hosts []string) bool {
+	for _, candidate := range hosts {
+		if host == candidate || strings.HasSuffix(host, "."+candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesSuffix(host string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeHostForMatch normalizes a configured host; invalid hosts are dropped.
+func normalizeHostForMatch(host string) string {
+	host = strings.TrimSpace(host)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimRight(strings.ToLower(host), ".")
+	if ascii, err := idna.Lookup.ToASCII(host); err == nil {
+		host = ascii
+	}
+	return host
+}
+
+// parseIPv4 parses IPv4 literals the way browsers do, including the shorthand,
+// octal and hexadecimal forms (127.1, 0177.0.0.1, 0x7f000001, 2130706433).
+func parseIPv4(host string) (netip.Addr, bool) {
+	parts := strings.Split(host, ".")
+	if len(parts) > 4 {
+		return netip.Addr{}, false
+	}
+
+	numbers := make([]uint64, len(parts))
+	for i, part := range parts {
+		n, ok := parseIPv4Number(part)
+		if !ok {
+			return netip.Addr{}, false
+		}
+		numbers[i] = n
+	}
+
+	var value uint64
+	for i, n := range numbers[:len(numbers)-1] {
+		if n > 255 {
+			return netip.Addr{}, false
+		}
+		value |= n << (8 * (3 - i))
+	}
+	last := numbers[len(numbers)-1]
+	if last >= 1<<(8*(5-len(numbers))) {
+		return netip.Addr{}, false
+	}
+	value |= last
+
+	return netip.AddrFrom4([4]byte{byte(value >> 24), byte(value >> 16), byte(value >> 8), byte(value)}), true
+}
+
+func parseIPv4Number(part string) (uint64, bool) {
+	if part == "" {
+		return 0, false
+	}
+	base := 10
+	switch {
+	case strings.HasPrefix(part, "0x") || strings.HasPrefix(part, "0X"):
+		part, base = part[2:], 16
+		if part == "" {
+			return 0, true
+		}
+	case len(part) > 1 && part[0] == '0':
+		part, base = part[1:], 8
+	}
+	n, err := strconv.ParseUint(part, base, 32)
+	return n, err == nil
+}
+
+func isASCIIHostname(host string) bool {
+	for i := 0; i < len(host); i++ {
+		c := host[i]
+		if !((c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-' || c == '_' || c == '.') {
+			return false
+		}
+	}
+	return host != ""
+}
+
+func isControlOrSpace(r rune) bool {
+	return r <= ' ' || r == 0x7f
+}
+
+func invalid(message string) *Error {
+	return &Error{Code: CodeInvalid, Message: message}
+}
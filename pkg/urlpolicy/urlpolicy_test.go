@@ -0,0 +1,146 @@
+package urlpolicy
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubResolver map[string][]string
+
+func (r stubResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	ips, ok := r[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	addrs := make([]net.IPAddr, 0, len(ips))
+	for _, ip := range ips {
+		addrs = append(addrs, net.IPAddr{IP: net.ParseIP(ip)})
+	}
+	return addrs, nil
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{raw: "  HTTPS://Example.COM./Path?q=A#Top ", want: "https://example.com/Path?q=A#Top"},
+		{raw: "http://example.com:80/", want: "http://example.com/"},
+		{raw: "https://example.com:443", want: "https://example.com"},
+		{raw: "https://example.com:8443/a", want: "https://example.com:8443/a"},
+		{raw: "https://пример.рф/страница", want: "https://xn--e1afmkfd.xn--p1ai/%D1%81%D1%82%D1%80%D0%B0%D0%BD%D0%B8%D1%86%D0%B0"},
+		{raw: "http://2130706433/", want: "http://127.0.0.1/"},
+		{raw: "http://0x7f.1/", want: "http://127.0.0.1/"},
+		{raw: "http://0177.0.0.01/", want: "http://127.0.0.1/"},
+		{raw: "http://[0:0:0:0:0:0:0:1]:8080/", want: "http://[::1]:8080/"},
+		{raw: "https://my_host.example.com/", want: "https://my_host.example.com/"},
+		{raw: "https://example.com/a%2Fb?x=%20", want: "https://example.com/a%2Fb?x=%20"},
+	}
+	for _, tt := range tests {
+		got, err := Normalize(tt.raw)
+		require.NoError(t, err, tt.raw)
+		assert.Equal(t, tt.want, got, tt.raw)
+	}
+}
+
+func TestNormalize_Invalid(t *testing.T) {
+	tests := []struct {
+		raw  string
+		code string
+	}{
+		{raw: "", code: CodeInvalid},
+		{raw: "example.com", code: CodeInvalid},
+		{raw: "example.com:8080/page", code: CodeInvalid},
+		{raw: "//example.com/page", code: CodeInvalid},
+		{raw: "https://exa mple.com/", code: CodeInvalid},
+		{raw: "https://intranet/", code: CodeInvalid},
+		{raw: "https://example.com:0/", code: CodeInvalid},
+		{raw: "javascript:alert(1)", code: CodeScheme},
+		{raw: "ftp://example.com/file", code: CodeScheme},
+		{raw: "data:text/html,<script>", code: CodeScheme},
+		{raw: "https://paypal.com@evil.example/", code: CodeCredentials},
+	}
+	for _, tt := range tests {
+		_, err := Normalize(tt.raw)
+		var policyErr *Error
+		if assert.True(t, errors.As(err, &policyErr), tt.raw) {
+			assert.Equal(t, tt.code, policyErr.Code, tt.raw)
+		}
+	}
+}
+
+func TestPolicy_Check(t *testing.T) {
+	policy := New(Options{
+		SelfHosts: []string{"gurls.ru:443"},
+		IsSelfHost: func(ctx context.Context, host string) (bool, error) {
+			return host == "go.customer.com", nil
+		},
+		ShortenerHosts: DefaultShortenerHosts,
+		BlockPrivate:   true,
+		Resolver: stubResolver{
+			"public.example.com":   {"93.184.216.34"},
+			"internal.example.com": {"93.184.216.34", "10.0.0.5"},
+			"mapped.example.com":   {"::ffff:127.0.0.1"},
+		},
+	})
+
+	tests := []struct {
+		raw  string
+		code string
+	}{
+		{raw: "https://public.example.com/page", code: ""},
+		{raw: "https://unresolvable.example.com/page", code: ""},
+		{raw: "https://GURLS.ru/abc", code: CodeSelfReference},
+		{raw: "https://www.gurls.ru/abc", code: CodeSelfReference},
+		{raw: "https://go.customer.com/abc", code: CodeSelfReference},
+		{raw: "https://bit.ly/abc", code: CodeShortener},
+		{raw: "https://www.tinyurl.com/abc", code: CodeShortener},
+		{raw: "http://localhost:8080/", code: CodePrivateNetwork},
+		{raw: "http://printer.local/", code: CodePrivateNetwork},
+		{raw: "http://192.168.1.1/admin", code: CodePrivateNetwork},
+		{raw: "http://169.254.169.254/latest/meta-data/", code: CodePrivateNetwork},
+		{raw: "http://2130706433/", code: CodePrivateNetwork},
+		{raw: "http://[::1]/", code: CodePrivateNetwork},
+		{raw: "http://[fd00::1]/", code: CodePrivateNetwork},
+		{raw: "http://100.64.0.1/", code: CodePrivateNetwork},
+		{raw: "https://internal.example.com/", code: CodePrivateNetwork},
+		{raw: "https://mapped.example.com/", code: CodePrivateNetwork},
+	}
+	for _, tt := range tests {
+		_, err := policy.Check(context.Background(), tt.raw)
+		if tt.code == "" {
+			assert.NoError(t, err, tt.raw)
+			continue
+		}
+		var policyErr *Error
+		if assert.True(t, errors.As(err, &policyErr), tt.raw) {
+			assert.Equal(t, tt.code, policyErr.Code, tt.raw)
+		}
+	}
+
+	// resources (pixels) may point at the service but not into the internal network
+	_, err := policy.CheckResource(context.Background(), "https://gurls.ru/pixel.gif")
+	assert.NoError(t, err)
+	_, err = policy.CheckResource(context.Background(), "http://10.0.0.1/pixel.gif")
+	assert.Error(t, err)
+}
+
+func TestPolicy_AllowPrivate(t *testing.T) {
+	policy := New(Options{})
+	got, err := policy.Check(context.Background(), "http://localhost:8080/")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080/", got)
+}
+
+func TestDialControl(t *testing.T) {
+	assert.ErrorIs(t, DialControl("tcp", "127.0.0.1:80", nil), ErrNonPublicAddress)
+	assert.ErrorIs(t, DialControl("tcp", "[::ffff:10.0.0.1]:443", nil), ErrNonPublicAddress)
+	assert.NoError(t, DialControl("tcp", "93.184.216.34:443", nil))
+	assert.True(t, IsPublicAddr(netip.MustParseAddr("2606:2800:220:1:248:1893:25c8:1946")))
+}
@@ -0,0 +1,45 @@
+package useragent
+
+// unfurlBot is a link-unfurling crawler: a messenger or social network
+// service that fetches a pasted link to build its preview card
+type unfurlBot struct {
+	token string // substring of the User-Agent, matched case-insensitively
+	name  string
+}
+
+// unfurlBots known link-unfurling crawlers. Order matters: some of them
+// mention others in their User-Agent (Telegram's bot includes "like
+// TwitterBot"), so more specific tokens come first. Only crawler tokens are
+// listed: in-app browsers of the same apps open links for real visitors.
+var unfurlBots = []unfurlBot{
+	{"TelegramBot", "telegram"},
+	{"Slackbot-LinkExpanding", "slack"},
+	{"Slack-ImgProxy", "slack"},
+	{"Slackbot", "slack"},
+	{"vkShare", "vk"},
+	{"OdklBot", "ok"},
+	{"facebookexternalhit", "facebook"},
+	{"Facebot", "facebook"},
+	{"Twitterbot", "twitter"},
+	{"LinkedInBot", "linkedin"},
+	{"WhatsApp", "whatsapp"},
+	{"Discordbot", "discord"},
+	{"SkypeUriPreview", "skype"},
+	{"MicrosoftPreview", "microsoft"},
+	{"redditbot", "reddit"},
+	{"Pinterestbot", "pinterest"},
+	{"Embedly", "embedly"},
+	{"Iframely", "iframely"},
+}
+
+// DetectUnfurlBot returns the name of the link-unfurling crawler that sent
+// the request (telegram, slack, vk, ...) or an empty string for anything
+// else, including search engine crawlers
+func DetectUnfurlBot(userAgent string) string {
+	for _, bot := range unfurlBots {
+		if containsIgnoreCase(userAgent, bot.token) {
+			return bot.name
+		}
+	}
+	return ""
+}
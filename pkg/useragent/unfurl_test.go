@@ -0,0 +1,27 @@
+package useragent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectUnfurlBot(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		want      string
+	}{
+		{"TelegramBot (like TwitterBot)", "telegram"},
+		{"Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)", "slack"},
+		{"Mozilla/5.0 (compatible; vkShare; +http://vk.com/dev/Share)", "vk"},
+		{"facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)", "facebook"},
+		{"WhatsApp/2.23.20.0 A", "whatsapp"},
+		{"Mozilla/5.0 (compatible; Discordbot/2.0; +https://discordapp.com)", "discord"},
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", ""},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 Mobile/15E148 [FBAN/FBIOS]", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, DetectUnfurlBot(tt.userAgent), tt.userAgent)
+	}
+}